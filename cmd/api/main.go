@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"log"
 	"net/http"
 	"os"
@@ -15,9 +16,12 @@ import (
 	redis "github.com/redis/go-redis/v9"
 
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/handler"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/mailer"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/metrics"
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/middleware"
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/repository"
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/config"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
 )
 
@@ -33,6 +37,9 @@ func main() {
 	defer db.Close()
 
 	userRepo := repository.NewSQLRepository(db)
+	if err := userRepo.Migrate(ctx); err != nil {
+		log.Fatalf("failed to migrate the database schema: %v", err)
+	}
 
 	redisClient := redis.NewClient(&redis.Options{
 		Addr:     cfg.RedisAddress,
@@ -45,21 +52,94 @@ func main() {
 	}
 	log.Println("Authenticated with Redis successfully")
 
-	authService := services.NewAuthService(
-		cfg.GoogleClientID,
-		cfg.GoogleClientSecret,
-		cfg.GoogleRedirectURL,
-		userRepo,
-		cfg.JWTPrivateKey,
-		redisClient,
-	)
-
-	authMiddleware := middleware.NewAuthMiddleware(cfg.JWTPublicKey, redisClient)
-	registrationService := services.NewRegistrationService(userRepo)
+	authMiddleware := middleware.NewAuthMiddleware(cfg.JWTPublicKey, redisClient, userRepo)
+	tokenIssuer := services.NewTokenIssuer(cfg.JWTPrivateKey, redisClient, userRepo, userRepo)
+	parentLifecycleService := services.NewParentLifecycleService(userRepo, userRepo, tokenIssuer)
+	acceptURLs := make(map[string]string, len(cfg.Tenants))
+	for _, tenant := range cfg.Tenants {
+		acceptURLs[tenant.ID] = tenant.AcceptURL()
+	}
+	invitationService := services.NewInvitationService(userRepo, userRepo, parentLifecycleService, mailer.New(cfg.Invitation.SMTP), services.InvitationSettings{
+		SigningKey: cfg.Invitation.SigningKey,
+		AcceptURLs: acceptURLs,
+		TTL:        cfg.Invitation.TTL,
+	})
+	registrationService := services.NewRegistrationService(userRepo, userRepo, invitationService)
+	visitorService := services.NewVisitorService(userRepo, userRepo, tokenIssuer)
+	oauthClients := make(map[string]services.OAuthClient, len(cfg.Tenants))
+	for _, tenant := range cfg.Tenants {
+		oauthClients[tenant.ID] = services.OAuthClient{
+			ClientID:     tenant.GoogleClientID,
+			ClientSecret: tenant.GoogleClientSecret,
+			RedirectURL:  tenant.GoogleRedirectURL,
+		}
+	}
+	impersonationService := services.NewImpersonationService(userRepo, userRepo, tokenIssuer, tokenIssuer)
+	authService := services.NewAuthService(oauthClients, userRepo, tokenIssuer, visitorService, invitationService, impersonationService)
+	enrollmentService := services.NewEnrollmentService(userRepo, userRepo, tokenIssuer, redisClient)
+	roleService := services.NewRoleService(userRepo)
+	wardPolicy := services.NewWardPolicy(userRepo, userRepo)
+	bulkRegistrationService := services.NewBulkRegistrationService(userRepo, wardPolicy, invitationService)
+	authzService := services.NewAuthzService(userRepo, userRepo, userRepo)
+	userDirectoryService := services.NewUserDirectoryService(userRepo)
+	userExportService := services.NewUserExportService(userRepo, userRepo)
+	erasureService := services.NewErasureService(userRepo, userRepo, tokenIssuer)
+	subjectAccessService := services.NewSubjectAccessService(userRepo, userRepo, userRepo, tokenIssuer)
+	tenantIDs := make([]string, len(cfg.Tenants))
+	for i, tenant := range cfg.Tenants {
+		tenantIDs[i] = tenant.ID
+	}
+	retentionService := services.NewRetentionService(domain.RetentionPolicy{
+		ParentAnonymizeAfter: cfg.Retention.ParentAnonymizeAfter,
+		OutboxRetention:      cfg.Retention.OutboxRetention,
+		BatchSize:            cfg.Retention.BatchSize,
+	}, userRepo, erasureService, metrics.RetentionMetrics{}, tenantIDs)
+	adminApprovalService := services.NewAdminApprovalService(userRepo, userRepo, tokenIssuer, cfg.AdminApprovalTTL)
+	transferService := services.NewTransferService(userRepo, userRepo)
+	userAdminService := services.NewUserAdminService(userRepo, userRepo, tokenIssuer, adminApprovalService, transferService)
+	for _, tenant := range cfg.Tenants {
+		if err := userRepo.SeedTenant(domain.WithTenant(ctx, tenant.ID)); err != nil {
+			log.Printf("Warning: failed to seed the roles and policies of tenant %s: %v", tenant.ID, err)
+		}
+		if admin := tenant.BootstrapAdmin; admin != nil {
+			created, err := adminApprovalService.Bootstrap(domain.WithTenant(ctx, tenant.ID), admin.Email, admin.FirstName, admin.LastName)
+			if err != nil {
+				log.Printf("Warning: failed to create the first admin of tenant %s: %v", tenant.ID, err)
+			} else if created {
+				log.Printf("Created the first admin of tenant %s", tenant.ID)
+			}
+		}
+		for _, id := range tenant.ApproveAdminRequests {
+			// Listed approvals stay in the configuration; once decided they are skipped
+			if _, err := adminApprovalService.ApproveAsOperator(domain.WithTenant(ctx, tenant.ID), id); err == nil {
+				log.Printf("Operator approved admin request %s of tenant %s", id, tenant.ID)
+			} else if !errors.Is(err, domain.ErrAdminApprovalClosed) {
+				log.Printf("Warning: operator approval of admin request %s of tenant %s failed: %v", id, tenant.ID, err)
+			}
+		}
+	}
+	admissionService := services.NewAdmissionService(userRepo, userRepo, invitationService, parentLifecycleService)
+	profileService := services.NewProfileService(userRepo)
 
 	authHandler := handler.NewAuthHandler(authService)
-	registrationHandler := handler.NewRegistrationHandler(registrationService)
+	registrationHandler := handler.NewRegistrationHandler(registrationService, wardPolicy, adminApprovalService)
+	bulkRegistrationHandler := handler.NewBulkRegistrationHandler(bulkRegistrationService)
 	healthHandler := handler.NewHealthHandler(db, redisClient)
+	impersonationHandler := handler.NewImpersonationHandler(impersonationService)
+	enrollmentHandler := handler.NewEnrollmentHandler(enrollmentService)
+	visitorHandler := handler.NewVisitorHandler(visitorService)
+	roleHandler := handler.NewRoleHandler(roleService)
+	authzHandler := handler.NewAuthzHandler(authzService)
+	userHandler := handler.NewUserHandler(userDirectoryService, userAdminService, wardPolicy)
+	exportHandler := handler.NewExportHandler(userExportService)
+	erasureHandler := handler.NewErasureHandler(erasureService)
+	subjectAccessHandler := handler.NewSubjectAccessHandler(subjectAccessService)
+	retentionHandler := handler.NewRetentionHandler(retentionService)
+	parentHandler := handler.NewParentHandler(transferService, parentLifecycleService, invitationService, wardPolicy)
+	invitationHandler := handler.NewInvitationHandler(invitationService)
+	admissionHandler := handler.NewAdmissionHandler(admissionService, wardPolicy)
+	adminApprovalHandler := handler.NewAdminApprovalHandler(adminApprovalService)
+	profileHandler := handler.NewProfileHandler(profileService)
 
 	mux := http.NewServeMux()
 
@@ -74,22 +154,220 @@ func main() {
 	// API endpoints
 	mux.HandleFunc("GET /login", authHandler.Login)
 	mux.HandleFunc("GET /auth/google/callback", authHandler.LoginCallback)
+	mux.HandleFunc("GET /invitations/accept", authHandler.AcceptInvitation)
+
+	mux.HandleFunc("POST /enroll", enrollmentHandler.Redeem)
+	mux.HandleFunc("POST /enroll/token", enrollmentHandler.DeviceToken)
+	mux.HandleFunc("POST /visitors/redeem", visitorHandler.Redeem)
 
 	mux.Handle("POST /register",
-		authMiddleware.RequireRole([]string{"ADMIN"}, http.HandlerFunc(registrationHandler.Register)),
+		authMiddleware.RequirePermission(domain.PermUsersRegister, middleware.DenyImpersonation(registrationHandler.Register)),
+	)
+
+	mux.Handle("POST /register/bulk",
+		authMiddleware.RequirePermission(domain.PermUsersRegister, middleware.DenyImpersonation(bulkRegistrationHandler.RegisterParents)),
+	)
+
+	mux.Handle("GET /invitations",
+		authMiddleware.RequirePermission(domain.PermInvitationsManage, invitationHandler.List),
+	)
+
+	mux.Handle("POST /invitations/{invitationID}/resend",
+		authMiddleware.RequirePermission(domain.PermInvitationsManage, middleware.DenyImpersonation(invitationHandler.Resend)),
+	)
+
+	mux.Handle("POST /invitations/{invitationID}/cancel",
+		authMiddleware.RequirePermission(domain.PermInvitationsManage, middleware.DenyImpersonation(invitationHandler.Cancel)),
+	)
+
+	mux.Handle("GET /admin-approvals",
+		authMiddleware.RequirePermission(domain.PermAdminsApprove, adminApprovalHandler.List),
+	)
+
+	mux.Handle("POST /admin-approvals/{approvalID}/approve",
+		authMiddleware.RequirePermission(domain.PermAdminsApprove, middleware.DenyImpersonation(adminApprovalHandler.Approve)),
+	)
+
+	mux.Handle("POST /admin-approvals/{approvalID}/reject",
+		authMiddleware.RequirePermission(domain.PermAdminsApprove, middleware.DenyImpersonation(adminApprovalHandler.Reject)),
+	)
+
+	mux.Handle("GET /users",
+		authMiddleware.RequirePermission(domain.PermUsersRead, userHandler.ListUsers),
+	)
+
+	mux.Handle("POST /users/{userID}/erase",
+		authMiddleware.RequirePermission(domain.PermUsersErase, middleware.DenyImpersonation(erasureHandler.EraseUser)),
+	)
+
+	mux.Handle("POST /users/{userID}/subject-access-requests",
+		authMiddleware.RequirePermission(domain.PermUsersSubjectAccess, middleware.DenyImpersonation(subjectAccessHandler.RequestForUser)),
+	)
+
+	mux.Handle("GET /subject-access-requests/{requestID}",
+		authMiddleware.RequirePermission(domain.PermUsersSubjectAccess, subjectAccessHandler.Status),
+	)
+
+	mux.Handle("GET /subject-access-requests/{requestID}/archive",
+		authMiddleware.RequirePermission(domain.PermUsersSubjectAccess, middleware.DenyImpersonation(subjectAccessHandler.Download)),
+	)
+
+	mux.Handle("GET /me/profile",
+		authMiddleware.RequirePermission(domain.PermSelfProfile, profileHandler.GetProfile),
+	)
+
+	mux.Handle("PATCH /me/profile",
+		authMiddleware.RequirePermission(domain.PermSelfProfile, middleware.DenyImpersonation(profileHandler.UpdateProfile)),
+	)
+
+	mux.Handle("POST /me/subject-access-requests",
+		authMiddleware.RequirePermission(domain.PermSelfSubjectAccess, middleware.DenyImpersonation(subjectAccessHandler.RequestOwn)),
+	)
+
+	mux.Handle("GET /me/subject-access-requests/{requestID}",
+		authMiddleware.RequirePermission(domain.PermSelfSubjectAccess, subjectAccessHandler.OwnStatus),
+	)
+
+	mux.Handle("GET /me/subject-access-requests/{requestID}/archive",
+		authMiddleware.RequirePermission(domain.PermSelfSubjectAccess, middleware.DenyImpersonation(subjectAccessHandler.OwnDownload)),
+	)
+
+	mux.Handle("GET /exports/users",
+		authMiddleware.RequirePermission(domain.PermUsersExport, middleware.DenyImpersonation(exportHandler.ExportUsers)),
+	)
+
+	mux.Handle("GET /users/{userID}",
+		authMiddleware.RequirePermission(domain.PermUsersRead, userHandler.GetUser),
+	)
+
+	mux.Handle("PATCH /users/{userID}",
+		authMiddleware.RequirePermission(domain.PermUsersManage, middleware.DenyImpersonation(userHandler.UpdateUser)),
+	)
+
+	mux.Handle("DELETE /users/{userID}",
+		authMiddleware.RequirePermission(domain.PermUsersManage, middleware.DenyImpersonation(userHandler.DeleteUser)),
 	)
 
 	mux.Handle("POST /logout",
-		authMiddleware.RequireRole([]string{"ADMIN", "PARENT"}, http.HandlerFunc(authHandler.Logout)),
+		authMiddleware.RequirePermission(domain.PermSessionLogout, authHandler.Logout),
+	)
+
+	mux.Handle("POST /visitors",
+		authMiddleware.RequirePermission(domain.PermVisitorsManage, middleware.DenyImpersonation(visitorHandler.Invite)),
+	)
+
+	mux.Handle("GET /visitors",
+		authMiddleware.RequirePermission(domain.PermVisitorsManage, visitorHandler.List),
+	)
+
+	mux.Handle("DELETE /visitors/{visitorID}",
+		authMiddleware.RequirePermission(domain.PermVisitorsManage, middleware.DenyImpersonation(visitorHandler.Revoke)),
 	)
 
 	mux.Handle("POST /discharge",
-		authMiddleware.RequireRole([]string{"ADMIN"}, http.HandlerFunc(authHandler.DischargeParent)),
+		authMiddleware.RequirePermission(domain.PermParentsDischarge, middleware.DenyImpersonation(parentHandler.DischargeParent)),
+	)
+
+	mux.Handle("POST /parents/{parentID}/transfer",
+		authMiddleware.RequirePermission(domain.PermParentsTransfer, middleware.DenyImpersonation(parentHandler.Transfer)),
+	)
+
+	mux.Handle("POST /parents/{parentID}/activate",
+		authMiddleware.RequirePermission(domain.PermParentsAdmit, middleware.DenyImpersonation(parentHandler.Activate)),
+	)
+
+	mux.Handle("POST /parents/{parentID}/readmit",
+		authMiddleware.RequirePermission(domain.PermParentsAdmit, middleware.DenyImpersonation(parentHandler.Readmit)),
+	)
+
+	mux.Handle("POST /parents/{parentID}/archive",
+		authMiddleware.RequirePermission(domain.PermParentsArchive, middleware.DenyImpersonation(parentHandler.Archive)),
+	)
+
+	mux.Handle("GET /parents/{parentID}/status-history",
+		authMiddleware.RequirePermission(domain.PermUsersRead, parentHandler.StatusHistory),
+	)
+
+	mux.Handle("GET /parents/{parentID}/admission",
+		authMiddleware.RequirePermission(domain.PermUsersRead, admissionHandler.ForParent),
+	)
+
+	mux.Handle("GET /admissions/{admissionID}",
+		authMiddleware.RequirePermission(domain.PermUsersRead, admissionHandler.Get),
+	)
+
+	mux.Handle("POST /admissions/{admissionID}/parents",
+		authMiddleware.RequirePermission(domain.PermUsersRegister, middleware.DenyImpersonation(admissionHandler.AddCoParent)),
+	)
+
+	mux.Handle("POST /admissions/{admissionID}/discharge",
+		authMiddleware.RequirePermission(domain.PermParentsDischarge, middleware.DenyImpersonation(admissionHandler.Discharge)),
+	)
+
+	mux.Handle("POST /enrollment-codes",
+		authMiddleware.RequirePermission(domain.PermEnrollmentIssue, middleware.DenyImpersonation(enrollmentHandler.IssueCode)),
+	)
+
+	mux.Handle("POST /impersonate/{userID}",
+		authMiddleware.RequirePermission(domain.PermUsersImpersonate, middleware.DenyImpersonation(impersonationHandler.Start)),
+	)
+
+	mux.Handle("POST /impersonate/end",
+		authMiddleware.RequirePermission(domain.PermSessionLogout, impersonationHandler.End),
+	)
+
+	mux.Handle("GET /retention/report",
+		authMiddleware.RequirePermission(domain.PermRetentionManage, retentionHandler.Report),
+	)
+
+	// Role and permission administration
+	mux.Handle("GET /roles",
+		authMiddleware.RequirePermission(domain.PermRolesManage, roleHandler.ListRoles),
+	)
+
+	mux.Handle("POST /roles",
+		authMiddleware.RequirePermission(domain.PermRolesManage, middleware.DenyImpersonation(roleHandler.CreateRole)),
+	)
+
+	mux.Handle("PUT /roles/{role}/permissions",
+		authMiddleware.RequirePermission(domain.PermRolesManage, middleware.DenyImpersonation(roleHandler.SetRolePermissions)),
+	)
+
+	mux.Handle("GET /permissions",
+		authMiddleware.RequirePermission(domain.PermRolesManage, roleHandler.ListPermissions),
+	)
+
+	mux.Handle("POST /permissions",
+		authMiddleware.RequirePermission(domain.PermRolesManage, middleware.DenyImpersonation(roleHandler.CreatePermission)),
+	)
+
+	// Central authorization decisions for other services
+	mux.Handle("POST /authz/check",
+		authMiddleware.RequirePermission(domain.PermAuthzCheck, authzHandler.Check),
+	)
+
+	mux.Handle("POST /authz/check/batch",
+		authMiddleware.RequirePermission(domain.PermAuthzCheck, authzHandler.CheckBatch),
+	)
+
+	mux.Handle("GET /authz/policies",
+		authMiddleware.RequirePermission(domain.PermAuthzManage, authzHandler.ListPolicies),
+	)
+
+	mux.Handle("POST /authz/policies",
+		authMiddleware.RequirePermission(domain.PermAuthzManage, middleware.DenyImpersonation(authzHandler.CreatePolicy)),
 	)
 
-	// Apply middleware chain: CORS -> Metrics
-	corsRouter := middleware.CORSMiddleware(cfg.CORSAllowedOrigins)(mux)
-	loggedRouter := middleware.MetricsMiddleware(corsRouter)
+	mux.Handle("DELETE /authz/policies/{policyID}",
+		authMiddleware.RequirePermission(domain.PermAuthzManage, middleware.DenyImpersonation(authzHandler.DeletePolicy)),
+	)
+
+	// Apply middleware chain: Tenant -> CORS -> Metrics
+	corsRouter := middleware.CORSMiddleware(cfg)(mux)
+	tenantRouter := middleware.TenantMiddleware(cfg)(corsRouter)
+	localizedRouter := middleware.LocaleMiddleware(tenantRouter)
+	correlatedRouter := middleware.CorrelationMiddleware(localizedRouter)
+	loggedRouter := middleware.MetricsMiddleware(correlatedRouter)
 
 	// Create HTTP server with timeouts
 	srv := &http.Server{
@@ -100,10 +378,16 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	// Apply the retention rules in the background; replicas take turns through a database lock
+	retentionCtx, stopRetention := context.WithCancel(ctx)
+	go retentionService.Start(retentionCtx, cfg.Retention.Interval)
+
 	// Start server in a goroutine
 	go func() {
 		log.Printf("Starting server on :%s", cfg.Port)
-		log.Printf("CORS allowed origins: %v", cfg.CORSAllowedOrigins)
+		for _, tenant := range cfg.Tenants {
+			log.Printf("Tenant %s: hosts %v, CORS allowed origins %v", tenant.ID, tenant.Hosts, tenant.CORSAllowedOrigins)
+		}
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Could not start server: %s\n", err)
 		}
@@ -117,6 +401,8 @@ func main() {
 	sig := <-quit
 	log.Printf("Received signal %v, initiating graceful shutdown...", sig)
 
+	stopRetention()
+
 	// Create shutdown context with timeout
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -126,6 +412,11 @@ func main() {
 		log.Printf("Server forced to shutdown: %v", err)
 	}
 
+	// Let archives being generated finish before their database goes away
+	if err := subjectAccessService.Wait(shutdownCtx); err != nil {
+		log.Printf("Subject access archives still being generated: %v", err)
+	}
+
 	// Close database connection
 	if err := db.Close(); err != nil {
 		log.Printf("Error closing database: %v", err)
@@ -31,9 +31,9 @@ func main() {
 		log.Println("relay: database connection initialized - circuit breaker will validate on first operation")
 	}
 
-	message_broker, err := messaging.NewRabbitMQBroker(cfg.RabbitMQURL, cfg.BabyQueueName)
+	message_broker, err := messaging.NewRabbitMQBroker(cfg.RabbitMQURL, cfg.Topology, cfg.CloudEvents)
 	if err != nil {
-		log.Printf("relay: WARNING - failed to create baby publisher: %v", err)
+		log.Printf("relay: WARNING - failed to create event publisher: %v", err)
 	} else {
 		defer message_broker.Close()
 		log.Println("relay: connected to RabbitMQ")
@@ -0,0 +1,15 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/middleware"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+)
+
+// actorFromRequest returns the authenticated caller placed in the context by the auth middleware
+func actorFromRequest(r *http.Request) domain.Actor {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(string)
+	role, _ := r.Context().Value(middleware.RoleKey).(string)
+	return domain.Actor{ID: userID, Role: domain.Role(role)}
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+)
+
+type AdminApprovalHandler struct {
+	approvals *services.AdminApprovalService
+}
+
+func NewAdminApprovalHandler(approvals *services.AdminApprovalService) *AdminApprovalHandler {
+	return &AdminApprovalHandler{approvals: approvals}
+}
+
+// List serves GET /admin-approvals, optionally filtered by ?status=
+func (h *AdminApprovalHandler) List(w http.ResponseWriter, r *http.Request) {
+	approvals, err := h.approvals.List(r.Context(), r.URL.Query().Get("status"))
+	if err != nil {
+		log.Printf("Listing admin approvals failed: %v", err)
+		writeError(w, err, "listing admin approvals failed")
+		return
+	}
+	writeJSON(w, http.StatusOK, approvals)
+}
+
+// Approve serves POST /admin-approvals/{approvalID}/approve and applies the requested change
+func (h *AdminApprovalHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	approvalID := r.PathValue("approvalID")
+
+	approval, err := h.approvals.Approve(r.Context(), actorFromRequest(r), approvalID)
+	if err != nil {
+		log.Printf("Approving admin approval %s failed: %v", approvalID, err)
+		writeAdminApprovalError(w, err, "approving failed")
+		return
+	}
+	writeJSON(w, http.StatusOK, approval)
+}
+
+// Reject serves POST /admin-approvals/{approvalID}/reject
+func (h *AdminApprovalHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	approvalID := r.PathValue("approvalID")
+
+	approval, err := h.approvals.Reject(r.Context(), actorFromRequest(r), approvalID)
+	if err != nil {
+		log.Printf("Rejecting admin approval %s failed: %v", approvalID, err)
+		writeAdminApprovalError(w, err, "rejecting failed")
+		return
+	}
+	writeJSON(w, http.StatusOK, approval)
+}
+
+// writeAdminApprovalError maps the errors of requesting and deciding admin changes
+func writeAdminApprovalError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrSelfApproval):
+		problem.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, domain.ErrAdminApprovalExpired):
+		problem.Error(w, err.Error(), http.StatusGone)
+	case errors.Is(err, domain.ErrAdminApprovalClosed),
+		errors.Is(err, domain.ErrAdminApprovalPending),
+		errors.Is(err, services.ErrNotAnAdmin),
+		errors.Is(err, services.ErrSelfDeactivation):
+		problem.Error(w, err.Error(), http.StatusConflict)
+	default:
+		writeError(w, err, fallback)
+	}
+}
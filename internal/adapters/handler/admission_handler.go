@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+)
+
+type AdmissionHandler struct {
+	admissions *services.AdmissionService
+	wardPolicy ports.WardPolicy
+}
+
+func NewAdmissionHandler(admissions *services.AdmissionService, wardPolicy ports.WardPolicy) *AdmissionHandler {
+	return &AdmissionHandler{admissions: admissions, wardPolicy: wardPolicy}
+}
+
+type AddCoParentRequest struct {
+	Email     string `json:"email"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	// Locale is the language the co-parent's invitation is written in
+	Locale string `json:"locale,omitempty"`
+}
+
+type AddCoParentResponse struct {
+	Message     string `json:"message"`
+	UserID      string `json:"user_id"`
+	AdmissionID string `json:"admission_id"`
+}
+
+// Get serves GET /admissions/{admissionID}
+func (h *AdmissionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	admission, err := h.admissions.Get(r.Context(), r.PathValue("admissionID"))
+	if err != nil {
+		log.Printf("Getting admission failed: %v", err)
+		writeError(w, err, "getting admission failed")
+		return
+	}
+	writeJSON(w, http.StatusOK, admission)
+}
+
+// ForParent serves GET /parents/{parentID}/admission
+func (h *AdmissionHandler) ForParent(w http.ResponseWriter, r *http.Request) {
+	admission, err := h.admissions.ForParent(r.Context(), r.PathValue("parentID"))
+	if err != nil {
+		log.Printf("Getting admission of parent failed: %v", err)
+		writeError(w, err, "getting admission failed")
+		return
+	}
+	writeJSON(w, http.StatusOK, admission)
+}
+
+// AddCoParent serves POST /admissions/{admissionID}/parents
+func (h *AdmissionHandler) AddCoParent(w http.ResponseWriter, r *http.Request) {
+	var req AddCoParentRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		if field, ok := unknownField(err); ok {
+			problem.Validation(w, domain.InvalidField(field, "unknown field "+field))
+			return
+		}
+		problem.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	admission, ok := h.authorizedAdmission(w, r)
+	if !ok {
+		return
+	}
+
+	parent, message, err := h.admissions.AddCoParent(r.Context(), admission.ID, req.Email, req.FirstName, req.LastName, req.Locale)
+	if err != nil {
+		log.Printf("Adding co-parent to admission %s failed: %v", admission.ID, err)
+		writeAdmissionError(w, err, "adding co-parent failed")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, AddCoParentResponse{
+		Message:     message,
+		UserID:      parent.ID,
+		AdmissionID: parent.AdmissionID,
+	})
+}
+
+// Discharge serves POST /admissions/{admissionID}/discharge with an optional {"reason": "..."}
+func (h *AdmissionHandler) Discharge(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Reason string `json:"reason"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			problem.Error(w, "Invalid request payload", http.StatusBadRequest)
+			return
+		}
+	}
+
+	admission, ok := h.authorizedAdmission(w, r)
+	if !ok {
+		return
+	}
+
+	changes, err := h.admissions.Discharge(r.Context(), actorFromRequest(r), admission.ID, payload.Reason)
+	if err != nil {
+		log.Printf("Discharging admission %s failed: %v", admission.ID, err)
+		writeAdmissionError(w, err, "discharging admission failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, changes)
+}
+
+// authorizedAdmission loads the admission in the path and checks that
+// ward-bound staff may act on parents in its room
+func (h *AdmissionHandler) authorizedAdmission(w http.ResponseWriter, r *http.Request) (*domain.Admission, bool) {
+	admission, err := h.admissions.Get(r.Context(), r.PathValue("admissionID"))
+	if err != nil {
+		log.Printf("Getting admission failed: %v", err)
+		writeError(w, err, "getting admission failed")
+		return nil, false
+	}
+
+	if err := h.wardPolicy.AuthorizeRegistration(r.Context(), actorFromRequest(r), domain.RoleParent, admission.RoomNumber); err != nil {
+		writeWardError(w, err, "Admission "+admission.ID)
+		return nil, false
+	}
+	return admission, true
+}
+
+func writeAdmissionError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrAdmissionClosed),
+		errors.Is(err, services.ErrInvalidTransition),
+		errors.Is(err, domain.ErrVersionConflict):
+		problem.Error(w, err.Error(), http.StatusConflict)
+	default:
+		writeError(w, err, fallback)
+	}
+}
@@ -6,9 +6,13 @@ import (
 	"net/http"
 
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/middleware"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
 )
 
+// invitationCookieName carries an invitation token through the Google sign-in
+const invitationCookieName = "invitation"
+
 type AuthHandler struct {
 	authService *services.AuthService
 }
@@ -19,7 +23,7 @@ func NewAuthHandler(auth *services.AuthService) *AuthHandler {
 
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 	log.Printf("Login endpoint hit: %s %s", r.Method, r.URL.Path)
@@ -27,7 +31,14 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	state, err := h.authService.GenerateState()
 	if err != nil {
 		log.Printf("Failed to generate state: %v", err)
-		http.Error(w, "failed to generate state", http.StatusInternalServerError)
+		problem.Error(w, "failed to generate state", http.StatusInternalServerError)
+		return
+	}
+
+	authURL, err := h.authService.GetAuthURL(r.Context(), state)
+	if err != nil {
+		log.Printf("Failed to build auth URL: %v", err)
+		problem.Error(w, "unknown tenant", http.StatusBadRequest)
 		return
 	}
 
@@ -43,7 +54,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(map[string]string{
-		"redirect_url": h.authService.GetAuthURL(state),
+		"redirect_url": authURL,
 	}); err != nil {
 		log.Printf("Failed to encode response: %v", err)
 	}
@@ -51,7 +62,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 func (h *AuthHandler) LoginCallback(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 	log.Printf("Method: %s", r.Method)
@@ -62,14 +73,14 @@ func (h *AuthHandler) LoginCallback(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		log.Printf("Missing state cookie: %v", err)
 		log.Printf("All cookies: %v", r.Cookies())
-		http.Error(w, "missing state cookie", http.StatusBadRequest)
+		problem.Error(w, "missing state cookie", http.StatusBadRequest)
 		return
 	}
 
 	stateParam := r.URL.Query().Get("state")
 	if stateParam != stateCookie.Value {
 		log.Printf("State mismatch")
-		http.Error(w, "invalid state", http.StatusForbidden)
+		problem.Error(w, "invalid state", http.StatusForbidden)
 		return
 	}
 
@@ -82,81 +93,111 @@ func (h *AuthHandler) LoginCallback(w http.ResponseWriter, r *http.Request) {
 
 	code := r.URL.Query().Get("code")
 	if code == "" {
-		http.Error(w, "missing code", http.StatusBadRequest)
+		problem.Error(w, "missing code", http.StatusBadRequest)
+		return
+	}
+
+	// A parent coming from an invitation link accepts it by signing in
+	if invitationCookie, err := r.Cookie(invitationCookieName); err == nil {
+		http.SetCookie(w, &http.Cookie{
+			Name:   invitationCookieName,
+			Value:  "",
+			Path:   "/",
+			MaxAge: -1,
+		})
+
+		token, err := h.authService.AcceptInvitation(r.Context(), code, invitationCookie.Value)
+		if err != nil {
+			log.Printf("Invitation acceptance failed: %v", err)
+			writeInvitationError(w, err, "accepting invitation failed")
+			return
+		}
+		writeLoginResponse(w, token)
 		return
 	}
 
 	token, err := h.authService.Authenticate(r.Context(), code)
 	if err != nil {
 		log.Printf("Auth failed: %v", err)
-		http.Error(w, "authentication failed: "+err.Error(), http.StatusUnauthorized)
+		problem.Error(w, "authentication failed: "+err.Error(), http.StatusUnauthorized)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(map[string]string{
-		"message": "Logged in successfully!",
-		"token":   token,
-	}); err != nil {
-		log.Printf("Failed to encode response: %v", err)
-	}
+	writeLoginResponse(w, token)
 }
 
-func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// AcceptInvitation opens an emailed invitation link: it checks the link and
+// sends the parent to Google, remembering the invitation for the callback.
+func (h *AuthHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	invitationToken := r.URL.Query().Get("token")
+	if invitationToken == "" {
+		problem.Error(w, "missing token", http.StatusBadRequest)
 		return
 	}
-	tokenString, ok := r.Context().Value(middleware.TokenKey).(string)
-	if !ok || tokenString == "" {
-		http.Error(w, "missing token in context", http.StatusUnauthorized)
+
+	state, err := h.authService.GenerateState()
+	if err != nil {
+		log.Printf("Failed to generate state: %v", err)
+		problem.Error(w, "failed to generate state", http.StatusInternalServerError)
 		return
 	}
 
-	err := h.authService.Logout(r.Context(), tokenString)
+	authURL, err := h.authService.StartInvitation(r.Context(), invitationToken, state)
 	if err != nil {
-		log.Printf("Logout failed: %v", err)
-		http.Error(w, "logout failed", http.StatusInternalServerError)
+		log.Printf("Invitation link rejected: %v", err)
+		writeInvitationError(w, err, "opening invitation failed")
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(map[string]string{"message": "logged out successfully"}); err != nil {
+	http.SetCookie(w, &http.Cookie{
+		Name:     "auth_state",
+		Value:    state,
+		Path:     "/",
+		MaxAge:   600,
+		HttpOnly: true,
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     invitationCookieName,
+		Value:    invitationToken,
+		Path:     "/",
+		MaxAge:   600,
+		HttpOnly: true,
+	})
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+func writeLoginResponse(w http.ResponseWriter, token string) {
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]string{
+		"message": "Logged in successfully!",
+		"token":   token,
+	}); err != nil {
 		log.Printf("Failed to encode response: %v", err)
 	}
 }
 
-func (h *AuthHandler) DischargeParent(w http.ResponseWriter, r *http.Request) {
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-	var payload struct {
-		ParentId string `json:"parent_id"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
-
-	if payload.ParentId == "" {
-		http.Error(w, "missing parent_id", http.StatusBadRequest)
+	tokenString, ok := r.Context().Value(middleware.TokenKey).(string)
+	if !ok || tokenString == "" {
+		problem.Error(w, "missing token in context", http.StatusUnauthorized)
 		return
 	}
 
-	err := h.authService.DischargeParent(r.Context(), payload.ParentId)
+	err := h.authService.Logout(r.Context(), tokenString)
 	if err != nil {
-		log.Printf("Discharge parent failed: %v %v", payload.ParentId, err)
-		http.Error(w, "discharge parent failed", http.StatusInternalServerError)
+		log.Printf("Logout failed: %v", err)
+		problem.Error(w, "logout failed", http.StatusInternalServerError)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-
-	if err := json.NewEncoder(w).Encode(
-		map[string]string{"message": "parent discharged successfully"}); err != nil {
+	if err := json.NewEncoder(w).Encode(map[string]string{"message": "logged out successfully"}); err != nil {
 		log.Printf("Failed to encode response: %v", err)
 	}
 }
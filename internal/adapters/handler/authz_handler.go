@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+)
+
+type AuthzHandler struct {
+	authzService *services.AuthzService
+}
+
+func NewAuthzHandler(authz *services.AuthzService) *AuthzHandler {
+	return &AuthzHandler{authzService: authz}
+}
+
+type BatchCheckRequest struct {
+	Checks []domain.AuthzRequest `json:"checks"`
+}
+
+type BatchCheckResponse struct {
+	Decisions []domain.AuthzDecision `json:"decisions"`
+}
+
+// Check evaluates a single decision. The subject defaults to the caller.
+func (h *AuthzHandler) Check(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req domain.AuthzRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if req.Subject == "" {
+		req.Subject = actorFromRequest(r).ID
+	}
+
+	decision, err := h.authzService.Check(r.Context(), req)
+	if err != nil {
+		writeAuthzError(w, "authorization check failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, decision)
+}
+
+// CheckBatch evaluates up to MaxAuthzBatchSize decisions in one round trip.
+// Decisions are returned in request order.
+func (h *AuthzHandler) CheckBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req BatchCheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	caller := actorFromRequest(r).ID
+	for i := range req.Checks {
+		if req.Checks[i].Subject == "" {
+			req.Checks[i].Subject = caller
+		}
+	}
+
+	decisions, err := h.authzService.CheckBatch(r.Context(), req.Checks)
+	if err != nil {
+		writeAuthzError(w, "authorization check failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, BatchCheckResponse{Decisions: decisions})
+}
+
+func (h *AuthzHandler) ListPolicies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	policies, err := h.authzService.ListPolicies(r.Context())
+	if err != nil {
+		log.Printf("Listing policies failed: %v", err)
+		problem.Error(w, "listing policies failed", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, policies)
+}
+
+func (h *AuthzHandler) CreatePolicy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req domain.Policy
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	policy, err := h.authzService.CreatePolicy(r.Context(), req)
+	if err != nil {
+		writeAuthzError(w, "creating policy failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, policy)
+}
+
+func (h *AuthzHandler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := h.authzService.DeletePolicy(r.Context(), r.PathValue("policyID")); err != nil {
+		writeAuthzError(w, "deleting policy failed", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeAuthzError(w http.ResponseWriter, message string, err error) {
+	log.Printf("%s: %v", message, err)
+	writeError(w, err, message)
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+)
+
+// maxBulkUploadBytes bounds the size of a bulk registration upload
+const maxBulkUploadBytes = 2 << 20
+
+// bulkColumns are the CSV header names every upload must have, in any order
+var bulkColumns = []string{"email", "first_name", "last_name", "room_number"}
+
+type BulkRegistrationHandler struct {
+	bulk *services.BulkRegistrationService
+}
+
+func NewBulkRegistrationHandler(bulk *services.BulkRegistrationService) *BulkRegistrationHandler {
+	return &BulkRegistrationHandler{bulk: bulk}
+}
+
+// RegisterParents serves POST /register/bulk[?dry_run=true]. The body is either
+// text/csv with a header row or application/x-ndjson with one parent object per line.
+func (h *BulkRegistrationHandler) RegisterParents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	dryRun := false
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			problem.Error(w, "dry_run must be true or false", http.StatusBadRequest)
+			return
+		}
+		dryRun = parsed
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	body := http.MaxBytesReader(w, r.Body, maxBulkUploadBytes)
+
+	var rows []domain.BulkParentRow
+	var err error
+	switch mediaType {
+	case "text/csv":
+		rows, err = parseBulkCSV(body)
+	case "application/x-ndjson", "application/jsonl":
+		rows, err = parseBulkJSONLines(body)
+	default:
+		problem.Error(w, "Content-Type must be text/csv or application/x-ndjson", http.StatusUnsupportedMediaType)
+		return
+	}
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			problem.Error(w, "upload too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		problem.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.bulk.RegisterParents(r.Context(), actorFromRequest(r), rows, dryRun)
+	if err != nil {
+		log.Printf("Bulk registration failed: %v", err)
+		switch {
+		case errors.Is(err, services.ErrEmptyUpload), errors.Is(err, services.ErrTooManyRows):
+			problem.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, domain.ErrEmailTaken):
+			problem.Error(w, "an email was registered while the upload was processed; retry the upload", http.StatusConflict)
+		default:
+			writeError(w, err, "Registration failed")
+		}
+		return
+	}
+
+	status := http.StatusOK
+	if !dryRun {
+		status = http.StatusCreated
+		if report.Created == 0 {
+			status = http.StatusUnprocessableEntity
+		}
+	}
+	writeJSON(w, status, report)
+}
+
+// parseBulkCSV reads a CSV upload; rows with the wrong number of fields are
+// reported per row, any other syntax error rejects the upload
+func parseBulkCSV(body io.Reader) ([]domain.BulkParentRow, error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	index := make(map[string]int)
+	for i, name := range header {
+		// Spreadsheet exports often start with a byte order mark
+		name = strings.TrimPrefix(name, "\ufeff")
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, column := range bulkColumns {
+		if _, ok := index[column]; !ok {
+			return nil, fmt.Errorf("CSV header is missing the %s column", column)
+		}
+	}
+
+	field := func(record []string, column string) string {
+		if i := index[column]; i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	var rows []domain.BulkParentRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+
+		line, _ := reader.FieldPos(0)
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		row := domain.BulkParentRow{
+			Line:       line,
+			Email:      field(record, "email"),
+			FirstName:  field(record, "first_name"),
+			LastName:   field(record, "last_name"),
+			RoomNumber: field(record, "room_number"),
+		}
+		if err != nil {
+			row.ParseError = fmt.Sprintf("expected %d fields, got %d", len(header), len(record))
+		}
+		rows = append(rows, row)
+	}
+}
+
+// parseBulkJSONLines reads one JSON object per line; blank lines are skipped
+// and malformed lines are reported per row
+func parseBulkJSONLines(body io.Reader) ([]domain.BulkParentRow, error) {
+	scanner := bufio.NewScanner(body)
+
+	var rows []domain.BulkParentRow
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		var entry struct {
+			Email      string `json:"email"`
+			FirstName  string `json:"first_name"`
+			LastName   string `json:"last_name"`
+			RoomNumber string `json:"room_number"`
+		}
+		row := domain.BulkParentRow{Line: line}
+
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&entry); err != nil {
+			row.ParseError = "invalid JSON: " + err.Error()
+		} else {
+			row.Email, row.FirstName, row.LastName, row.RoomNumber = entry.Email, entry.FirstName, entry.LastName, entry.RoomNumber
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/middleware"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+)
+
+type EnrollmentHandler struct {
+	enrollmentService *services.EnrollmentService
+}
+
+func NewEnrollmentHandler(enrollment *services.EnrollmentService) *EnrollmentHandler {
+	return &EnrollmentHandler{enrollmentService: enrollment}
+}
+
+type EnrollmentCodeRequest struct {
+	ParentID string `json:"parent_id"`
+}
+
+type EnrollmentCodeResponse struct {
+	Code       string `json:"code"`
+	ParentID   string `json:"parent_id"`
+	RoomNumber string `json:"room_number"`
+	ExpiresAt  string `json:"expires_at"`
+}
+
+type RedeemRequest struct {
+	Code       string `json:"code"`
+	DeviceName string `json:"device_name"`
+}
+
+type RedeemResponse struct {
+	Token            string `json:"token"`
+	DeviceID         string `json:"device_id"`
+	DeviceCredential string `json:"device_credential"`
+	CredentialExpiry string `json:"device_credential_expires_at"`
+}
+
+type DeviceTokenRequest struct {
+	DeviceCredential string `json:"device_credential"`
+}
+
+// IssueCode lets an admin mint a one-time enrollment code for a registered parent.
+// The code is meant to be rendered as a QR code and scanned by the parent's phone.
+func (h *EnrollmentHandler) IssueCode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	adminID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req EnrollmentCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if req.ParentID == "" {
+		problem.Error(w, "missing parent_id", http.StatusBadRequest)
+		return
+	}
+
+	code, err := h.enrollmentService.IssueCode(r.Context(), adminID, req.ParentID)
+	if err != nil {
+		log.Printf("Issuing enrollment code for %s failed: %v", req.ParentID, err)
+		switch {
+		case errors.Is(err, services.ErrEnrollmentNotAllowed):
+			problem.Error(w, err.Error(), http.StatusConflict)
+		default:
+			writeError(w, err, "issuing enrollment code failed")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(EnrollmentCodeResponse{
+		Code:       code.Code,
+		ParentID:   code.ParentID,
+		RoomNumber: code.RoomNumber,
+		ExpiresAt:  code.ExpiresAt.UTC().Format(time.RFC3339),
+	}); err != nil {
+		log.Printf("Failed to encode response: %v", err)
+	}
+}
+
+// Redeem swaps an enrollment code for a parent JWT and a long-lived device credential.
+func (h *EnrollmentHandler) Redeem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req RedeemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if req.Code == "" {
+		problem.Error(w, "missing code", http.StatusBadRequest)
+		return
+	}
+
+	enrollment, err := h.enrollmentService.Redeem(r.Context(), req.Code, req.DeviceName)
+	if err != nil {
+		log.Printf("Redeeming enrollment code failed: %v", err)
+		switch {
+		case errors.Is(err, services.ErrEnrollmentCodeInvalid), errors.Is(err, services.ErrEnrollmentNotAllowed):
+			problem.Error(w, "invalid enrollment code", http.StatusUnauthorized)
+		default:
+			problem.Error(w, "enrollment failed", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(RedeemResponse{
+		Token:            enrollment.Token.Token,
+		DeviceID:         enrollment.Device.DeviceID,
+		DeviceCredential: enrollment.DeviceCredential,
+		CredentialExpiry: time.Unix(enrollment.Device.Exp, 0).UTC().Format(time.RFC3339),
+	}); err != nil {
+		log.Printf("Failed to encode response: %v", err)
+	}
+}
+
+// DeviceToken exchanges a device credential for a fresh parent JWT.
+func (h *EnrollmentHandler) DeviceToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req DeviceTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if req.DeviceCredential == "" {
+		problem.Error(w, "missing device_credential", http.StatusBadRequest)
+		return
+	}
+
+	issued, err := h.enrollmentService.ExchangeDeviceCredential(r.Context(), req.DeviceCredential)
+	if err != nil {
+		log.Printf("Device token exchange failed: %v", err)
+		if errors.Is(err, services.ErrDeviceCredentialInvalid) {
+			problem.Error(w, "invalid device credential", http.StatusUnauthorized)
+			return
+		}
+		problem.Error(w, "device token exchange failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]string{
+		"token": issued.Token,
+	}); err != nil {
+		log.Printf("Failed to encode response: %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+)
+
+type ErasureHandler struct {
+	erasure *services.ErasureService
+}
+
+func NewErasureHandler(erasure *services.ErasureService) *ErasureHandler {
+	return &ErasureHandler{erasure: erasure}
+}
+
+// EraseUser serves POST /users/{userID}/erase and returns the erasure tombstone
+func (h *ErasureHandler) EraseUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tombstone, err := h.erasure.Erase(r.Context(), actorFromRequest(r), r.PathValue("userID"))
+	if err != nil {
+		log.Printf("Erasing user failed: %v", err)
+		if errors.Is(err, services.ErrSelfErasure) || errors.Is(err, services.ErrActiveAdminErasure) {
+			problem.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		writeError(w, err, "erasure failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tombstone)
+}
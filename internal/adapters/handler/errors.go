@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+	"github.com/sony/gobreaker"
+)
+
+// writeError maps the typed domain errors to a problem response. A database
+// circuit breaker that is open is a 503. Anything else is a 500 with the
+// fallback detail, so internal errors never leak.
+// Handlers map their own specific errors first and fall back to this.
+func writeError(w http.ResponseWriter, err error, fallback string) {
+	var invalid *domain.ValidationError
+	switch {
+	case errors.As(err, &invalid):
+		problem.Validation(w, invalid)
+	case errors.Is(err, domain.ErrNotFound):
+		problem.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, domain.ErrEmailTaken), errors.Is(err, domain.ErrDischarged), errors.Is(err, domain.ErrLastAdmin):
+		problem.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, domain.ErrVersionConflict):
+		problem.Error(w, err.Error(), http.StatusPreconditionFailed)
+	case errors.Is(err, domain.ErrUnavailable), errors.Is(err, gobreaker.ErrOpenState), errors.Is(err, gobreaker.ErrTooManyRequests):
+		problem.Error(w, "service temporarily unavailable", http.StatusServiceUnavailable)
+	default:
+		problem.Error(w, fallback, http.StatusInternalServerError)
+	}
+}
+
+// writeWardError answers a request the ward policy did not allow: 403 when the
+// parent or room is outside the actor's ward, and the writeError mapping when
+// the policy could not be checked, so an outage is not reported as a denial
+func writeWardError(w http.ResponseWriter, err error, request string) {
+	if errors.Is(err, services.ErrOutsideWard) {
+		log.Printf("%s outside ward denied: %v", request, err)
+		problem.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	log.Printf("Ward check of %s failed: %v", request, err)
+	writeError(w, err, "ward check failed")
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+)
+
+// exportFlushRows is how many rows are buffered before they are sent to the client
+const exportFlushRows = 100
+
+type ExportHandler struct {
+	exports *services.UserExportService
+}
+
+func NewExportHandler(exports *services.UserExportService) *ExportHandler {
+	return &ExportHandler{exports: exports}
+}
+
+// ExportUsers serves GET /exports/users?format=csv|ndjson&columns=email,room_number,...
+// with the filters of GET /users. Rows are streamed as they are read from the database.
+func (h *ExportHandler) ExportUsers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := r.URL.Query()
+	filter, err := userFilterFromQuery(params)
+	if err != nil {
+		writeError(w, err, "export failed")
+		return
+	}
+
+	export := services.UserExport{Filter: filter, Format: domain.ExportFormat(params.Get("format"))}
+	if columns := params.Get("columns"); columns != "" {
+		for _, column := range strings.Split(columns, ",") {
+			export.Columns = append(export.Columns, strings.TrimSpace(column))
+		}
+	}
+
+	export, err = h.exports.Prepare(export)
+	if err != nil {
+		writeError(w, err, "export failed")
+		return
+	}
+
+	buffered := bufio.NewWriter(w)
+	var rows exportWriter
+	if export.Format == domain.ExportNDJSON {
+		rows = &ndjsonExportWriter{out: buffered, columns: export.Columns}
+	} else {
+		rows = &csvExportWriter{out: csv.NewWriter(buffered), columns: export.Columns}
+	}
+
+	// The status line is only sent with the first row, so that failures before
+	// it (e.g. the audit log being unavailable) still get a proper error response
+	started := false
+	count := 0
+	start := func() error {
+		if started {
+			return nil
+		}
+		started = true
+		w.Header().Set("Content-Type", rows.contentType())
+		w.Header().Set("Content-Disposition", `attachment; filename="users-`+time.Now().UTC().Format("20060102")+`.`+string(export.Format)+`"`)
+		w.WriteHeader(http.StatusOK)
+		return rows.header()
+	}
+	flush := func() error {
+		if err := rows.flush(); err != nil {
+			return err
+		}
+		if err := buffered.Flush(); err != nil {
+			return err
+		}
+		if err := http.NewResponseController(w).Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		return nil
+	}
+
+	err = h.exports.Export(r.Context(), actorFromRequest(r), export, func(record domain.UserRecord) error {
+		if err := start(); err != nil {
+			return err
+		}
+		if err := rows.row(record); err != nil {
+			return err
+		}
+		count++
+		if count%exportFlushRows == 0 {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		if err = start(); err == nil {
+			err = flush()
+		}
+	}
+
+	if err != nil && !started {
+		log.Printf("User export failed: %v", err)
+		writeError(w, err, "export failed")
+		return
+	}
+	if err != nil {
+		// The status is already sent; abort the response so the client sees a
+		// broken transfer instead of a silently truncated export
+		log.Printf("User export aborted after %d rows: %v", count, err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// exportWriter renders exported users in one output format
+type exportWriter interface {
+	contentType() string
+	header() error
+	row(record domain.UserRecord) error
+	flush() error
+}
+
+type csvExportWriter struct {
+	out     *csv.Writer
+	columns []string
+}
+
+func (c *csvExportWriter) contentType() string { return "text/csv; charset=utf-8" }
+
+func (c *csvExportWriter) header() error { return c.out.Write(c.columns) }
+
+func (c *csvExportWriter) row(record domain.UserRecord) error {
+	values := make([]string, len(c.columns))
+	for i, column := range c.columns {
+		values[i] = csvSafe(record.ExportValue(column))
+	}
+	return c.out.Write(values)
+}
+
+func (c *csvExportWriter) flush() error {
+	c.out.Flush()
+	return c.out.Error()
+}
+
+// csvSafe keeps spreadsheets from evaluating user-entered values such as
+// names starting with "=" as formulas
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+type ndjsonExportWriter struct {
+	out     io.Writer
+	columns []string
+}
+
+func (n *ndjsonExportWriter) contentType() string { return "application/x-ndjson" }
+
+func (n *ndjsonExportWriter) header() error { return nil }
+
+// row writes one object per line with the keys in column order
+func (n *ndjsonExportWriter) row(record domain.UserRecord) error {
+	var line strings.Builder
+	line.WriteByte('{')
+	for i, column := range n.columns {
+		if i > 0 {
+			line.WriteByte(',')
+		}
+		key, _ := json.Marshal(column)
+		value, _ := json.Marshal(record.ExportValue(column))
+		line.Write(key)
+		line.WriteByte(':')
+		line.Write(value)
+	}
+	line.WriteString("}\n")
+	_, err := io.WriteString(n.out, line.String())
+	return err
+}
+
+func (n *ndjsonExportWriter) flush() error { return nil }
@@ -9,6 +9,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
 	redis "github.com/redis/go-redis/v9"
 )
 
@@ -49,7 +50,7 @@ type Check struct {
 // Health is a simple liveness check - just confirms the Go process is running
 func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
@@ -71,7 +72,7 @@ func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
 // Ready checks if the service is ready to accept traffic (readiness probe)
 func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
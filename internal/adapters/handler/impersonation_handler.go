@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/middleware"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+)
+
+type ImpersonationHandler struct {
+	impersonationService *services.ImpersonationService
+}
+
+func NewImpersonationHandler(impersonation *services.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{impersonationService: impersonation}
+}
+
+type ImpersonationResponse struct {
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expires_at"`
+	Subject   string `json:"subject"`
+	Actor     string `json:"actor"`
+}
+
+func (h *ImpersonationHandler) Start(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	adminID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok || adminID == "" {
+		problem.Error(w, "missing user in context", http.StatusUnauthorized)
+		return
+	}
+
+	targetID := r.PathValue("userID")
+	if targetID == "" {
+		problem.Error(w, "missing userID", http.StatusBadRequest)
+		return
+	}
+
+	issued, err := h.impersonationService.Start(r.Context(), adminID, targetID)
+	if err != nil {
+		log.Printf("Impersonation of %s by %s failed: %v", targetID, adminID, err)
+		switch {
+		case errors.Is(err, services.ErrImpersonationNotAllowed):
+			problem.Error(w, "impersonation not allowed", http.StatusForbidden)
+		default:
+			writeError(w, err, "impersonation failed")
+		}
+		return
+	}
+
+	log.Printf("Impersonation started - Admin: %s, Target: %s", adminID, targetID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(ImpersonationResponse{
+		Token:     issued.Token,
+		ExpiresAt: issued.ExpiresAt.UTC().Format(time.RFC3339),
+		Subject:   targetID,
+		Actor:     adminID,
+	}); err != nil {
+		log.Printf("Failed to encode response: %v", err)
+	}
+}
+
+func (h *ImpersonationHandler) End(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tokenString, ok := r.Context().Value(middleware.TokenKey).(string)
+	if !ok || tokenString == "" {
+		problem.Error(w, "missing token in context", http.StatusUnauthorized)
+		return
+	}
+
+	err := h.impersonationService.End(r.Context(), tokenString)
+	if errors.Is(err, services.ErrNotImpersonating) {
+		problem.Error(w, "not an impersonation session", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("Ending impersonation failed: %v", err)
+		problem.Error(w, "ending impersonation failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(map[string]string{"message": "impersonation ended"}); err != nil {
+		log.Printf("Failed to encode response: %v", err)
+	}
+}
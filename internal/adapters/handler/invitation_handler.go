@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+)
+
+type InvitationHandler struct {
+	invitationService *services.InvitationService
+}
+
+func NewInvitationHandler(invitations *services.InvitationService) *InvitationHandler {
+	return &InvitationHandler{invitationService: invitations}
+}
+
+// List returns the clinic's parent invitations, optionally filtered by ?status=.
+func (h *InvitationHandler) List(w http.ResponseWriter, r *http.Request) {
+	status := domain.InvitationStatus(r.URL.Query().Get("status"))
+
+	invitations, err := h.invitationService.List(r.Context(), status)
+	if err != nil {
+		log.Printf("Listing invitations failed: %v", err)
+		writeError(w, err, "listing invitations failed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(invitations); err != nil {
+		log.Printf("Failed to encode response: %v", err)
+	}
+}
+
+// Resend mails a new accept link; earlier links stop working.
+func (h *InvitationHandler) Resend(w http.ResponseWriter, r *http.Request) {
+	invitationID := r.PathValue("invitationID")
+
+	invitation, err := h.invitationService.Resend(r.Context(), invitationID)
+	if err != nil {
+		log.Printf("Resending invitation %s failed: %v", invitationID, err)
+		writeInvitationError(w, err, "resending invitation failed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(invitation); err != nil {
+		log.Printf("Failed to encode response: %v", err)
+	}
+}
+
+// Cancel withdraws a pending invitation and archives the parent if they were never admitted.
+func (h *InvitationHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	invitationID := r.PathValue("invitationID")
+
+	invitation, err := h.invitationService.Cancel(r.Context(), actorFromRequest(r), invitationID)
+	if err != nil {
+		log.Printf("Cancelling invitation %s failed: %v", invitationID, err)
+		writeInvitationError(w, err, "cancelling invitation failed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(invitation); err != nil {
+		log.Printf("Failed to encode response: %v", err)
+	}
+}
+
+// writeInvitationError maps the errors of opening, accepting and managing invitations
+func writeInvitationError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrInvitationInvalid):
+		problem.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, domain.ErrInvitationEmailMismatch):
+		problem.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, domain.ErrInvitationExpired):
+		problem.Error(w, err.Error(), http.StatusGone)
+	case errors.Is(err, domain.ErrInvitationClosed):
+		problem.Error(w, err.Error(), http.StatusConflict)
+	default:
+		writeError(w, err, fallback)
+	}
+}
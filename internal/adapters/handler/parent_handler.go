@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+)
+
+type ParentHandler struct {
+	transfers   *services.TransferService
+	lifecycle   *services.ParentLifecycleService
+	invitations *services.InvitationService
+	wardPolicy  ports.WardPolicy
+}
+
+func NewParentHandler(
+	transfers *services.TransferService,
+	lifecycle *services.ParentLifecycleService,
+	invitations *services.InvitationService,
+	wardPolicy ports.WardPolicy,
+) *ParentHandler {
+	return &ParentHandler{transfers: transfers, lifecycle: lifecycle, invitations: invitations, wardPolicy: wardPolicy}
+}
+
+// DischargeParent serves POST /discharge with {"parent_id": "...", "reason": "..."}
+func (h *ParentHandler) DischargeParent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var payload struct {
+		ParentId string `json:"parent_id"`
+		Reason   string `json:"reason"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		problem.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if payload.ParentId == "" {
+		problem.Error(w, "missing parent_id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.wardPolicy.AuthorizeParent(r.Context(), actorFromRequest(r), payload.ParentId); err != nil {
+		writeWardError(w, err, "Discharge of "+payload.ParentId)
+		return
+	}
+
+	if _, err := h.lifecycle.Discharge(r.Context(), actorFromRequest(r), payload.ParentId, payload.Reason); err != nil {
+		log.Printf("Discharge parent failed: %v %v", payload.ParentId, err)
+		writeLifecycleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "parent discharged successfully"})
+}
+
+// Activate serves POST /parents/{parentID}/activate for parents without a pending invitation
+func (h *ParentHandler) Activate(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.invitations.Activate)
+}
+
+// Readmit serves POST /parents/{parentID}/readmit
+func (h *ParentHandler) Readmit(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.lifecycle.Readmit)
+}
+
+// Archive serves POST /parents/{parentID}/archive
+func (h *ParentHandler) Archive(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.lifecycle.Archive)
+}
+
+// changeStatus runs a lifecycle transition with the optional {"reason": "..."} body
+func (h *ParentHandler) changeStatus(
+	w http.ResponseWriter,
+	r *http.Request,
+	transition func(ctx context.Context, actor domain.Actor, parentID, reason string) (*domain.ParentStatusChange, error),
+) {
+	if r.Method != http.MethodPost {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var payload struct {
+		Reason string `json:"reason"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			problem.Error(w, "Invalid request payload", http.StatusBadRequest)
+			return
+		}
+	}
+
+	parentID := r.PathValue("parentID")
+	actor := actorFromRequest(r)
+
+	if err := h.wardPolicy.AuthorizeParent(r.Context(), actor, parentID); err != nil {
+		writeWardError(w, err, "Status change of "+parentID)
+		return
+	}
+
+	change, err := transition(r.Context(), actor, parentID, payload.Reason)
+	if err != nil {
+		log.Printf("Changing parent status failed: %v %v", parentID, err)
+		writeLifecycleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, change)
+}
+
+// StatusHistory serves GET /parents/{parentID}/status-history
+func (h *ParentHandler) StatusHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	history, err := h.lifecycle.History(r.Context(), r.PathValue("parentID"))
+	if err != nil {
+		log.Printf("Listing parent status history failed: %v", err)
+		writeLifecycleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, history)
+}
+
+func writeLifecycleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidTransition), errors.Is(err, domain.ErrVersionConflict),
+		errors.Is(err, domain.ErrInvitationOutstanding):
+		problem.Error(w, err.Error(), http.StatusConflict)
+	default:
+		writeError(w, err, "parent status change failed")
+	}
+}
+
+// Transfer serves POST /parents/{parentID}/transfer with {"room_number": "...", "reason": "..."}
+func (h *ParentHandler) Transfer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var payload struct {
+		RoomNumber string `json:"room_number"`
+		Reason     string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		problem.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	parentID := r.PathValue("parentID")
+	actor := actorFromRequest(r)
+
+	// Ward-bound staff may only move their own parents, and only within their ward
+	if err := h.wardPolicy.AuthorizeParent(r.Context(), actor, parentID); err != nil {
+		writeWardError(w, err, "Transfer of "+parentID)
+		return
+	}
+	if err := h.wardPolicy.AuthorizeRegistration(r.Context(), actor, domain.RoleParent, payload.RoomNumber); err != nil {
+		writeWardError(w, err, "Transfer to room "+payload.RoomNumber)
+		return
+	}
+
+	transfer, err := h.transfers.Transfer(r.Context(), actor, parentID, payload.RoomNumber, payload.Reason)
+	if err != nil {
+		log.Printf("Transferring parent failed: %v %v", parentID, err)
+		switch {
+		case errors.Is(err, services.ErrParentNotActive),
+			errors.Is(err, services.ErrSameRoom),
+			errors.Is(err, domain.ErrVersionConflict):
+			problem.Error(w, err.Error(), http.StatusConflict)
+		default:
+			writeError(w, err, "transfer failed")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, transfer)
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+)
+
+// lockedProfileFields are the fields of a profile only the clinic may change
+var lockedProfileFields = map[string]bool{
+	"id":          true,
+	"email":       true,
+	"role":        true,
+	"first_name":  true,
+	"last_name":   true,
+	"created_at":  true,
+	"version":     true,
+	"parent":      true,
+	"room_number": true,
+	"status":      true,
+}
+
+type ProfileHandler struct {
+	profiles *services.ProfileService
+}
+
+func NewProfileHandler(profiles *services.ProfileService) *ProfileHandler {
+	return &ProfileHandler{profiles: profiles}
+}
+
+// GetProfile serves GET /me/profile: the caller's own user record
+func (h *ProfileHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	record, err := h.profiles.Get(r.Context(), actorFromRequest(r).ID)
+	if err != nil {
+		log.Printf("Getting profile failed: %v", err)
+		writeError(w, err, "getting profile failed")
+		return
+	}
+
+	w.Header().Set("ETag", userETag(record.Version))
+	writeJSON(w, http.StatusOK, record)
+}
+
+// UpdateProfile serves PATCH /me/profile. Like PATCH /users/{userID} it needs
+// the ETag in If-Match; only display_name, locale and contact_email may be sent.
+func (h *ProfileHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		problem.Error(w, "If-Match header is required", http.StatusPreconditionRequired)
+		return
+	}
+	version, err := parseUserETag(ifMatch)
+	if err != nil {
+		problem.Error(w, "If-Match must be the ETag of the user", http.StatusBadRequest)
+		return
+	}
+
+	var update domain.ProfileUpdate
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&update); err != nil {
+		if field, ok := unknownField(err); ok {
+			if lockedProfileFields[field] {
+				problem.Validation(w, services.LockedProfileField(field))
+			} else {
+				problem.Validation(w, domain.InvalidField(field, "unknown field "+field))
+			}
+			return
+		}
+		problem.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	record, err := h.profiles.Update(r.Context(), actorFromRequest(r).ID, version, update)
+	if err != nil {
+		log.Printf("Updating profile failed: %v", err)
+		writeUserAdminError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", userETag(record.Version))
+	writeJSON(w, http.StatusOK, record)
+}
@@ -4,16 +4,22 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strings"
 
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
 )
 
 type RegistrationHandler struct {
 	registrationService ports.RegistrationService
+	wardPolicy          ports.WardPolicy
+	adminApprovals      *services.AdminApprovalService
 }
 
-func NewRegistrationHandler(registration ports.RegistrationService) *RegistrationHandler {
-	return &RegistrationHandler{registrationService: registration}
+func NewRegistrationHandler(registration ports.RegistrationService, wards ports.WardPolicy, adminApprovals *services.AdminApprovalService) *RegistrationHandler {
+	return &RegistrationHandler{registrationService: registration, wardPolicy: wards, adminApprovals: adminApprovals}
 }
 
 type RegistrationRequest struct {
@@ -22,39 +28,68 @@ type RegistrationRequest struct {
 	FirstName  string `json:"first_name"`
 	LastName   string `json:"last_name"`
 	RoomNumber string `json:"room_number,omitempty"`
+	Ward       string `json:"ward,omitempty"`
+	// Locale is the language a parent's invitation is written in
+	Locale string `json:"locale,omitempty"`
 }
 
 type RegistrationResponse struct {
 	Message string `json:"message"`
+	// ApprovalID names the approval a new admin waits for
+	ApprovalID string `json:"approval_id,omitempty"`
 }
 
 func (h *RegistrationHandler) Register(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
 	var req RegistrationRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		if field, ok := unknownField(err); ok {
+			problem.Validation(w, domain.InvalidField(field, "unknown field "+field))
+			return
+		}
+		problem.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	// Any other role is checked against the clinic's roles by RegisterStaff
+	role := domain.Role(req.Role)
+	if role == domain.RoleVisitor {
+		problem.Validation(w, services.ErrInvalidStaffRole)
+		return
+	}
+
+	// A parent without a room is rejected by the service; there is no room to check yet
+	if role != domain.RoleParent || req.RoomNumber != "" {
+		if err := h.wardPolicy.AuthorizeRegistration(r.Context(), actorFromRequest(r), role, req.RoomNumber); err != nil {
+			writeWardError(w, err, "Registration")
+			return
+		}
+	}
+
+	if role == domain.RoleAdmin {
+		h.requestAdmin(w, r, req)
 		return
 	}
 
 	var message string
 	var err error
 
-	switch req.Role {
-	case "PARENT":
-		message, err = h.registrationService.RegisterParent(r.Context(), req.Email, req.FirstName, req.LastName, req.RoomNumber)
-	case "ADMIN":
-		message, err = h.registrationService.RegisterAdmin(r.Context(), req.Email, req.FirstName, req.LastName)
+	switch role {
+	case domain.RoleParent:
+		message, err = h.registrationService.RegisterParent(r.Context(), req.Email, req.FirstName, req.LastName, req.RoomNumber, req.Locale)
 	default:
-		http.Error(w, "Unsupported role", http.StatusBadRequest)
-		return
+		message, err = h.registrationService.RegisterStaff(r.Context(), req.Email, req.FirstName, req.LastName, role, req.Ward)
 	}
 
 	if err != nil {
-		http.Error(w, "Registration failed", http.StatusInternalServerError)
+		log.Printf("Registration failed: %v", err)
+		writeError(w, err, "Registration failed")
 		return
 	}
 
@@ -67,3 +102,29 @@ func (h *RegistrationHandler) Register(w http.ResponseWriter, r *http.Request) {
 		log.Printf("Failed to write response: %v", err)
 	}
 }
+
+// requestAdmin answers 202: a new admin is only created once another admin,
+// or the operator of a clinic with a single admin, approves
+func (h *RegistrationHandler) requestAdmin(w http.ResponseWriter, r *http.Request, req RegistrationRequest) {
+	approval, err := h.adminApprovals.RequestCreation(r.Context(), actorFromRequest(r), req.Email, req.FirstName, req.LastName)
+	if err != nil {
+		log.Printf("Admin registration failed: %v", err)
+		writeAdminApprovalError(w, err, "Registration failed")
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, RegistrationResponse{
+		Message:    "Admin registration awaits approval by another admin",
+		ApprovalID: approval.ID,
+	})
+}
+
+// unknownField returns the field named by a decoder's DisallowUnknownFields
+// error; encoding/json has no typed error for it
+func unknownField(err error) (string, bool) {
+	field, ok := strings.CutPrefix(err.Error(), "json: unknown field ")
+	if !ok {
+		return "", false
+	}
+	return strings.Trim(field, `"`), true
+}
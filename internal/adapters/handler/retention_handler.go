@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+)
+
+type RetentionHandler struct {
+	retention *services.RetentionService
+}
+
+func NewRetentionHandler(retention *services.RetentionService) *RetentionHandler {
+	return &RetentionHandler{retention: retention}
+}
+
+// Report serves GET /retention/report: a dry run of the retention rules for the clinic
+func (h *RetentionHandler) Report(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	report, err := h.retention.Report(r.Context())
+	if err != nil {
+		log.Printf("Retention report failed: %v", err)
+		problem.Error(w, "retention report failed", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
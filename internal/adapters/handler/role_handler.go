@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+)
+
+type RoleHandler struct {
+	roleService *services.RoleService
+}
+
+func NewRoleHandler(roles *services.RoleService) *RoleHandler {
+	return &RoleHandler{roleService: roles}
+}
+
+type CreateRoleRequest struct {
+	Name        domain.Role         `json:"name"`
+	Description string              `json:"description"`
+	Permissions []domain.Permission `json:"permissions"`
+	WardScoped  bool                `json:"ward_scoped"`
+}
+
+type SetRolePermissionsRequest struct {
+	Permissions []domain.Permission `json:"permissions"`
+}
+
+type CreatePermissionRequest struct {
+	Name        domain.Permission `json:"name"`
+	Description string            `json:"description"`
+}
+
+func (h *RoleHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	roles, err := h.roleService.ListRoles(r.Context())
+	if err != nil {
+		log.Printf("Listing roles failed: %v", err)
+		problem.Error(w, "listing roles failed", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, roles)
+}
+
+func (h *RoleHandler) CreateRole(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req CreateRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	role, err := h.roleService.CreateRole(r.Context(), req.Name, req.Description, req.Permissions, req.WardScoped)
+	if err != nil {
+		writeRoleError(w, "creating role failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, role)
+}
+
+func (h *RoleHandler) SetRolePermissions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req SetRolePermissionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	role, err := h.roleService.SetRolePermissions(r.Context(), domain.Role(r.PathValue("role")), req.Permissions)
+	if err != nil {
+		writeRoleError(w, "updating role permissions failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, role)
+}
+
+func (h *RoleHandler) ListPermissions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	permissions, err := h.roleService.ListPermissions(r.Context())
+	if err != nil {
+		log.Printf("Listing permissions failed: %v", err)
+		problem.Error(w, "listing permissions failed", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, permissions)
+}
+
+func (h *RoleHandler) CreatePermission(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req CreatePermissionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	permission, err := h.roleService.CreatePermission(r.Context(), req.Name, req.Description)
+	if err != nil {
+		writeRoleError(w, "creating permission failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, permission)
+}
+
+func writeRoleError(w http.ResponseWriter, message string, err error) {
+	log.Printf("%s: %v", message, err)
+	if errors.Is(err, services.ErrAdminRoleProtected) {
+		problem.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	writeError(w, err, message)
+}
+
+// writeJSON encodes v as the JSON response body with the given status
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Failed to encode response: %v", err)
+	}
+}
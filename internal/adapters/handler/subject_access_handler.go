@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+)
+
+type SubjectAccessHandler struct {
+	subjectAccess *services.SubjectAccessService
+}
+
+func NewSubjectAccessHandler(subjectAccess *services.SubjectAccessService) *SubjectAccessHandler {
+	return &SubjectAccessHandler{subjectAccess: subjectAccess}
+}
+
+// RequestForUser serves POST /users/{userID}/subject-access-requests
+func (h *SubjectAccessHandler) RequestForUser(w http.ResponseWriter, r *http.Request) {
+	h.request(w, r, r.PathValue("userID"), "/subject-access-requests/")
+}
+
+// RequestOwn serves POST /me/subject-access-requests for the caller's own data
+func (h *SubjectAccessHandler) RequestOwn(w http.ResponseWriter, r *http.Request) {
+	h.request(w, r, actorFromRequest(r).ID, "/me/subject-access-requests/")
+}
+
+// Status serves GET /subject-access-requests/{requestID}
+func (h *SubjectAccessHandler) Status(w http.ResponseWriter, r *http.Request) {
+	h.status(w, r, "")
+}
+
+// OwnStatus serves GET /me/subject-access-requests/{requestID}
+func (h *SubjectAccessHandler) OwnStatus(w http.ResponseWriter, r *http.Request) {
+	h.status(w, r, actorFromRequest(r).ID)
+}
+
+// Download serves GET /subject-access-requests/{requestID}/archive
+func (h *SubjectAccessHandler) Download(w http.ResponseWriter, r *http.Request) {
+	h.download(w, r, "")
+}
+
+// OwnDownload serves GET /me/subject-access-requests/{requestID}/archive
+func (h *SubjectAccessHandler) OwnDownload(w http.ResponseWriter, r *http.Request) {
+	h.download(w, r, actorFromRequest(r).ID)
+}
+
+// request queues the export and answers 202 with the request and where to poll its status
+func (h *SubjectAccessHandler) request(w http.ResponseWriter, r *http.Request, userID, statusPath string) {
+	if r.Method != http.MethodPost {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	request, err := h.subjectAccess.Request(r.Context(), actorFromRequest(r), userID)
+	if err != nil {
+		log.Printf("Subject access request failed: %v", err)
+		writeSubjectAccessError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", statusPath+request.ID)
+	writeJSON(w, http.StatusAccepted, request)
+}
+
+func (h *SubjectAccessHandler) status(w http.ResponseWriter, r *http.Request, subjectID string) {
+	if r.Method != http.MethodGet {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	request, err := h.subjectAccess.Get(r.Context(), r.PathValue("requestID"), subjectID)
+	if err != nil {
+		writeSubjectAccessError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, request)
+}
+
+func (h *SubjectAccessHandler) download(w http.ResponseWriter, r *http.Request, subjectID string) {
+	if r.Method != http.MethodGet {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	requestID := r.PathValue("requestID")
+	archive, err := h.subjectAccess.Archive(r.Context(), actorFromRequest(r), requestID, subjectID)
+	if err != nil {
+		log.Printf("Subject access archive download failed: %v", err)
+		writeSubjectAccessError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="subject-access-`+requestID+`.json"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(archive)
+}
+
+func writeSubjectAccessError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrSubjectAccessInProgress), errors.Is(err, domain.ErrSubjectAccessNotReady):
+		problem.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, domain.ErrSubjectAccessExpired):
+		problem.Error(w, err.Error(), http.StatusGone)
+	default:
+		writeError(w, err, "subject access request failed")
+	}
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/middleware"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+)
+
+type UserHandler struct {
+	directory  *services.UserDirectoryService
+	admin      *services.UserAdminService
+	wardPolicy ports.WardPolicy
+}
+
+func NewUserHandler(directory *services.UserDirectoryService, admin *services.UserAdminService, wardPolicy ports.WardPolicy) *UserHandler {
+	return &UserHandler{directory: directory, admin: admin, wardPolicy: wardPolicy}
+}
+
+// ListUsers serves GET /users?role=&status=&room=&created_from=&created_to=&q=&cursor=&limit=
+// Dates are RFC 3339 timestamps or YYYY-MM-DD; created_to is exclusive.
+func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := r.URL.Query()
+	filter, err := userFilterFromQuery(params)
+	if err != nil {
+		writeError(w, err, "listing users failed")
+		return
+	}
+	query := services.UserQuery{Filter: filter, Cursor: params.Get("cursor")}
+
+	if limit := params.Get("limit"); limit != "" {
+		if query.Filter.Limit, err = strconv.Atoi(limit); err != nil || query.Filter.Limit == 0 {
+			problem.Validation(w, services.ErrInvalidPageSize)
+			return
+		}
+	}
+
+	page, err := h.directory.List(r.Context(), query)
+	if err != nil {
+		log.Printf("Listing users failed: %v", err)
+		writeError(w, err, "listing users failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	record, err := h.directory.Get(r.Context(), r.PathValue("userID"))
+	if err != nil {
+		log.Printf("Getting user failed: %v", err)
+		writeError(w, err, "getting user failed")
+		return
+	}
+
+	w.Header().Set("ETag", userETag(record.Version))
+	writeJSON(w, http.StatusOK, record)
+}
+
+// UpdateUser serves PATCH /users/{userID}. The request must carry the ETag of
+// the version being edited in If-Match; only the fields present are changed.
+// A room_number transfers the parent and so needs the parents:transfer permission.
+func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		problem.Error(w, "If-Match header is required", http.StatusPreconditionRequired)
+		return
+	}
+	version, err := parseUserETag(ifMatch)
+	if err != nil {
+		problem.Error(w, "If-Match must be the ETag of the user", http.StatusBadRequest)
+		return
+	}
+
+	var update domain.UserUpdate
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&update); err != nil {
+		problem.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	userID := r.PathValue("userID")
+	actor := actorFromRequest(r)
+
+	// A room change is a transfer and is checked like POST /parents/{parentID}/transfer
+	if update.RoomNumber != nil {
+		if !middleware.HasPermission(r.Context(), domain.PermParentsTransfer) {
+			log.Printf("Room change of %s denied: %s lacks %s", userID, actor.Role, domain.PermParentsTransfer)
+			problem.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		if err := h.wardPolicy.AuthorizeParent(r.Context(), actor, userID); err != nil {
+			writeWardError(w, err, "Room change of "+userID)
+			return
+		}
+		if err := h.wardPolicy.AuthorizeRegistration(r.Context(), actor, domain.RoleParent, *update.RoomNumber); err != nil {
+			writeWardError(w, err, "Room change to "+*update.RoomNumber)
+			return
+		}
+	}
+
+	record, err := h.admin.Update(r.Context(), actor, userID, version, update)
+	if err != nil {
+		log.Printf("Updating user failed: %v", err)
+		writeUserAdminError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", userETag(record.Version))
+	writeJSON(w, http.StatusOK, record)
+}
+
+// DeleteUser serves DELETE /users/{userID}: the user is deactivated and all of their sessions revoked.
+// Removing an admin returns 202 with the approval another admin has to give.
+func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	approval, err := h.admin.Deactivate(r.Context(), actorFromRequest(r), r.PathValue("userID"))
+	if err != nil {
+		log.Printf("Deactivating user failed: %v", err)
+		writeUserAdminError(w, err)
+		return
+	}
+	if approval != nil {
+		writeJSON(w, http.StatusAccepted, approval)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeUserAdminError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrMissingUserVersion):
+		problem.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, services.ErrParentNotActive):
+		problem.Error(w, err.Error(), http.StatusConflict)
+	default:
+		writeAdminApprovalError(w, err, "user update failed")
+	}
+}
+
+// userETag is the strong entity tag of a user version, e.g. "3"
+func userETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+func parseUserETag(etag string) (int, error) {
+	unquoted, err := strconv.Unquote(strings.TrimSpace(etag))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(unquoted)
+}
+
+// userFilterFromQuery reads the directory filters role, status, room, q,
+// created_from and created_to shared by listings and exports
+func userFilterFromQuery(params url.Values) (domain.UserFilter, error) {
+	filter := domain.UserFilter{
+		Role:         domain.Role(params.Get("role")),
+		ParentStatus: domain.ParentStatus(params.Get("status")),
+		RoomNumber:   params.Get("room"),
+		Search:       params.Get("q"),
+	}
+
+	var err error
+	if filter.CreatedFrom, err = parseDateParam(params, "created_from"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedTo, err = parseDateParam(params, "created_to"); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+func parseDateParam(params url.Values, name string) (time.Time, error) {
+	value := params.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, domain.InvalidField(name, name+" must be an RFC 3339 timestamp or YYYY-MM-DD date")
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/middleware"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+)
+
+type VisitorHandler struct {
+	visitorService *services.VisitorService
+}
+
+func NewVisitorHandler(visitors *services.VisitorService) *VisitorHandler {
+	return &VisitorHandler{visitorService: visitors}
+}
+
+type InviteVisitorRequest struct {
+	Email       string    `json:"email,omitempty"`
+	DisplayName string    `json:"display_name"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+type InviteVisitorResponse struct {
+	ID        string `json:"id"`
+	Email     string `json:"email,omitempty"`
+	Code      string `json:"code,omitempty"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+type RedeemVisitorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// Invite lets an active parent invite a visitor by email or by a generated code.
+func (h *VisitorHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parentID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	var req InviteVisitorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if req.DisplayName == "" {
+		problem.Error(w, "missing display_name", http.StatusBadRequest)
+		return
+	}
+
+	invitation, err := h.visitorService.Invite(r.Context(), parentID, req.Email, req.DisplayName, req.ExpiresAt)
+	if err != nil {
+		log.Printf("Visitor invitation by %s failed: %v", parentID, err)
+		switch {
+		case errors.Is(err, services.ErrVisitorInviteNotAllowed):
+			problem.Error(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, services.ErrVisitorEmailTaken):
+			problem.Error(w, err.Error(), http.StatusConflict)
+		default:
+			writeError(w, err, "visitor invitation failed")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(InviteVisitorResponse{
+		ID:        invitation.Visitor.ID,
+		Email:     invitation.Visitor.Email,
+		Code:      invitation.Code,
+		ExpiresAt: invitation.Visitor.ExpiresAt.UTC().Format(time.RFC3339),
+	}); err != nil {
+		log.Printf("Failed to encode response: %v", err)
+	}
+}
+
+// List returns the calling parent's visitors.
+func (h *VisitorHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parentID, _ := r.Context().Value(middleware.UserIDKey).(string)
+
+	visitors, err := h.visitorService.List(r.Context(), parentID)
+	if err != nil {
+		log.Printf("Listing visitors of %s failed: %v", parentID, err)
+		problem.Error(w, "listing visitors failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(visitors); err != nil {
+		log.Printf("Failed to encode response: %v", err)
+	}
+}
+
+// Revoke disables one of the calling parent's visitors.
+func (h *VisitorHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parentID, _ := r.Context().Value(middleware.UserIDKey).(string)
+	visitorID := r.PathValue("visitorID")
+
+	err := h.visitorService.Revoke(r.Context(), parentID, visitorID)
+	if errors.Is(err, services.ErrVisitorNotFound) {
+		problem.Error(w, "visitor not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Revoking visitor %s failed: %v", visitorID, err)
+		problem.Error(w, "revoking visitor failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]string{"message": "visitor revoked"}); err != nil {
+		log.Printf("Failed to encode response: %v", err)
+	}
+}
+
+// Redeem swaps a visitor invitation code for a visitor token.
+func (h *VisitorHandler) Redeem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		problem.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req RedeemVisitorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if req.Code == "" {
+		problem.Error(w, "missing code", http.StatusBadRequest)
+		return
+	}
+
+	token, err := h.visitorService.RedeemCode(r.Context(), req.Code)
+	if err != nil {
+		log.Printf("Visitor code redemption failed: %v", err)
+		if errors.Is(err, services.ErrVisitorAccessDenied) {
+			problem.Error(w, "invalid visitor code", http.StatusUnauthorized)
+			return
+		}
+		problem.Error(w, "visitor login failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]string{
+		"message": "Logged in successfully!",
+		"token":   token,
+	}); err != nil {
+		log.Printf("Failed to encode response: %v", err)
+	}
+}
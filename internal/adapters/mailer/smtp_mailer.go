@@ -0,0 +1,70 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/config"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+// New returns an SMTP mailer, or a mailer that only logs when no SMTP host is configured
+func New(cfg config.SMTPConfig) ports.Mailer {
+	if cfg.Host == "" {
+		return LogMailer{}
+	}
+	return &SMTPMailer{cfg: cfg}
+}
+
+// SMTPMailer sends mail through an SMTP relay, using STARTTLS when the relay offers it.
+type SMTPMailer struct {
+	cfg config.SMTPConfig
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, message ports.MailMessage) error {
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+
+	// net/smtp takes no context; give up in the background once ctx is done
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, m.cfg.From, []string{message.To}, m.format(message))
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *SMTPMailer) format(message ports.MailMessage) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", m.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", message.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", message.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// LogMailer logs that mail would have been sent, without its body, which may
+// hold a secret link. It stands in for SMTP in development.
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, message ports.MailMessage) error {
+	log.Printf("SMTP_HOST not configured; not sending %q to %s", message.Subject, message.To)
+	return nil
+}
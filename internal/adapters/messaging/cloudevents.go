@@ -0,0 +1,111 @@
+package messaging
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/config"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	cloudEventsSpecVersion = "1.0"
+	// cloudEventsPrefix marks event attributes among the application
+	// properties of a binary mode message (CloudEvents AMQP binding, 3.1.3)
+	cloudEventsPrefix     = "cloudEvents:"
+	structuredContentType = "application/cloudevents+json; charset=utf-8"
+	dataContentType       = "application/json"
+)
+
+// cloudEvent is an outbox event in the CloudEvents 1.0 JSON format. The
+// outbox metadata without a CloudEvents counterpart travels in extension
+// attributes, which must be lowercase alphanumeric.
+type cloudEvent struct {
+	SpecVersion     string `json:"specversion"`
+	ID              string `json:"id"`
+	Source          string `json:"source"`
+	Type            string `json:"type"`
+	Subject         string `json:"subject,omitempty"`
+	Time            string `json:"time"`
+	DataContentType string `json:"datacontenttype"`
+
+	SchemaVersion int    `json:"schemaversion"`
+	TenantID      string `json:"tenantid"`
+	AggregateType string `json:"aggregatetype"`
+	AggregateID   string `json:"aggregateid"`
+	CorrelationID string `json:"correlationid,omitempty"`
+
+	Data json.RawMessage `json:"data"`
+}
+
+func newCloudEvent(envelope ports.EventEnvelope, source string) cloudEvent {
+	return cloudEvent{
+		SpecVersion:     cloudEventsSpecVersion,
+		ID:              envelope.ID,
+		Source:          source,
+		Type:            envelope.Type,
+		Subject:         envelope.AggregateID,
+		Time:            envelope.OccurredAt.UTC().Format(time.RFC3339Nano),
+		DataContentType: dataContentType,
+		SchemaVersion:   envelope.SchemaVersion,
+		TenantID:        envelope.TenantID,
+		AggregateType:   envelope.AggregateType,
+		AggregateID:     envelope.AggregateID,
+		CorrelationID:   envelope.CorrelationID,
+		Data:            envelope.Payload,
+	}
+}
+
+// applicationProperties returns the attributes of a binary mode message;
+// datacontenttype is the message's content-type instead.
+func (e cloudEvent) applicationProperties() amqp.Table {
+	properties := amqp.Table{
+		cloudEventsPrefix + "specversion":   e.SpecVersion,
+		cloudEventsPrefix + "id":            e.ID,
+		cloudEventsPrefix + "source":        e.Source,
+		cloudEventsPrefix + "type":          e.Type,
+		cloudEventsPrefix + "time":          e.Time,
+		cloudEventsPrefix + "schemaversion": int32(e.SchemaVersion),
+		cloudEventsPrefix + "tenantid":      e.TenantID,
+		cloudEventsPrefix + "aggregatetype": e.AggregateType,
+		cloudEventsPrefix + "aggregateid":   e.AggregateID,
+	}
+	if e.Subject != "" {
+		properties[cloudEventsPrefix+"subject"] = e.Subject
+	}
+	if e.CorrelationID != "" {
+		properties[cloudEventsPrefix+"correlationid"] = e.CorrelationID
+	}
+	return properties
+}
+
+// EncodeCloudEvent writes the envelope as a CloudEvent in the content mode
+// of cfg. The outbox row ID is the event ID. Both modes also fill the AMQP
+// message properties, for consumers that do not read CloudEvents.
+func EncodeCloudEvent(envelope ports.EventEnvelope, cfg config.CloudEventsConfig) (amqp.Publishing, error) {
+	event := newCloudEvent(envelope, cfg.Source)
+	msg := amqp.Publishing{
+		DeliveryMode:  amqp.Persistent,
+		MessageId:     envelope.ID,
+		Type:          envelope.Type,
+		Timestamp:     envelope.OccurredAt,
+		CorrelationId: envelope.CorrelationID,
+		AppId:         appID,
+	}
+
+	if cfg.Mode == config.CloudEventsStructured {
+		body, err := json.Marshal(event)
+		if err != nil {
+			return amqp.Publishing{}, err
+		}
+		msg.ContentType = structuredContentType
+		msg.Body = body
+		return msg, nil
+	}
+
+	msg.ContentType = event.DataContentType
+	msg.Headers = event.applicationProperties()
+	msg.Body = envelope.Payload
+	return msg, nil
+}
@@ -0,0 +1,46 @@
+package messaging
+
+import (
+	"context"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+// appID names this service as the publisher of every message
+const appID = "identity-access-service"
+
+// Publish sends the envelope as a CloudEvent to the destination the topology
+// gives its type: the topology's exchange with a routing key such as
+// identity.parent.registered, or in queues mode the queue routed for the type.
+// In binary mode the body is the payload itself, so consumers of the plain
+// JSON events keep working.
+func (rmq *RabbitMQBroker) Publish(ctx context.Context, envelope ports.EventEnvelope) error {
+	exchange, routingKey := rmq.topology.Destination(envelope.Type)
+
+	msg, err := EncodeCloudEvent(envelope, rmq.cloudEvents)
+	if err != nil {
+		return err
+	}
+
+	// Respect context deadline
+	if deadline, ok := ctx.Deadline(); ok {
+		if time.Until(deadline) <= 0 {
+			return ctx.Err()
+		}
+	}
+
+	// Use circuit breaker to protect RabbitMQ publish operation
+	_, err = rmq.cb.Execute(func() (interface{}, error) {
+		err := rmq.ch.PublishWithContext(
+			ctx,
+			exchange,
+			routingKey,
+			false, // mandatory
+			false, // immediate
+			msg,
+		)
+		return nil, err
+	})
+	return err
+}
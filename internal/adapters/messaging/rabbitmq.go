@@ -1,20 +1,31 @@
 package messaging
 
 import (
+	"fmt"
+
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/config"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
 	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/sony/gobreaker"
 )
 
-// RabbitMQBroker implements ports.BabyEventPublisher using RabbitMQ.
+// Ensure RabbitMQBroker implements ports.EventPublisher at compile time.
+var _ ports.EventPublisher = (*RabbitMQBroker)(nil)
+
+// RabbitMQBroker implements ports.EventPublisher using RabbitMQ.
 type RabbitMQBroker struct {
-	conn      *amqp.Connection
-	ch        *amqp.Channel
-	queueName string
-	cb        *gobreaker.CircuitBreaker
+	conn *amqp.Connection
+	ch   *amqp.Channel
+	// topology decides the exchange and routing key of each event type
+	topology    config.TopologyConfig
+	cloudEvents config.CloudEventsConfig
+	cb          *gobreaker.CircuitBreaker
 }
 
-func NewRabbitMQBroker(amqpURL, queueName string) (*RabbitMQBroker, error) {
+// NewRabbitMQBroker declares the exchanges, queues and bindings of the
+// topology. Events are written as CloudEvents in the content mode of
+// cloudEvents.
+func NewRabbitMQBroker(amqpURL string, topology config.TopologyConfig, cloudEvents config.CloudEventsConfig) (*RabbitMQBroker, error) {
 	conn, err := amqp.Dial(amqpURL)
 	if err != nil {
 		return nil, err
@@ -26,16 +37,7 @@ func NewRabbitMQBroker(amqpURL, queueName string) (*RabbitMQBroker, error) {
 		return nil, err
 	}
 
-	// Declare the queue (idempotent)
-	_, err = ch.QueueDeclare(
-		queueName,
-		true,  // durable
-		false, // autoDelete
-		false, // exclusive
-		false, // noWait
-		nil,   // args
-	)
-	if err != nil {
+	if err := declareTopology(ch, topology); err != nil {
 		ch.Close()
 		conn.Close()
 		return nil, err
@@ -45,13 +47,66 @@ func NewRabbitMQBroker(amqpURL, queueName string) (*RabbitMQBroker, error) {
 	cb := config.NewCircuitBreaker("RabbitMQ-Publisher")
 
 	return &RabbitMQBroker{
-		conn:      conn,
-		ch:        ch,
-		queueName: queueName,
-		cb:        cb,
+		conn:        conn,
+		ch:          ch,
+		topology:    topology,
+		cloudEvents: cloudEvents,
+		cb:          cb,
 	}, nil
 }
 
+// declareTopology declares the exchanges, then the queues, then the bindings
+// between them. Declarations are idempotent, but RabbitMQ refuses to redeclare
+// an existing exchange or queue with different settings.
+func declareTopology(ch *amqp.Channel, topology config.TopologyConfig) error {
+	for _, exchange := range topology.Exchanges {
+		err := ch.ExchangeDeclare(
+			exchange.Name,
+			exchange.Type,
+			true,  // durable
+			false, // autoDelete
+			false, // internal
+			false, // noWait
+			nil,   // args
+		)
+		if err != nil {
+			return fmt.Errorf("declare exchange %q: %w", exchange.Name, err)
+		}
+	}
+
+	for _, queue := range topology.Queues {
+		args, err := queue.Arguments()
+		if err != nil {
+			return err
+		}
+		_, err = ch.QueueDeclare(
+			queue.Name,
+			true,  // durable
+			false, // autoDelete
+			false, // exclusive
+			false, // noWait
+			amqp.Table(args),
+		)
+		if err != nil {
+			return fmt.Errorf("declare queue %q: %w", queue.Name, err)
+		}
+	}
+
+	for _, binding := range topology.Bindings {
+		err := ch.QueueBind(
+			binding.Queue,
+			binding.RoutingKey,
+			binding.Exchange,
+			false, // noWait
+			nil,   // args
+		)
+		if err != nil {
+			return fmt.Errorf("bind queue %q to exchange %q: %w", binding.Queue, binding.Exchange, err)
+		}
+	}
+	return nil
+}
+
 func (rmq *RabbitMQBroker) Close() error {
 	if rmq.ch != nil {
 		if err := rmq.ch.Close(); err != nil {
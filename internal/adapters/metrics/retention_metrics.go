@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	retentionRecordsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "retention_records_total",
+			Help: "Records anonymized or deleted by the retention job",
+		},
+		[]string{"tenant", "rule", "outcome"},
+	)
+
+	retentionRecordsDue = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "retention_records_due",
+			Help: "Records past their retention period at the start of the last run",
+		},
+		[]string{"tenant", "rule"},
+	)
+
+	retentionLastRun = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "retention_last_run_timestamp_seconds",
+			Help: "Time of the last completed retention run",
+		},
+		[]string{"tenant"},
+	)
+
+	retentionRunsSkipped = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "retention_runs_skipped_total",
+			Help: "Retention runs skipped because another replica held the lock",
+		},
+	)
+)
+
+// RetentionMetrics exports the retention job's reports to Prometheus.
+type RetentionMetrics struct{}
+
+var _ ports.RetentionMetrics = RetentionMetrics{}
+
+func (RetentionMetrics) ObserveRetentionRun(report domain.RetentionReport) {
+	for _, rule := range report.Rules {
+		if !rule.Enabled {
+			continue
+		}
+		retentionRecordsDue.WithLabelValues(report.TenantID, rule.Rule).Set(float64(rule.Due))
+		retentionRecordsTotal.WithLabelValues(report.TenantID, rule.Rule, "applied").Add(float64(rule.Applied))
+		retentionRecordsTotal.WithLabelValues(report.TenantID, rule.Rule, "failed").Add(float64(rule.Failed))
+	}
+	retentionLastRun.WithLabelValues(report.TenantID).Set(float64(report.GeneratedAt.Unix()))
+}
+
+func (RetentionMetrics) ObserveRetentionSkipped() {
+	retentionRunsSkipped.Inc()
+}
@@ -3,106 +3,241 @@ package middleware
 import (
 	"context"
 	"crypto/rsa"
+	"database/sql"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/config"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
 	jwt "github.com/golang-jwt/jwt/v5"
 	redis "github.com/redis/go-redis/v9"
 	"github.com/sony/gobreaker"
 )
 
+// roleVersionTTL is how long a role's version is cached; a permission change
+// reaches tokens issued before it at most this late
+const roleVersionTTL = 30 * time.Second
+
 type AuthMiddleware struct {
 	publicKey   *rsa.PublicKey
 	redisClient *redis.Client
 	redisCB     *gobreaker.CircuitBreaker
+	roles       ports.PermissionRepository
+
+	mu           sync.Mutex
+	roleVersions map[string]cachedRoleVersion // tenant + "/" + role -> version
+}
+
+type cachedRoleVersion struct {
+	version   int
+	fetchedAt time.Time
 }
 
-func NewAuthMiddleware(publicKey *rsa.PublicKey, redisClient *redis.Client) *AuthMiddleware {
+// NewAuthMiddleware validates bearer tokens. With a permission repository,
+// tokens issued under an older version of their role's permission set are
+// rejected; without one the role_version claim is not checked.
+func NewAuthMiddleware(publicKey *rsa.PublicKey, redisClient *redis.Client, roles ports.PermissionRepository) *AuthMiddleware {
 	// Configure circuit breaker for Redis operations
 	// Fail-closed strategy: When circuit is open, reject requests for security
 	redisCB := config.NewCircuitBreaker("Redis-Auth")
 
 	return &AuthMiddleware{
-		publicKey:   publicKey,
-		redisClient: redisClient,
-		redisCB:     redisCB,
+		publicKey:    publicKey,
+		redisClient:  redisClient,
+		redisCB:      redisCB,
+		roles:        roles,
+		roleVersions: make(map[string]cachedRoleVersion),
 	}
 }
 
 type ContextKey string
 
 const (
-	UserIDKey ContextKey = "userID"
-	RoleKey   ContextKey = "role"
-	TokenKey  ContextKey = "token"
+	UserIDKey       ContextKey = "userID"
+	RoleKey         ContextKey = "role"
+	TokenKey        ContextKey = "token"
+	ImpersonatorKey ContextKey = "impersonator"
+	PermissionsKey  ContextKey = "permissions"
 )
 
 func (m *AuthMiddleware) RequireRole(roles []string, next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			log.Printf("Missing Authorization header")
-			http.Error(w, "missing authorization header", http.StatusUnauthorized)
+		ctx, ok := m.authenticate(w, r)
+		if !ok {
 			return
 		}
 
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+		userRole, _ := ctx.Value(RoleKey).(string)
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-				return nil, jwt.ErrSignatureInvalid
+		allowedRoles := false
+		for _, r := range roles {
+			if userRole == r {
+				allowedRoles = true
+				break
 			}
-			return m.publicKey, nil
-		})
-
-		if err != nil || !token.Valid {
-			http.Error(w, "invalid token", http.StatusUnauthorized)
+		}
+		if !allowedRoles {
+			log.Printf("Role mismatch: required one of %v, got %s", roles, userRole)
+			problem.Error(w, "forbidden", http.StatusForbidden)
 			return
 		}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// RequirePermission authorizes the request against the permission set embedded in the token,
+// so routes do not have to hard-code which roles may call them.
+func (m *AuthMiddleware) RequirePermission(permission domain.Permission, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, ok := m.authenticate(w, r)
 		if !ok {
-			http.Error(w, "invalid token claims", http.StatusUnauthorized)
 			return
 		}
 
-		revoked, err := m.isBlacklisted(claims, r.Context())
-		if err != nil {
-			// Circuit breaker is open or Redis failed - FAIL CLOSED
-			log.Printf("[CRITICAL] Authentication service unavailable: %v", err)
-			http.Error(w, "authentication service unavailable", http.StatusServiceUnavailable)
+		if !HasPermission(ctx, permission) {
+			permissions, _ := ctx.Value(PermissionsKey).([]string)
+			userRole, _ := ctx.Value(RoleKey).(string)
+			log.Printf("Permission denied: required %s, role %s has %v", permission, userRole, permissions)
+			problem.Error(w, "forbidden", http.StatusForbidden)
 			return
 		}
-		if revoked {
-			http.Error(w, "token revoked", http.StatusUnauthorized)
-			return
+
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// HasPermission reports whether the token the request was authenticated with grants permission.
+func HasPermission(ctx context.Context, permission domain.Permission) bool {
+	permissions, _ := ctx.Value(PermissionsKey).([]string)
+	for _, p := range permissions {
+		if p == string(permission) {
+			return true
 		}
+	}
+	return false
+}
 
-		userID, _ := claims["sub"].(string)
-		userRole, _ := claims["role"].(string)
+// authenticate validates the bearer token and returns a context carrying its identity.
+// On failure the error response has already been written.
+func (m *AuthMiddleware) authenticate(w http.ResponseWriter, r *http.Request) (context.Context, bool) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		log.Printf("Missing Authorization header")
+		problem.Error(w, "missing authorization header", http.StatusUnauthorized)
+		return nil, false
+	}
 
-		log.Printf("Token validated - UserID: %s, Role: %s", userID, userRole)
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
-		allowedRoles := false
-		for _, r := range roles {
-			if userRole == r {
-				allowedRoles = true
-				break
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return m.publicKey, nil
+	})
+
+	if err != nil || !token.Valid {
+		problem.Error(w, "invalid token", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		problem.Error(w, "invalid token claims", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	// Tokens are only valid for the clinic that issued them
+	tokenTenant, _ := claims["tenant"].(string)
+	if tokenTenant == "" {
+		problem.Error(w, "invalid token claims", http.StatusUnauthorized)
+		return nil, false
+	}
+	if requestTenant, ok := domain.TenantFromContext(r.Context()); ok && requestTenant != tokenTenant {
+		log.Printf("Tenant mismatch: token for %s used on %s", tokenTenant, requestTenant)
+		problem.Error(w, "forbidden", http.StatusForbidden)
+		return nil, false
+	}
+
+	// A token issued before its role's permissions changed no longer grants them
+	current, err := m.hasCurrentRoleVersion(domain.WithTenant(r.Context(), tokenTenant), claims)
+	if err != nil {
+		log.Printf("[CRITICAL] Role version check failed: %v", err)
+		problem.Error(w, "authentication service unavailable", http.StatusServiceUnavailable)
+		return nil, false
+	}
+	if !current {
+		problem.Error(w, "token was issued before the role's permissions changed; sign in again", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	revoked, err := m.isBlacklisted(claims, r.Context())
+	if err != nil {
+		// Circuit breaker is open or Redis failed - FAIL CLOSED
+		log.Printf("[CRITICAL] Authentication service unavailable: %v", err)
+		problem.Error(w, "authentication service unavailable", http.StatusServiceUnavailable)
+		return nil, false
+	}
+	if revoked {
+		problem.Error(w, "token revoked", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	userID, _ := claims["sub"].(string)
+	userRole, _ := claims["role"].(string)
+
+	// The user's preferred locale wins over the client's Accept-Language
+	if locale, _ := claims["locale"].(string); locale != "" {
+		problem.SetLocale(w, locale)
+	}
+
+	log.Printf("Token validated - UserID: %s, Role: %s", userID, userRole)
+
+	var permissions []string
+	if perms, ok := claims["perms"].([]interface{}); ok {
+		for _, p := range perms {
+			if permission, ok := p.(string); ok {
+				permissions = append(permissions, permission)
 			}
 		}
-		if !allowedRoles {
-			log.Printf("Role mismatch: required one of %v, got %s", roles, userRole)
-			http.Error(w, "forbidden", http.StatusForbidden)
-			return
+	}
+
+	ctx := domain.WithTenant(r.Context(), tokenTenant)
+	ctx = context.WithValue(ctx, UserIDKey, userID)
+	ctx = context.WithValue(ctx, RoleKey, userRole)
+	ctx = context.WithValue(ctx, TokenKey, tokenString)
+	ctx = context.WithValue(ctx, PermissionsKey, permissions)
+
+	// RFC 8693 actor claim: the token was issued to an admin acting as this user
+	if act, ok := claims["act"].(map[string]interface{}); ok {
+		if impersonatorID, _ := act["sub"].(string); impersonatorID != "" {
+			log.Printf("Impersonated session - UserID: %s, Impersonator: %s", userID, impersonatorID)
+			w.Header().Set("X-Impersonated-By", impersonatorID)
+			ctx = context.WithValue(ctx, ImpersonatorKey, impersonatorID)
 		}
+	}
 
-		ctx := context.WithValue(r.Context(), UserIDKey, userID)
-		ctx = context.WithValue(ctx, RoleKey, userRole)
-		ctx = context.WithValue(ctx, TokenKey, tokenString)
+	return ctx, true
+}
 
-		next(w, r.WithContext(ctx))
+// DenyImpersonation rejects requests made with an impersonation token.
+// It must be wrapped by RequireRole or RequirePermission so the token has already been validated.
+func DenyImpersonation(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if impersonatorID, ok := r.Context().Value(ImpersonatorKey).(string); ok && impersonatorID != "" {
+			log.Printf("Blocked impersonated request to %s by impersonator %s", r.URL.Path, impersonatorID)
+			problem.Error(w, "not allowed during impersonation", http.StatusForbidden)
+			return
+		}
+		next(w, r)
 	}
 }
 
@@ -123,3 +258,50 @@ func (m *AuthMiddleware) isBlacklisted(claims jwt.MapClaims, ctx context.Context
 	isRevoked, ok := result.(int64)
 	return ok && isRevoked > 0, nil
 }
+
+// hasCurrentRoleVersion reports whether the token's role_version is the
+// current version of its role. Tokens without a role carry no version.
+func (m *AuthMiddleware) hasCurrentRoleVersion(ctx context.Context, claims jwt.MapClaims) (bool, error) {
+	role, _ := claims["role"].(string)
+	if m.roles == nil || role == "" {
+		return true, nil
+	}
+	// JSON numbers decode as float64
+	tokenVersion, ok := claims["role_version"].(float64)
+	if !ok {
+		return false, nil
+	}
+
+	version, err := m.roleVersion(ctx, domain.Role(role))
+	if errors.Is(err, sql.ErrNoRows) {
+		// The role no longer exists in the clinic
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return int(tokenVersion) == version, nil
+}
+
+// roleVersion returns the current version of the role, cached for roleVersionTTL
+func (m *AuthMiddleware) roleVersion(ctx context.Context, role domain.Role) (int, error) {
+	tenant, _ := domain.TenantFromContext(ctx)
+	key := tenant + "/" + string(role)
+
+	m.mu.Lock()
+	cached, ok := m.roleVersions[key]
+	m.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < roleVersionTTL {
+		return cached.version, nil
+	}
+
+	definition, err := m.roles.GetRole(ctx, role)
+	if err != nil {
+		return 0, fmt.Errorf("looking up role %s: %w", role, err)
+	}
+
+	m.mu.Lock()
+	m.roleVersions[key] = cachedRoleVersion{version: definition.Version, fetchedAt: time.Now()}
+	m.mu.Unlock()
+	return definition.Version, nil
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// CorrelationHeader carries the ID that ties a request to the events it causes.
+const CorrelationHeader = "X-Correlation-ID"
+
+// maxCorrelationIDLength bounds caller-supplied IDs to the outbox column
+const maxCorrelationIDLength = 64
+
+// CorrelationMiddleware takes the correlation ID from the X-Correlation-ID
+// header, or generates one, stores it in the request context for the outbox
+// and echoes it in the response.
+func CorrelationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		correlationID := r.Header.Get(CorrelationHeader)
+		if correlationID == "" || len(correlationID) > maxCorrelationIDLength {
+			correlationID = uuid.NewString()
+		}
+
+		w.Header().Set(CorrelationHeader, correlationID)
+		next.ServeHTTP(w, r.WithContext(domain.WithCorrelationID(r.Context(), correlationID)))
+	})
+}
@@ -2,14 +2,25 @@ package middleware
 
 import (
 	"net/http"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/config"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
 )
 
-// CORSMiddleware handles Cross-Origin Resource Sharing (CORS) headers
-func CORSMiddleware(allowedOrigins []string) func(http.Handler) http.Handler {
+// CORSMiddleware handles Cross-Origin Resource Sharing (CORS) headers using the
+// allowed origins of the request's clinic. It must run inside TenantMiddleware.
+func CORSMiddleware(cfg *config.Config) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			origin := r.Header.Get("Origin")
 
+			var allowedOrigins []string
+			if tenantID, ok := domain.TenantFromContext(r.Context()); ok {
+				if tenant, ok := cfg.Tenant(tenantID); ok {
+					allowedOrigins = tenant.CORSAllowedOrigins
+				}
+			}
+
 			// Check if the origin is allowed
 			allowed := false
 			for _, allowedOrigin := range allowedOrigins {
@@ -27,8 +38,10 @@ func CORSMiddleware(allowedOrigins []string) func(http.Handler) http.Handler {
 					w.Header().Set("Access-Control-Allow-Origin", "*")
 				}
 
-				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With")
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, If-Match, "+TenantHeader+", "+CorrelationHeader)
+				// The ETag has to be readable by the frontend to send it back in If-Match
+				w.Header().Set("Access-Control-Expose-Headers", "ETag")
 				w.Header().Set("Access-Control-Allow-Credentials", "true")
 				w.Header().Set("Access-Control-Max-Age", "86400") // 24 hours
 			}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+)
+
+// LocaleMiddleware writes problems in the best supported language of the
+// Accept-Language header. Once a token is validated, the user's preferred
+// locale in it takes precedence (see AuthMiddleware).
+func LocaleMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Language")
+		next.ServeHTTP(problem.WithLocale(w, MatchLocale(r.Header.Get("Accept-Language"))), r)
+	})
+}
+
+// MatchLocale returns the supported locale with the highest weight in an
+// Accept-Language header, such as "nl-NL,nl;q=0.9,en;q=0.8", or the default
+// locale when none is supported
+func MatchLocale(acceptLanguage string) string {
+	best, bestWeight := domain.DefaultLocale, 0.0
+	for _, entry := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(entry, ";")
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			weight = parsed
+		}
+
+		locale, ok := domain.ParseLocale(tag)
+		if ok && weight > bestWeight {
+			best, bestWeight = locale, weight
+		}
+	}
+	return best
+}
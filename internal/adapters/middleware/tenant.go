@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/config"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+)
+
+// TenantHeader lets clients name their clinic explicitly instead of relying on the host.
+const TenantHeader = "X-Tenant-ID"
+
+// TenantMiddleware scopes the request to a clinic, chosen by the X-Tenant-ID header,
+// then the request host, then the default tenant. Requests that match no clinic
+// continue unscoped; tenant-scoped operations reject them.
+func TenantMiddleware(cfg *config.Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tenantID := r.Header.Get(TenantHeader)
+			if tenantID != "" {
+				if _, ok := cfg.Tenant(tenantID); !ok {
+					log.Printf("Unknown tenant requested: %s", tenantID)
+					problem.Error(w, "unknown tenant", http.StatusBadRequest)
+					return
+				}
+			} else if tenant, ok := cfg.TenantForHost(r.Host); ok {
+				tenantID = tenant.ID
+			} else {
+				tenantID = cfg.DefaultTenant
+			}
+
+			if tenantID != "" {
+				r = r.WithContext(domain.WithTenant(r.Context(), tenantID))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
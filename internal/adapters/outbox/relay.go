@@ -4,8 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"log"
-	"os"
 	"time"
 
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/config"
@@ -36,7 +36,8 @@ const (
 // and publishes events to RabbitMQ.
 type Relay struct {
 	db            *sql.DB
-	publisher     ports.BabyEventPublisher
+	publisher     ports.EventPublisher
+	events        *ports.EventRegistry
 	listener      *pq.Listener
 	dbURL         string
 	dbCB          *gobreaker.CircuitBreaker
@@ -45,7 +46,8 @@ type Relay struct {
 }
 
 // NewRelay creates a new outbox relay that listens for PostgreSQL notifications.
-func NewRelay(db *sql.DB, dbURL string, publisher ports.BabyEventPublisher) *Relay {
+// It publishes the event types registered in ports.Events.
+func NewRelay(db *sql.DB, dbURL string, publisher ports.EventPublisher) *Relay {
 	// Configure circuit breaker for database operations
 	dbCB := config.NewCircuitBreaker("Relay-PostgreSQL")
 
@@ -53,6 +55,7 @@ func NewRelay(db *sql.DB, dbURL string, publisher ports.BabyEventPublisher) *Rel
 		db:            db,
 		dbURL:         dbURL,
 		publisher:     publisher,
+		events:        ports.Events,
 		dbCB:          dbCB,
 		lastProcessed: time.Now(),
 		isHealthy:     true,
@@ -152,6 +155,44 @@ func (r *Relay) Start(ctx context.Context) error {
 	}
 }
 
+var (
+	errInvalidPayload       = errors.New("invalid event payload")
+	errPublisherUnavailable = errors.New("publisher not available")
+	errUnknownEventType     = errors.New("unknown event type")
+)
+
+// outboxColumns are the outbox_events columns scanned by scanEnvelope
+const outboxColumns = `id, tenant_id, aggregate_type, aggregate_id, event_type, schema_version,
+	COALESCE(correlation_id, ''), payload, created_at`
+
+func scanEnvelope(row interface{ Scan(dest ...any) error }) (ports.EventEnvelope, error) {
+	var envelope ports.EventEnvelope
+	var payload []byte
+	err := row.Scan(&envelope.ID, &envelope.TenantID, &envelope.AggregateType, &envelope.AggregateID, &envelope.Type,
+		&envelope.SchemaVersion, &envelope.CorrelationID, &payload, &envelope.OccurredAt)
+	envelope.Payload = payload
+	return envelope, err
+}
+
+// publish sends an outbox event to the broker. Events written under a former
+// name of their type are published under the current one; events of a type
+// this relay does not know are left in the outbox for one that does.
+func (r *Relay) publish(ctx context.Context, envelope ports.EventEnvelope) error {
+	eventType, ok := r.events.Lookup(envelope.Type)
+	if !ok {
+		return errUnknownEventType
+	}
+	if !json.Valid(envelope.Payload) {
+		return errInvalidPayload
+	}
+	if r.publisher == nil {
+		return errPublisherUnavailable
+	}
+
+	envelope.Type = eventType.Name
+	return r.publisher.Publish(ctx, envelope)
+}
+
 // processEventByID processes a single event by its ID.
 func (r *Relay) processEventByID(ctx context.Context, eventID string) error {
 	ctx, cancel := context.WithTimeout(ctx, eventProcessTimeout)
@@ -166,13 +207,11 @@ func (r *Relay) processEventByID(ctx context.Context, eventID string) error {
 		defer func() { _ = tx.Rollback() }()
 
 		// Lock and fetch the event
-		var id, eventType string
-		var payload []byte
-		err = tx.QueryRowContext(ctx, `
-			SELECT id, event_type, payload
+		envelope, err := scanEnvelope(tx.QueryRowContext(ctx, `
+			SELECT `+outboxColumns+`
 			FROM outbox_events
 			WHERE id = $1 AND processed_at IS NULL
-			FOR UPDATE SKIP LOCKED`, eventID).Scan(&id, &eventType, &payload)
+			FOR UPDATE SKIP LOCKED`, eventID))
 
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -180,26 +219,22 @@ func (r *Relay) processEventByID(ctx context.Context, eventID string) error {
 		if err != nil {
 			return nil, err
 		}
+		id := envelope.ID
 
-		eventQueue := os.Getenv("BABY_QUEUE_NAME")
-		if eventType == eventQueue {
-			var evt ports.CreateBabyEvent
-			if err := json.Unmarshal(payload, &evt); err != nil {
-				log.Printf("outbox relay: invalid payload for event %s: %v", id, err)
-				// Mark as processed anyway to avoid infinite retries on bad data
-				_, _ = tx.ExecContext(ctx, `UPDATE outbox_events SET processed_at = NOW() WHERE id = $1`, id)
-				return nil, tx.Commit()
-			}
-
-			// Check if publisher is available before attempting to publish
-			if r.publisher == nil {
-				log.Printf("outbox relay: publisher not available, cannot process event %s", id)
-				return nil, nil // Don't mark as processed, will retry when publisher is available
-			}
-
-			if err := r.publisher.PublishBabyCreated(ctx, evt); err != nil {
-				return nil, err
-			}
+		switch err := r.publish(ctx, envelope); {
+		case errors.Is(err, errUnknownEventType):
+			log.Printf("outbox relay: unknown event type %q for event %s, leaving it for a newer relay", envelope.Type, id)
+			return nil, nil
+		case errors.Is(err, errInvalidPayload):
+			log.Printf("outbox relay: invalid payload for event %s: %v", id, err)
+			// Mark as processed anyway to avoid infinite retries on bad data
+			_, _ = tx.ExecContext(ctx, `UPDATE outbox_events SET processed_at = NOW() WHERE id = $1`, id)
+			return nil, tx.Commit()
+		case errors.Is(err, errPublisherUnavailable):
+			log.Printf("outbox relay: publisher not available, cannot process event %s", id)
+			return nil, nil // Don't mark as processed, will retry when publisher is available
+		case err != nil:
+			return nil, err
 		}
 
 		if _, err := tx.ExecContext(ctx, `UPDATE outbox_events SET processed_at = NOW() WHERE id = $1`, id); err != nil {
@@ -224,56 +259,43 @@ func (r *Relay) processUnprocessedEvents(ctx context.Context) error {
 		}
 		defer func() { _ = tx.Rollback() }()
 
+		// Unknown event types are skipped so they cannot crowd out the batch
 		rows, err := tx.QueryContext(ctx, `
-			SELECT id, event_type, payload
+			SELECT `+outboxColumns+`
 			FROM outbox_events
-			WHERE processed_at IS NULL
+			WHERE processed_at IS NULL AND event_type = ANY($2)
 			ORDER BY created_at
 			LIMIT $1
-			FOR UPDATE SKIP LOCKED`, maxEventsPerBatch)
+			FOR UPDATE SKIP LOCKED`, maxEventsPerBatch, pq.Array(r.events.Names()))
 		if err != nil {
 			return nil, err
 		}
 		defer rows.Close()
 
-		type record struct {
-			ID        string
-			EventType string
-			Payload   []byte
-		}
-
-		var records []record
+		var records []ports.EventEnvelope
 		for rows.Next() {
-			var r record
-			if err := rows.Scan(&r.ID, &r.EventType, &r.Payload); err != nil {
+			envelope, err := scanEnvelope(rows)
+			if err != nil {
 				return nil, err
 			}
-			records = append(records, r)
+			records = append(records, envelope)
 		}
 		if err := rows.Err(); err != nil {
 			return nil, err
 		}
 
 		for _, rec := range records {
-			eventQueue := os.Getenv("BABY_QUEUE_NAME")
-			if rec.EventType == eventQueue {
-				var evt ports.CreateBabyEvent
-				if err := json.Unmarshal(rec.Payload, &evt); err != nil {
-					log.Printf("outbox relay: invalid payload for event %s: %v", rec.ID, err)
-					_, _ = tx.ExecContext(ctx, `UPDATE outbox_events SET processed_at = NOW() WHERE id = $1`, rec.ID)
-					continue
-				}
-
-				// Check if publisher is available before attempting to publish
-				if r.publisher == nil {
-					log.Printf("outbox relay: publisher not available, skipping event %s", rec.ID)
-					continue // Don't mark as processed, will retry when publisher is available
-				}
-
-				if err := r.publisher.PublishBabyCreated(ctx, evt); err != nil {
-					log.Printf("outbox relay: failed to publish event %s: %v", rec.ID, err)
-					continue
-				}
+			switch err := r.publish(ctx, rec); {
+			case errors.Is(err, errInvalidPayload):
+				log.Printf("outbox relay: invalid payload for event %s: %v", rec.ID, err)
+				_, _ = tx.ExecContext(ctx, `UPDATE outbox_events SET processed_at = NOW() WHERE id = $1`, rec.ID)
+				continue
+			case errors.Is(err, errPublisherUnavailable):
+				log.Printf("outbox relay: publisher not available, skipping event %s", rec.ID)
+				continue // Don't mark as processed, will retry when publisher is available
+			case err != nil:
+				log.Printf("outbox relay: failed to publish event %s: %v", rec.ID, err)
+				continue
 			}
 
 			if _, err := tx.ExecContext(ctx, `UPDATE outbox_events SET processed_at = NOW() WHERE id = $1`, rec.ID); err != nil {
@@ -0,0 +1,95 @@
+package problem
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+)
+
+// localizedWriter carries the locale problems written to it are translated to
+type localizedWriter struct {
+	http.ResponseWriter
+	locale string
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer, e.g. to flush streamed responses
+func (lw *localizedWriter) Unwrap() http.ResponseWriter {
+	return lw.ResponseWriter
+}
+
+// WithLocale returns a writer whose problems are written in locale, falling
+// back to English for messages without a translation
+func WithLocale(w http.ResponseWriter, locale string) http.ResponseWriter {
+	return &localizedWriter{ResponseWriter: w, locale: locale}
+}
+
+// SetLocale switches the problems written to w, or a writer it wraps, to
+// locale, e.g. to the preferred locale in the caller's token. Unsupported
+// locales are ignored.
+func SetLocale(w http.ResponseWriter, locale string) {
+	locale, ok := domain.ParseLocale(locale)
+	if !ok {
+		return
+	}
+	if lw := findLocalizedWriter(w); lw != nil {
+		lw.locale = locale
+	}
+}
+
+func findLocalizedWriter(w http.ResponseWriter) *localizedWriter {
+	for {
+		switch v := w.(type) {
+		case *localizedWriter:
+			return v
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = v.Unwrap()
+		default:
+			return nil
+		}
+	}
+}
+
+func localeOf(w http.ResponseWriter) string {
+	if lw := findLocalizedWriter(w); lw != nil {
+		return lw.locale
+	}
+	return domain.DefaultLocale
+}
+
+// localize translates the title, detail and field messages of p. The fields
+// are copied, as validation errors are often shared package variables.
+func localize(p Details, locale string) Details {
+	catalog, ok := catalogs[locale]
+	if !ok {
+		return p
+	}
+	translate := func(message string) string {
+		if translated, ok := catalog[message]; ok {
+			return translated
+		}
+		return message
+	}
+
+	p.Title = translate(p.Title)
+	if len(p.Errors) == 0 {
+		p.Detail = translate(p.Detail)
+		return p
+	}
+
+	// A validation problem's detail lists its field messages
+	listsFields := p.Detail == (&domain.ValidationError{Fields: p.Errors}).Error()
+	fields := make([]domain.FieldError, len(p.Errors))
+	messages := make([]string, len(p.Errors))
+	for i, f := range p.Errors {
+		fields[i] = domain.FieldError{Field: f.Field, Message: translate(f.Message)}
+		messages[i] = fields[i].Message
+	}
+	p.Errors = fields
+	if listsFields {
+		p.Detail = strings.Join(messages, "; ")
+	} else {
+		p.Detail = translate(p.Detail)
+	}
+	return p
+}
@@ -0,0 +1,90 @@
+package problem
+
+import (
+	"net/http"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+)
+
+// catalogs translate problem titles, details and field messages, keyed by
+// locale and then by the English text. English needs no catalog; messages
+// missing from a catalog are written in English.
+var catalogs = map[string]map[string]string{
+	domain.LocaleDutch: {
+		// Titles
+		http.StatusText(http.StatusBadRequest):            "Ongeldig verzoek",
+		http.StatusText(http.StatusUnauthorized):          "Niet aangemeld",
+		http.StatusText(http.StatusForbidden):             "Geen toegang",
+		http.StatusText(http.StatusNotFound):              "Niet gevonden",
+		http.StatusText(http.StatusMethodNotAllowed):      "Methode niet toegestaan",
+		http.StatusText(http.StatusConflict):              "Conflict",
+		http.StatusText(http.StatusGone):                  "Verlopen",
+		http.StatusText(http.StatusPreconditionFailed):    "Versie komt niet overeen",
+		http.StatusText(http.StatusRequestEntityTooLarge): "Verzoek te groot",
+		http.StatusText(http.StatusUnsupportedMediaType):  "Niet-ondersteund mediatype",
+		http.StatusText(http.StatusPreconditionRequired):  "Versie vereist",
+		http.StatusText(http.StatusInternalServerError):   "Interne serverfout",
+		http.StatusText(http.StatusServiceUnavailable):    "Dienst niet beschikbaar",
+
+		// Authentication and requests
+		"missing authorization header":             "de Authorization-header ontbreekt",
+		"invalid token":                            "ongeldig token",
+		"invalid token claims":                     "ongeldige gegevens in het token",
+		"token revoked":                            "het token is ingetrokken",
+		"forbidden":                                "geen toegang",
+		"not allowed during impersonation":         "niet toegestaan tijdens het meekijken als deze gebruiker",
+		"authentication service unavailable":       "de aanmelddienst is niet beschikbaar",
+		"unknown tenant":                           "onbekende kliniek",
+		"Invalid request payload":                  "ongeldige inhoud van het verzoek",
+		"Method not allowed":                       "methode niet toegestaan",
+		"If-Match header is required":              "de If-Match-header is verplicht",
+		"If-Match must be the ETag of the user":    "If-Match moet de ETag van de gebruiker zijn",
+		"user has been modified; reload and retry": "de gebruiker is intussen gewijzigd; laad opnieuw en probeer het nog eens",
+		"the current user version is required":     "de huidige versie van de gebruiker is verplicht",
+
+		// Users and profiles
+		"user not found":                                       "gebruiker niet gevonden",
+		"user not registered":                                  "gebruiker is niet geregistreerd",
+		"email not verified":                                   "e-mailadres is niet geverifieerd",
+		"email is already in use":                              "dit e-mailadres is al in gebruik",
+		"email is required":                                    "e-mailadres is verplicht",
+		"email is not a valid address":                         "e-mailadres is geen geldig adres",
+		"first_name and last_name must be 1 to 100 characters": "voornaam en achternaam moeten 1 tot 100 tekens lang zijn",
+		"update contains no fields":                            "de wijziging bevat geen velden",
+		"display_name must be at most 100 characters":          "weergavenaam mag hoogstens 100 tekens lang zijn",
+		"contact_email is not a valid address":                 "contact-e-mailadres is geen geldig adres",
+		"locale must be one of en, nl":                         "taal moet en of nl zijn",
+		"this field is maintained by the clinic and cannot be changed on your profile": "dit veld wordt door de kliniek beheerd en kan niet in uw profiel worden gewijzigd",
+
+		// Parents, visitors and devices
+		"parent not found":                                        "ouder niet gevonden",
+		"parent is not admitted":                                  "ouder is niet opgenomen",
+		"parent has been discharged":                              "ouder is ontslagen",
+		"room_number is required":                                 "kamernummer is verplicht",
+		"room_number must be 1 to 20 characters":                  "kamernummer moet 1 tot 20 tekens lang zijn",
+		"room_number only applies to parents":                     "kamernummer geldt alleen voor ouders",
+		"only active parents can invite visitors":                 "alleen opgenomen ouders kunnen bezoekers uitnodigen",
+		"visitor not found":                                       "bezoeker niet gevonden",
+		"visitor access is revoked or expired":                    "de toegang van de bezoeker is ingetrokken of verlopen",
+		"visitor expiry must be in the future and within 30 days": "de toegang van een bezoeker moet binnen 30 dagen verlopen",
+		"invalid visitor code":                                    "ongeldige bezoekerscode",
+		"missing display_name":                                    "weergavenaam ontbreekt",
+		"invalid enrollment code":                                 "ongeldige koppelcode",
+		"enrollment code is invalid or expired":                   "de koppelcode is ongeldig of verlopen",
+		"parent cannot be enrolled":                               "dit apparaat kan niet aan de ouder worden gekoppeld",
+		"invalid device credential":                               "ongeldige apparaatsleutel",
+		"device credential is invalid or revoked":                 "de apparaatsleutel is ongeldig of ingetrokken",
+
+		// Invitations
+		"invitation link is invalid":                        "de uitnodigingslink is ongeldig",
+		"invitation has expired":                            "de uitnodiging is verlopen",
+		"invitation has already been accepted or cancelled": "de uitnodiging is al geaccepteerd of geannuleerd",
+		"signed-in email does not match the invitation":     "het aangemelde e-mailadres hoort niet bij de uitnodiging",
+
+		// Subject access
+		"subject access request not found":                              "inzageverzoek niet gevonden",
+		"a subject access request for this user is already in progress": "er loopt al een inzageverzoek voor deze gebruiker",
+		"subject access archive is not ready":                           "het inzagearchief is nog niet klaar",
+		"subject access archive has expired":                            "het inzagearchief is verlopen",
+	},
+}
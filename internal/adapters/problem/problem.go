@@ -0,0 +1,61 @@
+// Package problem writes error responses as RFC 7807 problem details
+// (application/problem+json), shared by the handlers and the middleware.
+package problem
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+)
+
+// ContentType is the media type of a problem details response
+const ContentType = "application/problem+json"
+
+// Details is a problem details object. Type is always about:blank, so Title
+// is the standard text of Status; Errors lists the rejected fields of a
+// validation problem.
+type Details struct {
+	Type   string              `json:"type"`
+	Title  string              `json:"title"`
+	Status int                 `json:"status"`
+	Detail string              `json:"detail,omitempty"`
+	Errors []domain.FieldError `json:"errors,omitempty"`
+}
+
+// New returns the problem for status with a human readable detail
+func New(status int, detail string) Details {
+	return Details{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// Error replies with a problem; it takes the arguments of http.Error
+func Error(w http.ResponseWriter, detail string, status int) {
+	Write(w, New(status, detail))
+}
+
+// Validation replies 400 with every rejected field of err
+func Validation(w http.ResponseWriter, err *domain.ValidationError) {
+	p := New(http.StatusBadRequest, err.Error())
+	p.Errors = err.Fields
+	Write(w, p)
+}
+
+// Write replies with p, translated to the locale of w (see WithLocale)
+func Write(w http.ResponseWriter, p Details) {
+	locale := localeOf(w)
+	p = localize(p, locale)
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("Content-Language", locale)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		log.Printf("Failed to encode problem: %v", err)
+	}
+}
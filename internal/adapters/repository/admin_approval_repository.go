@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/lib/pq"
+)
+
+const adminApprovalColumns = `id, action, status, COALESCE(email, ''), COALESCE(first_name, ''), COALESCE(last_name, ''),
+	COALESCE(target_user_id, ''), requested_by, created_at, expires_at, COALESCE(decided_by, ''), decided_at`
+
+func (r *SQLRepository) CreateAdminApproval(ctx context.Context, approval domain.AdminApproval) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		_, err = tx.ExecContext(ctx,
+			"UPDATE admin_approvals SET status = $1 WHERE tenant_id = $2 AND status = $3 AND expires_at <= $4",
+			domain.AdminApprovalExpired, tenant, domain.AdminApprovalPending, approval.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO admin_approvals (id, tenant_id, action, status, email, first_name, last_name, target_user_id,
+			requested_by, created_at, expires_at)
+			VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''), NULLIF($8, ''), $9, $10, $11)`,
+			approval.ID, tenant, approval.Action, approval.Status, approval.Email, approval.FirstName, approval.LastName,
+			approval.TargetUserID, approval.RequestedBy, approval.CreatedAt, approval.ExpiresAt,
+		)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+				return nil, domain.ErrAdminApprovalPending
+			}
+			return nil, err
+		}
+		return nil, tx.Commit()
+	})
+	return err
+}
+
+func (r *SQLRepository) GetAdminApproval(ctx context.Context, id string) (*domain.AdminApproval, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		approval, err := scanAdminApproval(r.db.QueryRowContext(ctx,
+			"SELECT "+adminApprovalColumns+" FROM admin_approvals WHERE tenant_id = $1 AND id = $2",
+			tenant, id,
+		))
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrAdminApprovalNotFound
+		}
+		return approval, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*domain.AdminApproval), nil
+}
+
+func (r *SQLRepository) ListAdminApprovals(ctx context.Context, status domain.AdminApprovalStatus) ([]domain.AdminApproval, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		rows, err := r.db.QueryContext(ctx,
+			"SELECT "+adminApprovalColumns+` FROM admin_approvals
+			WHERE tenant_id = $1 AND ($2 = '' OR status = $2)
+			ORDER BY created_at DESC, id`,
+			tenant, status,
+		)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		approvals := []domain.AdminApproval{}
+		for rows.Next() {
+			approval, err := scanAdminApproval(rows)
+			if err != nil {
+				return nil, err
+			}
+			approvals = append(approvals, *approval)
+		}
+		return approvals, rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]domain.AdminApproval), nil
+}
+
+func (r *SQLRepository) DecideAdminApproval(ctx context.Context, approval domain.AdminApproval) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		return nil, decideAdminApproval(ctx, r.db, tenant, approval)
+	})
+	return err
+}
+
+// ApproveAdminCreation records the approval, creates the admin and queues an
+// admin.registered event in one transaction
+func (r *SQLRepository) ApproveAdminCreation(ctx context.Context, approval domain.AdminApproval, admin domain.User, outboxPayload []byte) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if err := decideAdminApproval(ctx, tx, tenant, approval); err != nil {
+			return nil, err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO users (id, tenant_id, email, role, first_name, last_name, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+			admin.ID, tenant, admin.Email, admin.Role, admin.FirstName, admin.LastName, admin.CreatedAt,
+		)
+		if err != nil {
+			return nil, emailTakenOr(err)
+		}
+		if err := insertOutboxEvent(ctx, tx, tenant, "user", admin.ID, ports.EventAdminRegistered, outboxPayload); err != nil {
+			return nil, err
+		}
+		return nil, tx.Commit()
+	})
+	return err
+}
+
+// ApproveAdminRemoval records the approval and deactivates the admin in one
+// transaction, which fails with domain.ErrLastAdmin if no other admin is left
+func (r *SQLRepository) ApproveAdminRemoval(ctx context.Context, approval domain.AdminApproval, outboxPayload []byte) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if err := decideAdminApproval(ctx, tx, tenant, approval); err != nil {
+			return nil, err
+		}
+		if err := deactivateUser(ctx, tx, tenant, approval.TargetUserID, outboxPayload); err != nil {
+			return nil, err
+		}
+		return nil, tx.Commit()
+	})
+	return err
+}
+
+func (r *SQLRepository) CountActiveAdmins(ctx context.Context) (int, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		var count int
+		err := r.db.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM users WHERE tenant_id = $1 AND role = $2 AND deleted_at IS NULL",
+			tenant, domain.RoleAdmin,
+		).Scan(&count)
+		return count, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int), nil
+}
+
+// decideAdminApproval records the decision of a still pending approval; an
+// approval decided in the meantime fails with domain.ErrAdminApprovalClosed
+func decideAdminApproval(ctx context.Context, q querier, tenant string, approval domain.AdminApproval) error {
+	res, err := q.ExecContext(ctx,
+		`UPDATE admin_approvals SET status = $1, decided_by = NULLIF($2, ''), decided_at = $3
+		WHERE tenant_id = $4 AND id = $5 AND status = $6`,
+		approval.Status, approval.DecidedBy, approval.DecidedAt, tenant, approval.ID, domain.AdminApprovalPending,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrAdminApprovalClosed
+	}
+	return nil
+}
+
+func scanAdminApproval(row rowScanner) (*domain.AdminApproval, error) {
+	var approval domain.AdminApproval
+	var decidedAt sql.NullTime
+	if err := row.Scan(&approval.ID, &approval.Action, &approval.Status, &approval.Email, &approval.FirstName,
+		&approval.LastName, &approval.TargetUserID, &approval.RequestedBy, &approval.CreatedAt, &approval.ExpiresAt,
+		&approval.DecidedBy, &decidedAt); err != nil {
+		return nil, err
+	}
+	if decidedAt.Valid {
+		approval.DecidedAt = &decidedAt.Time
+	}
+	return &approval, nil
+}
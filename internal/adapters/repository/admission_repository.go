@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+)
+
+// GetAdmission returns the admission with its parents, oldest first. Deactivated parents are left out.
+func (r *SQLRepository) GetAdmission(ctx context.Context, id string) (*domain.Admission, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		admission := domain.Admission{ID: id}
+		err := r.db.QueryRowContext(ctx,
+			"SELECT created_at FROM admissions WHERE tenant_id = $1 AND id = $2",
+			tenant, id,
+		).Scan(&admission.CreatedAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrAdmissionNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		rows, err := r.db.QueryContext(ctx,
+			`SELECT u.id, u.email, u.role, u.first_name, u.last_name, u.created_at, p.room_number, p.status
+			FROM users u JOIN parents p ON p.user_id = u.id AND p.tenant_id = u.tenant_id
+			WHERE u.tenant_id = $1 AND p.admission_id = $2 AND u.deleted_at IS NULL
+			ORDER BY u.created_at, u.id`,
+			tenant, id,
+		)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		admission.Parents = []domain.Parent{}
+		for rows.Next() {
+			parent := domain.Parent{AdmissionID: id}
+			if err := rows.Scan(&parent.ID, &parent.Email, &parent.Role, &parent.FirstName, &parent.LastName,
+				&parent.CreatedAt, &parent.RoomNumber, &parent.Status); err != nil {
+				return nil, err
+			}
+			admission.Parents = append(admission.Parents, parent)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+
+		admission.RoomNumber = admission.CurrentRoom()
+		return &admission, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*domain.Admission), nil
+}
@@ -0,0 +1,324 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/lib/pq"
+)
+
+// uniqueViolation is the PostgreSQL error code for a unique constraint violation
+const uniqueViolation = "23505"
+
+// emailTakenOr maps a unique violation on a users insert or update to
+// domain.ErrEmailTaken; the email is the only unique user column a caller sets
+func emailTakenOr(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return domain.ErrEmailTaken
+	}
+	return err
+}
+
+const userRecordQuery = `SELECT u.id, u.email, u.role, u.first_name, u.last_name, u.created_at,
+	COALESCE(u.display_name, ''), COALESCE(u.locale, ''), COALESCE(u.contact_email, ''), u.version, p.room_number, p.status
+	FROM users u LEFT JOIN parents p ON p.user_id = u.id AND p.tenant_id = u.tenant_id`
+
+// likeEscaper escapes LIKE wildcards so search text is matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (r *SQLRepository) ListUsers(ctx context.Context, filter domain.UserFilter) ([]domain.UserRecord, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query, args := userListQuery(tenant, filter)
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		rows, err := r.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		records := []domain.UserRecord{}
+		for rows.Next() {
+			record, err := scanUserRecord(rows)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, *record)
+		}
+		return records, rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]domain.UserRecord), nil
+}
+
+// StreamUsers calls fn for every user matching the filter, newest first, reading
+// rows from the connection as fn consumes them. Only opening the query goes
+// through the circuit breaker, so a slow or aborted consumer does not trip it.
+func (r *SQLRepository) StreamUsers(ctx context.Context, filter domain.UserFilter, fn func(domain.UserRecord) error) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+	query, args := userListQuery(tenant, filter)
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		return r.db.QueryContext(ctx, query, args...)
+	})
+	if err != nil {
+		return err
+	}
+	rows := result.(*sql.Rows)
+	defer rows.Close()
+
+	for rows.Next() {
+		record, err := scanUserRecord(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(*record); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// userListQuery builds the directory query for the filter; a zero Limit returns every match
+func userListQuery(tenant string, filter domain.UserFilter) (string, []any) {
+	conditions := []string{"u.tenant_id = $1", "u.deleted_at IS NULL"}
+	args := []any{tenant}
+	addCondition := func(format string, values ...any) {
+		placeholders := make([]any, len(values))
+		for i, v := range values {
+			args = append(args, v)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		conditions = append(conditions, fmt.Sprintf(format, placeholders...))
+	}
+
+	if filter.Role != "" {
+		addCondition("u.role = %s", filter.Role)
+	}
+	if filter.ParentStatus != "" {
+		addCondition("p.status = %s", filter.ParentStatus)
+	}
+	if filter.RoomNumber != "" {
+		addCondition("p.room_number = %s", filter.RoomNumber)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		addCondition("u.created_at >= %s", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		addCondition("u.created_at < %s", filter.CreatedTo)
+	}
+	if filter.Search != "" {
+		pattern := "%" + likeEscaper.Replace(filter.Search) + "%"
+		addCondition("(u.email ILIKE %[1]s OR u.first_name ILIKE %[1]s OR u.last_name ILIKE %[1]s OR (u.first_name || ' ' || u.last_name) ILIKE %[1]s)", pattern)
+	}
+	if filter.After != nil {
+		addCondition("(u.created_at, u.id) < (%s, %s)", filter.After.CreatedAt, filter.After.ID)
+	}
+
+	query := fmt.Sprintf("%s WHERE %s ORDER BY u.created_at DESC, u.id DESC",
+		userRecordQuery, strings.Join(conditions, " AND "))
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	return query, args
+}
+
+func (r *SQLRepository) GetUserRecord(ctx context.Context, id string) (*domain.UserRecord, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		return scanUserRecord(r.db.QueryRowContext(ctx,
+			userRecordQuery+" WHERE u.tenant_id = $1 AND u.id = $2 AND u.deleted_at IS NULL",
+			tenant, id,
+		))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*domain.UserRecord), nil
+}
+
+// UpdateUser writes the record's names, email and profile if its version is
+// still expectedVersion, bumping the version and queueing outboxPayload atomically.
+// A new email is also written to the user's pending invitation. The room is not
+// written here: it only changes through TransferParent.
+func (r *SQLRepository) UpdateUser(ctx context.Context, record domain.UserRecord, expectedVersion int, outboxPayload []byte) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		res, err := tx.ExecContext(ctx,
+			`UPDATE users SET email = $1, first_name = $2, last_name = $3, display_name = NULLIF($4, ''),
+			locale = NULLIF($5, ''), contact_email = NULLIF($6, ''), version = version + 1
+			WHERE tenant_id = $7 AND id = $8 AND version = $9 AND deleted_at IS NULL`,
+			record.Email, record.FirstName, record.LastName, record.DisplayName, record.Locale, record.ContactEmail,
+			tenant, record.ID, expectedVersion,
+		)
+		if err != nil {
+			return nil, emailTakenOr(err)
+		}
+		if err := requireVersionMatch(ctx, tx, res, tenant, record.ID); err != nil {
+			return nil, err
+		}
+
+		// A corrected email moves the pending invitation along; links mailed to the old address stop working
+		_, err = tx.ExecContext(ctx,
+			`UPDATE invitations SET email = $1, sends = sends + 1
+			WHERE tenant_id = $2 AND user_id = $3 AND status = $4 AND email <> $1`,
+			record.Email, tenant, record.ID, domain.InvitationPending,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := insertOutboxEvent(ctx, tx, tenant, "user", record.ID, ports.EventUserUpdated, outboxPayload); err != nil {
+			return nil, err
+		}
+		return nil, tx.Commit()
+	})
+	return err
+}
+
+// DeactivateUser soft-deletes a user; deactivated users can no longer sign in
+// and disappear from the directory, but their rows are kept.
+func (r *SQLRepository) DeactivateUser(ctx context.Context, id string, outboxPayload []byte) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if err := deactivateUser(ctx, tx, tenant, id, outboxPayload); err != nil {
+			return nil, err
+		}
+		return nil, tx.Commit()
+	})
+	return err
+}
+
+// deactivateUser soft-deletes the user with its user.deactivated event,
+// unless they are the clinic's last active admin
+func deactivateUser(ctx context.Context, tx *sql.Tx, tenant, id string, outboxPayload []byte) error {
+	if err := requireAnotherAdmin(ctx, tx, tenant, id); err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx,
+		"UPDATE users SET deleted_at = NOW(), version = version + 1 WHERE tenant_id = $1 AND id = $2 AND deleted_at IS NULL",
+		tenant, id,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return insertOutboxEvent(ctx, tx, tenant, "user", id, ports.EventUserDeactivated, outboxPayload)
+}
+
+// requireAnotherAdmin fails with domain.ErrLastAdmin if userID is the clinic's
+// only active admin. The admins stay locked until the transaction ends, so
+// concurrent removals of two admins cannot both pass.
+func requireAnotherAdmin(ctx context.Context, tx *sql.Tx, tenant, userID string) error {
+	rows, err := tx.QueryContext(ctx,
+		"SELECT id FROM users WHERE tenant_id = $1 AND role = $2 AND deleted_at IS NULL ORDER BY id FOR UPDATE",
+		tenant, domain.RoleAdmin,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	admins, isAdmin := 0, false
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		admins++
+		isAdmin = isAdmin || id == userID
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if isAdmin && admins == 1 {
+		return domain.ErrLastAdmin
+	}
+	return nil
+}
+
+// requireVersionMatch tells a missing user (sql.ErrNoRows) apart from a stale
+// version (domain.ErrVersionConflict) when a versioned update matched no rows
+func requireVersionMatch(ctx context.Context, tx *sql.Tx, res sql.Result, tenant, id string) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected > 0 {
+		return nil
+	}
+
+	var version int
+	err = tx.QueryRowContext(ctx,
+		"SELECT version FROM users WHERE tenant_id = $1 AND id = $2 AND deleted_at IS NULL",
+		tenant, id,
+	).Scan(&version)
+	if err != nil {
+		return err
+	}
+	return domain.ErrVersionConflict
+}
+
+func scanUserRecord(row rowScanner) (*domain.UserRecord, error) {
+	var record domain.UserRecord
+	var roomNumber, status sql.NullString
+	err := row.Scan(&record.ID, &record.Email, &record.Role, &record.FirstName, &record.LastName, &record.CreatedAt,
+		&record.DisplayName, &record.Locale, &record.ContactEmail, &record.Version, &roomNumber, &status)
+	if err != nil {
+		return nil, err
+	}
+	if status.Valid {
+		record.Parent = &domain.ParentRecord{
+			RoomNumber: roomNumber.String,
+			Status:     domain.ParentStatus(status.String),
+		}
+	}
+	return &record, nil
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+func (r *SQLRepository) FindErasableUser(ctx context.Context, id string) (*domain.User, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		user, err := scanUser(r.db.QueryRowContext(ctx,
+			"SELECT "+userColumns+" FROM users WHERE tenant_id = $1 AND id = $2 AND erased_at IS NULL",
+			tenant, id,
+		))
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNothingToErase
+		}
+		return user, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*domain.User), nil
+}
+
+// EraseUser pseudonymizes the user (deactivating them if they were still
+// active, unless they are the last active admin), archives a parent and their visitors, blanks free-text reasons in
+// the parent's history, drops subject access archives and fails open requests, scrubs personal data from every
+// stored event about the user, and records the tombstone and user.erased event, all in one transaction.
+func (r *SQLRepository) EraseUser(ctx context.Context, tombstone domain.ErasureTombstone, outboxPayload []byte) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if err := requireAnotherAdmin(ctx, tx, tenant, tombstone.UserID); err != nil {
+			return nil, err
+		}
+
+		// Approvals to create an admin hold the email and names, not the user's id
+		_, err = tx.ExecContext(ctx,
+			`UPDATE admin_approvals SET email = $3, first_name = $4, last_name = $4
+			WHERE tenant_id = $1 AND LOWER(email) = (SELECT LOWER(email) FROM users WHERE tenant_id = $1 AND id = $2)`,
+			tenant, tombstone.UserID, domain.ErasedEmail(tombstone.UserID), domain.ErasedValue,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := tx.ExecContext(ctx,
+			`UPDATE users SET email = $1, first_name = $2, last_name = $2, display_name = NULL, contact_email = NULL,
+			erased_at = $3, deleted_at = COALESCE(deleted_at, $3), version = version + 1
+			WHERE tenant_id = $4 AND id = $5 AND erased_at IS NULL`,
+			domain.ErasedEmail(tombstone.UserID), domain.ErasedValue, tombstone.ErasedAt, tenant, tombstone.UserID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if affected == 0 {
+			return nil, domain.ErrNothingToErase
+		}
+
+		statements := []struct {
+			query string
+			args  []any
+		}{
+			{"UPDATE parents SET status = $3 WHERE tenant_id = $1 AND user_id = $2",
+				[]any{tenant, tombstone.UserID, domain.ParentArchived}},
+			{"UPDATE visitors SET display_name = $3, email = NULL, revoked_at = COALESCE(revoked_at, $4) WHERE tenant_id = $1 AND parent_id = $2",
+				[]any{tenant, tombstone.UserID, domain.ErasedValue, tombstone.ErasedAt}},
+			{"UPDATE invitations SET email = $3 WHERE tenant_id = $1 AND user_id = $2",
+				[]any{tenant, tombstone.UserID, domain.ErasedEmail(tombstone.UserID)}},
+			{"UPDATE parent_status_history SET reason = '' WHERE tenant_id = $1 AND parent_id = $2",
+				[]any{tenant, tombstone.UserID}},
+			{"UPDATE parent_room_history SET reason = '' WHERE tenant_id = $1 AND parent_id = $2",
+				[]any{tenant, tombstone.UserID}},
+			{`UPDATE subject_access_requests SET archive = NULL,
+				status = CASE WHEN status IN ($3, $4) THEN $5 ELSE status END,
+				error = CASE WHEN status IN ($3, $4) THEN $6 ELSE error END,
+				completed_at = COALESCE(completed_at, $7)
+				WHERE tenant_id = $1 AND user_id = $2`,
+				[]any{tenant, tombstone.UserID, domain.SubjectAccessPending, domain.SubjectAccessRunning,
+					domain.SubjectAccessFailed, "the user was erased", tombstone.ErasedAt}},
+		}
+		for _, statement := range statements {
+			if _, err := tx.ExecContext(ctx, statement.query, statement.args...); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := scrubOutboxEvents(ctx, tx, tenant, tombstone.UserID); err != nil {
+			return nil, err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO erasure_tombstones (id, tenant_id, user_id, role, requested_by, erased_at)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			tombstone.ID, tenant, tombstone.UserID, tombstone.Role, tombstone.RequestedBy, tombstone.ErasedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := insertOutboxEvent(ctx, tx, tenant, "user", tombstone.UserID, ports.EventUserErased, outboxPayload); err != nil {
+			return nil, err
+		}
+		return nil, tx.Commit()
+	})
+	return err
+}
+
+// scrubOutboxEvents rewrites the payload of every stored event about the user,
+// processed or not, without its personal data
+func scrubOutboxEvents(ctx context.Context, tx *sql.Tx, tenant, userID string) error {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id, payload FROM outbox_events
+		WHERE tenant_id = $1 AND (aggregate_id = $2 OR payload->>'user_id' = $2)
+		FOR UPDATE`,
+		tenant, userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	scrubbed := make(map[string][]byte)
+	for rows.Next() {
+		var id string
+		var payload []byte
+		if err := rows.Scan(&id, &payload); err != nil {
+			rows.Close()
+			return err
+		}
+		if scrubbed[id], err = domain.ScrubPersonalData(payload); err != nil {
+			rows.Close()
+			return err
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, payload := range scrubbed {
+		if _, err := tx.ExecContext(ctx, "UPDATE outbox_events SET payload = $1 WHERE id = $2", payload, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+)
+
+const invitationColumns = `id, user_id, email, status, sends, created_at, last_sent_at, expires_at, accepted_at, cancelled_at`
+
+func (r *SQLRepository) CreateParentInvitation(ctx context.Context, parent domain.Parent, invitation domain.Invitation, outboxPayload []byte) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if err := insertParentInvitation(ctx, tx, tenant, parent, invitation, outboxPayload); err != nil {
+			return nil, err
+		}
+
+		return nil, tx.Commit()
+	})
+	return err
+}
+
+func (r *SQLRepository) CreateParentInvitations(ctx context.Context, parents []domain.Parent, invitations []domain.Invitation, outboxPayloads [][]byte) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		for i, parent := range parents {
+			// An email registered since the rows were validated fails the whole batch
+			if err := insertParentInvitation(ctx, tx, tenant, parent, invitations[i], outboxPayloads[i]); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, tx.Commit()
+	})
+	return err
+}
+
+// insertParentInvitation writes the pending parent, their registration event and their invitation
+func insertParentInvitation(ctx context.Context, tx *sql.Tx, tenant string, parent domain.Parent, invitation domain.Invitation, outboxPayload []byte) error {
+	if err := insertParent(ctx, tx, tenant, parent, outboxPayload); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO invitations (id, tenant_id, user_id, email, status, sends, created_at, last_sent_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		invitation.ID, tenant, invitation.UserID, invitation.Email, invitation.Status, invitation.Sends,
+		invitation.CreatedAt, invitation.LastSentAt, invitation.ExpiresAt,
+	)
+	return err
+}
+
+func (r *SQLRepository) GetInvitation(ctx context.Context, id string) (*domain.Invitation, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		invitation, err := scanInvitation(r.db.QueryRowContext(ctx,
+			"SELECT "+invitationColumns+" FROM invitations WHERE tenant_id = $1 AND id = $2",
+			tenant, id,
+		))
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvitationNotFound
+		}
+		return invitation, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*domain.Invitation), nil
+}
+
+func (r *SQLRepository) FindPendingInvitation(ctx context.Context, userID string) (*domain.Invitation, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		invitation, err := scanInvitation(r.db.QueryRowContext(ctx,
+			"SELECT "+invitationColumns+" FROM invitations WHERE tenant_id = $1 AND user_id = $2 AND status = $3",
+			tenant, userID, domain.InvitationPending,
+		))
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvitationNotFound
+		}
+		return invitation, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*domain.Invitation), nil
+}
+
+func (r *SQLRepository) ListInvitations(ctx context.Context, status domain.InvitationStatus) ([]domain.Invitation, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		rows, err := r.db.QueryContext(ctx,
+			"SELECT "+invitationColumns+` FROM invitations
+			WHERE tenant_id = $1 AND ($2 = '' OR status = $2)
+			ORDER BY created_at DESC, id`,
+			tenant, status,
+		)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		invitations := []domain.Invitation{}
+		for rows.Next() {
+			invitation, err := scanInvitation(rows)
+			if err != nil {
+				return nil, err
+			}
+			invitations = append(invitations, *invitation)
+		}
+		return invitations, rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]domain.Invitation), nil
+}
+
+func (r *SQLRepository) UpdateInvitation(ctx context.Context, invitation domain.Invitation) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		res, err := r.db.ExecContext(ctx,
+			`UPDATE invitations SET status = $1, sends = $2, last_sent_at = $3, expires_at = $4, accepted_at = $5, cancelled_at = $6
+			WHERE tenant_id = $7 AND id = $8`,
+			invitation.Status, invitation.Sends, invitation.LastSentAt, invitation.ExpiresAt,
+			invitation.AcceptedAt, invitation.CancelledAt, tenant, invitation.ID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if affected == 0 {
+			return nil, domain.ErrInvitationNotFound
+		}
+		return nil, nil
+	})
+	return err
+}
+
+func scanInvitation(row rowScanner) (*domain.Invitation, error) {
+	var invitation domain.Invitation
+	var acceptedAt, cancelledAt sql.NullTime
+	if err := row.Scan(&invitation.ID, &invitation.UserID, &invitation.Email, &invitation.Status, &invitation.Sends,
+		&invitation.CreatedAt, &invitation.LastSentAt, &invitation.ExpiresAt, &acceptedAt, &cancelledAt); err != nil {
+		return nil, err
+	}
+	if acceptedAt.Valid {
+		invitation.AcceptedAt = &acceptedAt.Time
+	}
+	if cancelledAt.Valid {
+		invitation.CancelledAt = &cancelledAt.Time
+	}
+	return &invitation, nil
+}
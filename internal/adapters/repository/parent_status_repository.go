@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+// ChangeParentStatus moves a parent from change.FromStatus to change.ToStatus,
+// records the transition in parent_status_history and queues its events, such
+// as parent.status_changed, in one transaction. domain.ErrVersionConflict is
+// returned when the parent is no longer in change.FromStatus.
+func (r *SQLRepository) ChangeParentStatus(ctx context.Context, change domain.ParentStatusChange, events []ports.OutboxEvent) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		res, err := tx.ExecContext(ctx,
+			"UPDATE parents SET status = $1 WHERE tenant_id = $2 AND user_id = $3 AND status = $4",
+			change.ToStatus, tenant, change.ParentID, change.FromStatus,
+		)
+		if err != nil {
+			return nil, err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if affected == 0 {
+			return nil, domain.ErrVersionConflict
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"UPDATE users SET version = version + 1 WHERE tenant_id = $1 AND id = $2",
+			tenant, change.ParentID,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO parent_status_history (id, tenant_id, parent_id, from_status, to_status, reason, changed_by, changed_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			change.ID, tenant, change.ParentID, change.FromStatus, change.ToStatus,
+			change.Reason, change.ChangedBy, change.ChangedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range events {
+			if err := insertOutboxEvent(ctx, tx, tenant, "parent", change.ParentID, event.Type, event.Payload); err != nil {
+				return nil, err
+			}
+		}
+		return nil, tx.Commit()
+	})
+	return err
+}
+
+func (r *SQLRepository) ListParentStatusHistory(ctx context.Context, parentID string) ([]domain.ParentStatusChange, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		rows, err := r.db.QueryContext(ctx,
+			`SELECT id, parent_id, from_status, to_status, reason, changed_by, changed_at
+			FROM parent_status_history WHERE tenant_id = $1 AND parent_id = $2 ORDER BY changed_at`,
+			tenant, parentID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		changes := []domain.ParentStatusChange{}
+		for rows.Next() {
+			var change domain.ParentStatusChange
+			if err := rows.Scan(&change.ID, &change.ParentID, &change.FromStatus, &change.ToStatus,
+				&change.Reason, &change.ChangedBy, &change.ChangedAt); err != nil {
+				return nil, err
+			}
+			changes = append(changes, change)
+		}
+		return changes, rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]domain.ParentStatusChange), nil
+}
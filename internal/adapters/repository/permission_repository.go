@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+var _ ports.PermissionRepository = (*SQLRepository)(nil)
+
+func (r *SQLRepository) ListRoles(ctx context.Context) ([]domain.RoleDefinition, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		rows, err := r.db.QueryContext(ctx,
+			`SELECT r.name, r.description, r.ward_scoped, r.version, r.created_at, COALESCE(rp.permission, '')
+			FROM roles r LEFT JOIN role_permissions rp ON rp.tenant_id = r.tenant_id AND rp.role = r.name
+			WHERE r.tenant_id = $1
+			ORDER BY r.name, rp.permission`,
+			tenant,
+		)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		roles := []domain.RoleDefinition{}
+		for rows.Next() {
+			var role domain.RoleDefinition
+			var permission domain.Permission
+			if err := rows.Scan(&role.Name, &role.Description, &role.WardScoped, &role.Version, &role.CreatedAt, &permission); err != nil {
+				return nil, err
+			}
+
+			if n := len(roles); n == 0 || roles[n-1].Name != role.Name {
+				role.Permissions = []domain.Permission{}
+				roles = append(roles, role)
+			}
+			if permission != "" {
+				last := &roles[len(roles)-1]
+				last.Permissions = append(last.Permissions, permission)
+			}
+		}
+		return roles, rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]domain.RoleDefinition), nil
+}
+
+func (r *SQLRepository) GetRole(ctx context.Context, name domain.Role) (*domain.RoleDefinition, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		return getRole(ctx, r.db, tenant, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*domain.RoleDefinition), nil
+}
+
+func (r *SQLRepository) CreateRole(ctx context.Context, role domain.RoleDefinition) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO roles (tenant_id, name, description, ward_scoped, version, created_at) VALUES ($1, $2, $3, $4, 1, $5)",
+			tenant, role.Name, role.Description, role.WardScoped, role.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, permission := range role.Permissions {
+			if _, err := tx.ExecContext(ctx,
+				"INSERT INTO role_permissions (tenant_id, role, permission) VALUES ($1, $2, $3)",
+				tenant, role.Name, permission,
+			); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, tx.Commit()
+	})
+	return err
+}
+
+func (r *SQLRepository) SetRolePermissions(ctx context.Context, name domain.Role, permissions []domain.Permission) (*domain.RoleDefinition, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		// Bumping the version locks the role row and fails if it does not exist
+		res, err := tx.ExecContext(ctx, "UPDATE roles SET version = version + 1 WHERE tenant_id = $1 AND name = $2", tenant, name)
+		if err != nil {
+			return nil, err
+		}
+		if affected, err := res.RowsAffected(); err != nil {
+			return nil, err
+		} else if affected == 0 {
+			return nil, sql.ErrNoRows
+		}
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM role_permissions WHERE tenant_id = $1 AND role = $2", tenant, name); err != nil {
+			return nil, err
+		}
+
+		for _, permission := range permissions {
+			if _, err := tx.ExecContext(ctx,
+				"INSERT INTO role_permissions (tenant_id, role, permission) VALUES ($1, $2, $3)",
+				tenant, name, permission,
+			); err != nil {
+				return nil, err
+			}
+		}
+
+		role, err := getRole(ctx, tx, tenant, name)
+		if err != nil {
+			return nil, err
+		}
+
+		return role, tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*domain.RoleDefinition), nil
+}
+
+func (r *SQLRepository) ListPermissions(ctx context.Context) ([]domain.PermissionDefinition, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		rows, err := r.db.QueryContext(ctx, "SELECT name, description FROM permissions WHERE tenant_id = $1 ORDER BY name", tenant)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		permissions := []domain.PermissionDefinition{}
+		for rows.Next() {
+			var permission domain.PermissionDefinition
+			if err := rows.Scan(&permission.Name, &permission.Description); err != nil {
+				return nil, err
+			}
+			permissions = append(permissions, permission)
+		}
+		return permissions, rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]domain.PermissionDefinition), nil
+}
+
+func (r *SQLRepository) CreatePermission(ctx context.Context, permission domain.PermissionDefinition) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		_, err := r.db.ExecContext(ctx,
+			"INSERT INTO permissions (tenant_id, name, description) VALUES ($1, $2, $3)",
+			tenant, permission.Name, permission.Description,
+		)
+		return nil, err
+	})
+	return err
+}
+
+// SeedTenant gives the context's tenant the default roles, permissions and
+// authorization policies (see seed_tenant in the database init script),
+// keeping whatever the clinic already has.
+func (r *SQLRepository) SeedTenant(ctx context.Context) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		return r.db.ExecContext(ctx, "SELECT seed_tenant($1)", tenant)
+	})
+	return err
+}
+
+// querier is satisfied by both *sql.DB and *sql.Tx
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func getRole(ctx context.Context, q querier, tenant string, name domain.Role) (*domain.RoleDefinition, error) {
+	var role domain.RoleDefinition
+	err := q.QueryRowContext(ctx,
+		"SELECT name, description, ward_scoped, version, created_at FROM roles WHERE tenant_id = $1 AND name = $2",
+		tenant, name,
+	).Scan(&role.Name, &role.Description, &role.WardScoped, &role.Version, &role.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.QueryContext(ctx,
+		"SELECT permission FROM role_permissions WHERE tenant_id = $1 AND role = $2 ORDER BY permission",
+		tenant, name,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	role.Permissions = []domain.Permission{}
+	for rows.Next() {
+		var permission domain.Permission
+		if err := rows.Scan(&permission); err != nil {
+			return nil, err
+		}
+		role.Permissions = append(role.Permissions, permission)
+	}
+	return &role, rows.Err()
+}
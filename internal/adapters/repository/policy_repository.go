@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+var _ ports.PolicyRepository = (*SQLRepository)(nil)
+
+const policyColumns = "id, description, action, effect, subject_role, conditions, created_at"
+
+func (r *SQLRepository) ListPolicies(ctx context.Context) ([]domain.Policy, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.queryPolicies(ctx, "SELECT "+policyColumns+" FROM authz_policies WHERE tenant_id = $1 ORDER BY created_at", tenant)
+}
+
+// ListPoliciesForAction returns the policies for the action plus wildcard policies
+func (r *SQLRepository) ListPoliciesForAction(ctx context.Context, action string) ([]domain.Policy, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.queryPolicies(ctx,
+		"SELECT "+policyColumns+" FROM authz_policies WHERE tenant_id = $1 AND (action = $2 OR action = $3) ORDER BY created_at",
+		tenant, action, domain.AnyAction,
+	)
+}
+
+func (r *SQLRepository) CreatePolicy(ctx context.Context, policy domain.Policy) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	conditions, err := json.Marshal(policy.Conditions)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		return r.db.ExecContext(ctx,
+			"INSERT INTO authz_policies (tenant_id, "+policyColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+			tenant, policy.ID, policy.Description, policy.Action, policy.Effect, nullString(string(policy.SubjectRole)), conditions, policy.CreatedAt,
+		)
+	})
+	return err
+}
+
+func (r *SQLRepository) DeletePolicy(ctx context.Context, id string) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		res, err := r.db.ExecContext(ctx, "DELETE FROM authz_policies WHERE tenant_id = $1 AND id = $2", tenant, id)
+		if err != nil {
+			return nil, err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return nil, sql.ErrNoRows
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// RecordDecisions appends a batch of decisions to the decision log in one transaction
+func (r *SQLRepository) RecordDecisions(ctx context.Context, entries []domain.DecisionLogEntry) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		for _, entry := range entries {
+			resource, err := json.Marshal(entry.Request.Resource)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO authz_decisions (id, tenant_id, subject, action, resource, allowed, reason, policy_id, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+				entry.ID, tenant, entry.Request.Subject, entry.Request.Action, resource,
+				entry.Decision.Allowed, entry.Decision.Reason, nullString(entry.Decision.PolicyID), entry.CreatedAt,
+			); err != nil {
+				return nil, err
+			}
+		}
+		return nil, tx.Commit()
+	})
+	return err
+}
+
+func (r *SQLRepository) queryPolicies(ctx context.Context, query string, args ...any) ([]domain.Policy, error) {
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		rows, err := r.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		policies := []domain.Policy{}
+		for rows.Next() {
+			var policy domain.Policy
+			var subjectRole sql.NullString
+			var conditions []byte
+			if err := rows.Scan(&policy.ID, &policy.Description, &policy.Action, &policy.Effect,
+				&subjectRole, &conditions, &policy.CreatedAt); err != nil {
+				return nil, err
+			}
+			policy.SubjectRole = domain.Role(subjectRole.String)
+			if err := json.Unmarshal(conditions, &policy.Conditions); err != nil {
+				return nil, err
+			}
+			policies = append(policies, policy)
+		}
+		return policies, rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]domain.Policy), nil
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+)
+
+// retentionLockKey identifies the retention job's PostgreSQL advisory lock
+const retentionLockKey = 4172001
+
+// parentsDischargedBefore selects parents whose last discharge is before the
+// cutoff ($2). Parents discharged before status history was kept have no
+// discharge date and are never selected.
+const parentsDischargedBefore = `FROM parents p JOIN users u ON u.id = p.user_id AND u.tenant_id = p.tenant_id
+	WHERE p.tenant_id = $1 AND p.status IN ($3, $4) AND u.erased_at IS NULL
+	AND (SELECT MAX(h.changed_at) FROM parent_status_history h
+		WHERE h.tenant_id = p.tenant_id AND h.parent_id = p.user_id AND h.to_status = $3) < $2`
+
+// AcquireRetentionLock holds a session-level advisory lock on a dedicated
+// connection, so it is released as well if the replica dies
+func (r *SQLRepository) AcquireRetentionLock(ctx context.Context) (func(), bool, error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", retentionLockKey).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return nil, false, err
+	}
+	if !acquired {
+		_ = conn.Close()
+		return nil, false, nil
+	}
+
+	release := func() {
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", retentionLockKey)
+		_ = conn.Close()
+	}
+	return release, true, nil
+}
+
+func (r *SQLRepository) ListParentsDischargedBefore(ctx context.Context, cutoff time.Time, limit int) ([]string, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		rows, err := r.db.QueryContext(ctx,
+			"SELECT p.user_id "+parentsDischargedBefore+" ORDER BY p.user_id LIMIT $5",
+			tenant, cutoff, domain.ParentDischarged, domain.ParentArchived, limit,
+		)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		var ids []string
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+		return ids, rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]string), nil
+}
+
+func (r *SQLRepository) CountParentsDischargedBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		var count int
+		err := r.db.QueryRowContext(ctx,
+			"SELECT COUNT(*) "+parentsDischargedBefore,
+			tenant, cutoff, domain.ParentDischarged, domain.ParentArchived,
+		).Scan(&count)
+		return count, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int), nil
+}
+
+// DeleteProcessedOutboxEvents skips rows locked by another transaction, such
+// as the relay's, instead of waiting for them
+func (r *SQLRepository) DeleteProcessedOutboxEvents(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		res, err := r.db.ExecContext(ctx,
+			`DELETE FROM outbox_events WHERE id IN (
+				SELECT id FROM outbox_events
+				WHERE tenant_id = $1 AND processed_at IS NOT NULL AND processed_at < $2
+				ORDER BY processed_at LIMIT $3
+				FOR UPDATE SKIP LOCKED)`,
+			tenant, cutoff, limit,
+		)
+		if err != nil {
+			return nil, err
+		}
+		deleted, err := res.RowsAffected()
+		return int(deleted), err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int), nil
+}
+
+func (r *SQLRepository) CountProcessedOutboxEvents(ctx context.Context, cutoff time.Time) (int, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		var count int
+		err := r.db.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM outbox_events WHERE tenant_id = $1 AND processed_at IS NOT NULL AND processed_at < $2",
+			tenant, cutoff,
+		).Scan(&count)
+		return count, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int), nil
+}
+
+// PurgeExpiredSubjectAccessArchives keeps the request, so its status stays
+// readable, and drops the archive
+func (r *SQLRepository) PurgeExpiredSubjectAccessArchives(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		res, err := r.db.ExecContext(ctx,
+			`UPDATE subject_access_requests SET archive = NULL WHERE id IN (
+				SELECT id FROM subject_access_requests
+				WHERE tenant_id = $1 AND archive IS NOT NULL AND expires_at <= $2
+				ORDER BY expires_at LIMIT $3
+				FOR UPDATE SKIP LOCKED)`,
+			tenant, cutoff, limit,
+		)
+		if err != nil {
+			return nil, err
+		}
+		purged, err := res.RowsAffected()
+		return int(purged), err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int), nil
+}
+
+func (r *SQLRepository) CountExpiredSubjectAccessArchives(ctx context.Context, cutoff time.Time) (int, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		var count int
+		err := r.db.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM subject_access_requests WHERE tenant_id = $1 AND archive IS NOT NULL AND expires_at <= $2",
+			tenant, cutoff,
+		).Scan(&count)
+		return count, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int), nil
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	_ "embed"
+)
+
+// migrationLockKey identifies the PostgreSQL advisory lock held while the schema is applied
+const migrationLockKey = 4172002
+
+//go:embed schema.sql
+var schema string
+
+// Migrate brings the database up to the current schema. The script only adds
+// what is missing, so it runs on every startup; replicas starting together
+// apply it one at a time.
+func (r *SQLRepository) Migrate(ctx context.Context) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", migrationLockKey); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, schema); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,485 @@
+-- Schema of the identity access service, applied by the API at startup (see
+-- SQLRepository.Migrate). Every statement is idempotent: tables are created if
+-- missing, and columns, keys and indexes added since a table was first created
+-- are added to existing databases after its CREATE TABLE.
+
+-- tenant_id isolates clinics sharing this deployment; 'default' is the single-clinic tenant.
+-- Tables created before clinics shared the deployment get the column here, with their rows moved to 'default'
+CREATE OR REPLACE FUNCTION add_tenant_column(p_table regclass) RETURNS void AS $$
+BEGIN
+    EXECUTE format('ALTER TABLE %s ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(50)', p_table);
+    EXECUTE format('UPDATE %s SET tenant_id = ''default'' WHERE tenant_id IS NULL', p_table);
+    EXECUTE format('ALTER TABLE %s ALTER COLUMN tenant_id SET DEFAULT ''default'', ALTER COLUMN tenant_id SET NOT NULL', p_table);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TABLE IF NOT EXISTS users (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(50) NOT NULL,
+    first_name VARCHAR(100) NOT NULL,
+    last_name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    -- chosen by the user on their own profile; locale is a supported language such as 'en' or 'nl'
+    display_name VARCHAR(100),
+    locale VARCHAR(10),
+    contact_email VARCHAR(255),
+    -- version backs optimistic concurrency (ETag/If-Match); deleted_at marks deactivated users
+    version INTEGER NOT NULL DEFAULT 1,
+    deleted_at TIMESTAMP,
+    -- set when the user's personal data was erased on request; erased users are also deleted
+    erased_at TIMESTAMP
+);
+SELECT add_tenant_column('users');
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS display_name VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS locale VARCHAR(10),
+    ADD COLUMN IF NOT EXISTS contact_email VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS erased_at TIMESTAMP;
+
+-- Sign-in looks users up by email regardless of case, so an address is taken in any case
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_tenant_email ON users (tenant_id, LOWER(email));
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_tenant_id_email_key;
+DROP INDEX IF EXISTS idx_users_email_lower;
+
+-- One admission per baby; co-parents join the admission of the first parent
+CREATE TABLE IF NOT EXISTS admissions (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS parents (
+  user_id VARCHAR(36) PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+  room_number VARCHAR(20),
+  -- Pending, Active, Discharged, Readmitted or Archived; transitions are enforced by the service
+  status VARCHAR(20) NOT NULL DEFAULT 'Active',
+  admission_id VARCHAR(36) REFERENCES admissions(id)
+);
+SELECT add_tenant_column('parents');
+ALTER TABLE parents ADD COLUMN IF NOT EXISTS admission_id VARCHAR(36) REFERENCES admissions(id);
+
+CREATE INDEX IF NOT EXISTS idx_parents_admission
+    ON parents (admission_id);
+
+-- Every lifecycle transition of a parent; written with the parent.status_changed event
+CREATE TABLE IF NOT EXISTS parent_status_history (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+    parent_id VARCHAR(36) NOT NULL REFERENCES parents(user_id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    changed_by VARCHAR(36) NOT NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_parent_status_history_parent ON parent_status_history (tenant_id, parent_id, changed_at);
+
+-- Every room move of a parent, e.g. to the NICU; written with the parent.room_changed event
+CREATE TABLE IF NOT EXISTS parent_room_history (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+    parent_id VARCHAR(36) NOT NULL REFERENCES parents(user_id) ON DELETE CASCADE,
+    from_room VARCHAR(20) NOT NULL,
+    to_room VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    transferred_by VARCHAR(36) NOT NULL,
+    transferred_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_parent_room_history_parent ON parent_room_history (tenant_id, parent_id, transferred_at);
+
+-- Wards group rooms; clinical staff are scoped to the rooms on their ward
+CREATE TABLE IF NOT EXISTS wards (
+    tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+    name VARCHAR(50) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (tenant_id, name)
+);
+SELECT add_tenant_column('wards');
+
+CREATE TABLE IF NOT EXISTS rooms (
+    tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+    room_number VARCHAR(20) NOT NULL,
+    ward VARCHAR(50) NOT NULL,
+    PRIMARY KEY (tenant_id, room_number),
+    FOREIGN KEY (tenant_id, ward) REFERENCES wards (tenant_id, name)
+);
+SELECT add_tenant_column('rooms');
+
+CREATE TABLE IF NOT EXISTS staff (
+    user_id VARCHAR(36) PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+    ward VARCHAR(50) NOT NULL,
+    FOREIGN KEY (tenant_id, ward) REFERENCES wards (tenant_id, name)
+);
+SELECT add_tenant_column('staff');
+
+-- Permission-based authorization: roles map to permissions embedded in issued tokens.
+-- Each clinic edits its own copy, seeded by seed_tenant below
+CREATE TABLE IF NOT EXISTS roles (
+    tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+    name VARCHAR(50) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    -- Members of ward-scoped roles are assigned a ward and only act on its rooms
+    ward_scoped BOOLEAN NOT NULL DEFAULT FALSE,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, name)
+);
+SELECT add_tenant_column('roles');
+
+-- Databases created before roles had ward_scoped keep nurses and pediatricians on their wards
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'roles' AND column_name = 'ward_scoped') THEN
+        ALTER TABLE roles ADD COLUMN ward_scoped BOOLEAN NOT NULL DEFAULT FALSE;
+        UPDATE roles SET ward_scoped = TRUE WHERE name IN ('NURSE', 'PEDIATRICIAN');
+    END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS permissions (
+    tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (tenant_id, name)
+);
+SELECT add_tenant_column('permissions');
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+    role VARCHAR(50) NOT NULL,
+    permission VARCHAR(100) NOT NULL,
+    PRIMARY KEY (tenant_id, role, permission),
+    FOREIGN KEY (tenant_id, role) REFERENCES roles (tenant_id, name) ON DELETE CASCADE,
+    FOREIGN KEY (tenant_id, permission) REFERENCES permissions (tenant_id, name) ON DELETE CASCADE
+);
+SELECT add_tenant_column('role_permissions');
+
+-- Attribute-based policies evaluated by POST /authz/check
+CREATE TABLE IF NOT EXISTS authz_policies (
+    tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+    id VARCHAR(36) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    action VARCHAR(100) NOT NULL,
+    effect VARCHAR(10) NOT NULL CHECK (effect IN ('allow', 'deny')),
+    subject_role VARCHAR(50),
+    conditions JSONB NOT NULL DEFAULT '[]'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, id)
+);
+SELECT add_tenant_column('authz_policies');
+
+-- Indexes named without the tenant predate it and cover the other columns alone
+DROP INDEX IF EXISTS idx_authz_policies_action;
+CREATE INDEX IF NOT EXISTS idx_authz_policies_tenant_action ON authz_policies (tenant_id, action);
+
+-- Every decision made by POST /authz/check
+CREATE TABLE IF NOT EXISTS authz_decisions (
+    id UUID PRIMARY KEY,
+    tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+    subject VARCHAR(36) NOT NULL,
+    action VARCHAR(100) NOT NULL,
+    resource JSONB NOT NULL DEFAULT '{}'::jsonb,
+    allowed BOOLEAN NOT NULL,
+    reason TEXT NOT NULL,
+    policy_id VARCHAR(36),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+SELECT add_tenant_column('authz_decisions');
+
+DROP INDEX IF EXISTS idx_authz_decisions_subject;
+CREATE INDEX IF NOT EXISTS idx_authz_decisions_tenant_subject ON authz_decisions (tenant_id, subject, created_at);
+
+-- Keys created before clinics shared the deployment covered the email, name or id alone
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'users_email_key') THEN
+        ALTER TABLE users DROP CONSTRAINT users_email_key;
+    END IF;
+    IF EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'rooms_ward_fkey') THEN
+        ALTER TABLE rooms DROP CONSTRAINT rooms_ward_fkey;
+        ALTER TABLE staff DROP CONSTRAINT IF EXISTS staff_ward_fkey;
+        ALTER TABLE wards DROP CONSTRAINT wards_pkey, ADD PRIMARY KEY (tenant_id, name);
+        ALTER TABLE rooms DROP CONSTRAINT rooms_pkey, ADD PRIMARY KEY (tenant_id, room_number),
+            ADD FOREIGN KEY (tenant_id, ward) REFERENCES wards (tenant_id, name);
+        ALTER TABLE staff ADD FOREIGN KEY (tenant_id, ward) REFERENCES wards (tenant_id, name);
+    END IF;
+    IF EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'role_permissions_role_fkey') THEN
+        ALTER TABLE role_permissions DROP CONSTRAINT role_permissions_role_fkey,
+            DROP CONSTRAINT role_permissions_permission_fkey;
+        ALTER TABLE roles DROP CONSTRAINT roles_pkey, ADD PRIMARY KEY (tenant_id, name);
+        ALTER TABLE permissions DROP CONSTRAINT permissions_pkey, ADD PRIMARY KEY (tenant_id, name);
+        ALTER TABLE role_permissions DROP CONSTRAINT role_permissions_pkey, ADD PRIMARY KEY (tenant_id, role, permission),
+            ADD FOREIGN KEY (tenant_id, role) REFERENCES roles (tenant_id, name) ON DELETE CASCADE,
+            ADD FOREIGN KEY (tenant_id, permission) REFERENCES permissions (tenant_id, name) ON DELETE CASCADE;
+    END IF;
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint c JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = ANY(c.conkey)
+        WHERE c.conname = 'authz_policies_pkey' AND a.attname = 'tenant_id'
+    ) THEN
+        ALTER TABLE authz_policies DROP CONSTRAINT authz_policies_pkey, ADD PRIMARY KEY (tenant_id, id);
+    END IF;
+END $$;
+
+-- Seeds the default roles, permissions and policies of a clinic; existing rows are kept.
+-- The API calls it for every configured tenant at startup
+CREATE OR REPLACE FUNCTION seed_tenant(p_tenant VARCHAR) RETURNS void AS $$
+INSERT INTO roles (tenant_id, name, description, ward_scoped)
+SELECT p_tenant, name, description, ward_scoped FROM (VALUES
+    ('ADMIN', 'Clinic administrator', FALSE),
+    ('PARENT', 'Admitted parent', FALSE),
+    ('VISITOR', 'Family visitor invited by a parent', FALSE),
+    ('NURSE', 'Ward nurse; registers and discharges parents on their ward', TRUE),
+    ('PEDIATRICIAN', 'Ward pediatrician', TRUE)
+) AS seed (name, description, ward_scoped)
+ON CONFLICT (tenant_id, name) DO NOTHING;
+
+INSERT INTO permissions (tenant_id, name, description)
+SELECT p_tenant, name, description FROM (VALUES
+    ('users:register', 'Register admins and parents'),
+    ('invitations:manage', 'List, resend and cancel parent invitations'),
+    ('admins:approve', 'Approve or reject the creation and removal of admins'),
+    ('users:read', 'Browse the user directory'),
+    ('users:manage', 'Correct and deactivate users'),
+    ('users:export', 'Export user lists for reporting'),
+    ('users:erase', 'Erase personal data on request (GDPR)'),
+    ('users:subject-access', 'Export all data held about a user on request (GDPR)'),
+    ('users:impersonate', 'Act as a parent through an audited impersonation token'),
+    ('parents:discharge', 'Discharge parents and revoke their sessions'),
+    ('parents:transfer', 'Move parents between rooms'),
+    ('parents:admit', 'Admit pending parents and readmit discharged parents'),
+    ('parents:archive', 'Archive discharged or never admitted parents'),
+    ('enrollment:issue', 'Mint QR enrollment codes for parents'),
+    ('visitors:manage', 'Invite and revoke family visitors'),
+    ('session:logout', 'Invalidate the current session'),
+    ('roles:manage', 'Manage roles and permissions'),
+    ('authz:check', 'Ask the central authorization decision API'),
+    ('authz:manage', 'Manage attribute-based authorization policies'),
+    ('self:subject-access', 'Export all data held about oneself'),
+    ('self:profile', 'View and edit one''s own profile and preferred language'),
+    ('retention:manage', 'Review what the data retention job will anonymize and delete')
+) AS seed (name, description)
+ON CONFLICT (tenant_id, name) DO NOTHING;
+
+INSERT INTO role_permissions (tenant_id, role, permission)
+SELECT p_tenant, role, permission FROM (VALUES
+    ('ADMIN', 'users:register'),
+    ('ADMIN', 'invitations:manage'),
+    ('ADMIN', 'admins:approve'),
+    ('ADMIN', 'users:read'),
+    ('ADMIN', 'users:manage'),
+    ('ADMIN', 'users:export'),
+    ('ADMIN', 'users:erase'),
+    ('ADMIN', 'users:subject-access'),
+    ('ADMIN', 'users:impersonate'),
+    ('ADMIN', 'parents:discharge'),
+    ('ADMIN', 'parents:transfer'),
+    ('ADMIN', 'parents:admit'),
+    ('ADMIN', 'parents:archive'),
+    ('ADMIN', 'enrollment:issue'),
+    ('ADMIN', 'session:logout'),
+    ('ADMIN', 'roles:manage'),
+    ('ADMIN', 'authz:check'),
+    ('ADMIN', 'authz:manage'),
+    ('ADMIN', 'retention:manage'),
+    ('PARENT', 'visitors:manage'),
+    ('PARENT', 'session:logout'),
+    ('PARENT', 'authz:check'),
+    ('PARENT', 'self:subject-access'),
+    ('PARENT', 'self:profile'),
+    ('VISITOR', 'session:logout'),
+    ('VISITOR', 'authz:check'),
+    ('NURSE', 'users:register'),
+    ('NURSE', 'parents:discharge'),
+    ('NURSE', 'parents:admit'),
+    ('NURSE', 'session:logout'),
+    ('NURSE', 'authz:check'),
+    ('PEDIATRICIAN', 'session:logout'),
+    ('PEDIATRICIAN', 'authz:check')
+) AS seed (role, permission)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO authz_policies (tenant_id, id, description, action, effect, subject_role, conditions)
+SELECT p_tenant, id, description, action, effect, subject_role, conditions::jsonb FROM (VALUES
+    ('c0000000-0000-0000-0000-000000000001', 'discharged parents have no access', '*', 'deny', 'PARENT',
+        '[{"subject": "status", "value": "Discharged"}]'),
+    ('c0000000-0000-0000-0000-000000000002', 'parents see their own room', 'room:view', 'allow', 'PARENT',
+        '[{"subject": "room_number", "resource": "room_number"}]'),
+    ('c0000000-0000-0000-0000-000000000003', 'parents see the baby in their own room', 'baby:view', 'allow', 'PARENT',
+        '[{"subject": "room_number", "resource": "room_number"}]'),
+    ('c0000000-0000-0000-0000-000000000004', 'nurses see rooms on their ward', 'room:view', 'allow', 'NURSE',
+        '[{"subject": "ward", "resource": "ward"}]'),
+    ('c0000000-0000-0000-0000-000000000005', 'nurses see babies on their ward', 'baby:view', 'allow', 'NURSE',
+        '[{"subject": "ward", "resource": "ward"}]'),
+    ('c0000000-0000-0000-0000-000000000006', 'pediatricians see rooms on their ward', 'room:view', 'allow', 'PEDIATRICIAN',
+        '[{"subject": "ward", "resource": "ward"}]'),
+    ('c0000000-0000-0000-0000-000000000007', 'pediatricians see babies on their ward', 'baby:view', 'allow', 'PEDIATRICIAN',
+        '[{"subject": "ward", "resource": "ward"}]'),
+    ('c0000000-0000-0000-0000-000000000008', 'pending parents have no access yet', '*', 'deny', 'PARENT',
+        '[{"subject": "status", "value": "Pending"}]'),
+    ('c0000000-0000-0000-0000-000000000009', 'archived parents have no access', '*', 'deny', 'PARENT',
+        '[{"subject": "status", "value": "Archived"}]')
+) AS seed (id, description, action, effect, subject_role, conditions)
+ON CONFLICT (tenant_id, id) DO NOTHING;
+$$ LANGUAGE sql;
+
+SELECT seed_tenant('default');
+
+-- Family visitors delegated by parents (email or code based)
+CREATE TABLE IF NOT EXISTS visitors (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+    parent_id VARCHAR(36) NOT NULL REFERENCES parents(user_id) ON DELETE CASCADE,
+    email VARCHAR(255),
+    code_hash VARCHAR(64) UNIQUE,
+    display_name VARCHAR(100) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+SELECT add_tenant_column('visitors');
+
+DROP INDEX IF EXISTS idx_visitors_parent;
+DROP INDEX IF EXISTS idx_visitors_email;
+CREATE INDEX IF NOT EXISTS idx_visitors_tenant_parent ON visitors (tenant_id, parent_id);
+-- Visitors sign in with their email in any case. Not unique: co-parents may invite the same
+-- person, and an expired invitation is not revoked before the visitor is invited again
+DROP INDEX IF EXISTS idx_visitors_tenant_email;
+CREATE INDEX IF NOT EXISTS idx_visitors_tenant_email_lower ON visitors (tenant_id, LOWER(email)) WHERE revoked_at IS NULL;
+
+-- Outbox table for transactional event publishing
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+    aggregate_type TEXT NOT NULL,
+    aggregate_id VARCHAR(36) NOT NULL,
+    event_type TEXT NOT NULL,
+    -- Version of the payload schema of event_type, see ports.Events
+    schema_version INT NOT NULL DEFAULT 1,
+    -- Ties the event to the request that caused it (X-Correlation-ID)
+    correlation_id VARCHAR(64),
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    processed_at TIMESTAMPTZ
+);
+SELECT add_tenant_column('outbox_events');
+ALTER TABLE outbox_events
+    ADD COLUMN IF NOT EXISTS schema_version INT NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS correlation_id VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_unprocessed
+    ON outbox_events (processed_at, created_at);
+
+-- Proof that a user's personal data was erased; holds no personal data itself
+CREATE TABLE IF NOT EXISTS erasure_tombstones (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+    user_id VARCHAR(36) NOT NULL,
+    role VARCHAR(50) NOT NULL,
+    requested_by VARCHAR(36) NOT NULL,
+    erased_at TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_erasure_tombstones_user ON erasure_tombstones (tenant_id, user_id);
+
+-- Subject access requests (GDPR article 15); the JSON archive is kept until expires_at
+CREATE TABLE IF NOT EXISTS subject_access_requests (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+    user_id VARCHAR(36) NOT NULL,
+    requested_by VARCHAR(36) NOT NULL,
+    -- Pending, Running, Completed or Failed
+    status VARCHAR(20) NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    archive JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ
+);
+
+-- At most one open request per user
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subject_access_requests_open
+    ON subject_access_requests (tenant_id, user_id) WHERE status IN ('Pending', 'Running');
+
+-- Invitations of newly registered parents, who stay Pending until they accept
+CREATE TABLE IF NOT EXISTS invitations (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+    user_id VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    -- Pending, Accepted or Cancelled
+    status VARCHAR(20) NOT NULL,
+    -- Only the link of the latest email is valid
+    sends INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    accepted_at TIMESTAMPTZ,
+    cancelled_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_invitations_status
+    ON invitations (tenant_id, status, created_at);
+
+-- Admin creation and removal wait here for a second admin to approve them
+CREATE TABLE IF NOT EXISTS admin_approvals (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+    -- CreateAdmin (with email and names) or RemoveAdmin (with target_user_id)
+    action VARCHAR(20) NOT NULL,
+    -- Pending, Approved, Rejected or Expired
+    status VARCHAR(20) NOT NULL,
+    email VARCHAR(255),
+    first_name VARCHAR(100),
+    last_name VARCHAR(100),
+    target_user_id VARCHAR(36) REFERENCES users(id) ON DELETE CASCADE,
+    requested_by VARCHAR(36) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    decided_by VARCHAR(36),
+    decided_at TIMESTAMPTZ
+);
+
+-- At most one pending approval per new admin email and per admin to remove
+CREATE UNIQUE INDEX IF NOT EXISTS idx_admin_approvals_pending_email
+    ON admin_approvals (tenant_id, LOWER(email)) WHERE status = 'Pending' AND action = 'CreateAdmin';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_admin_approvals_pending_target
+    ON admin_approvals (tenant_id, target_user_id) WHERE status = 'Pending' AND action = 'RemoveAdmin';
+
+-- Audit trail for privileged actions (e.g. impersonation)
+CREATE TABLE IF NOT EXISTS audit_log (
+    id UUID PRIMARY KEY,
+    tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+    actor_id VARCHAR(36) NOT NULL,
+    action TEXT NOT NULL,
+    subject_id VARCHAR(36),
+    metadata JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+SELECT add_tenant_column('audit_log');
+
+DROP INDEX IF EXISTS idx_audit_log_subject;
+CREATE INDEX IF NOT EXISTS idx_audit_log_tenant_subject
+    ON audit_log (tenant_id, subject_id, created_at);
+
+-- Create a Trigger Function to NOTIFY
+CREATE OR REPLACE FUNCTION notify_outbox_event()
+RETURNS TRIGGER AS $$
+BEGIN
+    -- This sends a signal on the 'outbox_channel'
+    PERFORM pg_notify('outbox_channel', NEW.id::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Attach the Trigger to the table
+CREATE OR REPLACE TRIGGER trg_outbox_notify
+AFTER INSERT ON outbox_events
+FOR EACH ROW
+EXECUTE FUNCTION notify_outbox_event();
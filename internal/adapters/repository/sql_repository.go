@@ -4,12 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/config"
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/sony/gobreaker"
 )
 
@@ -18,7 +21,11 @@ type SQLRepository struct {
 	cb *gobreaker.CircuitBreaker
 }
 
-var _ ports.UserRepository = (*SQLRepository)(nil)
+var (
+	_ ports.UserRepository  = (*SQLRepository)(nil)
+	_ ports.AuditRepository = (*SQLRepository)(nil)
+	_ ports.EventRepository = (*SQLRepository)(nil)
+)
 
 func NewSQLRepository(db *sql.DB) *SQLRepository {
 	// Configure circuit breaker for database operations
@@ -30,18 +37,43 @@ func NewSQLRepository(db *sql.DB) *SQLRepository {
 	}
 }
 
+// tenantID returns the clinic every query must be scoped to
+func tenantID(ctx context.Context) (string, error) {
+	tenant, ok := domain.TenantFromContext(ctx)
+	if !ok {
+		return "", domain.ErrNoTenant
+	}
+	return tenant, nil
+}
+
+// userColumns are the users columns scanned by scanUser
+const userColumns = `id, email, role, first_name, last_name, created_at,
+	COALESCE(display_name, ''), COALESCE(locale, ''), COALESCE(contact_email, '')`
+
+func scanUser(row rowScanner) (*domain.User, error) {
+	var user domain.User
+	if err := row.Scan(&user.ID, &user.Email, &user.Role, &user.FirstName, &user.LastName, &user.CreatedAt,
+		&user.DisplayName, &user.Locale, &user.ContactEmail); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByEmail ignores case, since mail providers do; an exact match wins
+// over accounts that differ only in case
 func (r *SQLRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	result, err := r.cb.Execute(func() (interface{}, error) {
-		var user domain.User
-		err := r.db.QueryRowContext(
+		return scanUser(r.db.QueryRowContext(
 			ctx,
-			"SELECT id, email, role, first_name, last_name, created_at FROM users WHERE email = $1",
-			email,
-		).Scan(&user.ID, &user.Email, &user.Role, &user.FirstName, &user.LastName, &user.CreatedAt)
-		if err != nil {
-			return nil, err
-		}
-		return &user, nil
+			"SELECT "+userColumns+` FROM users WHERE tenant_id = $1 AND LOWER(email) = LOWER($2) AND deleted_at IS NULL
+			ORDER BY email = $2 DESC LIMIT 1`,
+			tenant, email,
+		))
 	})
 	if err != nil {
 		return nil, err
@@ -49,48 +81,105 @@ func (r *SQLRepository) FindByEmail(ctx context.Context, email string) (*domain.
 	return result.(*domain.User), nil
 }
 
-func (r *SQLRepository) CreateParent(ctx context.Context, parent domain.Parent, outboxPayload []byte) (*domain.Parent, error) {
+func (r *SQLRepository) EmailsTaken(ctx context.Context, emails []string) (map[string]bool, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lowered := make([]string, len(emails))
+	for i, email := range emails {
+		lowered[i] = strings.ToLower(email)
+	}
+
 	result, err := r.cb.Execute(func() (interface{}, error) {
-		tx, err := r.db.BeginTx(ctx, nil)
+		rows, err := r.db.QueryContext(ctx,
+			"SELECT lower(email) FROM users WHERE tenant_id = $1 AND lower(email) = ANY($2) AND deleted_at IS NULL",
+			tenant, pq.Array(lowered),
+		)
 		if err != nil {
 			return nil, err
 		}
-		defer func() { _ = tx.Rollback() }()
+		defer rows.Close()
 
-		_, err = tx.ExecContext(ctx,
-			"INSERT INTO users (id, email, role, first_name, last_name, created_at) VALUES ($1, $2, $3, $4, $5, $6)",
-			parent.ID, parent.Email, parent.Role, parent.FirstName, parent.LastName, parent.CreatedAt,
-		)
+		taken := make(map[string]bool)
+		for rows.Next() {
+			var email string
+			if err := rows.Scan(&email); err != nil {
+				return nil, err
+			}
+			taken[email] = true
+		}
+		return taken, rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(map[string]bool), nil
+}
+
+func (r *SQLRepository) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		return scanUser(r.db.QueryRowContext(
+			ctx,
+			"SELECT "+userColumns+" FROM users WHERE tenant_id = $1 AND id = $2 AND deleted_at IS NULL",
+			tenant, id,
+		))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*domain.User), nil
+}
+
+func (r *SQLRepository) FindParentByID(ctx context.Context, id string) (*domain.Parent, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		var parent domain.Parent
+		err := r.db.QueryRowContext(
+			ctx,
+			`SELECT u.id, u.email, u.role, u.first_name, u.last_name, u.created_at, COALESCE(u.locale, ''),
+			p.room_number, p.status, COALESCE(p.admission_id, '')
+			FROM users u JOIN parents p ON p.user_id = u.id AND p.tenant_id = u.tenant_id
+			WHERE u.tenant_id = $1 AND u.id = $2 AND u.deleted_at IS NULL`,
+			tenant, id,
+		).Scan(&parent.ID, &parent.Email, &parent.Role, &parent.FirstName, &parent.LastName, &parent.CreatedAt,
+			&parent.Locale, &parent.RoomNumber, &parent.Status, &parent.AdmissionID)
 		if err != nil {
 			return nil, err
 		}
+		return &parent, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*domain.Parent), nil
+}
 
-		_, err = tx.ExecContext(ctx,
-			"INSERT INTO parents (user_id, room_number, status) VALUES ($1, $2, $3)",
-			parent.ID, parent.RoomNumber, parent.Status,
-		)
+func (r *SQLRepository) CreateParent(ctx context.Context, parent domain.Parent, outboxPayload []byte) (*domain.Parent, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		tx, err := r.db.BeginTx(ctx, nil)
 		if err != nil {
 			return nil, err
 		}
+		defer func() { _ = tx.Rollback() }()
 
-		if len(outboxPayload) > 0 {
-			var tmp map[string]any
-			if err := json.Unmarshal(outboxPayload, &tmp); err != nil {
-				return nil, err
-			}
-
-			_, err = tx.ExecContext(ctx,
-				"INSERT INTO outbox_events (id, aggregate_type, aggregate_id, event_type, payload, created_at) VALUES ($1, $2, $3, $4, $5, $6)",
-				uuid.NewString(),
-				"parent",
-				parent.ID,
-				"babies",
-				outboxPayload,
-				time.Now(),
-			)
-			if err != nil {
-				return nil, err
-			}
+		if err := insertParent(ctx, tx, tenant, parent, outboxPayload); err != nil {
+			return nil, err
 		}
 
 		if err := tx.Commit(); err != nil {
@@ -104,35 +193,91 @@ func (r *SQLRepository) CreateParent(ctx context.Context, parent domain.Parent,
 	return result.(*domain.Parent), nil
 }
 
-func (r *SQLRepository) CreateAdmin(ctx context.Context, user domain.User) (*domain.User, error) {
-	_, err := r.cb.Execute(func() (interface{}, error) {
-		_, err := r.db.ExecContext(ctx,
-			"INSERT INTO users (id, email, role, first_name, last_name, created_at) VALUES ($1, $2, $3, $4, $5, $6)",
-			user.ID, user.Email, user.Role, user.FirstName, user.LastName, user.CreatedAt,
+// insertParent writes the user and parent rows and, if given, the parent's registration event
+func insertParent(ctx context.Context, tx *sql.Tx, tenant string, parent domain.Parent, outboxPayload []byte) error {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO users (id, tenant_id, email, role, first_name, last_name, created_at, locale)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''))`,
+		parent.ID, tenant, parent.Email, parent.Role, parent.FirstName, parent.LastName, parent.CreatedAt, parent.Locale,
+	)
+	if err != nil {
+		return emailTakenOr(err)
+	}
+
+	// The first parent of an admission opens it; a co-parent joins the existing one
+	if parent.AdmissionID != "" {
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO admissions (id, tenant_id, created_at) VALUES ($1, $2, $3) ON CONFLICT (id) DO NOTHING",
+			parent.AdmissionID, tenant, parent.CreatedAt,
 		)
-		return nil, err
-	})
-	return &user, err
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO parents (user_id, tenant_id, room_number, status, admission_id) VALUES ($1, $2, $3, $4, NULLIF($5, ''))",
+		parent.ID, tenant, parent.RoomNumber, parent.Status, parent.AdmissionID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if len(outboxPayload) > 0 {
+		return insertOutboxEvent(ctx, tx, tenant, "parent", parent.ID, ports.EventParentRegistered, outboxPayload)
+	}
+	return nil
 }
 
-func (r *SQLRepository) UpdateParentStatus(ctx context.Context, parentID string) error {
-	_, err := r.cb.Execute(func() (interface{}, error) {
-		_, err := r.db.ExecContext(ctx,
-			"UPDATE parents SET status = 'Discharged' WHERE user_id = $1",
-			parentID,
-		)
+func (r *SQLRepository) CreateStaff(ctx context.Context, staff domain.Staff) (*domain.Staff, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
 		return nil, err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO users (id, tenant_id, email, role, first_name, last_name, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+			staff.ID, tenant, staff.Email, staff.Role, staff.FirstName, staff.LastName, staff.CreatedAt,
+		)
+		if err != nil {
+			return nil, emailTakenOr(err)
+		}
+
+		// Only members of ward-scoped roles have a ward assignment
+		if staff.Ward != "" {
+			_, err = tx.ExecContext(ctx,
+				"INSERT INTO staff (user_id, tenant_id, ward) VALUES ($1, $2, $3)",
+				staff.ID, tenant, staff.Ward,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, tx.Commit()
 	})
-	return err
+	return &staff, err
 }
 
 func (r *SQLRepository) GetParentStatus(ctx context.Context, parentID string) (string, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return "", err
+	}
+
 	result, err := r.cb.Execute(func() (interface{}, error) {
 		var status string
 		err := r.db.QueryRowContext(
 			ctx,
-			"SELECT status FROM parents WHERE user_id = $1",
-			parentID,
+			"SELECT status FROM parents WHERE tenant_id = $1 AND user_id = $2",
+			tenant, parentID,
 		).Scan(&status)
 		if err != nil {
 			return "", err
@@ -144,3 +289,61 @@ func (r *SQLRepository) GetParentStatus(ctx context.Context, parentID string) (s
 	}
 	return result.(string), nil
 }
+
+func (r *SQLRepository) RecordAudit(ctx context.Context, entry domain.AuditEntry) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+	metadata, err := json.Marshal(entry.Metadata)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		_, err := r.db.ExecContext(ctx,
+			"INSERT INTO audit_log (id, tenant_id, actor_id, action, subject_id, metadata, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+			entry.ID, tenant, entry.ActorID, entry.Action, entry.SubjectID, metadata, entry.CreatedAt,
+		)
+		return nil, err
+	})
+	return err
+}
+
+// AppendEvent queues an event that is not part of a larger change, such as a login
+func (r *SQLRepository) AppendEvent(ctx context.Context, aggregateType, aggregateID string, event ports.OutboxEvent) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if err := insertOutboxEvent(ctx, tx, tenant, aggregateType, aggregateID, event.Type, event.Payload); err != nil {
+			return nil, err
+		}
+		return nil, tx.Commit()
+	})
+	return err
+}
+
+// insertOutboxEvent queues an event in the same transaction as the change it
+// describes, stamped with its type's schema version and the request's correlation ID
+func insertOutboxEvent(ctx context.Context, tx *sql.Tx, tenant, aggregateType, aggregateID, eventType string, payload []byte) error {
+	if !json.Valid(payload) {
+		return errors.New("outbox payload is not valid JSON")
+	}
+	correlationID, _ := domain.CorrelationIDFromContext(ctx)
+
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO outbox_events (id, tenant_id, aggregate_type, aggregate_id, event_type, schema_version, correlation_id, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9)`,
+		uuid.NewString(), tenant, aggregateType, aggregateID, eventType, ports.Events.SchemaVersion(eventType), correlationID, payload, time.Now(),
+	)
+	return err
+}
@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/lib/pq"
+)
+
+// subjectDataQueries select every stored record about a user, one query per
+// archive section. Each query returns one JSON object per row and takes the
+// tenant and the user as arguments.
+var subjectDataQueries = []struct {
+	section string
+	query   string
+}{
+	{"users", `SELECT row_to_json(t) FROM (
+		SELECT id, email, role, first_name, last_name, display_name, locale, contact_email, created_at, version,
+		deleted_at, erased_at FROM users WHERE tenant_id = $1 AND id = $2) t`},
+	{"parents", `SELECT row_to_json(t) FROM (
+		SELECT user_id, room_number, status, admission_id FROM parents WHERE tenant_id = $1 AND user_id = $2) t`},
+	{"staff", `SELECT row_to_json(t) FROM (
+		SELECT s.user_id, s.ward FROM staff s JOIN users u ON u.id = s.user_id
+		WHERE u.tenant_id = $1 AND s.user_id = $2) t`},
+	{"parent_status_history", `SELECT row_to_json(t) FROM (
+		SELECT id, from_status, to_status, reason, changed_by, changed_at FROM parent_status_history
+		WHERE tenant_id = $1 AND parent_id = $2 ORDER BY changed_at) t`},
+	{"parent_room_history", `SELECT row_to_json(t) FROM (
+		SELECT id, from_room, to_room, reason, transferred_by, transferred_at FROM parent_room_history
+		WHERE tenant_id = $1 AND parent_id = $2 ORDER BY transferred_at) t`},
+	// Visitor code hashes are credentials, not data about the parent
+	{"visitors", `SELECT row_to_json(t) FROM (
+		SELECT id, email, display_name, expires_at, revoked_at, created_at FROM visitors
+		WHERE tenant_id = $1 AND parent_id = $2 ORDER BY created_at) t`},
+	{"invitations", `SELECT row_to_json(t) FROM (
+		SELECT id, email, status, sends, created_at, last_sent_at, expires_at, accepted_at, cancelled_at FROM invitations
+		WHERE tenant_id = $1 AND user_id = $2 ORDER BY created_at) t`},
+	// Approvals about the user: to create them as an admin, to remove them, or requested or decided by them
+	{"admin_approvals", `SELECT row_to_json(t) FROM (
+		SELECT a.id, a.action, a.status, a.email, a.first_name, a.last_name, a.target_user_id, a.requested_by,
+			a.created_at, a.expires_at, a.decided_by, a.decided_at
+		FROM admin_approvals a JOIN users u ON u.tenant_id = a.tenant_id AND u.id = $2
+		WHERE a.tenant_id = $1 AND (LOWER(a.email) = LOWER(u.email) OR $2 IN (a.target_user_id, a.requested_by, a.decided_by))
+		ORDER BY a.created_at) t`},
+	{"outbox_events", `SELECT row_to_json(t) FROM (
+		SELECT id, aggregate_type, event_type, schema_version, correlation_id, payload, created_at, processed_at FROM outbox_events
+		WHERE tenant_id = $1 AND aggregate_id = $2 ORDER BY created_at) t`},
+	{"audit_log", `SELECT row_to_json(t) FROM (
+		SELECT id, actor_id, action, subject_id, metadata, created_at FROM audit_log
+		WHERE tenant_id = $1 AND (subject_id = $2 OR actor_id = $2) ORDER BY created_at) t`},
+	{"authz_decisions", `SELECT row_to_json(t) FROM (
+		SELECT id, action, resource, allowed, reason, policy_id, created_at FROM authz_decisions
+		WHERE tenant_id = $1 AND subject = $2 ORDER BY created_at) t`},
+}
+
+// SubjectDataSections returns the names of the sections CollectSubjectData
+// fills, in archive order.
+func SubjectDataSections() []string {
+	names := make([]string, len(subjectDataQueries))
+	for i, q := range subjectDataQueries {
+		names[i] = q.section
+	}
+	return names
+}
+
+func (r *SQLRepository) CreateSubjectAccessRequest(ctx context.Context, request domain.SubjectAccessRequest, staleBefore time.Time) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		_, err = tx.ExecContext(ctx,
+			`UPDATE subject_access_requests SET status = $1, error = $2, completed_at = $3
+			WHERE tenant_id = $4 AND user_id = $5 AND status IN ($6, $7) AND created_at < $8`,
+			domain.SubjectAccessFailed, "the request was interrupted", request.CreatedAt,
+			tenant, request.UserID, domain.SubjectAccessPending, domain.SubjectAccessRunning, staleBefore,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO subject_access_requests (id, tenant_id, user_id, requested_by, status, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			request.ID, tenant, request.UserID, request.RequestedBy, request.Status, request.CreatedAt,
+		)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+				return nil, domain.ErrSubjectAccessInProgress
+			}
+			return nil, err
+		}
+		return nil, tx.Commit()
+	})
+	return err
+}
+
+func (r *SQLRepository) UpdateSubjectAccessRequest(ctx context.Context, request domain.SubjectAccessRequest, archive []byte) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		res, err := r.db.ExecContext(ctx,
+			`UPDATE subject_access_requests SET status = $1, error = $2, completed_at = $3, expires_at = $4, archive = $5
+			WHERE tenant_id = $6 AND id = $7 AND status IN ($8, $9)`,
+			request.Status, request.Error, request.CompletedAt, request.ExpiresAt, nullableJSON(archive), tenant, request.ID,
+			domain.SubjectAccessPending, domain.SubjectAccessRunning,
+		)
+		if err != nil {
+			return nil, err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if affected == 0 {
+			return nil, domain.ErrSubjectAccessNotFound
+		}
+		return nil, nil
+	})
+	return err
+}
+
+func (r *SQLRepository) GetSubjectAccessRequest(ctx context.Context, id string) (*domain.SubjectAccessRequest, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		var request domain.SubjectAccessRequest
+		var completedAt, expiresAt sql.NullTime
+		err := r.db.QueryRowContext(ctx,
+			`SELECT id, user_id, requested_by, status, error, created_at, completed_at, expires_at
+			FROM subject_access_requests WHERE tenant_id = $1 AND id = $2`,
+			tenant, id,
+		).Scan(&request.ID, &request.UserID, &request.RequestedBy, &request.Status, &request.Error,
+			&request.CreatedAt, &completedAt, &expiresAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrSubjectAccessNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		if completedAt.Valid {
+			request.CompletedAt = &completedAt.Time
+		}
+		if expiresAt.Valid {
+			request.ExpiresAt = &expiresAt.Time
+		}
+		return &request, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*domain.SubjectAccessRequest), nil
+}
+
+func (r *SQLRepository) GetSubjectAccessArchive(ctx context.Context, id string) ([]byte, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		var archive []byte
+		err := r.db.QueryRowContext(ctx,
+			"SELECT archive FROM subject_access_requests WHERE tenant_id = $1 AND id = $2",
+			tenant, id,
+		).Scan(&archive)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrSubjectAccessNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		if archive == nil {
+			return nil, domain.ErrSubjectAccessNotReady
+		}
+		return archive, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]byte), nil
+}
+
+// CollectSubjectData reads all sections in one read-only transaction so the
+// archive is a consistent snapshot
+func (r *SQLRepository) CollectSubjectData(ctx context.Context, userID string) ([]domain.SubjectDataSection, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		sections := make([]domain.SubjectDataSection, 0, len(subjectDataQueries))
+		for _, q := range subjectDataQueries {
+			records, err := queryJSONRows(ctx, tx, q.query, tenant, userID)
+			if err != nil {
+				return nil, err
+			}
+			sections = append(sections, domain.SubjectDataSection{Name: q.section, Records: records})
+		}
+		return sections, tx.Commit()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]domain.SubjectDataSection), nil
+}
+
+// queryJSONRows collects a query that selects one JSON object per row
+func queryJSONRows(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]json.RawMessage, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []json.RawMessage{}
+	for rows.Next() {
+		var record []byte
+		if err := rows.Scan(&record); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// nullableJSON stores an empty document as NULL
+func nullableJSON(document []byte) any {
+	if len(document) == 0 {
+		return nil
+	}
+	return document
+}
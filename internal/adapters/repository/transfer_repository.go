@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// TransferParent moves an admitted parent and their co-parents to another room,
+// records each move in parent_room_history and queues one parent.room_changed
+// event in one transaction. domain.ErrVersionConflict is returned when any of
+// them is no longer in transfer.FromRoom.
+func (r *SQLRepository) TransferParent(ctx context.Context, transfer domain.RoomTransfer, outboxPayload []byte) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		res, err := tx.ExecContext(ctx,
+			`UPDATE parents SET room_number = $1
+			WHERE tenant_id = $2 AND user_id = $3 AND room_number = $4 AND status IN ($5, $6)`,
+			transfer.ToRoom, tenant, transfer.ParentID, transfer.FromRoom, domain.ParentActive, domain.ParentReadmitted,
+		)
+		if err != nil {
+			return nil, err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if affected == 0 {
+			return nil, domain.ErrVersionConflict
+		}
+
+		// Pending co-parents move too, so that they join their baby's room once they accept
+		for _, coParentID := range transfer.CoParentIDs {
+			res, err := tx.ExecContext(ctx,
+				`UPDATE parents SET room_number = $1
+				WHERE tenant_id = $2 AND user_id = $3 AND room_number = $4 AND status IN ($5, $6, $7)`,
+				transfer.ToRoom, tenant, coParentID, transfer.FromRoom, domain.ParentPending, domain.ParentActive, domain.ParentReadmitted,
+			)
+			if err != nil {
+				return nil, err
+			}
+			affected, err := res.RowsAffected()
+			if err != nil {
+				return nil, err
+			}
+			if affected == 0 {
+				return nil, domain.ErrVersionConflict
+			}
+		}
+
+		for i, parentID := range append([]string{transfer.ParentID}, transfer.CoParentIDs...) {
+			// The room is part of the user record, so a transfer invalidates ETags handed out before it
+			_, err = tx.ExecContext(ctx,
+				"UPDATE users SET version = version + 1 WHERE tenant_id = $1 AND id = $2",
+				tenant, parentID,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			historyID := transfer.ID
+			if i > 0 {
+				historyID = uuid.NewString()
+			}
+			_, err = tx.ExecContext(ctx,
+				`INSERT INTO parent_room_history (id, tenant_id, parent_id, from_room, to_room, reason, transferred_by, transferred_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+				historyID, tenant, parentID, transfer.FromRoom, transfer.ToRoom,
+				transfer.Reason, transfer.TransferredBy, transfer.TransferredAt,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if err := insertOutboxEvent(ctx, tx, tenant, "parent", transfer.ParentID, ports.EventParentRoomChanged, outboxPayload); err != nil {
+			return nil, err
+		}
+		return nil, tx.Commit()
+	})
+	return err
+}
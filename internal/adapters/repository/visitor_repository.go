@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+var _ ports.VisitorRepository = (*SQLRepository)(nil)
+
+const visitorColumns = "id, parent_id, COALESCE(email, ''), display_name, expires_at, revoked_at, created_at"
+
+func (r *SQLRepository) CreateVisitor(ctx context.Context, visitor domain.Visitor, codeHash string) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		_, err := r.db.ExecContext(ctx,
+			"INSERT INTO visitors (id, tenant_id, parent_id, email, code_hash, display_name, expires_at, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+			visitor.ID, tenant, visitor.ParentID, nullString(visitor.Email), nullString(codeHash),
+			visitor.DisplayName, visitor.ExpiresAt, visitor.CreatedAt,
+		)
+		return nil, err
+	})
+	return err
+}
+
+func (r *SQLRepository) FindVisitorByEmail(ctx context.Context, email string) (*domain.Visitor, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.findVisitor(ctx,
+		"SELECT "+visitorColumns+" FROM visitors WHERE tenant_id = $1 AND LOWER(email) = LOWER($2) AND revoked_at IS NULL AND expires_at > NOW() ORDER BY created_at DESC LIMIT 1",
+		tenant, email,
+	)
+}
+
+func (r *SQLRepository) FindVisitorByCodeHash(ctx context.Context, codeHash string) (*domain.Visitor, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.findVisitor(ctx,
+		"SELECT "+visitorColumns+" FROM visitors WHERE tenant_id = $1 AND code_hash = $2",
+		tenant, codeHash,
+	)
+}
+
+func (r *SQLRepository) ListVisitorsByParent(ctx context.Context, parentID string) ([]domain.Visitor, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		rows, err := r.db.QueryContext(ctx,
+			"SELECT "+visitorColumns+" FROM visitors WHERE tenant_id = $1 AND parent_id = $2 ORDER BY created_at",
+			tenant, parentID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		visitors := []domain.Visitor{}
+		for rows.Next() {
+			visitor, err := scanVisitor(rows)
+			if err != nil {
+				return nil, err
+			}
+			visitors = append(visitors, *visitor)
+		}
+		return visitors, rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]domain.Visitor), nil
+}
+
+func (r *SQLRepository) RevokeVisitor(ctx context.Context, parentID, visitorID string) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.cb.Execute(func() (interface{}, error) {
+		res, err := r.db.ExecContext(ctx,
+			"UPDATE visitors SET revoked_at = NOW() WHERE tenant_id = $1 AND id = $2 AND parent_id = $3 AND revoked_at IS NULL",
+			tenant, visitorID, parentID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if affected == 0 {
+			return nil, sql.ErrNoRows
+		}
+		return nil, nil
+	})
+	return err
+}
+
+func (r *SQLRepository) RevokeVisitorsByParent(ctx context.Context, parentID string) ([]string, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		rows, err := r.db.QueryContext(ctx,
+			"UPDATE visitors SET revoked_at = NOW() WHERE tenant_id = $1 AND parent_id = $2 AND revoked_at IS NULL RETURNING id",
+			tenant, parentID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		ids := []string{}
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+		return ids, rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]string), nil
+}
+
+func (r *SQLRepository) findVisitor(ctx context.Context, query string, args ...any) (*domain.Visitor, error) {
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		return scanVisitor(r.db.QueryRowContext(ctx, query, args...))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*domain.Visitor), nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanVisitor(row rowScanner) (*domain.Visitor, error) {
+	var visitor domain.Visitor
+	var revokedAt sql.NullTime
+	err := row.Scan(&visitor.ID, &visitor.ParentID, &visitor.Email, &visitor.DisplayName,
+		&visitor.ExpiresAt, &revokedAt, &visitor.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if revokedAt.Valid {
+		visitor.RevokedAt = &revokedAt.Time
+	}
+	return &visitor, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
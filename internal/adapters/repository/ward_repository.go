@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/lib/pq"
+)
+
+var _ ports.WardRepository = (*SQLRepository)(nil)
+
+func (r *SQLRepository) GetStaffWard(ctx context.Context, userID string) (string, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return r.queryString(ctx,
+		"SELECT s.ward FROM staff s JOIN users u ON u.id = s.user_id WHERE u.tenant_id = $1 AND s.user_id = $2",
+		tenant, userID,
+	)
+}
+
+func (r *SQLRepository) GetRoomWard(ctx context.Context, roomNumber string) (string, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return r.queryString(ctx, "SELECT ward FROM rooms WHERE tenant_id = $1 AND room_number = $2", tenant, roomNumber)
+}
+
+func (r *SQLRepository) GetRoomWards(ctx context.Context, roomNumbers []string) (map[string]string, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		rows, err := r.db.QueryContext(ctx,
+			"SELECT room_number, ward FROM rooms WHERE tenant_id = $1 AND room_number = ANY($2)",
+			tenant, pq.Array(roomNumbers),
+		)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		wards := make(map[string]string)
+		for rows.Next() {
+			var roomNumber, ward string
+			if err := rows.Scan(&roomNumber, &ward); err != nil {
+				return nil, err
+			}
+			wards[roomNumber] = ward
+		}
+		return wards, rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(map[string]string), nil
+}
+
+func (r *SQLRepository) IsWardScoped(ctx context.Context, role domain.Role) (bool, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		var scoped bool
+		err := r.db.QueryRowContext(ctx,
+			"SELECT ward_scoped FROM roles WHERE tenant_id = $1 AND name = $2",
+			tenant, role,
+		).Scan(&scoped)
+		return scoped, err
+	})
+	if err != nil {
+		return false, err
+	}
+	return result.(bool), nil
+}
+
+// queryString runs a single-row, single-column query through the circuit breaker
+func (r *SQLRepository) queryString(ctx context.Context, query string, args ...any) (string, error) {
+	result, err := r.cb.Execute(func() (interface{}, error) {
+		var value string
+		if err := r.db.QueryRowContext(ctx, query, args...).Scan(&value); err != nil {
+			return "", err
+		}
+		return value, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.(string), nil
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// loadAdminApprovalTTL reads ADMIN_APPROVAL_TTL (a Go duration, default 24h),
+// how long a request to create or remove an admin waits for a second admin.
+func loadAdminApprovalTTL() (time.Duration, error) {
+	value := os.Getenv("ADMIN_APPROVAL_TTL")
+	if value == "" {
+		return 24 * time.Hour, nil
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		return 0, fmt.Errorf("ADMIN_APPROVAL_TTL must be a positive duration, got %q", value)
+	}
+	return ttl, nil
+}
@@ -1,9 +1,12 @@
 package config
 
 import (
+	"database/sql"
+	"errors"
 	"log"
 	"time"
 
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
 	"github.com/sony/gobreaker"
 )
 
@@ -32,6 +35,11 @@ func NewCircuitBreaker(name string) *gobreaker.CircuitBreaker {
 			// Open circuit after 3 consecutive failures
 			return counts.ConsecutiveFailures >= 3
 		},
+		// A query that finds no row, or a request the data refused, was
+		// answered by a healthy database
+		IsSuccessful: func(err error) bool {
+			return err == nil || errors.Is(err, sql.ErrNoRows) || domain.IsRejection(err)
+		},
 		OnStateChange: func(name string, from, to gobreaker.State) {
 			log.Printf("[CRITICAL] Circuit Breaker %s: %s -> %s", name, from, to)
 		},
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+	"os"
+)
+
+// CloudEvents content modes of the AMQP protocol binding
+const (
+	// CloudEventsBinary keeps the payload as the message body and carries the
+	// event attributes in cloudEvents: application properties
+	CloudEventsBinary = "binary"
+	// CloudEventsStructured sends the whole event, attributes and data, as an
+	// application/cloudevents+json body
+	CloudEventsStructured = "structured"
+)
+
+// CloudEventsConfig chooses how the relay writes events to the broker.
+type CloudEventsConfig struct {
+	Mode string
+	// Source is the CloudEvents source attribute, a URI reference naming this service
+	Source string
+}
+
+// loadCloudEvents reads CLOUDEVENTS_MODE (binary or structured, default
+// binary, which consumers of the plain JSON payload keep understanding) and
+// CLOUDEVENTS_SOURCE (default /identity-access-service).
+func loadCloudEvents() (CloudEventsConfig, error) {
+	cfg := CloudEventsConfig{
+		Mode:   os.Getenv("CLOUDEVENTS_MODE"),
+		Source: os.Getenv("CLOUDEVENTS_SOURCE"),
+	}
+	switch cfg.Mode {
+	case "":
+		cfg.Mode = CloudEventsBinary
+	case CloudEventsBinary, CloudEventsStructured:
+	default:
+		return CloudEventsConfig{}, fmt.Errorf("CLOUDEVENTS_MODE must be %s or %s, got %q", CloudEventsBinary, CloudEventsStructured, cfg.Mode)
+	}
+	if cfg.Source == "" {
+		cfg.Source = "/identity-access-service"
+	}
+	return cfg, nil
+}
@@ -3,22 +3,26 @@ package config
 import (
 	"crypto/rsa"
 	"os"
-	"strings"
+	"time"
 
 	jwt "github.com/golang-jwt/jwt/v5"
 )
 
 type Config struct {
-	JWTPrivateKey      *rsa.PrivateKey
-	JWTPublicKey       *rsa.PublicKey
-	DatabaseURL        string
-	Port               string
-	GoogleClientID     string
-	GoogleClientSecret string
-	GoogleRedirectURL  string
-	RedisAddress       string
-	RedisPassword      string
-	CORSAllowedOrigins []string
+	JWTPrivateKey *rsa.PrivateKey
+	JWTPublicKey  *rsa.PublicKey
+	DatabaseURL   string
+	Port          string
+	RedisAddress  string
+	RedisPassword string
+	Tenants       []TenantConfig
+	// DefaultTenant serves requests that name no clinic by header or host.
+	// It is empty when several clinics are configured without DEFAULT_TENANT.
+	DefaultTenant string
+	Retention     RetentionConfig
+	Invitation    InvitationConfig
+	// AdminApprovalTTL is how long a second admin has to approve an admin change
+	AdminApprovalTTL time.Duration
 }
 
 func Load() *Config {
@@ -45,19 +49,14 @@ func Load() *Config {
 		panic("DB_CONNECTION_STRING environment variable is required")
 	}
 
-	googleClientID := os.Getenv("GOOGLE_CLIENT_ID")
-	if googleClientID == "" {
-		panic("GOOGLE_CLIENT_ID environment variable is required")
-	}
-
-	googleClientSecret := os.Getenv("GOOGLE_CLIENT_SECRET")
-	if googleClientSecret == "" {
-		panic("GOOGLE_CLIENT_SECRET environment variable is required")
+	tenants, err := loadTenants()
+	if err != nil {
+		panic("Failed to load tenants: " + err.Error())
 	}
 
-	googleRedirectURL := os.Getenv("GOOGLE_REDIRECT_URL")
-	if googleRedirectURL == "" {
-		panic("GOOGLE_REDIRECT_URL environment variable is required")
+	defaultTenant := os.Getenv("DEFAULT_TENANT")
+	if defaultTenant == "" && len(tenants) == 1 {
+		defaultTenant = tenants[0].ID
 	}
 
 	redisAddress := os.Getenv("REDIS_ADDRESS")
@@ -70,34 +69,43 @@ func Load() *Config {
 		panic("REDIS_PASSWORD environment variable is required")
 	}
 
+	retention, err := loadRetention()
+	if err != nil {
+		panic("Failed to load retention rules: " + err.Error())
+	}
+
+	invitation, err := loadInvitation()
+	if err != nil {
+		panic("Failed to load invitation settings: " + err.Error())
+	}
+
+	adminApprovalTTL, err := loadAdminApprovalTTL()
+	if err != nil {
+		panic("Failed to load admin approval settings: " + err.Error())
+	}
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
-	corsOrigins := os.Getenv("CORS_ALLOWED_ORIGINS")
-	var allowedOrigins []string
-	if corsOrigins == "" {
-		allowedOrigins = []string{"*"} // Default to allow all for development
-	} else {
-		allowedOrigins = strings.Split(corsOrigins, ",")
-		for i, origin := range allowedOrigins {
-			allowedOrigins[i] = strings.TrimSpace(origin)
-		}
-	}
-
-	return &Config{
-		JWTPrivateKey:      privateKey,
-		JWTPublicKey:       publicKey,
-		DatabaseURL:        dbURL,
-		Port:               port,
-		GoogleClientID:     googleClientID,
-		GoogleClientSecret: googleClientSecret,
-		GoogleRedirectURL:  googleRedirectURL,
-		RedisAddress:       redisAddress,
-		RedisPassword:      redisPassword,
-		CORSAllowedOrigins: allowedOrigins,
+	cfg := &Config{
+		JWTPrivateKey:    privateKey,
+		JWTPublicKey:     publicKey,
+		DatabaseURL:      dbURL,
+		Port:             port,
+		RedisAddress:     redisAddress,
+		RedisPassword:    redisPassword,
+		Tenants:          tenants,
+		DefaultTenant:    defaultTenant,
+		Retention:        retention,
+		Invitation:       invitation,
+		AdminApprovalTTL: adminApprovalTTL,
+	}
+	if _, ok := cfg.Tenant(defaultTenant); defaultTenant != "" && !ok {
+		panic("DEFAULT_TENANT names an unknown tenant: " + defaultTenant)
 	}
+	return cfg
 }
 
 func loadPrivateKey(path string) (*rsa.PrivateKey, error) {
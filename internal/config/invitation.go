@@ -0,0 +1,62 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// InvitationConfig holds how parent invitations are signed and mailed.
+type InvitationConfig struct {
+	// SigningKey signs the accept links
+	SigningKey []byte
+	// TTL is how long an accept link stays valid
+	TTL  time.Duration
+	SMTP SMTPConfig
+}
+
+// SMTPConfig is the mail relay invitations are sent through. Without a host,
+// mail is only logged.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// loadInvitation reads INVITATION_SIGNING_KEY (required, at least 32
+// characters), INVITATION_TTL (a Go duration, default 72h) and SMTP_HOST,
+// SMTP_PORT (default 587), SMTP_USERNAME, SMTP_PASSWORD and SMTP_FROM.
+func loadInvitation() (InvitationConfig, error) {
+	key := os.Getenv("INVITATION_SIGNING_KEY")
+	if len(key) < 32 {
+		return InvitationConfig{}, errors.New("INVITATION_SIGNING_KEY must be at least 32 characters")
+	}
+
+	ttl := 72 * time.Hour
+	if value := os.Getenv("INVITATION_TTL"); value != "" {
+		var err error
+		if ttl, err = time.ParseDuration(value); err != nil || ttl <= 0 {
+			return InvitationConfig{}, fmt.Errorf("INVITATION_TTL must be a positive duration, got %q", value)
+		}
+	}
+
+	port, err := envInt("SMTP_PORT", 587)
+	if err != nil {
+		return InvitationConfig{}, err
+	}
+	smtp := SMTPConfig{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     port,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	}
+	if smtp.Host != "" && smtp.From == "" {
+		return InvitationConfig{}, errors.New("SMTP_FROM is required with SMTP_HOST")
+	}
+
+	return InvitationConfig{SigningKey: []byte(key), TTL: ttl, SMTP: smtp}, nil
+}
@@ -1,13 +1,26 @@
 package config
 
-import "os"
+import (
+	"os"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
 
 // RelayConfig holds configuration for the outbox relay service.
 // This is a minimal config that only includes what the relay needs.
 type RelayConfig struct {
-	DatabaseURL   string
-	RabbitMQURL   string
-	BabyQueueName string
+	DatabaseURL          string
+	RabbitMQURL          string
+	BabyQueueName        string
+	ParentRoomQueueName  string
+	UserErasureQueueName string
+	// ParentDischargeQueueName lets the baby service close the records of discharged families
+	ParentDischargeQueueName string
+	// EventsQueueName receives every event type without a queue of its own
+	EventsQueueName string
+	// Topology is what the relay declares on the broker and publishes to
+	Topology    TopologyConfig
+	CloudEvents CloudEventsConfig
 }
 
 func LoadRelayConfig() *RelayConfig {
@@ -26,9 +39,59 @@ func LoadRelayConfig() *RelayConfig {
 		babyQueueName = "babies"
 	}
 
-	return &RelayConfig{
-		DatabaseURL:   dbURL,
-		RabbitMQURL:   rabbitURL,
-		BabyQueueName: babyQueueName,
+	parentRoomQueueName := os.Getenv("PARENT_ROOM_QUEUE_NAME")
+	if parentRoomQueueName == "" {
+		parentRoomQueueName = "parent-room-changes"
+	}
+
+	userErasureQueueName := os.Getenv("USER_ERASURE_QUEUE_NAME")
+	if userErasureQueueName == "" {
+		userErasureQueueName = "user-erasures"
+	}
+
+	parentDischargeQueueName := os.Getenv("PARENT_DISCHARGE_QUEUE_NAME")
+	if parentDischargeQueueName == "" {
+		parentDischargeQueueName = "parent-discharges"
+	}
+
+	eventsQueueName := os.Getenv("EVENTS_QUEUE_NAME")
+	if eventsQueueName == "" {
+		eventsQueueName = "identity-events"
+	}
+
+	cfg := &RelayConfig{
+		DatabaseURL:              dbURL,
+		RabbitMQURL:              rabbitURL,
+		BabyQueueName:            babyQueueName,
+		ParentRoomQueueName:      parentRoomQueueName,
+		UserErasureQueueName:     userErasureQueueName,
+		ParentDischargeQueueName: parentDischargeQueueName,
+		EventsQueueName:          eventsQueueName,
+	}
+
+	topology, err := loadTopology(cfg.EventRoutes(), eventsQueueName)
+	if err != nil {
+		panic("Failed to load RabbitMQ topology: " + err.Error())
+	}
+	cfg.Topology = topology
+
+	cloudEvents, err := loadCloudEvents()
+	if err != nil {
+		panic("Failed to load CloudEvents settings: " + err.Error())
+	}
+
+	cfg.CloudEvents = cloudEvents
+
+	return cfg
+}
+
+// EventRoutes maps the event types that have a dedicated consumer to its
+// queue in the queues topology.
+func (c *RelayConfig) EventRoutes() map[string]string {
+	return map[string]string{
+		ports.EventParentRegistered:  c.BabyQueueName,
+		ports.EventParentRoomChanged: c.ParentRoomQueueName,
+		ports.EventUserErased:        c.UserErasureQueueName,
+		ports.EventParentDischarged:  c.ParentDischargeQueueName,
 	}
 }
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// RetentionConfig holds the data retention rules applied by the background
+// retention job. A zero period disables its rule.
+type RetentionConfig struct {
+	// ParentAnonymizeAfter is how long after discharge a parent is anonymized
+	ParentAnonymizeAfter time.Duration
+	// OutboxRetention is how long processed outbox events are kept
+	OutboxRetention time.Duration
+	// BatchSize bounds how many records one batch touches
+	BatchSize int
+	// Interval is the time between two runs of the job
+	Interval time.Duration
+}
+
+// loadRetention reads RETENTION_PARENT_ANONYMIZE_DAYS (default 0, disabled),
+// RETENTION_OUTBOX_DAYS (default 0, disabled), RETENTION_BATCH_SIZE (default 200) and
+// RETENTION_INTERVAL (a Go duration, default 1h).
+func loadRetention() (RetentionConfig, error) {
+	parentDays, err := envInt("RETENTION_PARENT_ANONYMIZE_DAYS", 0)
+	if err != nil {
+		return RetentionConfig{}, err
+	}
+	outboxDays, err := envInt("RETENTION_OUTBOX_DAYS", 0)
+	if err != nil {
+		return RetentionConfig{}, err
+	}
+	batchSize, err := envInt("RETENTION_BATCH_SIZE", 200)
+	if err != nil {
+		return RetentionConfig{}, err
+	}
+	if batchSize < 1 {
+		return RetentionConfig{}, fmt.Errorf("RETENTION_BATCH_SIZE must be positive, got %d", batchSize)
+	}
+
+	interval := time.Hour
+	if value := os.Getenv("RETENTION_INTERVAL"); value != "" {
+		if interval, err = time.ParseDuration(value); err != nil || interval <= 0 {
+			return RetentionConfig{}, fmt.Errorf("RETENTION_INTERVAL must be a positive duration, got %q", value)
+		}
+	}
+
+	return RetentionConfig{
+		ParentAnonymizeAfter: time.Duration(parentDays) * 24 * time.Hour,
+		OutboxRetention:      time.Duration(outboxDays) * 24 * time.Hour,
+		BatchSize:            batchSize,
+		Interval:             interval,
+	}, nil
+}
+
+// envInt reads a non-negative integer environment variable
+func envInt(name string, fallback int) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer, got %q", name, value)
+	}
+	return n, nil
+}
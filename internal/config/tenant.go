@@ -0,0 +1,173 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// DefaultTenantID is used when the service runs with the legacy single-clinic environment variables.
+const DefaultTenantID = "default"
+
+// TenantConfig holds the identity-provider and CORS settings of one clinic.
+type TenantConfig struct {
+	ID                 string   `json:"id"`
+	Name               string   `json:"name"`
+	Hosts              []string `json:"hosts"`
+	GoogleClientID     string   `json:"google_client_id"`
+	GoogleClientSecret string   `json:"google_client_secret"`
+	GoogleRedirectURL  string   `json:"google_redirect_url"`
+	CORSAllowedOrigins []string `json:"cors_allowed_origins"`
+	// InvitationAcceptURL is where invitation emails link to; it defaults
+	// to /invitations/accept on the host of GoogleRedirectURL
+	InvitationAcceptURL string `json:"invitation_accept_url,omitempty"`
+	// BootstrapAdmin becomes the clinic's first admin while it has none
+	BootstrapAdmin *AdminAccount `json:"bootstrap_admin,omitempty"`
+	// ApproveAdminRequests lists pending admin approvals the operator approves
+	// on startup, for a clinic with a single admin
+	ApproveAdminRequests []string `json:"approve_admin_requests,omitempty"`
+}
+
+// AdminAccount names an admin created from configuration
+type AdminAccount struct {
+	Email     string `json:"email"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// AcceptURL returns the invitation accept endpoint of the clinic
+func (t TenantConfig) AcceptURL() string {
+	if t.InvitationAcceptURL != "" {
+		return t.InvitationAcceptURL
+	}
+	redirect, err := url.Parse(t.GoogleRedirectURL)
+	if err != nil {
+		return ""
+	}
+	return (&url.URL{Scheme: redirect.Scheme, Host: redirect.Host, Path: "/invitations/accept"}).String()
+}
+
+// Tenant returns the configuration of the clinic with the given ID
+func (c *Config) Tenant(id string) (*TenantConfig, bool) {
+	for i := range c.Tenants {
+		if c.Tenants[i].ID == id {
+			return &c.Tenants[i], true
+		}
+	}
+	return nil, false
+}
+
+// TenantForHost returns the clinic served on the given host (port is ignored)
+func (c *Config) TenantForHost(host string) (*TenantConfig, bool) {
+	if i := strings.LastIndex(host, ":"); i != -1 {
+		host = host[:i]
+	}
+	for i := range c.Tenants {
+		for _, h := range c.Tenants[i].Hosts {
+			if strings.EqualFold(h, host) {
+				return &c.Tenants[i], true
+			}
+		}
+	}
+	return nil, false
+}
+
+// loadTenants reads the clinics from TENANTS_CONFIG_PATH (a JSON array, usually a mounted secret).
+// Without it the GOOGLE_* and CORS_ALLOWED_ORIGINS variables describe a single default clinic.
+func loadTenants() ([]TenantConfig, error) {
+	path := os.Getenv("TENANTS_CONFIG_PATH")
+	if path == "" {
+		return []TenantConfig{legacyTenant()}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tenants []TenantConfig
+	if err := json.Unmarshal(data, &tenants); err != nil {
+		return nil, err
+	}
+	if len(tenants) == 0 {
+		return nil, errors.New("no tenants configured")
+	}
+
+	seen := make(map[string]bool, len(tenants))
+	for _, t := range tenants {
+		if t.ID == "" || t.GoogleClientID == "" || t.GoogleClientSecret == "" || t.GoogleRedirectURL == "" {
+			return nil, fmt.Errorf("tenant %q: id and google client settings are required", t.ID)
+		}
+		if a := t.BootstrapAdmin; a != nil && (a.Email == "" || a.FirstName == "" || a.LastName == "") {
+			return nil, fmt.Errorf("tenant %q: bootstrap_admin needs an email and names", t.ID)
+		}
+		if seen[t.ID] {
+			return nil, fmt.Errorf("tenant %q configured twice", t.ID)
+		}
+		seen[t.ID] = true
+	}
+	return tenants, nil
+}
+
+func legacyTenant() TenantConfig {
+	googleClientID := os.Getenv("GOOGLE_CLIENT_ID")
+	if googleClientID == "" {
+		panic("GOOGLE_CLIENT_ID environment variable is required")
+	}
+
+	googleClientSecret := os.Getenv("GOOGLE_CLIENT_SECRET")
+	if googleClientSecret == "" {
+		panic("GOOGLE_CLIENT_SECRET environment variable is required")
+	}
+
+	googleRedirectURL := os.Getenv("GOOGLE_REDIRECT_URL")
+	if googleRedirectURL == "" {
+		panic("GOOGLE_REDIRECT_URL environment variable is required")
+	}
+
+	corsOrigins := os.Getenv("CORS_ALLOWED_ORIGINS")
+	var allowedOrigins []string
+	if corsOrigins == "" {
+		allowedOrigins = []string{"*"} // Default to allow all for development
+	} else {
+		allowedOrigins = strings.Split(corsOrigins, ",")
+		for i, origin := range allowedOrigins {
+			allowedOrigins[i] = strings.TrimSpace(origin)
+		}
+	}
+
+	var bootstrapAdmin *AdminAccount
+	if email := os.Getenv("BOOTSTRAP_ADMIN_EMAIL"); email != "" {
+		bootstrapAdmin = &AdminAccount{
+			Email:     email,
+			FirstName: os.Getenv("BOOTSTRAP_ADMIN_FIRST_NAME"),
+			LastName:  os.Getenv("BOOTSTRAP_ADMIN_LAST_NAME"),
+		}
+		if bootstrapAdmin.FirstName == "" || bootstrapAdmin.LastName == "" {
+			panic("BOOTSTRAP_ADMIN_FIRST_NAME and BOOTSTRAP_ADMIN_LAST_NAME are required with BOOTSTRAP_ADMIN_EMAIL")
+		}
+	}
+
+	var approveAdminRequests []string
+	if ids := os.Getenv("APPROVE_ADMIN_REQUESTS"); ids != "" {
+		approveAdminRequests = strings.Split(ids, ",")
+		for i, id := range approveAdminRequests {
+			approveAdminRequests[i] = strings.TrimSpace(id)
+		}
+	}
+
+	return TenantConfig{
+		ID:                   DefaultTenantID,
+		Name:                 "Baby Kliniek",
+		GoogleClientID:       googleClientID,
+		GoogleClientSecret:   googleClientSecret,
+		GoogleRedirectURL:    googleRedirectURL,
+		CORSAllowedOrigins:   allowedOrigins,
+		InvitationAcceptURL:  os.Getenv("INVITATION_ACCEPT_URL"),
+		BootstrapAdmin:       bootstrapAdmin,
+		ApproveAdminRequests: approveAdminRequests,
+	}
+}
@@ -0,0 +1,207 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Topology modes of the relay's RabbitMQ broker
+const (
+	// TopologyQueues publishes through the default exchange straight to one
+	// queue per routed event type, as the relay always did. One consumer
+	// reads each queue, so an event reaches a single service
+	TopologyQueues = "queues"
+	// TopologyExchange publishes every event to one exchange with a routing
+	// key derived from its type, and each consumer binds its own queue
+	TopologyExchange = "exchange"
+)
+
+// DefaultRoutingKeyPrefix is prepended to the event type to form the routing
+// key, e.g. identity.parent.registered
+const DefaultRoutingKeyPrefix = "identity"
+
+// TopologyConfig describes the exchanges, queues and bindings the relay
+// declares at startup, and where it publishes each event.
+type TopologyConfig struct {
+	Mode string `json:"-"`
+	// Exchange receives every event in exchange mode
+	Exchange         string           `json:"exchange"`
+	RoutingKeyPrefix string           `json:"routing_key_prefix,omitempty"`
+	Exchanges        []ExchangeConfig `json:"exchanges"`
+	Queues           []QueueConfig    `json:"queues"`
+	Bindings         []BindingConfig  `json:"bindings"`
+	// Routes and DefaultQueue name the queue of each event type in queues mode
+	Routes       map[string]string `json:"-"`
+	DefaultQueue string            `json:"-"`
+}
+
+// ExchangeConfig declares a durable exchange.
+type ExchangeConfig struct {
+	Name string `json:"name"`
+	// Type is direct, fanout, topic or headers
+	Type string `json:"type"`
+}
+
+// QueueConfig declares a durable queue. Messages that expire, are rejected or
+// overflow MaxLength go to DeadLetterExchange when one is set.
+type QueueConfig struct {
+	Name                 string `json:"name"`
+	DeadLetterExchange   string `json:"dead_letter_exchange,omitempty"`
+	DeadLetterRoutingKey string `json:"dead_letter_routing_key,omitempty"`
+	// MessageTTL is a Go duration, e.g. 72h
+	MessageTTL string `json:"message_ttl,omitempty"`
+	MaxLength  int    `json:"max_length,omitempty"`
+}
+
+// BindingConfig binds a queue to an exchange. Topic exchanges accept the
+// * and # wildcards in RoutingKey, e.g. identity.parent.#
+type BindingConfig struct {
+	Queue      string `json:"queue"`
+	Exchange   string `json:"exchange"`
+	RoutingKey string `json:"routing_key"`
+}
+
+// RoutingKey returns the routing key of an event type.
+func (t TopologyConfig) RoutingKey(eventType string) string {
+	return t.RoutingKeyPrefix + "." + eventType
+}
+
+// Destination returns the exchange and routing key an event type is
+// published with. In queues mode that is the default exchange and the name
+// of the event type's queue.
+func (t TopologyConfig) Destination(eventType string) (exchange, routingKey string) {
+	if t.Mode == TopologyExchange {
+		return t.Exchange, t.RoutingKey(eventType)
+	}
+	if queue, ok := t.Routes[eventType]; ok {
+		return "", queue
+	}
+	return "", t.DefaultQueue
+}
+
+// Arguments returns the x- queue arguments of the queue.
+func (q QueueConfig) Arguments() (map[string]interface{}, error) {
+	args := make(map[string]interface{})
+	if q.DeadLetterExchange != "" {
+		args["x-dead-letter-exchange"] = q.DeadLetterExchange
+	}
+	if q.DeadLetterRoutingKey != "" {
+		args["x-dead-letter-routing-key"] = q.DeadLetterRoutingKey
+	}
+	if q.MessageTTL != "" {
+		ttl, err := time.ParseDuration(q.MessageTTL)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("queue %q: message_ttl must be a positive duration, got %q", q.Name, q.MessageTTL)
+		}
+		args["x-message-ttl"] = ttl.Milliseconds()
+	}
+	if q.MaxLength > 0 {
+		args["x-max-length"] = int64(q.MaxLength)
+	}
+	if len(args) == 0 {
+		return nil, nil
+	}
+	return args, nil
+}
+
+// loadTopology reads the topology from RABBITMQ_TOPOLOGY_PATH (a JSON object,
+// usually a mounted config map). Without it the relay keeps the queues mode:
+// the routed queues and the default queue, addressed through the default
+// exchange.
+func loadTopology(routes map[string]string, defaultQueue string) (TopologyConfig, error) {
+	path := os.Getenv("RABBITMQ_TOPOLOGY_PATH")
+	if path == "" {
+		return QueuesTopology(routes, defaultQueue), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return TopologyConfig{}, err
+	}
+
+	var topology TopologyConfig
+	if err := json.Unmarshal(data, &topology); err != nil {
+		return TopologyConfig{}, err
+	}
+	topology.Mode = TopologyExchange
+	if topology.RoutingKeyPrefix == "" {
+		topology.RoutingKeyPrefix = DefaultRoutingKeyPrefix
+	}
+	if err := topology.validate(); err != nil {
+		return TopologyConfig{}, err
+	}
+	return topology, nil
+}
+
+// QueuesTopology is the queues mode topology: one durable queue per route and
+// the default queue, which receives every event type without a route.
+func QueuesTopology(routes map[string]string, defaultQueue string) TopologyConfig {
+	topology := TopologyConfig{
+		Mode:             TopologyQueues,
+		RoutingKeyPrefix: DefaultRoutingKeyPrefix,
+		Routes:           routes,
+		DefaultQueue:     defaultQueue,
+	}
+	queues := []string{defaultQueue}
+	for _, queue := range routes {
+		queues = append(queues, queue)
+	}
+	seen := make(map[string]bool, len(queues))
+	for _, queue := range queues {
+		if !seen[queue] {
+			seen[queue] = true
+			topology.Queues = append(topology.Queues, QueueConfig{Name: queue})
+		}
+	}
+	return topology
+}
+
+// validate checks that the publish exchange and every binding refer to
+// declared exchanges and queues.
+func (t TopologyConfig) validate() error {
+	exchanges := make(map[string]bool, len(t.Exchanges))
+	for _, e := range t.Exchanges {
+		if e.Name == "" {
+			return errors.New("exchange name is required")
+		}
+		switch e.Type {
+		case "direct", "fanout", "topic", "headers":
+		default:
+			return fmt.Errorf("exchange %q: type must be direct, fanout, topic or headers, got %q", e.Name, e.Type)
+		}
+		if exchanges[e.Name] {
+			return fmt.Errorf("exchange %q declared twice", e.Name)
+		}
+		exchanges[e.Name] = true
+	}
+	if !exchanges[t.Exchange] {
+		return fmt.Errorf("publish exchange %q is not declared", t.Exchange)
+	}
+
+	queues := make(map[string]bool, len(t.Queues))
+	for _, q := range t.Queues {
+		if q.Name == "" {
+			return errors.New("queue name is required")
+		}
+		if queues[q.Name] {
+			return fmt.Errorf("queue %q declared twice", q.Name)
+		}
+		if _, err := q.Arguments(); err != nil {
+			return err
+		}
+		queues[q.Name] = true
+	}
+
+	for _, b := range t.Bindings {
+		if !queues[b.Queue] {
+			return fmt.Errorf("binding of queue %q: queue is not declared", b.Queue)
+		}
+		if !exchanges[b.Exchange] {
+			return fmt.Errorf("binding of queue %q: exchange %q is not declared", b.Queue, b.Exchange)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AdminApprovalAction is the change to the clinic's admins an approval asks for.
+type AdminApprovalAction string
+
+const (
+	AdminApprovalCreate AdminApprovalAction = "CreateAdmin"
+	AdminApprovalRemove AdminApprovalAction = "RemoveAdmin"
+)
+
+// AdminApprovalStatus is the stage of an admin approval.
+type AdminApprovalStatus string
+
+const (
+	AdminApprovalPending  AdminApprovalStatus = "Pending"
+	AdminApprovalApproved AdminApprovalStatus = "Approved"
+	AdminApprovalRejected AdminApprovalStatus = "Rejected"
+	AdminApprovalExpired  AdminApprovalStatus = "Expired"
+)
+
+var (
+	ErrAdminApprovalNotFound = fmt.Errorf("admin approval %w", ErrNotFound)
+	ErrAdminApprovalClosed   = errors.New("admin approval has already been decided")
+	ErrAdminApprovalExpired  = errors.New("admin approval has expired")
+	ErrAdminApprovalPending  = errors.New("an approval for this admin is already pending")
+	ErrSelfApproval          = errors.New("admin approvals must be decided by another admin")
+	// ErrLastAdmin is returned when a change would leave the clinic without an active admin.
+	ErrLastAdmin = errors.New("the clinic must keep at least one active admin")
+)
+
+// AdminApproval is a request to create or remove an admin that only takes
+// effect once a second admin approves it before ExpiresAt. A creation carries
+// the new admin's details; a removal names the admin in TargetUserID.
+type AdminApproval struct {
+	ID           string              `json:"id"`
+	Action       AdminApprovalAction `json:"action"`
+	Status       AdminApprovalStatus `json:"status"`
+	Email        string              `json:"email,omitempty"`
+	FirstName    string              `json:"first_name,omitempty"`
+	LastName     string              `json:"last_name,omitempty"`
+	TargetUserID string              `json:"target_user_id,omitempty"`
+	RequestedBy  string              `json:"requested_by"`
+	CreatedAt    time.Time           `json:"created_at"`
+	ExpiresAt    time.Time           `json:"expires_at"`
+	DecidedBy    string              `json:"decided_by,omitempty"`
+	DecidedAt    *time.Time          `json:"decided_at,omitempty"`
+}
+
+// Expired reports whether a pending approval can no longer be decided
+func (a AdminApproval) Expired(now time.Time) bool {
+	return a.Status == AdminApprovalPending && !now.Before(a.ExpiresAt)
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrAdmissionNotFound = fmt.Errorf("admission %w", ErrNotFound)
+	ErrAdmissionClosed   = errors.New("admission has been discharged")
+)
+
+// Admission groups the parents of one baby. They share a room, and the baby
+// is created downstream once, when the admission is opened by its first parent.
+type Admission struct {
+	ID         string    `json:"id"`
+	RoomNumber string    `json:"room_number"`
+	CreatedAt  time.Time `json:"created_at"`
+	Parents    []Parent  `json:"parents"`
+}
+
+// Open reports whether any parent of the admission is still at, or expected at, the clinic
+func (a Admission) Open() bool {
+	for _, parent := range a.Parents {
+		if !parent.Status.HasLeft() {
+			return true
+		}
+	}
+	return false
+}
+
+// CurrentRoom is the room of the parents still at the clinic, or of the first parent once all have left
+func (a Admission) CurrentRoom() string {
+	for _, parent := range a.Parents {
+		if !parent.Status.HasLeft() {
+			return parent.RoomNumber
+		}
+	}
+	if len(a.Parents) > 0 {
+		return a.Parents[0].RoomNumber
+	}
+	return ""
+}
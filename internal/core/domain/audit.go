@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+type AuditAction string
+
+const (
+	AuditImpersonationStarted    AuditAction = "IMPERSONATION_STARTED"
+	AuditImpersonationEnded      AuditAction = "IMPERSONATION_ENDED"
+	AuditEnrollmentCodeIssued    AuditAction = "ENROLLMENT_CODE_ISSUED"
+	AuditUsersExported           AuditAction = "USERS_EXPORTED"
+	AuditSubjectAccessRequested  AuditAction = "SUBJECT_ACCESS_REQUESTED"
+	AuditSubjectAccessDownloaded AuditAction = "SUBJECT_ACCESS_DOWNLOADED"
+)
+
+// AuditEntry records a privileged action taken by an actor against a subject.
+type AuditEntry struct {
+	ID        string            `json:"id"`
+	ActorID   string            `json:"actor_id"`
+	Action    AuditAction       `json:"action"`
+	SubjectID string            `json:"subject_id"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
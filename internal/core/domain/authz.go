@@ -0,0 +1,76 @@
+package domain
+
+import "time"
+
+type PolicyEffect string
+
+const (
+	EffectAllow PolicyEffect = "allow"
+	EffectDeny  PolicyEffect = "deny"
+)
+
+// AnyAction matches every action in a policy.
+const AnyAction = "*"
+
+// AuthzRequest asks whether subject may perform action on a resource described by its attributes.
+type AuthzRequest struct {
+	Subject  string            `json:"subject"`
+	Action   string            `json:"action"`
+	Resource map[string]string `json:"resource"`
+}
+
+type AuthzDecision struct {
+	Allowed  bool   `json:"allowed"`
+	Reason   string `json:"reason"`
+	PolicyID string `json:"policy_id,omitempty"`
+}
+
+// PolicyCondition compares a subject attribute with either a resource
+// attribute or a fixed value. A missing subject attribute never matches.
+type PolicyCondition struct {
+	Subject  string `json:"subject"`
+	Resource string `json:"resource,omitempty"`
+	Value    string `json:"value,omitempty"`
+}
+
+// Policy is an attribute-based rule. It applies when the action matches, the
+// subject has SubjectRole (empty matches any role) and every condition holds.
+type Policy struct {
+	ID          string            `json:"id"`
+	Description string            `json:"description"`
+	Action      string            `json:"action"`
+	Effect      PolicyEffect      `json:"effect"`
+	SubjectRole Role              `json:"subject_role,omitempty"`
+	Conditions  []PolicyCondition `json:"conditions"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// DecisionLogEntry records a decision together with the request that produced it.
+type DecisionLogEntry struct {
+	ID        string
+	Request   AuthzRequest
+	Decision  AuthzDecision
+	CreatedAt time.Time
+}
+
+// Matches reports whether the policy applies to the subject and resource attributes.
+// The action is assumed to have been matched when the policy was loaded.
+func (p Policy) Matches(subject, resource map[string]string) bool {
+	if p.SubjectRole != "" && subject["role"] != string(p.SubjectRole) {
+		return false
+	}
+	for _, c := range p.Conditions {
+		actual := subject[c.Subject]
+		if actual == "" {
+			return false
+		}
+		expected := c.Value
+		if c.Resource != "" {
+			expected = resource[c.Resource]
+		}
+		if actual != expected {
+			return false
+		}
+	}
+	return true
+}
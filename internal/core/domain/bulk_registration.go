@@ -0,0 +1,42 @@
+package domain
+
+// BulkParentRow is one parent read from a bulk registration upload.
+type BulkParentRow struct {
+	// Line is the line of the upload the row was read from, for error reporting
+	Line       int
+	Email      string
+	FirstName  string
+	LastName   string
+	RoomNumber string
+	// ParseError is set when the line itself could not be read
+	ParseError string
+}
+
+type BulkRowStatus string
+
+const (
+	BulkRowCreated BulkRowStatus = "created"
+	BulkRowValid   BulkRowStatus = "valid" // passed validation in a dry run
+	BulkRowInvalid BulkRowStatus = "invalid"
+)
+
+// BulkRowResult is the outcome of one row of a bulk registration.
+type BulkRowResult struct {
+	Line   int           `json:"line"`
+	Email  string        `json:"email,omitempty"`
+	Status BulkRowStatus `json:"status"`
+	UserID string        `json:"user_id,omitempty"`
+	// InvitationSent is false for a created parent whose invitation email failed; resend it
+	InvitationSent bool     `json:"invitation_sent,omitempty"`
+	Errors         []string `json:"errors,omitempty"`
+}
+
+// BulkRegistrationReport summarizes a bulk registration, row by row.
+type BulkRegistrationReport struct {
+	DryRun  bool            `json:"dry_run"`
+	Total   int             `json:"total"`
+	Valid   int             `json:"valid"`
+	Created int             `json:"created"`
+	Invalid int             `json:"invalid"`
+	Rows    []BulkRowResult `json:"rows"`
+}
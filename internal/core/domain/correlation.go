@@ -0,0 +1,17 @@
+package domain
+
+import "context"
+
+type correlationKey struct{}
+
+// WithCorrelationID returns a context tagged with the ID that ties together
+// the request and the events it causes.
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, correlationID)
+}
+
+// CorrelationIDFromContext returns the correlation ID of the context, if any.
+func CorrelationIDFromContext(ctx context.Context) (string, bool) {
+	correlationID, ok := ctx.Value(correlationKey{}).(string)
+	return correlationID, ok && correlationID != ""
+}
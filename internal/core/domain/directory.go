@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrVersionConflict is returned when a user changed since the version the caller read.
+	ErrVersionConflict = errors.New("user has been modified; reload and retry")
+)
+
+// UserRecord is a user as shown in the admin directory, including the
+// parent record when the user is a parent.
+// Version increases with every change and backs optimistic concurrency.
+type UserRecord struct {
+	User
+	Version int           `json:"version"`
+	Parent  *ParentRecord `json:"parent,omitempty"`
+}
+
+type ParentRecord struct {
+	RoomNumber string       `json:"room_number"`
+	Status     ParentStatus `json:"status"`
+}
+
+// UserCursor is the keyset position of the last user on a page.
+// Users are listed newest first, ties broken by ID.
+type UserCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// UserFilter narrows a directory listing. Zero values do not filter.
+type UserFilter struct {
+	Role         Role
+	ParentStatus ParentStatus
+	RoomNumber   string
+	CreatedFrom  time.Time
+	CreatedTo    time.Time
+	// Search matches name or email, case-insensitively
+	Search string
+	After  *UserCursor
+	Limit  int
+}
+
+// UserUpdate is a partial change to a user. Nil fields are left unchanged.
+// RoomNumber only applies to parents and is carried out as a room transfer.
+type UserUpdate struct {
+	Email      *string `json:"email"`
+	FirstName  *string `json:"first_name"`
+	LastName   *string `json:"last_name"`
+	RoomNumber *string `json:"room_number"`
+}
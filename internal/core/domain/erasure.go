@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ErasedValue replaces every piece of personal data removed by an erasure.
+const ErasedValue = "[erased]"
+
+// ErrNothingToErase is returned when the user does not exist or was already erased.
+var ErrNothingToErase = fmt.Errorf("user %w or already erased", ErrNotFound)
+
+// personalDataKeys are the payload fields that may carry personal data. Reasons
+// are free text and can mention names, so they are scrubbed as well.
+var personalDataKeys = map[string]bool{
+	"email":         true,
+	"first_name":    true,
+	"last_name":     true,
+	"display_name":  true,
+	"contact_email": true,
+	"reason":        true,
+}
+
+// ErasureTombstone records that a user's personal data was erased, and by whom.
+// It holds no personal data itself, so it can be kept indefinitely.
+type ErasureTombstone struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"user_id"`
+	Role        Role      `json:"role"`
+	RequestedBy string    `json:"requested_by"`
+	ErasedAt    time.Time `json:"erased_at"`
+}
+
+// ErasedEmail is the pseudonymous address an erased user keeps; it stays unique
+// per user and can never receive mail.
+func ErasedEmail(userID string) string {
+	return "erased+" + userID + "@erased.invalid"
+}
+
+// ScrubPersonalData replaces the values of personal data fields, at any depth,
+// in a JSON event payload with ErasedValue.
+func ScrubPersonalData(payload []byte) ([]byte, error) {
+	var data any
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, err
+	}
+	return json.Marshal(scrub(data))
+}
+
+func scrub(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if _, isString := field.(string); isString && personalDataKeys[key] {
+				v[key] = ErasedValue
+			} else {
+				v[key] = scrub(field)
+			}
+		}
+	case []any:
+		for i := range v {
+			v[i] = scrub(v[i])
+		}
+	}
+	return value
+}
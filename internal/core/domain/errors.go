@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// The typed error set shared by services and repositories. Specific errors
+// wrap one of these, so adapters can map a whole family to a response
+// without knowing every error a service returns.
+var (
+	// ErrValidation is matched by every *ValidationError.
+	ErrValidation = errors.New("validation failed")
+	// ErrNotFound is returned when the requested resource does not exist in the clinic.
+	ErrNotFound = errors.New("not found")
+	// ErrEmailTaken is returned when another account in the clinic already uses the email.
+	ErrEmailTaken = errors.New("email is already in use")
+	// ErrDischarged is returned when an operation needs an admitted parent.
+	ErrDischarged = errors.New("parent has been discharged")
+	// ErrUnavailable is wrapped around a failed lookup that a decision depends
+	// on, so the request fails instead of being decided without the data.
+	ErrUnavailable = errors.New("service temporarily unavailable")
+)
+
+// IsRejection reports whether err refuses the request rather than reporting
+// a failure: the input was invalid, or the data did not allow the change. A
+// dependency that answered with a rejection is healthy, so circuit breakers
+// count these as successes.
+func IsRejection(err error) bool {
+	if errors.Is(err, ErrValidation) {
+		return true
+	}
+	for _, rejection := range []error{
+		ErrNotFound, ErrEmailTaken, ErrDischarged, ErrVersionConflict, ErrLastAdmin,
+		ErrAdminApprovalClosed, ErrAdminApprovalExpired, ErrAdminApprovalPending, ErrSelfApproval,
+		ErrAdmissionClosed, ErrInvitationInvalid, ErrInvitationExpired, ErrInvitationClosed,
+		ErrInvitationEmailMismatch, ErrInvitationOutstanding, ErrSubjectAccessInProgress, ErrSubjectAccessNotReady, ErrSubjectAccessExpired,
+	} {
+		if errors.Is(err, rejection) {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldError describes why one input field was rejected. Field is the JSON
+// name of the field, or empty when the input as a whole was rejected.
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every rejected field of one input.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// InvalidField returns a validation error for a single field
+func InvalidField(field, message string) *ValidationError {
+	return &ValidationError{Fields: []FieldError{{Field: field, Message: message}}}
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		messages[i] = f.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Is matches ErrValidation, and any validation error whose fields are all
+// part of e, so errors.Is still finds a single-field error after it was
+// collected together with others.
+func (e *ValidationError) Is(target error) bool {
+	if target == ErrValidation {
+		return true
+	}
+	other, ok := target.(*ValidationError)
+	if !ok || len(other.Fields) == 0 {
+		return false
+	}
+	for _, want := range other.Fields {
+		found := false
+		for _, f := range e.Fields {
+			if f == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Validation collects field errors so an input can be rejected with all its
+// problems at once.
+type Validation struct {
+	fields []FieldError
+}
+
+// Append records the fields of err
+func (v *Validation) Append(err *ValidationError) {
+	v.fields = append(v.fields, err.Fields...)
+}
+
+// Add records a rejected field
+func (v *Validation) Add(field, message string) {
+	v.fields = append(v.fields, FieldError{Field: field, Message: message})
+}
+
+// Err returns the collected errors, or nil when there are none
+func (v *Validation) Err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: v.fields}
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+type ExportFormat string
+
+const (
+	ExportCSV    ExportFormat = "csv"
+	ExportNDJSON ExportFormat = "ndjson"
+)
+
+// ExportColumns are the columns a user export may select, in their default order.
+var ExportColumns = []string{
+	"id", "email", "role", "first_name", "last_name", "room_number", "status", "created_at",
+}
+
+// ExportValue returns the value of an export column for the record; parent
+// columns are empty for other roles.
+func (r UserRecord) ExportValue(column string) string {
+	switch column {
+	case "id":
+		return r.ID
+	case "email":
+		return r.Email
+	case "role":
+		return string(r.Role)
+	case "first_name":
+		return r.FirstName
+	case "last_name":
+		return r.LastName
+	case "created_at":
+		return r.CreatedAt.UTC().Format(time.RFC3339)
+	case "room_number":
+		if r.Parent != nil {
+			return r.Parent.RoomNumber
+		}
+	case "status":
+		if r.Parent != nil {
+			return string(r.Parent.Status)
+		}
+	}
+	return ""
+}
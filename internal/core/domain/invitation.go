@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// InvitationStatus is the stage of a parent's invitation.
+type InvitationStatus string
+
+const (
+	InvitationPending   InvitationStatus = "Pending"
+	InvitationAccepted  InvitationStatus = "Accepted"
+	InvitationCancelled InvitationStatus = "Cancelled"
+)
+
+var (
+	ErrInvitationNotFound      = fmt.Errorf("invitation %w", ErrNotFound)
+	ErrInvitationInvalid       = errors.New("invitation link is invalid")
+	ErrInvitationExpired       = errors.New("invitation has expired")
+	ErrInvitationClosed        = errors.New("invitation has already been accepted or cancelled")
+	ErrInvitationEmailMismatch = errors.New("signed-in email does not match the invitation")
+	// ErrInvitationOutstanding is returned when staff try to admit a parent
+	// who is admitted by accepting their pending invitation
+	ErrInvitationOutstanding = errors.New("parent is admitted by accepting their invitation; resend or cancel it")
+)
+
+// Invitation asks a newly registered parent to confirm their email. The
+// parent stays Pending until they sign in with that email through the link.
+// Sends counts the emails sent; only the link of the latest one is valid.
+type Invitation struct {
+	ID          string           `json:"id"`
+	UserID      string           `json:"user_id"`
+	Email       string           `json:"email"`
+	Status      InvitationStatus `json:"status"`
+	Sends       int              `json:"sends"`
+	CreatedAt   time.Time        `json:"created_at"`
+	LastSentAt  time.Time        `json:"last_sent_at"`
+	ExpiresAt   time.Time        `json:"expires_at"`
+	AcceptedAt  *time.Time       `json:"accepted_at,omitempty"`
+	CancelledAt *time.Time       `json:"cancelled_at,omitempty"`
+}
+
+// Expired reports whether the latest link can no longer be used
+func (i Invitation) Expired(now time.Time) bool {
+	return !now.Before(i.ExpiresAt)
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// ParentStatusChange is one transition in a parent's lifecycle.
+type ParentStatusChange struct {
+	ID         string       `json:"id"`
+	ParentID   string       `json:"parent_id"`
+	FromStatus ParentStatus `json:"from_status"`
+	ToStatus   ParentStatus `json:"to_status"`
+	Reason     string       `json:"reason,omitempty"`
+	ChangedBy  string       `json:"changed_by"`
+	ChangedAt  time.Time    `json:"changed_at"`
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+type Permission string
+
+// Permissions checked by this service. Other permissions may exist in the
+// database for use by downstream services; these are the ones routes require.
+const (
+	PermUsersRegister      Permission = "users:register"
+	PermInvitationsManage  Permission = "invitations:manage"
+	PermAdminsApprove      Permission = "admins:approve"
+	PermUsersRead          Permission = "users:read"
+	PermUsersManage        Permission = "users:manage"
+	PermUsersExport        Permission = "users:export"
+	PermUsersErase         Permission = "users:erase"
+	PermUsersSubjectAccess Permission = "users:subject-access"
+	PermUsersImpersonate   Permission = "users:impersonate"
+	PermParentsDischarge   Permission = "parents:discharge"
+	PermParentsTransfer    Permission = "parents:transfer"
+	PermParentsAdmit       Permission = "parents:admit"
+	PermParentsArchive     Permission = "parents:archive"
+	PermEnrollmentIssue    Permission = "enrollment:issue"
+	PermVisitorsManage     Permission = "visitors:manage"
+	PermSessionLogout      Permission = "session:logout"
+	PermRolesManage        Permission = "roles:manage"
+	PermAuthzCheck         Permission = "authz:check"
+	PermAuthzManage        Permission = "authz:manage"
+	PermRetentionManage    Permission = "retention:manage"
+	PermSelfSubjectAccess  Permission = "self:subject-access"
+	PermSelfProfile        Permission = "self:profile"
+)
+
+// AdminOnly reports whether the permission decides who is an admin or what
+// roles grant. Only the ADMIN role holds these, so no other role can sidestep
+// the four-eyes approval of admins.
+func (p Permission) AdminOnly() bool {
+	return strings.HasPrefix(string(p), "admins:") || strings.HasPrefix(string(p), "roles:")
+}
+
+// RoleDefinition maps a role to the permissions it grants. Version is bumped
+// every time the mapping changes so tokens can be traced to the mapping they were issued under.
+type RoleDefinition struct {
+	Name        Role         `json:"name"`
+	Description string       `json:"description"`
+	Permissions []Permission `json:"permissions"`
+	// WardScoped roles are assigned to a ward at registration and may only act
+	// on parents in rooms of that ward. It is fixed when the role is created.
+	WardScoped bool      `json:"ward_scoped"`
+	Version    int       `json:"version"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type PermissionDefinition struct {
+	Name        Permission `json:"name"`
+	Description string     `json:"description"`
+}
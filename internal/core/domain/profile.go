@@ -0,0 +1,36 @@
+package domain
+
+import "strings"
+
+// Locales the service answers in. Errors and notifications in any other
+// language fall back to DefaultLocale.
+const (
+	LocaleEnglish = "en"
+	LocaleDutch   = "nl"
+	DefaultLocale = LocaleEnglish
+)
+
+// SupportedLocales are the locales a user may prefer
+var SupportedLocales = []string{LocaleEnglish, LocaleDutch}
+
+// ParseLocale returns the supported locale of a language tag such as "nl",
+// "nl-BE" or "en_GB". Only the language is significant.
+func ParseLocale(tag string) (string, bool) {
+	language, _, _ := strings.Cut(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"), "-")
+	language = strings.ToLower(language)
+	for _, locale := range SupportedLocales {
+		if language == locale {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// ProfileUpdate is a user's change to their own profile. Nil fields are left
+// unchanged and an empty value clears the field. Everything else about the
+// user, such as their sign-in email, name and room, is maintained by the clinic.
+type ProfileUpdate struct {
+	DisplayName  *string `json:"display_name"`
+	Locale       *string `json:"locale"`
+	ContactEmail *string `json:"contact_email"`
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// Retention rules applied by the retention job
+const (
+	RetentionAnonymizeParents = "anonymize_discharged_parents"
+	RetentionDeleteOutbox     = "delete_processed_outbox_events"
+	// RetentionPurgeArchives always runs: archives carry their own expiry
+	RetentionPurgeArchives = "purge_expired_subject_access_archives"
+)
+
+// RetentionActor is recorded as the requester of erasures made by the retention job.
+const RetentionActor = "system:retention"
+
+// RetentionPolicy says how long data is kept. A zero period disables its rule.
+type RetentionPolicy struct {
+	ParentAnonymizeAfter time.Duration
+	OutboxRetention      time.Duration
+	BatchSize            int
+}
+
+// RetentionRuleReport tells how many records a rule selects and, outside of
+// dry runs, what it did with them.
+type RetentionRuleReport struct {
+	Rule    string     `json:"rule"`
+	Enabled bool       `json:"enabled"`
+	Cutoff  *time.Time `json:"cutoff,omitempty"`
+	Due     int        `json:"due"`
+	Applied int        `json:"applied"`
+	Failed  int        `json:"failed"`
+}
+
+// RetentionReport is the outcome of one retention run, or dry run, for one clinic.
+type RetentionReport struct {
+	TenantID    string                `json:"tenant_id"`
+	DryRun      bool                  `json:"dry_run"`
+	GeneratedAt time.Time             `json:"generated_at"`
+	Rules       []RetentionRuleReport `json:"rules"`
+}
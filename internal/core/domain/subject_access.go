@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type SubjectAccessStatus string
+
+const (
+	SubjectAccessPending   SubjectAccessStatus = "Pending"
+	SubjectAccessRunning   SubjectAccessStatus = "Running"
+	SubjectAccessCompleted SubjectAccessStatus = "Completed"
+	SubjectAccessFailed    SubjectAccessStatus = "Failed"
+)
+
+// SubjectAccessArchiveFormat identifies the layout of the archive so consumers
+// can tell future versions apart.
+const SubjectAccessArchiveFormat = "subject-access-archive/v1"
+
+var (
+	ErrSubjectAccessNotFound   = fmt.Errorf("subject access request %w", ErrNotFound)
+	ErrSubjectAccessInProgress = errors.New("a subject access request for this user is already in progress")
+	ErrSubjectAccessNotReady   = errors.New("subject access archive is not ready")
+	ErrSubjectAccessExpired    = errors.New("subject access archive has expired")
+)
+
+// SubjectAccessRequest tracks the asynchronous export of all data held about a
+// user (GDPR article 15). The archive itself is stored separately and only
+// kept until ExpiresAt.
+type SubjectAccessRequest struct {
+	ID          string              `json:"id"`
+	UserID      string              `json:"user_id"`
+	RequestedBy string              `json:"requested_by"`
+	Status      SubjectAccessStatus `json:"status"`
+	Error       string              `json:"error,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+	CompletedAt *time.Time          `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time          `json:"expires_at,omitempty"`
+}
+
+// Expired reports whether the archive of a completed request may no longer be downloaded
+func (r SubjectAccessRequest) Expired(now time.Time) bool {
+	return r.ExpiresAt != nil && !now.Before(*r.ExpiresAt)
+}
+
+// SubjectDataSection holds the records of one kind of data held about the
+// subject, each as it is stored.
+type SubjectDataSection struct {
+	Name    string            `json:"name"`
+	Records []json.RawMessage `json:"records"`
+}
+
+// SubjectAccessManifestEntry describes one section of the archive.
+type SubjectAccessManifestEntry struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Records     int    `json:"records"`
+}
+
+// SubjectAccessManifest lists what the archive contains and notes on data that
+// is not included.
+type SubjectAccessManifest struct {
+	Format      string                       `json:"format"`
+	RequestID   string                       `json:"request_id"`
+	TenantID    string                       `json:"tenant_id"`
+	UserID      string                       `json:"user_id"`
+	GeneratedAt time.Time                    `json:"generated_at"`
+	Sections    []SubjectAccessManifestEntry `json:"sections"`
+	Notes       []string                     `json:"notes"`
+}
+
+// SubjectAccessArchive is the downloadable JSON document: the manifest and
+// the records of every section, keyed by section name.
+type SubjectAccessArchive struct {
+	Manifest SubjectAccessManifest        `json:"manifest"`
+	Data     map[string][]json.RawMessage `json:"data"`
+}
+
+// SessionRecord describes a session a user currently holds: their active token
+// or an enrolled device. Expired sessions are not kept.
+type SessionRecord struct {
+	Kind       string     `json:"kind"`
+	ID         string     `json:"id"`
+	DeviceName string     `json:"device_name,omitempty"`
+	RoomNumber string     `json:"room_number,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+}
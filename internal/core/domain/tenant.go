@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoTenant is returned by tenant-scoped operations called without a tenant in the context.
+var ErrNoTenant = errors.New("no tenant in context")
+
+type tenantKey struct{}
+
+// WithTenant returns a context scoped to the given clinic.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext returns the clinic the context is scoped to.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(tenantKey{}).(string)
+	return tenantID, ok && tenantID != ""
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// RoomTransfer is one move of a parent between rooms, e.g. when the baby is moved to the NICU.
+// Co-parents of the same admission in the same room move along.
+type RoomTransfer struct {
+	ID            string    `json:"id"`
+	ParentID      string    `json:"parent_id"`
+	CoParentIDs   []string  `json:"co_parent_ids,omitempty"`
+	FromRoom      string    `json:"from_room"`
+	ToRoom        string    `json:"to_room"`
+	Reason        string    `json:"reason,omitempty"`
+	TransferredBy string    `json:"transferred_by"`
+	TransferredAt time.Time `json:"transferred_at"`
+}
@@ -5,17 +5,43 @@ import "time"
 type Role string
 
 const (
-	RoleAdmin  Role = "ADMIN"
-	RoleParent Role = "PARENT"
+	RoleAdmin        Role = "ADMIN"
+	RoleParent       Role = "PARENT"
+	RoleVisitor      Role = "VISITOR"
+	RoleNurse        Role = "NURSE"
+	RolePediatrician Role = "PEDIATRICIAN"
 )
 
+// RegisteredAsStaff reports whether accounts of the role are registered as
+// staff. Parents, admins and visitors each have their own flow; every other
+// role, including those a clinic creates, is a staff role.
+func (r Role) RegisteredAsStaff() bool {
+	return r != RoleParent && r != RoleAdmin && r != RoleVisitor
+}
+
+// ParentStatus is the stage of a parent's stay. Allowed transitions are
+// enforced by services.ParentLifecycleService.
 type ParentStatus string
 
 const (
+	ParentPending    ParentStatus = "Pending"
 	ParentActive     ParentStatus = "Active"
 	ParentDischarged ParentStatus = "Discharged"
+	ParentReadmitted ParentStatus = "Readmitted"
+	ParentArchived   ParentStatus = "Archived"
 )
 
+// IsAdmitted reports whether the parent is currently staying at the clinic
+// and may sign in, enroll devices and invite visitors.
+func (s ParentStatus) IsAdmitted() bool {
+	return s == ParentActive || s == ParentReadmitted
+}
+
+// HasLeft reports whether the parent was discharged and has not been readmitted
+func (s ParentStatus) HasLeft() bool {
+	return s == ParentDischarged || s == ParentArchived
+}
+
 type User struct {
 	ID        string    `json:"id"`
 	Email     string    `json:"email"`
@@ -23,10 +49,28 @@ type User struct {
 	FirstName string    `json:"first_name"`
 	LastName  string    `json:"last_name"`
 	CreatedAt time.Time `json:"created_at"`
+	// DisplayName, Locale and ContactEmail are chosen by the user on their own profile
+	DisplayName  string `json:"display_name,omitempty"`
+	Locale       string `json:"locale,omitempty"`
+	ContactEmail string `json:"contact_email,omitempty"`
 }
 
 type Parent struct {
 	User
 	RoomNumber string       `json:"room_number"`
 	Status     ParentStatus `json:"status"`
+	// AdmissionID links the parent to their baby's admission, shared with a co-parent
+	AdmissionID string `json:"admission_id,omitempty"`
+}
+
+// Staff is a clinical staff member assigned to a ward.
+type Staff struct {
+	User
+	Ward string `json:"ward"`
+}
+
+// Actor identifies the authenticated user performing an operation.
+type Actor struct {
+	ID   string
+	Role Role
 }
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// Visitor is a family member invited by a parent with limited, time-boxed access.
+// Visitors invited by email log in through Google; otherwise they redeem an invitation code.
+type Visitor struct {
+	ID          string     `json:"id"`
+	ParentID    string     `json:"parent_id"`
+	Email       string     `json:"email,omitempty"`
+	DisplayName string     `json:"display_name"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// IsActive reports whether the visitor may still access the family's updates.
+func (v Visitor) IsActive(now time.Time) bool {
+	return v.RevokedAt == nil && now.Before(v.ExpiresAt)
+}
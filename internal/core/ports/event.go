@@ -2,14 +2,174 @@ package ports
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 )
 
+// Outbox event types for user lifecycle changes
+const (
+	EventParentRegistered    = "parent.registered"
+	EventUserUpdated         = "user.updated"
+	EventUserDeactivated     = "user.deactivated"
+	EventParentRoomChanged   = "parent.room_changed"
+	EventParentStatusChanged = "parent.status_changed"
+	EventUserErased          = "user.erased"
+	EventParentDischarged    = "parent.discharged"
+	EventAdminRegistered     = "admin.registered"
+	EventUserLoggedIn        = "user.logged_in"
+	EventSessionRevoked      = "session.revoked"
+)
+
+// Login methods recorded in UserLoggedInEvent
+const (
+	LoginGoogle     = "google"
+	LoginInvitation = "invitation"
+	LoginDevice     = "device"
+)
+
+// Reasons recorded in SessionRevokedEvent
+const (
+	SessionLogout  = "logout"
+	SessionRevoked = "revoked"
+)
+
+// OutboxEvent is an event to queue in the outbox, together with the change it describes.
+type OutboxEvent struct {
+	Type    string
+	Payload []byte
+}
+
 type CreateBabyEvent struct {
-	UserID     string `json:"user_id"`
-	LastName   string `json:"last_name"`
-	RoomNumber string `json:"room_number"`
+	TenantID string `json:"tenant_id"`
+	// AdmissionID identifies the baby across all of its parents
+	AdmissionID string `json:"admission_id,omitempty"`
+	UserID      string `json:"user_id"`
+	LastName    string `json:"last_name"`
+	RoomNumber  string `json:"room_number"`
+}
+
+// ParentRoomChangedEvent tells the baby service that a parent, and their baby, moved rooms.
+type ParentRoomChangedEvent struct {
+	TenantID    string    `json:"tenant_id"`
+	UserID      string    `json:"user_id"`
+	AdmissionID string    `json:"admission_id,omitempty"`
+	LastName    string    `json:"last_name"`
+	FromRoom    string    `json:"from_room"`
+	ToRoom      string    `json:"to_room"`
+	Reason      string    `json:"reason,omitempty"`
+	ChangedAt   time.Time `json:"changed_at"`
+}
+
+// ParentStatusChangedEvent is emitted for every lifecycle transition of a parent.
+type ParentStatusChangedEvent struct {
+	TenantID    string    `json:"tenant_id"`
+	UserID      string    `json:"user_id"`
+	AdmissionID string    `json:"admission_id,omitempty"`
+	FromStatus  string    `json:"from_status"`
+	ToStatus    string    `json:"to_status"`
+	Reason      string    `json:"reason,omitempty"`
+	ChangedBy   string    `json:"changed_by"`
+	ChangedAt   time.Time `json:"changed_at"`
+}
+
+// EventEnvelope is an outbox event as it leaves the service: the payload
+// together with the metadata consumers need to route, order and trace it.
+type EventEnvelope struct {
+	// ID is the outbox row ID; consumers use it to drop redelivered events
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	SchemaVersion int             `json:"schema_version"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	TenantID      string          `json:"tenant_id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	CorrelationID string          `json:"correlation_id,omitempty"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// EventPublisher delivers outbox events to the message broker. It chooses the
+// destination from the envelope's type, so new event types need no new methods.
+type EventPublisher interface {
+	Publish(ctx context.Context, envelope EventEnvelope) error
+}
+
+// UserUpdatedEvent carries the new values of the fields that changed.
+type UserUpdatedEvent struct {
+	TenantID      string   `json:"tenant_id"`
+	UserID        string   `json:"user_id"`
+	Role          string   `json:"role"`
+	ChangedFields []string `json:"changed_fields"`
+	Email         string   `json:"email"`
+	FirstName     string   `json:"first_name"`
+	LastName      string   `json:"last_name"`
+	RoomNumber    string   `json:"room_number,omitempty"`
+	DisplayName   string   `json:"display_name,omitempty"`
+	Locale        string   `json:"locale,omitempty"`
+	ContactEmail  string   `json:"contact_email,omitempty"`
+	Version       int      `json:"version"`
+}
+
+// ParentDischargedEvent tells the baby service to close the records of a
+// family that left the clinic. It follows the parent.status_changed event of
+// the same transition.
+type ParentDischargedEvent struct {
+	TenantID     string    `json:"tenant_id"`
+	UserID       string    `json:"user_id"`
+	AdmissionID  string    `json:"admission_id,omitempty"`
+	RoomNumber   string    `json:"room_number"`
+	Reason       string    `json:"reason,omitempty"`
+	DischargedBy string    `json:"discharged_by"`
+	DischargedAt time.Time `json:"discharged_at"`
+}
+
+// AdminRegisteredEvent is emitted once a second admin approved a new admin.
+type AdminRegisteredEvent struct {
+	TenantID     string    `json:"tenant_id"`
+	UserID       string    `json:"user_id"`
+	Email        string    `json:"email"`
+	FirstName    string    `json:"first_name"`
+	LastName     string    `json:"last_name"`
+	ApprovalID   string    `json:"approval_id"`
+	RequestedBy  string    `json:"requested_by"`
+	ApprovedBy   string    `json:"approved_by"`
+	RegisteredAt time.Time `json:"registered_at"`
+}
+
+// UserLoggedInEvent is emitted for every session token issued to a user.
+type UserLoggedInEvent struct {
+	TenantID string `json:"tenant_id"`
+	UserID   string `json:"user_id"`
+	Role     string `json:"role"`
+	// Method is LoginGoogle, LoginInvitation or LoginDevice
+	Method     string    `json:"method"`
+	SessionID  string    `json:"session_id"`
+	LoggedInAt time.Time `json:"logged_in_at"`
+}
+
+// SessionRevokedEvent is emitted when a user's session token is revoked
+// before it expired, by logging out or by the clinic.
+type SessionRevokedEvent struct {
+	TenantID  string `json:"tenant_id"`
+	UserID    string `json:"user_id"`
+	SessionID string `json:"session_id"`
+	// Reason is SessionLogout or SessionRevoked
+	Reason    string    `json:"reason"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+type UserDeactivatedEvent struct {
+	TenantID      string    `json:"tenant_id"`
+	UserID        string    `json:"user_id"`
+	Role          string    `json:"role"`
+	DeactivatedBy string    `json:"deactivated_by"`
+	DeactivatedAt time.Time `json:"deactivated_at"`
 }
 
-type BabyEventPublisher interface {
-	PublishBabyCreated(ctx context.Context, evt CreateBabyEvent) error
+// UserErasedEvent asks every consumer to purge the personal data it holds about
+// the user. It deliberately carries no personal data itself.
+type UserErasedEvent struct {
+	TenantID string    `json:"tenant_id"`
+	UserID   string    `json:"user_id"`
+	Role     string    `json:"role"`
+	ErasedAt time.Time `json:"erased_at"`
 }
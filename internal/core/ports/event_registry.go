@@ -0,0 +1,81 @@
+package ports
+
+import "sort"
+
+// EventType describes an event type the outbox may contain.
+type EventType struct {
+	Name string
+	// SchemaVersion is bumped on every incompatible change to the payload, so
+	// consumers can tell old and new payloads apart while both are in flight
+	SchemaVersion int
+}
+
+// EventRegistry lists the known event types. The outbox stamps each event
+// with its type's schema version, and the relay publishes every registered
+// type without knowing its payload.
+type EventRegistry struct {
+	types   map[string]EventType
+	aliases map[string]string
+}
+
+// NewEventRegistry returns a registry of the given types.
+func NewEventRegistry(types ...EventType) *EventRegistry {
+	r := &EventRegistry{types: make(map[string]EventType), aliases: make(map[string]string)}
+	for _, t := range types {
+		r.types[t.Name] = t
+	}
+	return r
+}
+
+// Alias makes a former name of a registered type resolve to that type, so
+// events written before a rename are still delivered.
+func (r *EventRegistry) Alias(former, name string) *EventRegistry {
+	r.aliases[former] = name
+	return r
+}
+
+// Lookup returns the type registered under name or one of its former names.
+func (r *EventRegistry) Lookup(name string) (EventType, bool) {
+	if current, ok := r.aliases[name]; ok {
+		name = current
+	}
+	t, ok := r.types[name]
+	return t, ok
+}
+
+// SchemaVersion returns the schema version of the named type, or 1 for
+// types that are not registered.
+func (r *EventRegistry) SchemaVersion(name string) int {
+	if t, ok := r.Lookup(name); ok {
+		return t.SchemaVersion
+	}
+	return 1
+}
+
+// Names returns every name Lookup resolves, former names included, sorted.
+func (r *EventRegistry) Names() []string {
+	names := make([]string, 0, len(r.types)+len(r.aliases))
+	for name := range r.types {
+		names = append(names, name)
+	}
+	for former := range r.aliases {
+		names = append(names, former)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Events is the registry of the events this service emits. Adding a type here
+// is all the relay needs to deliver it.
+var Events = NewEventRegistry(
+	EventType{Name: EventParentRegistered, SchemaVersion: 1},
+	EventType{Name: EventParentRoomChanged, SchemaVersion: 1},
+	EventType{Name: EventParentStatusChanged, SchemaVersion: 1},
+	EventType{Name: EventUserUpdated, SchemaVersion: 1},
+	EventType{Name: EventUserDeactivated, SchemaVersion: 1},
+	EventType{Name: EventUserErased, SchemaVersion: 1},
+	EventType{Name: EventParentDischarged, SchemaVersion: 1},
+	EventType{Name: EventAdminRegistered, SchemaVersion: 1},
+	EventType{Name: EventUserLoggedIn, SchemaVersion: 1},
+	EventType{Name: EventSessionRevoked, SchemaVersion: 1},
+).Alias("babies", EventParentRegistered) // registrations queued before event types were named
@@ -2,14 +2,148 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
 )
 
 type UserRepository interface {
 	FindByEmail(ctx context.Context, email string) (*domain.User, error)
+	// EmailsTaken returns which of the emails an account in the clinic already uses, in one query.
+	// Emails are compared case-insensitively and the result is keyed by lowercase email.
+	EmailsTaken(ctx context.Context, emails []string) (map[string]bool, error)
+	FindByID(ctx context.Context, id string) (*domain.User, error)
+	FindParentByID(ctx context.Context, id string) (*domain.Parent, error)
 	CreateParent(ctx context.Context, parent domain.Parent, outboxPayload []byte) (*domain.Parent, error)
-	CreateAdmin(ctx context.Context, user domain.User) (*domain.User, error)
-	UpdateParentStatus(ctx context.Context, parentID string) error
+	CreateStaff(ctx context.Context, staff domain.Staff) (*domain.Staff, error)
+	ListUsers(ctx context.Context, filter domain.UserFilter) ([]domain.UserRecord, error)
+	// StreamUsers calls fn for each matching user without loading them all; an error from fn stops the stream
+	StreamUsers(ctx context.Context, filter domain.UserFilter, fn func(domain.UserRecord) error) error
+	// FindErasableUser finds a user that has not been erased yet, including deactivated users
+	FindErasableUser(ctx context.Context, id string) (*domain.User, error)
+	// EraseUser pseudonymizes the user and scrubs their stored events in one transaction
+	EraseUser(ctx context.Context, tombstone domain.ErasureTombstone, outboxPayload []byte) error
+	GetUserRecord(ctx context.Context, id string) (*domain.UserRecord, error)
+	UpdateUser(ctx context.Context, record domain.UserRecord, expectedVersion int, outboxPayload []byte) error
+	DeactivateUser(ctx context.Context, id string, outboxPayload []byte) error
+	TransferParent(ctx context.Context, transfer domain.RoomTransfer, outboxPayload []byte) error
+	// ChangeParentStatus stores the transition with its history entry and queues the events describing it
+	ChangeParentStatus(ctx context.Context, change domain.ParentStatusChange, events []OutboxEvent) error
+	ListParentStatusHistory(ctx context.Context, parentID string) ([]domain.ParentStatusChange, error)
 	GetParentStatus(ctx context.Context, parentID string) (string, error)
 }
+
+// EventRepository queues events about things that change no stored data, such as logins.
+type EventRepository interface {
+	// AppendEvent writes the event to the outbox in a transaction of its own
+	AppendEvent(ctx context.Context, aggregateType, aggregateID string, event OutboxEvent) error
+}
+
+type AuditRepository interface {
+	RecordAudit(ctx context.Context, entry domain.AuditEntry) error
+}
+
+type VisitorRepository interface {
+	CreateVisitor(ctx context.Context, visitor domain.Visitor, codeHash string) error
+	FindVisitorByEmail(ctx context.Context, email string) (*domain.Visitor, error)
+	FindVisitorByCodeHash(ctx context.Context, codeHash string) (*domain.Visitor, error)
+	ListVisitorsByParent(ctx context.Context, parentID string) ([]domain.Visitor, error)
+	RevokeVisitor(ctx context.Context, parentID, visitorID string) error
+	RevokeVisitorsByParent(ctx context.Context, parentID string) ([]string, error)
+}
+
+type PermissionRepository interface {
+	ListRoles(ctx context.Context) ([]domain.RoleDefinition, error)
+	GetRole(ctx context.Context, name domain.Role) (*domain.RoleDefinition, error)
+	CreateRole(ctx context.Context, role domain.RoleDefinition) error
+	SetRolePermissions(ctx context.Context, name domain.Role, permissions []domain.Permission) (*domain.RoleDefinition, error)
+	ListPermissions(ctx context.Context) ([]domain.PermissionDefinition, error)
+	CreatePermission(ctx context.Context, permission domain.PermissionDefinition) error
+}
+
+type WardRepository interface {
+	GetStaffWard(ctx context.Context, userID string) (string, error)
+	GetRoomWard(ctx context.Context, roomNumber string) (string, error)
+	// GetRoomWards returns the ward of each known room, in one query
+	GetRoomWards(ctx context.Context, roomNumbers []string) (map[string]string, error)
+	// IsWardScoped reports whether members of the role are bound to a ward
+	IsWardScoped(ctx context.Context, role domain.Role) (bool, error)
+}
+
+type PolicyRepository interface {
+	ListPolicies(ctx context.Context) ([]domain.Policy, error)
+	ListPoliciesForAction(ctx context.Context, action string) ([]domain.Policy, error)
+	CreatePolicy(ctx context.Context, policy domain.Policy) error
+	DeletePolicy(ctx context.Context, id string) error
+	RecordDecisions(ctx context.Context, entries []domain.DecisionLogEntry) error
+}
+
+// SubjectAccessRepository stores subject access requests with their archives
+// and collects the data they export.
+type SubjectAccessRepository interface {
+	// CreateSubjectAccessRequest fails open requests for the user created before staleBefore, then
+	// fails with domain.ErrSubjectAccessInProgress if another request for the user is still open
+	CreateSubjectAccessRequest(ctx context.Context, request domain.SubjectAccessRequest, staleBefore time.Time) error
+	// UpdateSubjectAccessRequest records the request's new status and, once completed, its archive.
+	// It fails with domain.ErrSubjectAccessNotFound once the request is closed, e.g. by an erasure
+	UpdateSubjectAccessRequest(ctx context.Context, request domain.SubjectAccessRequest, archive []byte) error
+	GetSubjectAccessRequest(ctx context.Context, id string) (*domain.SubjectAccessRequest, error)
+	GetSubjectAccessArchive(ctx context.Context, id string) ([]byte, error)
+	// CollectSubjectData reads every stored record about the user, one section per kind of data
+	CollectSubjectData(ctx context.Context, userID string) ([]domain.SubjectDataSection, error)
+}
+
+// RetentionRepository selects and removes data that is past its retention period.
+type RetentionRepository interface {
+	// AcquireRetentionLock takes a lock shared by every replica; acquired is
+	// false when another replica holds it. release must be called once done.
+	AcquireRetentionLock(ctx context.Context) (release func(), acquired bool, err error)
+	// ListParentsDischargedBefore returns up to limit parents, not yet erased,
+	// who are still discharged or were archived after a discharge before cutoff
+	ListParentsDischargedBefore(ctx context.Context, cutoff time.Time, limit int) ([]string, error)
+	CountParentsDischargedBefore(ctx context.Context, cutoff time.Time) (int, error)
+	// DeleteProcessedOutboxEvents deletes up to limit events processed before cutoff and returns how many it deleted
+	DeleteProcessedOutboxEvents(ctx context.Context, cutoff time.Time, limit int) (int, error)
+	CountProcessedOutboxEvents(ctx context.Context, cutoff time.Time) (int, error)
+	// PurgeExpiredSubjectAccessArchives drops up to limit subject access archives expired at cutoff and returns how many it dropped
+	PurgeExpiredSubjectAccessArchives(ctx context.Context, cutoff time.Time, limit int) (int, error)
+	CountExpiredSubjectAccessArchives(ctx context.Context, cutoff time.Time) (int, error)
+}
+
+// InvitationRepository stores the invitations of newly registered parents.
+type InvitationRepository interface {
+	// CreateParentInvitation stores the pending parent, their registration event and invitation in one transaction
+	CreateParentInvitation(ctx context.Context, parent domain.Parent, invitation domain.Invitation, outboxPayload []byte) error
+	// CreateParentInvitations stores all parents with their events and invitations in one transaction
+	CreateParentInvitations(ctx context.Context, parents []domain.Parent, invitations []domain.Invitation, outboxPayloads [][]byte) error
+	GetInvitation(ctx context.Context, id string) (*domain.Invitation, error)
+	// FindPendingInvitation returns the user's pending invitation, or domain.ErrInvitationNotFound
+	FindPendingInvitation(ctx context.Context, userID string) (*domain.Invitation, error)
+	// ListInvitations returns the invitations with the given status, or all of them, newest first
+	ListInvitations(ctx context.Context, status domain.InvitationStatus) ([]domain.Invitation, error)
+	UpdateInvitation(ctx context.Context, invitation domain.Invitation) error
+}
+
+// AdmissionRepository reads the admissions that group the parents of one baby.
+type AdmissionRepository interface {
+	GetAdmission(ctx context.Context, id string) (*domain.Admission, error)
+}
+
+// AdminApprovalRepository stores the approvals that gate admin creation and
+// removal. Approving applies the change in the same transaction, which also
+// refuses to deactivate the clinic's last active admin.
+type AdminApprovalRepository interface {
+	// CreateAdminApproval expires pending approvals past their deadline, then fails with
+	// domain.ErrAdminApprovalPending if one is still pending for the same email or admin
+	CreateAdminApproval(ctx context.Context, approval domain.AdminApproval) error
+	GetAdminApproval(ctx context.Context, id string) (*domain.AdminApproval, error)
+	// ListAdminApprovals returns the approvals with the given status, or all of them, newest first
+	ListAdminApprovals(ctx context.Context, status domain.AdminApprovalStatus) ([]domain.AdminApproval, error)
+	// DecideAdminApproval records a rejection or expiry of a still pending approval
+	DecideAdminApproval(ctx context.Context, approval domain.AdminApproval) error
+	// ApproveAdminCreation records the approval and creates the admin with an admin.registered event
+	ApproveAdminCreation(ctx context.Context, approval domain.AdminApproval, admin domain.User, outboxPayload []byte) error
+	// ApproveAdminRemoval records the approval and deactivates the admin with a user.deactivated event
+	ApproveAdminRemoval(ctx context.Context, approval domain.AdminApproval, outboxPayload []byte) error
+	CountActiveAdmins(ctx context.Context) (int, error)
+}
@@ -2,6 +2,9 @@ package ports
 
 import (
 	"context"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
 )
 
 type AuthService interface {
@@ -10,6 +13,52 @@ type AuthService interface {
 }
 
 type RegistrationService interface {
-	RegisterParent(ctx context.Context, email, firstName, lastName, roomNumber string) (string, error)
-	RegisterAdmin(ctx context.Context, email, firstName, lastName string) (string, error)
+	RegisterParent(ctx context.Context, email, firstName, lastName, roomNumber, locale string) (string, error)
+	RegisterStaff(ctx context.Context, email, firstName, lastName string, role domain.Role, ward string) (string, error)
+}
+
+// WardPolicy restricts ward-assigned staff to parents in rooms on their own ward.
+type WardPolicy interface {
+	AuthorizeRegistration(ctx context.Context, actor domain.Actor, role domain.Role, roomNumber string) error
+	AuthorizeParent(ctx context.Context, actor domain.Actor, parentID string) error
+	// AuthorizeParentRooms checks the registration of parents into many rooms at once and
+	// returns the rooms the actor may not register into, each with the reason
+	AuthorizeParentRooms(ctx context.Context, actor domain.Actor, roomNumbers []string) (map[string]error, error)
+}
+
+// SessionRevoker ends every session a user holds, including enrolled devices
+// and admins impersonating them.
+type SessionRevoker interface {
+	RevokeSession(ctx context.Context, userID string) error
+	RevokeDeviceCredentials(ctx context.Context, userID string) error
+	RevokeImpersonations(ctx context.Context, userID string) error
+}
+
+// ImpersonationTracker remembers the impersonation tokens issued for a user, so
+// that RevokeImpersonations can end them when the user loses access.
+type ImpersonationTracker interface {
+	TrackImpersonation(ctx context.Context, userID, jti string, expiresAt time.Time) error
+}
+
+// MailMessage is a plain text email.
+type MailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers email, such as parent invitations.
+type Mailer interface {
+	Send(ctx context.Context, message MailMessage) error
+}
+
+// SessionLister describes the sessions a user currently holds, for subject access requests.
+type SessionLister interface {
+	ListSessions(ctx context.Context, userID string) ([]domain.SessionRecord, error)
+}
+
+// RetentionMetrics exposes what the retention job did.
+type RetentionMetrics interface {
+	ObserveRetentionRun(report domain.RetentionReport)
+	ObserveRetentionSkipped()
 }
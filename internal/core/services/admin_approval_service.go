@@ -0,0 +1,319 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotAnAdmin                = errors.New("user is not an active admin")
+	ErrInvalidAdminApprovalState = domain.InvalidField("status", "status must be Pending, Approved, Rejected or Expired")
+)
+
+// AdminApprovalService applies the four-eyes rule to the clinic's admins:
+// creating or removing an admin is only a request until a second admin
+// approves it within the approval window.
+type AdminApprovalService struct {
+	userRepo  ports.UserRepository
+	approvals ports.AdminApprovalRepository
+	sessions  ports.SessionRevoker
+	ttl       time.Duration
+}
+
+func NewAdminApprovalService(
+	userRepo ports.UserRepository,
+	approvals ports.AdminApprovalRepository,
+	sessions ports.SessionRevoker,
+	ttl time.Duration,
+) *AdminApprovalService {
+	return &AdminApprovalService{
+		userRepo:  userRepo,
+		approvals: approvals,
+		sessions:  sessions,
+		ttl:       ttl,
+	}
+}
+
+var (
+	// bootstrapActor requests and approves the first admin of a clinic
+	bootstrapActor = domain.Actor{ID: "bootstrap", Role: domain.RoleAdmin}
+	// operatorActor approves requests listed in the clinic's configuration
+	operatorActor = domain.Actor{ID: "operator", Role: domain.RoleAdmin}
+)
+
+// RequestCreation asks for a new admin; they are created once another admin
+// approves. A clinic with a single admin has nobody else to approve, so its
+// requests wait for the operator (see ApproveAsOperator).
+func (s *AdminApprovalService) RequestCreation(ctx context.Context, actor domain.Actor, email, firstName, lastName string) (*domain.AdminApproval, error) {
+	email, firstName, lastName = trimUserFields(email, firstName, lastName)
+
+	var v domain.Validation
+	validateUserFields(&v, email, firstName, lastName)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	if _, err := s.userRepo.FindByEmail(ctx, email); err == nil {
+		return nil, domain.ErrEmailTaken
+	}
+
+	approval := s.newApproval(actor, domain.AdminApprovalCreate)
+	approval.Email = email
+	approval.FirstName = firstName
+	approval.LastName = lastName
+
+	if err := s.approvals.CreateAdminApproval(ctx, approval); err != nil {
+		return nil, err
+	}
+	return &approval, nil
+}
+
+// Bootstrap creates the first admin of a clinic that has none, the only
+// admin ever created without a second person. It does nothing once the
+// clinic has an active admin, so it is safe on every start.
+func (s *AdminApprovalService) Bootstrap(ctx context.Context, email, firstName, lastName string) (created bool, err error) {
+	admins, err := s.approvals.CountActiveAdmins(ctx)
+	if err != nil {
+		return false, err
+	}
+	if admins > 0 {
+		return false, nil
+	}
+
+	approval, err := s.RequestCreation(ctx, bootstrapActor, email, firstName, lastName)
+	if err != nil {
+		return false, err
+	}
+	decide(approval, bootstrapActor, domain.AdminApprovalApproved)
+	if err := s.create(ctx, bootstrapActor, *approval); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ApproveAsOperator approves a request on behalf of the clinic's operator,
+// who is out of band and so neither its requester nor its target. This is
+// how a clinic with a single admin gets a second one, and how a clinic with
+// two admins removes one.
+func (s *AdminApprovalService) ApproveAsOperator(ctx context.Context, id string) (*domain.AdminApproval, error) {
+	return s.Approve(ctx, operatorActor, id)
+}
+
+// RequestRemoval asks for an admin to be deactivated once another admin
+// approves. The clinic's last active admin cannot be removed. With two admins
+// neither can approve, so the request waits for the operator.
+func (s *AdminApprovalService) RequestRemoval(ctx context.Context, actor domain.Actor, userID string) (*domain.AdminApproval, error) {
+	if actor.ID == userID {
+		return nil, ErrSelfDeactivation
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, notFoundOr(err, ErrUserNotFound)
+	}
+	if user.Role != domain.RoleAdmin {
+		return nil, ErrNotAnAdmin
+	}
+	admins, err := s.approvals.CountActiveAdmins(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if admins <= 1 {
+		return nil, domain.ErrLastAdmin
+	}
+
+	approval := s.newApproval(actor, domain.AdminApprovalRemove)
+	approval.TargetUserID = userID
+
+	if err := s.approvals.CreateAdminApproval(ctx, approval); err != nil {
+		return nil, err
+	}
+	return &approval, nil
+}
+
+// List returns the clinic's approvals with the given status, or all of them.
+// Pending approvals past their deadline are reported as Expired.
+func (s *AdminApprovalService) List(ctx context.Context, status string) ([]domain.AdminApproval, error) {
+	filter := domain.AdminApprovalStatus(status)
+	switch filter {
+	case "", domain.AdminApprovalPending, domain.AdminApprovalApproved, domain.AdminApprovalRejected, domain.AdminApprovalExpired:
+	default:
+		return nil, ErrInvalidAdminApprovalState
+	}
+
+	// Expired approvals may still be stored as Pending
+	stored := filter
+	if filter == domain.AdminApprovalExpired {
+		stored = ""
+	}
+	approvals, err := s.approvals.ListAdminApprovals(ctx, stored)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	listed := []domain.AdminApproval{}
+	for _, approval := range approvals {
+		if approval.Expired(now) {
+			approval.Status = domain.AdminApprovalExpired
+		}
+		if filter == "" || approval.Status == filter {
+			listed = append(listed, approval)
+		}
+	}
+	return listed, nil
+}
+
+// Approve applies the requested change. The approver must be another admin
+// than the requester and, for a removal, the admin being removed.
+func (s *AdminApprovalService) Approve(ctx context.Context, actor domain.Actor, id string) (*domain.AdminApproval, error) {
+	approval, err := s.pending(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if actor.ID == approval.RequestedBy {
+		return nil, domain.ErrSelfApproval
+	}
+	decide(approval, actor, domain.AdminApprovalApproved)
+
+	switch approval.Action {
+	case domain.AdminApprovalCreate:
+		if err := s.create(ctx, actor, *approval); err != nil {
+			return nil, err
+		}
+	case domain.AdminApprovalRemove:
+		if err := s.remove(ctx, actor, *approval); err != nil {
+			return nil, err
+		}
+	}
+	return approval, nil
+}
+
+// Reject closes the approval without applying it. The requester may withdraw
+// their own request; an admin cannot reject their own removal.
+func (s *AdminApprovalService) Reject(ctx context.Context, actor domain.Actor, id string) (*domain.AdminApproval, error) {
+	approval, err := s.pending(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	decide(approval, actor, domain.AdminApprovalRejected)
+
+	if err := s.approvals.DecideAdminApproval(ctx, *approval); err != nil {
+		return nil, err
+	}
+	return approval, nil
+}
+
+// pending loads an approval that can still be decided by actor. An approval
+// past its deadline is recorded as Expired.
+func (s *AdminApprovalService) pending(ctx context.Context, actor domain.Actor, id string) (*domain.AdminApproval, error) {
+	approval, err := s.approvals.GetAdminApproval(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if approval.Status != domain.AdminApprovalPending {
+		return nil, domain.ErrAdminApprovalClosed
+	}
+	if approval.Expired(time.Now()) {
+		approval.Status = domain.AdminApprovalExpired
+		if err := s.approvals.DecideAdminApproval(ctx, *approval); err != nil && !errors.Is(err, domain.ErrAdminApprovalClosed) {
+			return nil, err
+		}
+		return nil, domain.ErrAdminApprovalExpired
+	}
+	if actor.ID == approval.TargetUserID {
+		return nil, domain.ErrSelfApproval
+	}
+	return approval, nil
+}
+
+// create adds the requested admin with an admin.registered event.
+func (s *AdminApprovalService) create(ctx context.Context, actor domain.Actor, approval domain.AdminApproval) error {
+	admin := domain.User{
+		ID:        uuid.NewString(),
+		Email:     approval.Email,
+		Role:      domain.RoleAdmin,
+		CreatedAt: time.Now(),
+		FirstName: approval.FirstName,
+		LastName:  approval.LastName,
+	}
+
+	tenant, _ := domain.TenantFromContext(ctx)
+	outboxPayload, err := json.Marshal(ports.AdminRegisteredEvent{
+		TenantID:     tenant,
+		UserID:       admin.ID,
+		Email:        admin.Email,
+		FirstName:    admin.FirstName,
+		LastName:     admin.LastName,
+		ApprovalID:   approval.ID,
+		RequestedBy:  approval.RequestedBy,
+		ApprovedBy:   actor.ID,
+		RegisteredAt: admin.CreatedAt.UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.approvals.ApproveAdminCreation(ctx, approval, admin, outboxPayload)
+}
+
+// remove ends the admin's sessions and deactivates them. Sessions are revoked
+// first so that a failure leaves the approval pending and it can be retried.
+func (s *AdminApprovalService) remove(ctx context.Context, actor domain.Actor, approval domain.AdminApproval) error {
+	if err := s.requireAnotherAdmin(ctx); err != nil {
+		return err
+	}
+	if err := revokeAccess(ctx, s.sessions, nil, approval.TargetUserID, false); err != nil {
+		return err
+	}
+
+	tenant, _ := domain.TenantFromContext(ctx)
+	outboxPayload, err := json.Marshal(ports.UserDeactivatedEvent{
+		TenantID:      tenant,
+		UserID:        approval.TargetUserID,
+		Role:          string(domain.RoleAdmin),
+		DeactivatedBy: actor.ID,
+		DeactivatedAt: approval.DecidedAt.UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.approvals.ApproveAdminRemoval(ctx, approval, outboxPayload)
+}
+
+// requireAnotherAdmin refuses early what the repository enforces when an admin is deactivated
+func (s *AdminApprovalService) requireAnotherAdmin(ctx context.Context) error {
+	admins, err := s.approvals.CountActiveAdmins(ctx)
+	if err != nil {
+		return err
+	}
+	if admins <= 1 {
+		return domain.ErrLastAdmin
+	}
+	return nil
+}
+
+func (s *AdminApprovalService) newApproval(actor domain.Actor, action domain.AdminApprovalAction) domain.AdminApproval {
+	now := time.Now()
+	return domain.AdminApproval{
+		ID:          uuid.NewString(),
+		Action:      action,
+		Status:      domain.AdminApprovalPending,
+		RequestedBy: actor.ID,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+	}
+}
+
+func decide(approval *domain.AdminApproval, actor domain.Actor, status domain.AdminApprovalStatus) {
+	now := time.Now()
+	approval.Status = status
+	approval.DecidedBy = actor.ID
+	approval.DecidedAt = &now
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// AdmissionService manages the admission that links the parents of one baby:
+// co-parents join it without creating another baby, and it is discharged as a whole.
+type AdmissionService struct {
+	userRepo    ports.UserRepository
+	admissions  ports.AdmissionRepository
+	invitations *InvitationService
+	lifecycle   *ParentLifecycleService
+}
+
+func NewAdmissionService(
+	userRepo ports.UserRepository,
+	admissions ports.AdmissionRepository,
+	invitations *InvitationService,
+	lifecycle *ParentLifecycleService,
+) *AdmissionService {
+	return &AdmissionService{
+		userRepo:    userRepo,
+		admissions:  admissions,
+		invitations: invitations,
+		lifecycle:   lifecycle,
+	}
+}
+
+func (s *AdmissionService) Get(ctx context.Context, admissionID string) (*domain.Admission, error) {
+	return s.admissions.GetAdmission(ctx, admissionID)
+}
+
+// ForParent returns the admission the parent belongs to
+func (s *AdmissionService) ForParent(ctx context.Context, parentID string) (*domain.Admission, error) {
+	parent, err := s.userRepo.FindParentByID(ctx, parentID)
+	if err != nil {
+		return nil, notFoundOr(err, ErrParentNotFound)
+	}
+	if parent.AdmissionID == "" {
+		return nil, domain.ErrAdmissionNotFound
+	}
+	return s.admissions.GetAdmission(ctx, parent.AdmissionID)
+}
+
+// AddCoParent registers another parent for the admission's baby, in the
+// admission's room. Like any new parent they are invited by email and stay
+// Pending until they accept; no baby is created for them.
+func (s *AdmissionService) AddCoParent(ctx context.Context, admissionID, email, firstName, lastName, locale string) (*domain.Parent, string, error) {
+	email, firstName, lastName = trimUserFields(email, firstName, lastName)
+
+	var v domain.Validation
+	validateUserFields(&v, email, firstName, lastName)
+	locale = parseLocale(&v, locale)
+	if err := v.Err(); err != nil {
+		return nil, "", err
+	}
+
+	admission, err := s.admissions.GetAdmission(ctx, admissionID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !admission.Open() {
+		return nil, "", domain.ErrAdmissionClosed
+	}
+
+	parent := domain.Parent{
+		User: domain.User{
+			ID:        uuid.NewString(),
+			Email:     email,
+			Role:      domain.RoleParent,
+			CreatedAt: time.Now(),
+			FirstName: firstName,
+			LastName:  lastName,
+			Locale:    locale,
+		},
+		RoomNumber:  admission.RoomNumber,
+		Status:      domain.ParentPending,
+		AdmissionID: admission.ID,
+	}
+
+	sent, err := s.invitations.InviteParent(ctx, parent, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if !sent {
+		return &parent, "Co-parent added, but the invitation email could not be sent; resend it", nil
+	}
+	return &parent, "Co-parent added; invitation sent", nil
+}
+
+// Discharge discharges every admitted parent of the admission and archives
+// co-parents who never accepted their invitation. Each parent's sessions,
+// devices and visitors are revoked as for a single discharge; on failure the
+// call can be retried and skips the parents already done.
+func (s *AdmissionService) Discharge(ctx context.Context, actor domain.Actor, admissionID, reason string) ([]domain.ParentStatusChange, error) {
+	admission, err := s.admissions.GetAdmission(ctx, admissionID)
+	if err != nil {
+		return nil, err
+	}
+	if !admission.Open() {
+		return nil, domain.ErrAdmissionClosed
+	}
+
+	changes := []domain.ParentStatusChange{}
+	for _, parent := range admission.Parents {
+		var change *domain.ParentStatusChange
+		switch {
+		case parent.Status.IsAdmitted():
+			change, err = s.lifecycle.Discharge(ctx, actor, parent.ID, reason)
+		case parent.Status == domain.ParentPending:
+			change, err = s.lifecycle.Archive(ctx, actor, parent.ID, reason)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, *change)
+	}
+	return changes, nil
+}
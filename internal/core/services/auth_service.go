@@ -17,17 +17,27 @@ import (
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
 	jwt "github.com/golang-jwt/jwt/v5"
-	"github.com/google/uuid"
-	redis "github.com/redis/go-redis/v9"
+)
+
+// OAuthClient is a clinic's Google OAuth client registration.
+type OAuthClient struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+}
+
+var (
+	ErrUnknownTenant     = errors.New("unknown tenant")
+	ErrParentNotAdmitted = errors.New("parent is not admitted")
 )
 
 type AuthService struct {
-	clientID     string
-	clientSecret string
-	redirectURL  string
-	userRepo     ports.UserRepository
-	privateKey   *rsa.PrivateKey
-	redisClient  *redis.Client
+	clients        map[string]OAuthClient
+	userRepo       ports.UserRepository
+	tokens         *TokenIssuer
+	visitors       *VisitorService
+	invitations    *InvitationService
+	impersonations *ImpersonationService
 }
 
 type googleTokenResponse struct {
@@ -55,22 +65,38 @@ type RedisSession struct {
 
 const TokenDuration = 30 * time.Minute
 
+// NewAuthService takes the OAuth client of every clinic, keyed by tenant ID
 func NewAuthService(
-	clientID, clientSecret, redirectURL string,
+	clients map[string]OAuthClient,
 	userRepo ports.UserRepository,
-	privateKey *rsa.PrivateKey,
-	redisClient *redis.Client,
+	tokens *TokenIssuer,
+	visitors *VisitorService,
+	invitations *InvitationService,
+	impersonations *ImpersonationService,
 ) *AuthService {
 	return &AuthService{
-		clientID:     clientID,
-		clientSecret: clientSecret,
-		redirectURL:  redirectURL,
-		userRepo:     userRepo,
-		privateKey:   privateKey,
-		redisClient:  redisClient,
+		clients:        clients,
+		userRepo:       userRepo,
+		tokens:         tokens,
+		visitors:       visitors,
+		invitations:    invitations,
+		impersonations: impersonations,
 	}
 }
 
+// client returns the OAuth client of the clinic the request is scoped to
+func (s *AuthService) client(ctx context.Context) (OAuthClient, error) {
+	tenant, ok := domain.TenantFromContext(ctx)
+	if !ok {
+		return OAuthClient{}, domain.ErrNoTenant
+	}
+	client, ok := s.clients[tenant]
+	if !ok {
+		return OAuthClient{}, ErrUnknownTenant
+	}
+	return client, nil
+}
+
 // GenerateState creates a random state for CSRF protection
 func (s *AuthService) GenerateState() (string, error) {
 	b := make([]byte, 32)
@@ -80,31 +106,79 @@ func (s *AuthService) GenerateState() (string, error) {
 	return base64.URLEncoding.EncodeToString(b), nil
 }
 
-// GetAuthURL returns the Google authorization URL
-func (s *AuthService) GetAuthURL(state string) string {
+// GetAuthURL returns the Google authorization URL of the clinic in ctx
+func (s *AuthService) GetAuthURL(ctx context.Context, state string) (string, error) {
+	client, err := s.client(ctx)
+	if err != nil {
+		return "", err
+	}
+
 	params := url.Values{}
-	params.Set("client_id", s.clientID)
-	params.Set("redirect_uri", s.redirectURL)
+	params.Set("client_id", client.ClientID)
+	params.Set("redirect_uri", client.RedirectURL)
 	params.Set("response_type", "code")
 	params.Set("scope", "openid email")
 	params.Set("state", state)
-	return "https://accounts.google.com/o/oauth2/v2/auth?" + params.Encode()
+	return "https://accounts.google.com/o/oauth2/v2/auth?" + params.Encode(), nil
 }
 
 // Authenticate exchanges code for tokens, verifies, and returns system JWT
 func (s *AuthService) Authenticate(ctx context.Context, code string) (string, error) {
-	idToken, err := s.exchangeCode(ctx, code)
+	email, err := s.verifiedEmail(ctx, code)
+	if err != nil {
+		return "", err
+	}
+	return s.issueFor(ctx, email, ports.LoginGoogle)
+}
+
+// StartInvitation checks an invitation link before the parent is sent to
+// Google, and returns the authorization URL to send them to
+func (s *AuthService) StartInvitation(ctx context.Context, invitationToken, state string) (string, error) {
+	if _, err := s.invitations.Verify(ctx, invitationToken); err != nil {
+		return "", err
+	}
+	return s.GetAuthURL(ctx, state)
+}
+
+// AcceptInvitation signs the parent in with code, activates them if their
+// verified email is the one they were invited with, and returns system JWT
+func (s *AuthService) AcceptInvitation(ctx context.Context, code, invitationToken string) (string, error) {
+	email, err := s.verifiedEmail(ctx, code)
+	if err != nil {
+		return "", err
+	}
+	invitation, err := s.invitations.Accept(ctx, invitationToken, email)
+	if err != nil {
+		return "", err
+	}
+	// Google may spell the address in another case than the clinic stored it
+	return s.issueFor(ctx, invitation.Email, ports.LoginInvitation)
+}
+
+// verifiedEmail exchanges code at the clinic's OAuth client and returns the verified email of the ID token
+func (s *AuthService) verifiedEmail(ctx context.Context, code string) (string, error) {
+	client, err := s.client(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	email, err := s.verifyIDToken(ctx, idToken)
+	idToken, err := s.exchangeCode(ctx, client, code)
 	if err != nil {
 		return "", err
 	}
 
+	return s.verifyIDToken(ctx, client, idToken)
+}
+
+// issueFor returns a system JWT for the user or visitor with the email; the
+// login of a user is recorded with method
+func (s *AuthService) issueFor(ctx context.Context, email, method string) (string, error) {
 	user, err := s.userRepo.FindByEmail(ctx, email)
 	if err != nil {
+		// Visitors are not users; they may log in while their invitation is active
+		if token, visitorErr := s.visitors.AuthenticateEmail(ctx, email); visitorErr == nil {
+			return token, nil
+		}
 		return "", errors.New("user not registered")
 	}
 
@@ -113,37 +187,21 @@ func (s *AuthService) Authenticate(ctx context.Context, code string) (string, er
 		if err != nil {
 			return "", err
 		}
-		if domain.ParentStatus(status) == domain.ParentDischarged {
-			return "", errors.New("parent is discharged")
+		if !domain.ParentStatus(status).IsAdmitted() {
+			return "", notAdmitted(domain.ParentStatus(status), ErrParentNotAdmitted)
 		}
 	}
 
-	jti := uuid.New().String()
-	expTime := time.Now().Add(TokenDuration)
-
-	claims := jwt.MapClaims{
-		"sub":  user.ID,
-		"role": string(user.Role),
-		"jti":  jti,
-		"iat":  time.Now().Unix(),
-		"exp":  expTime.Unix(),
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	signedToken, err := token.SignedString(s.privateKey)
+	issued, err := s.tokens.IssueSession(ctx, *user, method)
 	if err != nil {
 		return "", err
 	}
 
-	session := RedisSession{JTI: jti, Exp: expTime.Unix()}
-	data, _ := json.Marshal(session)
-
-	err = s.redisClient.Set(ctx, "active_session:"+user.ID, data, TokenDuration).Err()
-	if err != nil {
+	if err := s.tokens.TrackSession(ctx, user.ID, issued); err != nil {
 		log.Printf("Warning: failed to store active session in redis: %v", err)
 	}
 
-	return signedToken, nil
+	return issued.Token, nil
 }
 
 func (s *AuthService) Logout(ctx context.Context, tokenString string) error {
@@ -157,55 +215,25 @@ func (s *AuthService) Logout(ctx context.Context, tokenString string) error {
 		return errors.New("invalid claims")
 	}
 
-	jti, _ := claims["jti"].(string)
-	expTime, _ := claims["exp"].(float64)
-
-	return s.revokeToken(ctx, jti, int64(expTime))
-}
-
-func (s *AuthService) DischargeParent(ctx context.Context, parentID string) error {
-	metadata, err := s.redisClient.Get(ctx, "active_session:"+parentID).Result()
-	if err == redis.Nil {
-		return nil
-	} else if err != nil {
-		return err
-	}
-
-	var session RedisSession
-	if err := json.Unmarshal([]byte(metadata), &session); err != nil {
-		return err
-	}
-
-	err = s.revokeToken(ctx, session.JTI, session.Exp)
-	if err != nil {
-		return err
+	// Logging out of an impersonation ends it on the admin's behalf, not the parent's session
+	if _, impersonating := claims["act"].(map[string]interface{}); impersonating {
+		return s.impersonations.End(ctx, tokenString)
 	}
 
-	err = s.redisClient.Del(ctx, "active_session:"+parentID).Err()
-	if err != nil {
-		return err
-	}
-
-	return s.userRepo.UpdateParentStatus(ctx, parentID)
-}
+	sub, _ := claims["sub"].(string)
+	jti, _ := claims["jti"].(string)
+	expTime, _ := claims["exp"].(float64)
 
-// Internal helper to blacklist a specific JTI
-func (s *AuthService) revokeToken(ctx context.Context, jti string, expTime int64) error {
-	expirationTime := time.Unix(expTime, 0)
-	ttl := time.Until(expirationTime)
-	if ttl <= 0 {
-		return nil
-	}
-	return s.redisClient.Set(ctx, "blacklist:"+jti, "revoked", ttl).Err()
+	return s.tokens.EndSession(ctx, sub, jti, int64(expTime))
 }
 
-func (s *AuthService) exchangeCode(ctx context.Context, code string) (string, error) {
+func (s *AuthService) exchangeCode(ctx context.Context, client OAuthClient, code string) (string, error) {
 	data := url.Values{}
-	data.Set("client_id", s.clientID)
-	data.Set("client_secret", s.clientSecret)
+	data.Set("client_id", client.ClientID)
+	data.Set("client_secret", client.ClientSecret)
 	data.Set("code", code)
 	data.Set("grant_type", "authorization_code")
-	data.Set("redirect_uri", s.redirectURL)
+	data.Set("redirect_uri", client.RedirectURL)
 
 	req, _ := http.NewRequestWithContext(ctx, "POST", "https://oauth2.googleapis.com/token", strings.NewReader(data.Encode()))
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
@@ -228,7 +256,7 @@ func (s *AuthService) exchangeCode(ctx context.Context, code string) (string, er
 	return result.IDToken, nil
 }
 
-func (s *AuthService) verifyIDToken(ctx context.Context, idToken string) (string, error) {
+func (s *AuthService) verifyIDToken(ctx context.Context, client OAuthClient, idToken string) (string, error) {
 	keys, err := s.fetchGoogleKeys(ctx)
 	if err != nil {
 		return "", err
@@ -241,7 +269,7 @@ func (s *AuthService) verifyIDToken(ctx context.Context, idToken string) (string
 			return nil, errors.New("key not found")
 		}
 		return key, nil
-	})
+	}, jwt.WithAudience(client.ClientID)) // an ID token minted for another clinic's client is not accepted
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+const MaxAuthzBatchSize = 100
+
+var (
+	ErrInvalidAuthzRequest = domain.InvalidField("", "subject and action are required")
+	ErrAuthzBatchTooLarge  = domain.InvalidField("checks", fmt.Sprintf("a batch may contain at most %d checks", MaxAuthzBatchSize))
+	ErrInvalidPolicy       = domain.InvalidField("", "policy needs an action, an allow or deny effect and well-formed conditions")
+	ErrPolicyNotFound      = fmt.Errorf("policy %w", domain.ErrNotFound)
+)
+
+// AuthzService answers "may subject do action on resource" for other Baby Kliniek
+// services using attribute-based policies. Subject attributes come from the
+// user repository so callers do not have to trust or re-derive token claims.
+//
+// Deny policies override allow policies; without a matching allow the answer is deny.
+type AuthzService struct {
+	userRepo   ports.UserRepository
+	wardRepo   ports.WardRepository
+	policyRepo ports.PolicyRepository
+}
+
+func NewAuthzService(
+	userRepo ports.UserRepository,
+	wardRepo ports.WardRepository,
+	policyRepo ports.PolicyRepository,
+) *AuthzService {
+	return &AuthzService{
+		userRepo:   userRepo,
+		wardRepo:   wardRepo,
+		policyRepo: policyRepo,
+	}
+}
+
+func (s *AuthzService) Check(ctx context.Context, req domain.AuthzRequest) (*domain.AuthzDecision, error) {
+	decisions, err := s.CheckBatch(ctx, []domain.AuthzRequest{req})
+	if err != nil {
+		return nil, err
+	}
+	return &decisions[0], nil
+}
+
+// CheckBatch evaluates every request and writes all decisions to the decision log.
+// Subject attributes and policies are loaded once per batch.
+func (s *AuthzService) CheckBatch(ctx context.Context, reqs []domain.AuthzRequest) ([]domain.AuthzDecision, error) {
+	if len(reqs) > MaxAuthzBatchSize {
+		return nil, ErrAuthzBatchTooLarge
+	}
+	for _, req := range reqs {
+		if req.Subject == "" || req.Action == "" {
+			return nil, ErrInvalidAuthzRequest
+		}
+	}
+
+	subjects := make(map[string]map[string]string)
+	policies := make(map[string][]domain.Policy)
+	decisions := make([]domain.AuthzDecision, 0, len(reqs))
+	entries := make([]domain.DecisionLogEntry, 0, len(reqs))
+
+	for _, req := range reqs {
+		attrs, ok := subjects[req.Subject]
+		if !ok {
+			var err error
+			attrs, err = s.subjectAttributes(ctx, req.Subject)
+			if err != nil {
+				return nil, err
+			}
+			subjects[req.Subject] = attrs
+		}
+
+		actionPolicies, ok := policies[req.Action]
+		if !ok {
+			var err error
+			actionPolicies, err = s.policyRepo.ListPoliciesForAction(ctx, req.Action)
+			if err != nil {
+				return nil, err
+			}
+			policies[req.Action] = actionPolicies
+		}
+
+		resource, err := s.resourceAttributes(ctx, req.Resource)
+		if err != nil {
+			return nil, err
+		}
+		req.Resource = resource
+		decision := evaluate(actionPolicies, attrs, req)
+
+		decisions = append(decisions, decision)
+		entries = append(entries, domain.DecisionLogEntry{
+			ID:        uuid.NewString(),
+			Request:   req,
+			Decision:  decision,
+			CreatedAt: time.Now(),
+		})
+	}
+
+	// A decision that cannot be logged is not returned
+	if err := s.policyRepo.RecordDecisions(ctx, entries); err != nil {
+		return nil, err
+	}
+	return decisions, nil
+}
+
+func (s *AuthzService) ListPolicies(ctx context.Context) ([]domain.Policy, error) {
+	return s.policyRepo.ListPolicies(ctx)
+}
+
+func (s *AuthzService) CreatePolicy(ctx context.Context, policy domain.Policy) (*domain.Policy, error) {
+	if policy.Action == "" || (policy.Effect != domain.EffectAllow && policy.Effect != domain.EffectDeny) {
+		return nil, ErrInvalidPolicy
+	}
+	for _, c := range policy.Conditions {
+		if c.Subject == "" || (c.Resource == "") == (c.Value == "") {
+			return nil, ErrInvalidPolicy
+		}
+	}
+	if policy.Conditions == nil {
+		policy.Conditions = []domain.PolicyCondition{}
+	}
+
+	policy.ID = uuid.NewString()
+	policy.CreatedAt = time.Now()
+	if err := s.policyRepo.CreatePolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (s *AuthzService) DeletePolicy(ctx context.Context, id string) error {
+	if err := s.policyRepo.DeletePolicy(ctx, id); err != nil {
+		return notFoundOr(err, ErrPolicyNotFound)
+	}
+	return nil
+}
+
+// subjectAttributes loads id, role and, depending on the role, room_number, status and ward.
+// Unknown subjects get no attributes, so only policies without conditions can
+// match them. A failed lookup fails the check rather than deciding without it.
+func (s *AuthzService) subjectAttributes(ctx context.Context, subjectID string) (map[string]string, error) {
+	attrs := map[string]string{"id": subjectID}
+
+	user, err := s.userRepo.FindByID(ctx, subjectID)
+	if isNotFound(err) {
+		return attrs, nil
+	}
+	if err != nil {
+		return nil, attributesUnavailable(err)
+	}
+	attrs["role"] = string(user.Role)
+
+	switch {
+	case user.Role == domain.RoleParent:
+		parent, err := s.userRepo.FindParentByID(ctx, subjectID)
+		if isNotFound(err) {
+			break
+		}
+		if err != nil {
+			return nil, attributesUnavailable(err)
+		}
+		attrs["room_number"] = parent.RoomNumber
+		attrs["status"] = string(parent.Status)
+		if err := s.setRoomWard(ctx, attrs); err != nil {
+			return nil, err
+		}
+	case user.Role.RegisteredAsStaff():
+		// Only members of ward-scoped roles have a ward assignment
+		ward, err := s.wardRepo.GetStaffWard(ctx, subjectID)
+		if isNotFound(err) {
+			break
+		}
+		if err != nil {
+			return nil, attributesUnavailable(err)
+		}
+		attrs["ward"] = ward
+	}
+	return attrs, nil
+}
+
+// resourceAttributes fills in room_number from parent_id and ward from room_number
+// when the caller did not send them. The caller's map is not modified.
+func (s *AuthzService) resourceAttributes(ctx context.Context, resource map[string]string) (map[string]string, error) {
+	attrs := make(map[string]string, len(resource)+2)
+	for k, v := range resource {
+		attrs[k] = v
+	}
+
+	if attrs["room_number"] == "" && attrs["parent_id"] != "" {
+		parent, err := s.userRepo.FindParentByID(ctx, attrs["parent_id"])
+		switch {
+		case err == nil:
+			attrs["room_number"] = parent.RoomNumber
+		case !isNotFound(err):
+			return nil, attributesUnavailable(err)
+		}
+	}
+	if attrs["ward"] == "" && attrs["room_number"] != "" {
+		if err := s.setRoomWard(ctx, attrs); err != nil {
+			return nil, err
+		}
+	}
+	return attrs, nil
+}
+
+// setRoomWard sets ward to the ward of the room_number attribute, if the room is known
+func (s *AuthzService) setRoomWard(ctx context.Context, attrs map[string]string) error {
+	ward, err := s.wardRepo.GetRoomWard(ctx, attrs["room_number"])
+	if isNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return attributesUnavailable(err)
+	}
+	attrs["ward"] = ward
+	return nil
+}
+
+func attributesUnavailable(err error) error {
+	return fmt.Errorf("loading authorization attributes: %w: %w", domain.ErrUnavailable, err)
+}
+
+func evaluate(policies []domain.Policy, subject map[string]string, req domain.AuthzRequest) domain.AuthzDecision {
+	var allow *domain.Policy
+	for i := range policies {
+		policy := &policies[i]
+		if !policy.Matches(subject, req.Resource) {
+			continue
+		}
+		if policy.Effect == domain.EffectDeny {
+			return domain.AuthzDecision{
+				Allowed:  false,
+				Reason:   "denied by policy: " + policy.Description,
+				PolicyID: policy.ID,
+			}
+		}
+		if allow == nil {
+			allow = policy
+		}
+	}
+
+	if allow == nil {
+		return domain.AuthzDecision{Allowed: false, Reason: "no policy allows " + req.Action}
+	}
+	return domain.AuthzDecision{
+		Allowed:  true,
+		Reason:   "allowed by policy: " + allow.Description,
+		PolicyID: allow.ID,
+	}
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+const maxBulkRows = 500
+
+var (
+	ErrEmptyUpload    = errors.New("upload contains no rows")
+	ErrTooManyRows    = fmt.Errorf("upload exceeds %d rows", maxBulkRows)
+	ErrMissingRoom    = domain.InvalidField("room_number", "room_number is required")
+	ErrDuplicateInRun = domain.InvalidField("email", "email appears more than once in the upload")
+)
+
+// BulkRegistrationService registers many parents at once. Every row is
+// validated up front; the valid rows are then stored in a single transaction.
+type BulkRegistrationService struct {
+	userRepo    ports.UserRepository
+	wardPolicy  ports.WardPolicy
+	invitations *InvitationService
+}
+
+func NewBulkRegistrationService(userRepo ports.UserRepository, wardPolicy ports.WardPolicy, invitations *InvitationService) *BulkRegistrationService {
+	return &BulkRegistrationService{userRepo: userRepo, wardPolicy: wardPolicy, invitations: invitations}
+}
+
+// RegisterParents validates every row and, unless dryRun is set, registers
+// the parents of the valid rows as pending, each with its own registration
+// event and invitation, like a single registration.
+func (s *BulkRegistrationService) RegisterParents(
+	ctx context.Context,
+	actor domain.Actor,
+	rows []domain.BulkParentRow,
+	dryRun bool,
+) (*domain.BulkRegistrationReport, error) {
+	if len(rows) == 0 {
+		return nil, ErrEmptyUpload
+	}
+	if len(rows) > maxBulkRows {
+		return nil, ErrTooManyRows
+	}
+
+	report := &domain.BulkRegistrationReport{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Rows:   make([]domain.BulkRowResult, len(rows)),
+	}
+
+	emails := make([]string, 0, len(rows))
+	rooms := make([]string, 0, len(rows))
+	for i := range rows {
+		rows[i] = trimRow(rows[i])
+		if rows[i].ParseError != "" {
+			continue
+		}
+		if validEmail(rows[i].Email) {
+			emails = append(emails, strings.ToLower(rows[i].Email))
+		}
+		if validRoom(rows[i].RoomNumber) {
+			rooms = append(rooms, rows[i].RoomNumber)
+		}
+	}
+	// One query each for the whole upload instead of lookups per row
+	taken, err := s.userRepo.EmailsTaken(ctx, emails)
+	if err != nil {
+		return nil, err
+	}
+	deniedRooms, err := s.wardPolicy.AuthorizeParentRooms(ctx, actor, rooms)
+	if err != nil {
+		return nil, err
+	}
+
+	var parents []domain.Parent
+	var payloads [][]byte
+	var validRows []int
+	seen := make(map[string]bool)
+
+	for i, row := range rows {
+		result := domain.BulkRowResult{Line: row.Line, Email: row.Email}
+
+		for _, err := range validateRow(row, taken, seen, deniedRooms) {
+			result.Errors = append(result.Errors, err.Error())
+		}
+
+		if len(result.Errors) > 0 {
+			result.Status = domain.BulkRowInvalid
+			report.Invalid++
+			report.Rows[i] = result
+			continue
+		}
+
+		parent := domain.Parent{
+			User: domain.User{
+				ID:        uuid.NewString(),
+				Email:     row.Email,
+				Role:      domain.RoleParent,
+				CreatedAt: time.Now(),
+				FirstName: row.FirstName,
+				LastName:  row.LastName,
+			},
+			RoomNumber:  row.RoomNumber,
+			Status:      domain.ParentPending,
+			AdmissionID: uuid.NewString(),
+		}
+		payload, err := babyCreatedPayload(ctx, parent)
+		if err != nil {
+			return nil, err
+		}
+
+		parents = append(parents, parent)
+		payloads = append(payloads, payload)
+		validRows = append(validRows, i)
+
+		result.Status = domain.BulkRowValid
+		report.Valid++
+		report.Rows[i] = result
+	}
+
+	if dryRun || len(parents) == 0 {
+		return report, nil
+	}
+
+	sent, err := s.invitations.InviteParents(ctx, parents, payloads)
+	if err != nil {
+		return nil, err
+	}
+
+	for j, i := range validRows {
+		report.Rows[i].Status = domain.BulkRowCreated
+		report.Rows[i].UserID = parents[j].ID
+		report.Rows[i].InvitationSent = sent[j]
+	}
+	report.Created = len(parents)
+	return report, nil
+}
+
+// validateRow returns every problem with the row, so that one upload can fix them all
+func validateRow(row domain.BulkParentRow, taken, seen map[string]bool, deniedRooms map[string]error) []error {
+	if row.ParseError != "" {
+		return []error{errors.New(row.ParseError)}
+	}
+
+	var errs []error
+
+	if !validEmail(row.Email) {
+		errs = append(errs, ErrInvalidEmail)
+	} else {
+		key := strings.ToLower(row.Email)
+		if seen[key] {
+			errs = append(errs, ErrDuplicateInRun)
+		} else if taken[key] {
+			errs = append(errs, domain.ErrEmailTaken)
+		}
+		seen[key] = true
+	}
+
+	if row.FirstName == "" || row.LastName == "" || len(row.FirstName) > maxNameLength || len(row.LastName) > maxNameLength {
+		errs = append(errs, ErrInvalidName)
+	}
+
+	switch {
+	case row.RoomNumber == "":
+		errs = append(errs, ErrMissingRoom)
+	case len(row.RoomNumber) > maxRoomNumberLength:
+		errs = append(errs, ErrInvalidRoomNumber)
+	default:
+		if err := deniedRooms[row.RoomNumber]; err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+func validRoom(roomNumber string) bool {
+	return roomNumber != "" && len(roomNumber) <= maxRoomNumberLength
+}
+
+func trimRow(row domain.BulkParentRow) domain.BulkParentRow {
+	row.Email = strings.TrimSpace(row.Email)
+	row.FirstName = strings.TrimSpace(row.FirstName)
+	row.LastName = strings.TrimSpace(row.LastName)
+	row.RoomNumber = strings.TrimSpace(row.RoomNumber)
+	return row
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	redis "github.com/redis/go-redis/v9"
+)
+
+const DeviceCredentialDuration = 30 * 24 * time.Hour
+
+var ErrDeviceCredentialInvalid = errors.New("device credential is invalid or revoked")
+
+// DeviceSession is the session store record behind a long-lived device credential.
+// Only a hash of the credential is used as the key; the credential itself is never stored.
+type DeviceSession struct {
+	DeviceID   string `json:"device_id"`
+	UserID     string `json:"user_id"`
+	RoomNumber string `json:"room_number"`
+	DeviceName string `json:"device_name"`
+	CreatedAt  int64  `json:"created_at"`
+	Exp        int64  `json:"exp"`
+}
+
+// IssueDeviceCredential creates a long-lived opaque credential bound to a user's device
+func (t *TokenIssuer) IssueDeviceCredential(ctx context.Context, userID, roomNumber, deviceName string) (string, *DeviceSession, error) {
+	credential, err := randomSecret()
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
+	device := &DeviceSession{
+		DeviceID:   uuid.NewString(),
+		UserID:     userID,
+		RoomNumber: roomNumber,
+		DeviceName: deviceName,
+		CreatedAt:  now.Unix(),
+		Exp:        now.Add(DeviceCredentialDuration).Unix(),
+	}
+	data, _ := json.Marshal(device)
+
+	key := hashSecret(credential)
+	pipe := t.redisClient.TxPipeline()
+	pipe.Set(ctx, "device_credential:"+key, data, DeviceCredentialDuration)
+	pipe.SAdd(ctx, "devices:"+userID, key)
+	pipe.Expire(ctx, "devices:"+userID, DeviceCredentialDuration)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", nil, err
+	}
+
+	return credential, device, nil
+}
+
+// LookupDeviceCredential resolves a device credential to its session record
+func (t *TokenIssuer) LookupDeviceCredential(ctx context.Context, credential string) (*DeviceSession, error) {
+	data, err := t.redisClient.Get(ctx, "device_credential:"+hashSecret(credential)).Result()
+	if err == redis.Nil {
+		return nil, ErrDeviceCredentialInvalid
+	} else if err != nil {
+		return nil, err
+	}
+
+	var device DeviceSession
+	if err := json.Unmarshal([]byte(data), &device); err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+// RevokeDeviceCredentials removes every device credential issued to the user
+func (t *TokenIssuer) RevokeDeviceCredentials(ctx context.Context, userID string) error {
+	keys, err := t.redisClient.SMembers(ctx, "devices:"+userID).Result()
+	if err != nil && err != redis.Nil {
+		return err
+	}
+
+	pipe := t.redisClient.TxPipeline()
+	for _, key := range keys {
+		pipe.Del(ctx, "device_credential:"+key)
+	}
+	pipe.Del(ctx, "devices:"+userID)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// randomSecret returns a URL-safe random string suitable for one-time codes and credentials
+func randomSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/google/uuid"
+	redis "github.com/redis/go-redis/v9"
+)
+
+const EnrollmentCodeDuration = 15 * time.Minute
+
+var (
+	ErrEnrollmentParentNotFound = fmt.Errorf("parent %w", domain.ErrNotFound)
+	ErrEnrollmentNotAllowed     = errors.New("parent cannot be enrolled")
+	ErrEnrollmentCodeInvalid    = errors.New("enrollment code is invalid or expired")
+)
+
+// EnrollmentCode is a one-time code, typically rendered as a QR code,
+// that logs a parent's device in without going through Google.
+type EnrollmentCode struct {
+	Code       string
+	ParentID   string
+	RoomNumber string
+	ExpiresAt  time.Time
+}
+
+// DeviceEnrollment is the result of redeeming an enrollment code.
+type DeviceEnrollment struct {
+	Token            *IssuedToken
+	DeviceCredential string
+	Device           *DeviceSession
+}
+
+type enrollmentRecord struct {
+	TenantID   string `json:"tenant_id"`
+	ParentID   string `json:"parent_id"`
+	RoomNumber string `json:"room_number"`
+	IssuedBy   string `json:"issued_by"`
+}
+
+type EnrollmentService struct {
+	userRepo    ports.UserRepository
+	auditRepo   ports.AuditRepository
+	tokens      *TokenIssuer
+	redisClient *redis.Client
+}
+
+func NewEnrollmentService(
+	userRepo ports.UserRepository,
+	auditRepo ports.AuditRepository,
+	tokens *TokenIssuer,
+	redisClient *redis.Client,
+) *EnrollmentService {
+	return &EnrollmentService{
+		userRepo:    userRepo,
+		auditRepo:   auditRepo,
+		tokens:      tokens,
+		redisClient: redisClient,
+	}
+}
+
+// IssueCode mints a one-time enrollment code tied to the parent and their current room
+func (s *EnrollmentService) IssueCode(ctx context.Context, adminID, parentID string) (*EnrollmentCode, error) {
+	parent, err := s.userRepo.FindParentByID(ctx, parentID)
+	if err != nil {
+		return nil, ErrEnrollmentParentNotFound
+	}
+	if !parent.Status.IsAdmitted() || parent.RoomNumber == "" {
+		return nil, notAdmitted(parent.Status, ErrEnrollmentNotAllowed)
+	}
+
+	code, err := randomSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	tenant, _ := domain.TenantFromContext(ctx)
+	data, _ := json.Marshal(enrollmentRecord{
+		TenantID:   tenant,
+		ParentID:   parent.ID,
+		RoomNumber: parent.RoomNumber,
+		IssuedBy:   adminID,
+	})
+
+	if err := s.redisClient.Set(ctx, "enrollment_code:"+hashSecret(code), data, EnrollmentCodeDuration).Err(); err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(EnrollmentCodeDuration)
+	if err := s.auditRepo.RecordAudit(ctx, domain.AuditEntry{
+		ID:        uuid.NewString(),
+		ActorID:   adminID,
+		Action:    domain.AuditEnrollmentCodeIssued,
+		SubjectID: parent.ID,
+		Metadata:  map[string]string{"room_number": parent.RoomNumber},
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &EnrollmentCode{
+		Code:       code,
+		ParentID:   parent.ID,
+		RoomNumber: parent.RoomNumber,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+// Redeem consumes an enrollment code and returns a parent JWT plus a device credential
+func (s *EnrollmentService) Redeem(ctx context.Context, code, deviceName string) (*DeviceEnrollment, error) {
+	key := "enrollment_code:" + hashSecret(code)
+	data, err := s.redisClient.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return nil, ErrEnrollmentCodeInvalid
+	} else if err != nil {
+		return nil, err
+	}
+
+	var record enrollmentRecord
+	if err := json.Unmarshal([]byte(data), &record); err != nil {
+		return nil, err
+	}
+
+	// A code presented to another clinic is left untouched so it can still be redeemed where it was issued
+	tenant, _ := domain.TenantFromContext(ctx)
+	if record.TenantID != tenant {
+		return nil, ErrEnrollmentCodeInvalid
+	}
+
+	// Only the request that actually deletes the code may redeem it, keeping it single-use under concurrency
+	deleted, err := s.redisClient.Del(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	if deleted == 0 {
+		return nil, ErrEnrollmentCodeInvalid
+	}
+
+	parent, err := s.activeParentInRoom(ctx, record.ParentID, record.RoomNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, device, err := s.tokens.IssueDeviceCredential(ctx, parent.ID, parent.RoomNumber, deviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	issued, err := s.issueParentToken(ctx, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeviceEnrollment{
+		Token:            issued,
+		DeviceCredential: credential,
+		Device:           device,
+	}, nil
+}
+
+// ExchangeDeviceCredential issues a fresh parent JWT for an enrolled device
+func (s *EnrollmentService) ExchangeDeviceCredential(ctx context.Context, credential string) (*IssuedToken, error) {
+	device, err := s.tokens.LookupDeviceCredential(ctx, credential)
+	if err != nil {
+		return nil, err
+	}
+
+	parent, err := s.activeParentInRoom(ctx, device.UserID, device.RoomNumber)
+	if err != nil {
+		return nil, ErrDeviceCredentialInvalid
+	}
+
+	return s.issueParentToken(ctx, parent)
+}
+
+// activeParentInRoom ensures the parent is still admitted to the room the code or device was bound to
+func (s *EnrollmentService) activeParentInRoom(ctx context.Context, parentID, roomNumber string) (*domain.Parent, error) {
+	parent, err := s.userRepo.FindParentByID(ctx, parentID)
+	if err != nil {
+		return nil, ErrEnrollmentCodeInvalid
+	}
+	if !parent.Status.IsAdmitted() || parent.RoomNumber != roomNumber {
+		return nil, notAdmitted(parent.Status, ErrEnrollmentNotAllowed)
+	}
+	return parent, nil
+}
+
+func (s *EnrollmentService) issueParentToken(ctx context.Context, parent *domain.Parent) (*IssuedToken, error) {
+	issued, err := s.tokens.IssueSession(ctx, parent.User, ports.LoginDevice)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tokens.TrackSession(ctx, parent.ID, issued); err != nil {
+		return nil, err
+	}
+	return issued, nil
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSelfErasure        = errors.New("admins cannot erase their own account")
+	ErrActiveAdminErasure = errors.New("an active admin must be removed through an approved removal before erasure")
+)
+
+// ErasureService handles right-to-be-forgotten requests: the user's personal
+// data is pseudonymized in place and downstream services are told to purge theirs.
+type ErasureService struct {
+	userRepo    ports.UserRepository
+	visitorRepo ports.VisitorRepository
+	sessions    ports.SessionRevoker
+}
+
+func NewErasureService(
+	userRepo ports.UserRepository,
+	visitorRepo ports.VisitorRepository,
+	sessions ports.SessionRevoker,
+) *ErasureService {
+	return &ErasureService{
+		userRepo:    userRepo,
+		visitorRepo: visitorRepo,
+		sessions:    sessions,
+	}
+}
+
+// Erase revokes every session of the user (and, for parents, their visitors)
+// and then erases their personal data. Deactivated users can be erased too.
+func (s *ErasureService) Erase(ctx context.Context, actor domain.Actor, userID string) (*domain.ErasureTombstone, error) {
+	if actor.ID == userID {
+		return nil, ErrSelfErasure
+	}
+
+	user, err := s.userRepo.FindErasableUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	// Removing an admin needs a second admin's approval; erasure must not bypass it
+	if user.Role == domain.RoleAdmin {
+		if _, err := s.userRepo.FindByID(ctx, user.ID); err == nil {
+			return nil, ErrActiveAdminErasure
+		}
+	}
+
+	if err := revokeAccess(ctx, s.sessions, s.visitorRepo, user.ID, user.Role == domain.RoleParent); err != nil {
+		return nil, err
+	}
+
+	tombstone := domain.ErasureTombstone{
+		ID:          uuid.NewString(),
+		UserID:      user.ID,
+		Role:        user.Role,
+		RequestedBy: actor.ID,
+		ErasedAt:    time.Now().UTC(),
+	}
+
+	tenant, _ := domain.TenantFromContext(ctx)
+	outboxPayload, err := json.Marshal(ports.UserErasedEvent{
+		TenantID: tenant,
+		UserID:   tombstone.UserID,
+		Role:     string(tombstone.Role),
+		ErasedAt: tombstone.ErasedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.EraseUser(ctx, tombstone, outboxPayload); err != nil {
+		return nil, err
+	}
+	return &tombstone, nil
+}
@@ -0,0 +1,23 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+)
+
+// isNotFound reports whether a repository found no matching row. Any other
+// error means the lookup itself failed and must not be mistaken for absence.
+func isNotFound(err error) bool {
+	return errors.Is(err, sql.ErrNoRows) || errors.Is(err, domain.ErrNotFound)
+}
+
+// notFoundOr returns notFound when the repository found no matching row, and
+// err otherwise, so a failed lookup is not reported as a missing resource
+func notFoundOr(err, notFound error) error {
+	if isNotFound(err) {
+		return notFound
+	}
+	return err
+}
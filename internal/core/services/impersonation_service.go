@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const ImpersonationTokenDuration = 10 * time.Minute
+
+var (
+	ErrImpersonationTargetNotFound = fmt.Errorf("impersonation target %w", domain.ErrNotFound)
+	ErrImpersonationNotAllowed     = errors.New("impersonation of this user is not allowed")
+	ErrNotImpersonating            = errors.New("token is not an impersonation token")
+)
+
+// ImpersonationService lets admins act as a parent through a short-lived,
+// audited token carrying an RFC 8693 "act" claim that names the admin.
+type ImpersonationService struct {
+	userRepo  ports.UserRepository
+	auditRepo ports.AuditRepository
+	tokens    *TokenIssuer
+	tracker   ports.ImpersonationTracker
+}
+
+func NewImpersonationService(
+	userRepo ports.UserRepository,
+	auditRepo ports.AuditRepository,
+	tokens *TokenIssuer,
+	tracker ports.ImpersonationTracker,
+) *ImpersonationService {
+	return &ImpersonationService{
+		userRepo:  userRepo,
+		auditRepo: auditRepo,
+		tokens:    tokens,
+		tracker:   tracker,
+	}
+}
+
+// Start issues an impersonation token for the target user on behalf of adminID.
+// The token is tracked against the target, so discharging, deactivating or
+// erasing them ends the impersonation too.
+func (s *ImpersonationService) Start(ctx context.Context, adminID, targetID string) (*IssuedToken, error) {
+	if adminID == targetID {
+		return nil, ErrImpersonationNotAllowed
+	}
+
+	target, err := s.userRepo.FindByID(ctx, targetID)
+	if isNotFound(err) {
+		return nil, ErrImpersonationTargetNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("looking up impersonation target %s: %w", targetID, err)
+	}
+
+	// Only parent accounts may be impersonated; admin power must never be borrowed
+	if target.Role != domain.RoleParent {
+		return nil, ErrImpersonationNotAllowed
+	}
+
+	status, err := s.userRepo.GetParentStatus(ctx, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.ParentStatus(status).IsAdmitted() {
+		return nil, notAdmitted(domain.ParentStatus(status), ErrImpersonationNotAllowed)
+	}
+
+	issued, err := s.tokens.Issue(ctx, jwt.MapClaims{
+		"sub":  target.ID,
+		"role": string(target.Role),
+		"act":  map[string]string{"sub": adminID},
+	}, ImpersonationTokenDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	// An impersonation that cannot be revoked with the target's access must not be usable
+	if err := s.tracker.TrackImpersonation(ctx, target.ID, issued.JTI, issued.ExpiresAt); err != nil {
+		_ = s.tokens.Revoke(ctx, issued.JTI, issued.ExpiresAt.Unix())
+		return nil, err
+	}
+
+	err = s.auditRepo.RecordAudit(ctx, domain.AuditEntry{
+		ID:        uuid.NewString(),
+		ActorID:   adminID,
+		Action:    domain.AuditImpersonationStarted,
+		SubjectID: target.ID,
+		Metadata: map[string]string{
+			"jti":        issued.JTI,
+			"expires_at": issued.ExpiresAt.UTC().Format(time.RFC3339),
+		},
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		// An impersonation that cannot be audited must not be usable
+		_ = s.tokens.Revoke(ctx, issued.JTI, issued.ExpiresAt.Unix())
+		return nil, err
+	}
+
+	return issued, nil
+}
+
+// End revokes an impersonation token and records the end of the session
+func (s *ImpersonationService) End(ctx context.Context, tokenString string) error {
+	token, _, err := new(jwt.Parser).ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
+		return err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return errors.New("invalid claims")
+	}
+
+	act, _ := claims["act"].(map[string]interface{})
+	adminID, _ := act["sub"].(string)
+	if adminID == "" {
+		return ErrNotImpersonating
+	}
+
+	subjectID, _ := claims["sub"].(string)
+	jti, _ := claims["jti"].(string)
+	expTime, _ := claims["exp"].(float64)
+
+	if err := s.tokens.Revoke(ctx, jti, int64(expTime)); err != nil {
+		return err
+	}
+
+	return s.auditRepo.RecordAudit(ctx, domain.AuditEntry{
+		ID:        uuid.NewString(),
+		ActorID:   adminID,
+		Action:    domain.AuditImpersonationEnded,
+		SubjectID: subjectID,
+		Metadata:  map[string]string{"jti": jti},
+		CreatedAt: time.Now(),
+	})
+}
@@ -0,0 +1,343 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+var ErrInvalidInvitationStatus = domain.InvalidField("status", "status must be Pending, Accepted or Cancelled")
+
+// invitationMail is the invitation email in one locale. Body takes the
+// parent's first name, the accept link and the link's expiry.
+type invitationMail struct {
+	subject    string
+	body       string
+	dateLayout string
+}
+
+var invitationMails = map[string]invitationMail{
+	domain.LocaleEnglish: {
+		subject: "You are invited to the baby clinic app",
+		body: "Hello %s,\n\n" +
+			"You have been registered at the clinic. Sign in with this email address through the link below to activate your account:\n\n" +
+			"%s\n\n" +
+			"The link is valid until %s.\n",
+		dateLayout: time.RFC1123,
+	},
+	domain.LocaleDutch: {
+		subject: "Uitnodiging voor de app van de babykliniek",
+		body: "Hallo %s,\n\n" +
+			"U bent ingeschreven bij de kliniek. Meld u met dit e-mailadres aan via de onderstaande link om uw account te activeren:\n\n" +
+			"%s\n\n" +
+			"De link is geldig tot %s.\n",
+		dateLayout: "02-01-2006 15:04 MST",
+	},
+}
+
+// InvitationSettings configures how accept links are signed and where they point.
+type InvitationSettings struct {
+	SigningKey []byte
+	// AcceptURLs is the accept page of every clinic, keyed by tenant ID
+	AcceptURLs map[string]string
+	TTL        time.Duration
+}
+
+// InvitationService invites newly registered parents by email. A parent stays
+// Pending until they sign in through the link with the email they were
+// registered with.
+type InvitationService struct {
+	repo      ports.InvitationRepository
+	userRepo  ports.UserRepository
+	lifecycle *ParentLifecycleService
+	mailer    ports.Mailer
+	settings  InvitationSettings
+}
+
+func NewInvitationService(
+	repo ports.InvitationRepository,
+	userRepo ports.UserRepository,
+	lifecycle *ParentLifecycleService,
+	mailer ports.Mailer,
+	settings InvitationSettings,
+) *InvitationService {
+	return &InvitationService{
+		repo:      repo,
+		userRepo:  userRepo,
+		lifecycle: lifecycle,
+		mailer:    mailer,
+		settings:  settings,
+	}
+}
+
+// InviteParent stores the pending parent with their invitation and mails the
+// accept link. A parent whose email could not be sent is still stored; sent
+// reports whether the email went out, so an admin can resend it.
+func (s *InvitationService) InviteParent(ctx context.Context, parent domain.Parent, outboxPayload []byte) (sent bool, err error) {
+	invitation := s.newInvitation(parent)
+	if err := s.repo.CreateParentInvitation(ctx, parent, invitation, outboxPayload); err != nil {
+		return false, err
+	}
+	return s.deliver(ctx, invitation, parent.User), nil
+}
+
+// InviteParents stores all pending parents with their invitations in one
+// transaction, then mails each accept link. sent[i] reports whether the email
+// of parents[i] went out.
+func (s *InvitationService) InviteParents(ctx context.Context, parents []domain.Parent, outboxPayloads [][]byte) (sent []bool, err error) {
+	invitations := make([]domain.Invitation, len(parents))
+	for i, parent := range parents {
+		invitations[i] = s.newInvitation(parent)
+	}
+	if err := s.repo.CreateParentInvitations(ctx, parents, invitations, outboxPayloads); err != nil {
+		return nil, err
+	}
+
+	sent = make([]bool, len(parents))
+	for i, parent := range parents {
+		sent[i] = s.deliver(ctx, invitations[i], parent.User)
+	}
+	return sent, nil
+}
+
+// newInvitation is the first invitation of a newly registered parent
+func (s *InvitationService) newInvitation(parent domain.Parent) domain.Invitation {
+	now := time.Now().UTC()
+	return domain.Invitation{
+		ID:         uuid.NewString(),
+		UserID:     parent.ID,
+		Email:      parent.Email,
+		Status:     domain.InvitationPending,
+		Sends:      1,
+		CreatedAt:  now,
+		LastSentAt: now,
+		ExpiresAt:  now.Add(s.settings.TTL),
+	}
+}
+
+// deliver sends a stored invitation; a failure is only logged, since an admin can resend it
+func (s *InvitationService) deliver(ctx context.Context, invitation domain.Invitation, parent domain.User) bool {
+	if err := s.send(ctx, invitation, parent); err != nil {
+		log.Printf("Warning: failed to send invitation %s: %v", invitation.ID, err)
+		return false
+	}
+	return true
+}
+
+// Resend mails a new accept link, valid for another TTL. Links sent before stop working.
+func (s *InvitationService) Resend(ctx context.Context, id string) (*domain.Invitation, error) {
+	invitation, err := s.repo.GetInvitation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.Status != domain.InvitationPending {
+		return nil, domain.ErrInvitationClosed
+	}
+
+	parent, err := s.userRepo.FindParentByID(ctx, invitation.UserID)
+	if err != nil {
+		return nil, notFoundOr(err, ErrParentNotFound)
+	}
+
+	now := time.Now().UTC()
+	invitation.Sends++
+	invitation.LastSentAt = now
+	invitation.ExpiresAt = now.Add(s.settings.TTL)
+	if err := s.repo.UpdateInvitation(ctx, *invitation); err != nil {
+		return nil, err
+	}
+
+	if err := s.send(ctx, *invitation, parent.User); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// Cancel withdraws a pending invitation. A parent who was never admitted is archived with it.
+func (s *InvitationService) Cancel(ctx context.Context, actor domain.Actor, id string) (*domain.Invitation, error) {
+	invitation, err := s.repo.GetInvitation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.Status != domain.InvitationPending {
+		return nil, domain.ErrInvitationClosed
+	}
+
+	parent, err := s.userRepo.FindParentByID(ctx, invitation.UserID)
+	if err != nil {
+		return nil, notFoundOr(err, ErrParentNotFound)
+	}
+	if parent.Status == domain.ParentPending {
+		if _, err := s.lifecycle.Archive(ctx, actor, parent.ID, "invitation cancelled"); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now().UTC()
+	invitation.Status = domain.InvitationCancelled
+	invitation.CancelledAt = &now
+	if err := s.repo.UpdateInvitation(ctx, *invitation); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// List returns the invitations with the given status, or all of them when status is empty.
+func (s *InvitationService) List(ctx context.Context, status domain.InvitationStatus) ([]domain.Invitation, error) {
+	switch status {
+	case "", domain.InvitationPending, domain.InvitationAccepted, domain.InvitationCancelled:
+	default:
+		return nil, ErrInvalidInvitationStatus
+	}
+	return s.repo.ListInvitations(ctx, status)
+}
+
+// Verify checks an accept link's token and returns its invitation if it can still be accepted.
+func (s *InvitationService) Verify(ctx context.Context, token string) (*domain.Invitation, error) {
+	tenant, ok := domain.TenantFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+
+	id, sends, expiresAt, err := s.parseToken(tenant, token)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err := s.repo.GetInvitation(ctx, id)
+	if err != nil {
+		return nil, domain.ErrInvitationInvalid
+	}
+	if invitation.Status != domain.InvitationPending {
+		return nil, domain.ErrInvitationClosed
+	}
+	// A resent invitation replaces the links sent before it
+	if invitation.Sends != sends {
+		return nil, domain.ErrInvitationInvalid
+	}
+	if !time.Now().Before(expiresAt) || invitation.Expired(time.Now()) {
+		return nil, domain.ErrInvitationExpired
+	}
+	return invitation, nil
+}
+
+// Activate lets staff admit a pending parent who has no pending invitation,
+// such as one registered before invitations were mailed. An invited parent
+// is only admitted by accepting, so their email is verified first.
+func (s *InvitationService) Activate(ctx context.Context, actor domain.Actor, parentID, reason string) (*domain.ParentStatusChange, error) {
+	_, err := s.repo.FindPendingInvitation(ctx, parentID)
+	if err == nil {
+		return nil, domain.ErrInvitationOutstanding
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	return s.lifecycle.Activate(ctx, actor, parentID, reason)
+}
+
+// Accept activates the invited parent once they signed in with the verified
+// email they were invited with.
+func (s *InvitationService) Accept(ctx context.Context, token, email string) (*domain.Invitation, error) {
+	invitation, err := s.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(strings.TrimSpace(email), invitation.Email) {
+		return nil, domain.ErrInvitationEmailMismatch
+	}
+
+	parent, err := s.userRepo.FindParentByID(ctx, invitation.UserID)
+	if err != nil {
+		return nil, notFoundOr(err, ErrParentNotFound)
+	}
+	switch {
+	case parent.Status == domain.ParentPending:
+		self := domain.Actor{ID: parent.ID, Role: domain.RoleParent}
+		if _, err := s.lifecycle.Activate(ctx, self, parent.ID, "invitation accepted"); err != nil {
+			return nil, err
+		}
+	case parent.Status.IsAdmitted():
+		// Staff admitted the parent before they accepted
+	default:
+		return nil, notAdmitted(parent.Status, domain.ErrInvitationClosed)
+	}
+
+	now := time.Now().UTC()
+	invitation.Status = domain.InvitationAccepted
+	invitation.AcceptedAt = &now
+	if err := s.repo.UpdateInvitation(ctx, *invitation); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// send mails the invitation's current accept link in the parent's locale
+func (s *InvitationService) send(ctx context.Context, invitation domain.Invitation, parent domain.User) error {
+	tenant, ok := domain.TenantFromContext(ctx)
+	if !ok {
+		return domain.ErrNoTenant
+	}
+	acceptURL, ok := s.settings.AcceptURLs[tenant]
+	if !ok {
+		return ErrUnknownTenant
+	}
+
+	mail, ok := invitationMails[parent.Locale]
+	if !ok {
+		mail = invitationMails[domain.DefaultLocale]
+	}
+
+	link := acceptURL + "?token=" + url.QueryEscape(s.token(tenant, invitation))
+	return s.mailer.Send(ctx, ports.MailMessage{
+		To:      invitation.Email,
+		Subject: mail.subject,
+		Body:    fmt.Sprintf(mail.body, parent.FirstName, link, invitation.ExpiresAt.Format(mail.dateLayout)),
+	})
+}
+
+// token signs "<id>.<sends>.<expires unix>" for the tenant
+func (s *InvitationService) token(tenant string, invitation domain.Invitation) string {
+	payload := fmt.Sprintf("%s.%d.%d", invitation.ID, invitation.Sends, invitation.ExpiresAt.Unix())
+	return payload + "." + s.sign(tenant, payload)
+}
+
+func (s *InvitationService) parseToken(tenant, token string) (id string, sends int, expiresAt time.Time, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 4 {
+		return "", 0, time.Time{}, domain.ErrInvitationInvalid
+	}
+
+	payload := strings.Join(parts[:3], ".")
+	if !hmac.Equal([]byte(parts[3]), []byte(s.sign(tenant, payload))) {
+		return "", 0, time.Time{}, domain.ErrInvitationInvalid
+	}
+
+	sends, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return "", 0, time.Time{}, domain.ErrInvitationInvalid
+	}
+	expires, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return "", 0, time.Time{}, domain.ErrInvitationInvalid
+	}
+	return parts[0], sends, time.Unix(expires, 0), nil
+}
+
+// sign binds the payload to the tenant so a link cannot be used at another clinic
+func (s *InvitationService) sign(tenant, payload string) string {
+	mac := hmac.New(sha256.New, s.settings.SigningKey)
+	mac.Write([]byte(tenant + "." + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+const maxStatusReasonLength = 500
+
+var (
+	ErrInvalidTransition   = errors.New("parent status transition not allowed")
+	ErrInvalidStatusReason = domain.InvalidField("reason", "reason must be at most 500 characters")
+)
+
+// parentTransitions lists the statuses each status may move to:
+// Pending -> Active -> Discharged -> Readmitted/Archived, and Readmitted -> Discharged again.
+var parentTransitions = map[domain.ParentStatus][]domain.ParentStatus{
+	domain.ParentPending:    {domain.ParentActive, domain.ParentArchived},
+	domain.ParentActive:     {domain.ParentDischarged},
+	domain.ParentDischarged: {domain.ParentReadmitted, domain.ParentArchived},
+	domain.ParentReadmitted: {domain.ParentDischarged},
+}
+
+func canTransition(from, to domain.ParentStatus) bool {
+	for _, allowed := range parentTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// notAdmitted returns err for a parent who may not act at the clinic, wrapped
+// with domain.ErrDischarged when the parent has left it
+func notAdmitted(status domain.ParentStatus, err error) error {
+	if status.HasLeft() {
+		return fmt.Errorf("%w: %w", err, domain.ErrDischarged)
+	}
+	return err
+}
+
+// ParentLifecycleService moves parents through their stay and keeps a history of every transition.
+type ParentLifecycleService struct {
+	userRepo    ports.UserRepository
+	visitorRepo ports.VisitorRepository
+	sessions    ports.SessionRevoker
+}
+
+func NewParentLifecycleService(
+	userRepo ports.UserRepository,
+	visitorRepo ports.VisitorRepository,
+	sessions ports.SessionRevoker,
+) *ParentLifecycleService {
+	return &ParentLifecycleService{
+		userRepo:    userRepo,
+		visitorRepo: visitorRepo,
+		sessions:    sessions,
+	}
+}
+
+// Activate admits a pending parent.
+func (s *ParentLifecycleService) Activate(ctx context.Context, actor domain.Actor, parentID, reason string) (*domain.ParentStatusChange, error) {
+	return s.transition(ctx, actor, parentID, domain.ParentActive, reason, nil)
+}
+
+// Discharge ends the parent's stay; their sessions, devices and visitors are revoked
+// before the status changes so that a failure can simply be retried.
+func (s *ParentLifecycleService) Discharge(ctx context.Context, actor domain.Actor, parentID, reason string) (*domain.ParentStatusChange, error) {
+	return s.transition(ctx, actor, parentID, domain.ParentDischarged, reason, func() error {
+		return revokeAccess(ctx, s.sessions, s.visitorRepo, parentID, true)
+	})
+}
+
+// Readmit brings a discharged parent back; they can sign in again.
+func (s *ParentLifecycleService) Readmit(ctx context.Context, actor domain.Actor, parentID, reason string) (*domain.ParentStatusChange, error) {
+	return s.transition(ctx, actor, parentID, domain.ParentReadmitted, reason, nil)
+}
+
+// Archive closes the record of a discharged or never admitted parent for good.
+func (s *ParentLifecycleService) Archive(ctx context.Context, actor domain.Actor, parentID, reason string) (*domain.ParentStatusChange, error) {
+	return s.transition(ctx, actor, parentID, domain.ParentArchived, reason, nil)
+}
+
+// History returns the parent's status transitions, oldest first.
+func (s *ParentLifecycleService) History(ctx context.Context, parentID string) ([]domain.ParentStatusChange, error) {
+	if _, err := s.userRepo.FindParentByID(ctx, parentID); err != nil {
+		return nil, notFoundOr(err, ErrParentNotFound)
+	}
+	return s.userRepo.ListParentStatusHistory(ctx, parentID)
+}
+
+// transition validates the move to status `to`, runs before (if any) and then
+// stores the change with its history entry and parent.status_changed event,
+// followed by a parent.discharged event for a discharge.
+func (s *ParentLifecycleService) transition(
+	ctx context.Context,
+	actor domain.Actor,
+	parentID string,
+	to domain.ParentStatus,
+	reason string,
+	before func() error,
+) (*domain.ParentStatusChange, error) {
+	reason = strings.TrimSpace(reason)
+	if len(reason) > maxStatusReasonLength {
+		return nil, ErrInvalidStatusReason
+	}
+
+	parent, err := s.userRepo.FindParentByID(ctx, parentID)
+	if err != nil {
+		return nil, notFoundOr(err, ErrParentNotFound)
+	}
+	if !canTransition(parent.Status, to) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, parent.Status, to)
+	}
+
+	if before != nil {
+		if err := before(); err != nil {
+			return nil, err
+		}
+	}
+
+	change := domain.ParentStatusChange{
+		ID:         uuid.NewString(),
+		ParentID:   parent.ID,
+		FromStatus: parent.Status,
+		ToStatus:   to,
+		Reason:     reason,
+		ChangedBy:  actor.ID,
+		ChangedAt:  time.Now().UTC(),
+	}
+
+	tenant, _ := domain.TenantFromContext(ctx)
+	outboxPayload, err := json.Marshal(ports.ParentStatusChangedEvent{
+		TenantID:    tenant,
+		UserID:      change.ParentID,
+		AdmissionID: parent.AdmissionID,
+		FromStatus:  string(change.FromStatus),
+		ToStatus:    string(change.ToStatus),
+		Reason:      change.Reason,
+		ChangedBy:   change.ChangedBy,
+		ChangedAt:   change.ChangedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	events := []ports.OutboxEvent{{Type: ports.EventParentStatusChanged, Payload: outboxPayload}}
+
+	if to == domain.ParentDischarged {
+		discharged, err := json.Marshal(ports.ParentDischargedEvent{
+			TenantID:     tenant,
+			UserID:       change.ParentID,
+			AdmissionID:  parent.AdmissionID,
+			RoomNumber:   parent.RoomNumber,
+			Reason:       change.Reason,
+			DischargedBy: change.ChangedBy,
+			DischargedAt: change.ChangedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, ports.OutboxEvent{Type: ports.EventParentDischarged, Payload: discharged})
+	}
+
+	if err := s.userRepo.ChangeParentStatus(ctx, change, events); err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+// revokeAccess ends the user's session, enrolled devices and impersonations of
+// them and, for parents, the access of every visitor they invited
+func revokeAccess(ctx context.Context, sessions ports.SessionRevoker, visitorRepo ports.VisitorRepository, userID string, isParent bool) error {
+	if err := sessions.RevokeSession(ctx, userID); err != nil {
+		return err
+	}
+	if err := sessions.RevokeImpersonations(ctx, userID); err != nil {
+		return err
+	}
+	if err := sessions.RevokeDeviceCredentials(ctx, userID); err != nil {
+		return err
+	}
+	if !isParent {
+		return nil
+	}
+
+	visitorIDs, err := visitorRepo.RevokeVisitorsByParent(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, visitorID := range visitorIDs {
+		if err := sessions.RevokeSession(ctx, visitorID); err != nil {
+			log.Printf("Warning: failed to revoke session of visitor %s: %v", visitorID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+var (
+	ErrInvalidDisplayName  = domain.InvalidField("display_name", "display_name must be at most 100 characters")
+	ErrInvalidContactEmail = domain.InvalidField("contact_email", "contact_email is not a valid address")
+	ErrUnsupportedLocale   = domain.InvalidField("locale", "locale must be one of "+strings.Join(domain.SupportedLocales, ", "))
+)
+
+// LockedProfileField rejects a change to a field of the profile that only the clinic may change
+func LockedProfileField(field string) *domain.ValidationError {
+	return domain.InvalidField(field, "this field is maintained by the clinic and cannot be changed on your profile")
+}
+
+// ProfileService lets users read their own account and change the few
+// fields that are theirs: display name, preferred locale and contact email.
+type ProfileService struct {
+	userRepo ports.UserRepository
+}
+
+func NewProfileService(userRepo ports.UserRepository) *ProfileService {
+	return &ProfileService{userRepo: userRepo}
+}
+
+func (s *ProfileService) Get(ctx context.Context, userID string) (*domain.UserRecord, error) {
+	record, err := s.userRepo.GetUserRecord(ctx, userID)
+	if err != nil {
+		return nil, notFoundOr(err, ErrUserNotFound)
+	}
+	return record, nil
+}
+
+// Update applies the user's change if their profile is still at
+// expectedVersion. A user.updated event is queued with the change; a new
+// locale is carried by the tokens issued from the next sign-in on.
+func (s *ProfileService) Update(ctx context.Context, userID string, expectedVersion int, update domain.ProfileUpdate) (*domain.UserRecord, error) {
+	if expectedVersion < 1 {
+		return nil, ErrMissingUserVersion
+	}
+	if update.DisplayName == nil && update.Locale == nil && update.ContactEmail == nil {
+		return nil, ErrEmptyUserUpdate
+	}
+
+	record, err := s.userRepo.GetUserRecord(ctx, userID)
+	if err != nil {
+		return nil, notFoundOr(err, ErrUserNotFound)
+	}
+	if record.Version != expectedVersion {
+		return nil, domain.ErrVersionConflict
+	}
+
+	var v domain.Validation
+	changed := []string{}
+	set := func(field string, value string, target *string) {
+		if value != *target {
+			*target = value
+			changed = append(changed, field)
+		}
+	}
+
+	if update.DisplayName != nil {
+		name := strings.TrimSpace(*update.DisplayName)
+		if len(name) > maxNameLength {
+			v.Append(ErrInvalidDisplayName)
+		}
+		set("display_name", name, &record.DisplayName)
+	}
+	if update.Locale != nil {
+		set("locale", parseLocale(&v, *update.Locale), &record.Locale)
+	}
+	if update.ContactEmail != nil {
+		email := strings.TrimSpace(*update.ContactEmail)
+		if email != "" && !validEmail(email) {
+			v.Append(ErrInvalidContactEmail)
+		}
+		set("contact_email", email, &record.ContactEmail)
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	if len(changed) == 0 {
+		return record, nil
+	}
+
+	if err := updateUser(ctx, s.userRepo, record, expectedVersion, changed); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// parseLocale returns the supported locale of an optional language tag,
+// recording a validation error for a language the service does not speak
+func parseLocale(v *domain.Validation, tag string) string {
+	if strings.TrimSpace(tag) == "" {
+		return ""
+	}
+	locale, ok := domain.ParseLocale(tag)
+	if !ok {
+		v.Append(ErrUnsupportedLocale)
+	}
+	return locale
+}
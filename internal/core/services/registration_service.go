@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
@@ -10,22 +12,54 @@ import (
 	"github.com/google/uuid"
 )
 
+var (
+	ErrUnsupportedRole   = domain.InvalidField("role", "role does not exist in this clinic")
+	ErrInvalidStaffRole  = domain.InvalidField("role", "role is not a staff role")
+	ErrMissingWard       = domain.InvalidField("ward", "ward is required for ward-scoped roles")
+	ErrWardNotApplicable = domain.InvalidField("ward", "ward only applies to ward-scoped roles")
+	ErrMissingEmail      = domain.InvalidField("email", "email is required")
+)
+
 type RegistrationService struct {
-	userRepo ports.UserRepository
+	userRepo       ports.UserRepository
+	permissionRepo ports.PermissionRepository
+	invitations    *InvitationService
 }
 
 func NewRegistrationService(
 	userRepo ports.UserRepository,
+	permissionRepo ports.PermissionRepository,
+	invitations *InvitationService,
 ) *RegistrationService {
 	return &RegistrationService{
-		userRepo: userRepo,
+		userRepo:       userRepo,
+		permissionRepo: permissionRepo,
+		invitations:    invitations,
 	}
 }
 
+// RegisterParent registers a pending parent and mails their invitation, in
+// their locale when one is given.
 func (s *RegistrationService) RegisterParent(
 	ctx context.Context,
-	email, firstName, lastName, roomNumber string,
+	email, firstName, lastName, roomNumber, locale string,
 ) (string, error) {
+	email, firstName, lastName = trimUserFields(email, firstName, lastName)
+	roomNumber = strings.TrimSpace(roomNumber)
+
+	var v domain.Validation
+	validateUserFields(&v, email, firstName, lastName)
+	switch {
+	case roomNumber == "":
+		v.Append(ErrMissingRoom)
+	case len(roomNumber) > maxRoomNumberLength:
+		v.Append(ErrInvalidRoomNumber)
+	}
+	locale = parseLocale(&v, locale)
+	if err := v.Err(); err != nil {
+		return "Registration failed", err
+	}
+
 	parent := domain.Parent{
 		User: domain.User{
 			ID:        uuid.NewString(),
@@ -34,52 +68,116 @@ func (s *RegistrationService) RegisterParent(
 			CreatedAt: time.Now(),
 			FirstName: firstName,
 			LastName:  lastName,
+			Locale:    locale,
 		},
-		RoomNumber: roomNumber,
-		Status:     domain.ParentActive,
-	}
-
-	event := struct {
-		UserID     string `json:"user_id"`
-		LastName   string `json:"last_name"`
-		RoomNumber string `json:"room_number"`
-	}{
-		UserID:     parent.User.ID,
-		LastName:   parent.User.LastName,
-		RoomNumber: parent.RoomNumber,
+		RoomNumber:  roomNumber,
+		Status:      domain.ParentPending,
+		AdmissionID: uuid.NewString(),
 	}
 
-	outboxPayload, err := json.Marshal(event)
+	outboxPayload, err := babyCreatedPayload(ctx, parent)
 	if err != nil {
 		return "Registration failed", err
 	}
 
-	_, err = s.userRepo.CreateParent(ctx, parent, outboxPayload)
+	// The parent becomes active once they accept the emailed invitation
+	sent, err := s.invitations.InviteParent(ctx, parent, outboxPayload)
 	if err != nil {
 		return "Registration failed", err
 	}
+	if !sent {
+		return "Parent registered, but the invitation email could not be sent; resend it", nil
+	}
 
-	return "Parent registered successfully", nil
+	return "Parent registered successfully; invitation sent", nil
 }
 
-func (s *RegistrationService) RegisterAdmin(
+// RegisterStaff registers a member of any staff role of the clinic, including
+// roles created through POST /roles. Members of ward-scoped roles need a ward.
+func (s *RegistrationService) RegisterStaff(
 	ctx context.Context,
 	email, firstName, lastName string,
+	role domain.Role,
+	ward string,
 ) (string, error) {
+	email, firstName, lastName = trimUserFields(email, firstName, lastName)
+	ward = strings.TrimSpace(ward)
+
+	var v domain.Validation
+	validateUserFields(&v, email, firstName, lastName)
+	definition, err := s.staffRole(ctx, role)
+	var invalid *domain.ValidationError
+	switch {
+	case errors.As(err, &invalid):
+		v.Append(invalid)
+	case err != nil:
+		return "Registration failed", err
+	case definition.WardScoped && ward == "":
+		v.Append(ErrMissingWard)
+	case !definition.WardScoped && ward != "":
+		v.Append(ErrWardNotApplicable)
+	}
+	if err := v.Err(); err != nil {
+		return "Registration failed", err
+	}
 
-	user := domain.User{
-		ID:        uuid.NewString(),
-		Email:     email,
-		Role:      domain.RoleParent,
-		CreatedAt: time.Now(),
-		FirstName: firstName,
-		LastName:  lastName,
+	staff := domain.Staff{
+		User: domain.User{
+			ID:        uuid.NewString(),
+			Email:     email,
+			Role:      role,
+			CreatedAt: time.Now(),
+			FirstName: firstName,
+			LastName:  lastName,
+		},
+		Ward: ward,
 	}
 
-	_, err := s.userRepo.CreateAdmin(ctx, user)
+	_, err = s.userRepo.CreateStaff(ctx, staff)
 	if err != nil {
 		return "Registration failed", err
 	}
 
-	return "Admin registered successfully", nil
+	return "Staff member registered successfully", nil
+}
+
+// staffRole returns the clinic's definition of a staff role
+func (s *RegistrationService) staffRole(ctx context.Context, role domain.Role) (*domain.RoleDefinition, error) {
+	if !role.RegisteredAsStaff() {
+		return nil, ErrInvalidStaffRole
+	}
+	definition, err := s.permissionRepo.GetRole(ctx, role)
+	if isNotFound(err) {
+		return nil, ErrUnsupportedRole
+	}
+	return definition, err
+}
+
+// validateUserFields checks the fields every account has
+func validateUserFields(v *domain.Validation, email, firstName, lastName string) {
+	switch {
+	case email == "":
+		v.Append(ErrMissingEmail)
+	case !validEmail(email):
+		v.Append(ErrInvalidEmail)
+	}
+	if firstName == "" || lastName == "" || len(firstName) > maxNameLength || len(lastName) > maxNameLength {
+		v.Append(ErrInvalidName)
+	}
+}
+
+func trimUserFields(email, firstName, lastName string) (string, string, string) {
+	return strings.TrimSpace(email), strings.TrimSpace(firstName), strings.TrimSpace(lastName)
+}
+
+// babyCreatedPayload is the outbox event that lets the baby service create the baby of a new admission
+func babyCreatedPayload(ctx context.Context, parent domain.Parent) ([]byte, error) {
+	tenant, _ := domain.TenantFromContext(ctx)
+	return json.Marshal(ports.CreateBabyEvent{
+		TenantID:    tenant,
+		AdmissionID: parent.AdmissionID,
+		UserID:      parent.ID,
+		LastName:    parent.LastName,
+		RoomNumber:  parent.RoomNumber,
+	})
 }
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+// retentionMaxBatches bounds how many batches one rule runs per clinic and run;
+// anything left is picked up by the next run
+const retentionMaxBatches = 20
+
+var ErrRetentionInProgress = errors.New("retention job is already running on another replica")
+
+// RetentionService applies the retention policy: parents are anonymized a
+// while after discharge, through the same erasure used for GDPR requests,
+// processed outbox events are deleted and expired subject access archives
+// are purged.
+type RetentionService struct {
+	policy  domain.RetentionPolicy
+	repo    ports.RetentionRepository
+	erasure *ErasureService
+	metrics ports.RetentionMetrics
+	tenants []string
+}
+
+func NewRetentionService(
+	policy domain.RetentionPolicy,
+	repo ports.RetentionRepository,
+	erasure *ErasureService,
+	metrics ports.RetentionMetrics,
+	tenants []string,
+) *RetentionService {
+	return &RetentionService{
+		policy:  policy,
+		repo:    repo,
+		erasure: erasure,
+		metrics: metrics,
+		tenants: tenants,
+	}
+}
+
+// Start runs the job every interval until ctx is done
+func (s *RetentionService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		reports, err := s.Run(ctx)
+		if errors.Is(err, ErrRetentionInProgress) {
+			continue
+		}
+		if err != nil {
+			log.Printf("Retention run failed: %v", err)
+		}
+		for _, report := range reports {
+			for _, rule := range report.Rules {
+				if rule.Applied > 0 || rule.Failed > 0 {
+					log.Printf("Retention %s for tenant %s: %d applied, %d failed", rule.Rule, report.TenantID, rule.Applied, rule.Failed)
+				}
+			}
+		}
+	}
+}
+
+// Report is a dry run for the clinic in ctx: what the next run would touch
+func (s *RetentionService) Report(ctx context.Context) (*domain.RetentionReport, error) {
+	report := s.newReport(ctx, true)
+	for i := range report.Rules {
+		if err := s.countDue(ctx, &report.Rules[i]); err != nil {
+			return nil, err
+		}
+	}
+	return &report, nil
+}
+
+// Run applies every enabled rule to every clinic in bounded batches. Only one
+// replica runs at a time; the others get ErrRetentionInProgress.
+func (s *RetentionService) Run(ctx context.Context) ([]domain.RetentionReport, error) {
+	release, acquired, err := s.repo.AcquireRetentionLock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		s.metrics.ObserveRetentionSkipped()
+		return nil, ErrRetentionInProgress
+	}
+	defer release()
+
+	var reports []domain.RetentionReport
+	var errs []error
+	for _, tenant := range s.tenants {
+		report, err := s.apply(domain.WithTenant(ctx, tenant))
+		s.metrics.ObserveRetentionRun(report)
+		reports = append(reports, report)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("tenant %s: %w", tenant, err))
+		}
+	}
+	return reports, errors.Join(errs...)
+}
+
+func (s *RetentionService) newReport(ctx context.Context, dryRun bool) domain.RetentionReport {
+	tenant, _ := domain.TenantFromContext(ctx)
+	now := time.Now().UTC()
+	return domain.RetentionReport{
+		TenantID:    tenant,
+		DryRun:      dryRun,
+		GeneratedAt: now,
+		Rules: []domain.RetentionRuleReport{
+			retentionRule(domain.RetentionAnonymizeParents, s.policy.ParentAnonymizeAfter, now),
+			retentionRule(domain.RetentionDeleteOutbox, s.policy.OutboxRetention, now),
+			{Rule: domain.RetentionPurgeArchives, Enabled: true, Cutoff: &now},
+		},
+	}
+}
+
+func retentionRule(rule string, period time.Duration, now time.Time) domain.RetentionRuleReport {
+	report := domain.RetentionRuleReport{Rule: rule, Enabled: period > 0}
+	if report.Enabled {
+		cutoff := now.Add(-period)
+		report.Cutoff = &cutoff
+	}
+	return report
+}
+
+func (s *RetentionService) countDue(ctx context.Context, rule *domain.RetentionRuleReport) error {
+	if !rule.Enabled {
+		return nil
+	}
+
+	var err error
+	switch rule.Rule {
+	case domain.RetentionAnonymizeParents:
+		rule.Due, err = s.repo.CountParentsDischargedBefore(ctx, *rule.Cutoff)
+	case domain.RetentionDeleteOutbox:
+		rule.Due, err = s.repo.CountProcessedOutboxEvents(ctx, *rule.Cutoff)
+	case domain.RetentionPurgeArchives:
+		rule.Due, err = s.repo.CountExpiredSubjectAccessArchives(ctx, *rule.Cutoff)
+	}
+	return err
+}
+
+func (s *RetentionService) apply(ctx context.Context) (domain.RetentionReport, error) {
+	report := s.newReport(ctx, false)
+	for i := range report.Rules {
+		rule := &report.Rules[i]
+		if !rule.Enabled {
+			continue
+		}
+		if err := s.countDue(ctx, rule); err != nil {
+			return report, err
+		}
+
+		var err error
+		switch rule.Rule {
+		case domain.RetentionAnonymizeParents:
+			err = s.anonymizeParents(ctx, rule)
+		case domain.RetentionDeleteOutbox:
+			err = s.deleteOutboxEvents(ctx, rule)
+		case domain.RetentionPurgeArchives:
+			err = s.purgeArchives(ctx, rule)
+		}
+		if err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// anonymizeParents erases parents batch by batch. A parent that fails is
+// counted once and skipped for the rest of the run.
+func (s *RetentionService) anonymizeParents(ctx context.Context, rule *domain.RetentionRuleReport) error {
+	actor := domain.Actor{ID: domain.RetentionActor}
+	failed := make(map[string]bool)
+
+	for batch := 0; batch < retentionMaxBatches && ctx.Err() == nil; batch++ {
+		ids, err := s.repo.ListParentsDischargedBefore(ctx, *rule.Cutoff, s.policy.BatchSize+len(failed))
+		if err != nil {
+			return err
+		}
+
+		progress := 0
+		for _, id := range ids {
+			if failed[id] {
+				continue
+			}
+			progress++
+			_, err := s.erasure.Erase(ctx, actor, id)
+			switch {
+			case err == nil:
+				rule.Applied++
+			case errors.Is(err, domain.ErrNothingToErase):
+				// Erased by someone else in the meantime
+			default:
+				log.Printf("Retention could not anonymize parent %s: %v", id, err)
+				failed[id] = true
+				rule.Failed++
+			}
+		}
+		if progress < s.policy.BatchSize {
+			break
+		}
+	}
+	return ctx.Err()
+}
+
+func (s *RetentionService) deleteOutboxEvents(ctx context.Context, rule *domain.RetentionRuleReport) error {
+	for batch := 0; batch < retentionMaxBatches && ctx.Err() == nil; batch++ {
+		deleted, err := s.repo.DeleteProcessedOutboxEvents(ctx, *rule.Cutoff, s.policy.BatchSize)
+		if err != nil {
+			return err
+		}
+		rule.Applied += deleted
+		if deleted < s.policy.BatchSize {
+			break
+		}
+	}
+	return ctx.Err()
+}
+
+func (s *RetentionService) purgeArchives(ctx context.Context, rule *domain.RetentionRuleReport) error {
+	for batch := 0; batch < retentionMaxBatches && ctx.Err() == nil; batch++ {
+		purged, err := s.repo.PurgeExpiredSubjectAccessArchives(ctx, *rule.Cutoff, s.policy.BatchSize)
+		if err != nil {
+			return err
+		}
+		rule.Applied += purged
+		if purged < s.policy.BatchSize {
+			break
+		}
+	}
+	return ctx.Err()
+}
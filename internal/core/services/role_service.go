@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+var (
+	ErrInvalidRoleName       = domain.InvalidField("name", "role name must be upper case letters and underscores")
+	ErrInvalidPermissionName = domain.InvalidField("permissions", "permission name must have the form resource:action")
+	ErrUnknownPermission     = domain.InvalidField("permissions", "unknown permission")
+	ErrAdminOnlyPermission   = domain.InvalidField("permissions", "admins:* and roles:* permissions are reserved for the ADMIN role")
+	ErrRoleNotFound          = fmt.Errorf("role %w", domain.ErrNotFound)
+	// ErrAdminRoleProtected is returned for changes to the ADMIN role, which
+	// would let a single admin lock the clinic out
+	ErrAdminRoleProtected = errors.New("the permissions of the ADMIN role cannot be changed")
+)
+
+var (
+	roleNamePattern       = regexp.MustCompile(`^[A-Z][A-Z_]{1,49}$`)
+	permissionNamePattern = regexp.MustCompile(`^[a-z][a-z_-]*:[a-z][a-z_-]*$`)
+)
+
+// RoleService manages the role -> permission mapping stored in the database.
+// Tokens embed the permission set of their role at issuance time.
+type RoleService struct {
+	permissionRepo ports.PermissionRepository
+}
+
+func NewRoleService(permissionRepo ports.PermissionRepository) *RoleService {
+	return &RoleService{permissionRepo: permissionRepo}
+}
+
+func (s *RoleService) ListRoles(ctx context.Context) ([]domain.RoleDefinition, error) {
+	return s.permissionRepo.ListRoles(ctx)
+}
+
+func (s *RoleService) ListPermissions(ctx context.Context) ([]domain.PermissionDefinition, error) {
+	return s.permissionRepo.ListPermissions(ctx)
+}
+
+// CreateRole adds a staff role to the clinic. Members of a ward-scoped role
+// are registered with a ward and only act on parents in its rooms.
+func (s *RoleService) CreateRole(ctx context.Context, name domain.Role, description string, permissions []domain.Permission, wardScoped bool) (*domain.RoleDefinition, error) {
+	if !roleNamePattern.MatchString(string(name)) {
+		return nil, ErrInvalidRoleName
+	}
+	if err := s.validatePermissions(ctx, name, permissions); err != nil {
+		return nil, err
+	}
+
+	role := domain.RoleDefinition{
+		Name:        name,
+		Description: description,
+		Permissions: permissions,
+		WardScoped:  wardScoped,
+		Version:     1,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.permissionRepo.CreateRole(ctx, role); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// SetRolePermissions replaces the permission set of any role but ADMIN
+func (s *RoleService) SetRolePermissions(ctx context.Context, name domain.Role, permissions []domain.Permission) (*domain.RoleDefinition, error) {
+	if name == domain.RoleAdmin {
+		return nil, ErrAdminRoleProtected
+	}
+	if err := s.validatePermissions(ctx, name, permissions); err != nil {
+		return nil, err
+	}
+
+	role, err := s.permissionRepo.SetRolePermissions(ctx, name, permissions)
+	if err != nil {
+		if _, lookupErr := s.permissionRepo.GetRole(ctx, name); isNotFound(lookupErr) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return role, nil
+}
+
+func (s *RoleService) CreatePermission(ctx context.Context, name domain.Permission, description string) (*domain.PermissionDefinition, error) {
+	if !permissionNamePattern.MatchString(string(name)) {
+		return nil, ErrInvalidPermissionName
+	}
+
+	permission := domain.PermissionDefinition{Name: name, Description: description}
+	if err := s.permissionRepo.CreatePermission(ctx, permission); err != nil {
+		return nil, err
+	}
+	return &permission, nil
+}
+
+func (s *RoleService) validatePermissions(ctx context.Context, role domain.Role, permissions []domain.Permission) error {
+	known, err := s.permissionRepo.ListPermissions(ctx)
+	if err != nil {
+		return err
+	}
+
+	exists := make(map[domain.Permission]bool, len(known))
+	for _, p := range known {
+		exists[p.Name] = true
+	}
+
+	for _, p := range permissions {
+		if !exists[p] {
+			return fmt.Errorf("%w: %s", ErrUnknownPermission, p)
+		}
+		if p.AdminOnly() && role != domain.RoleAdmin {
+			return fmt.Errorf("%w: %s", ErrAdminOnlyPermission, p)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+const (
+	// SubjectAccessArchiveTTL is how long a generated archive can be downloaded
+	SubjectAccessArchiveTTL = 7 * 24 * time.Hour
+	// subjectAccessTimeout bounds the generation of one archive, including the wait for a worker
+	subjectAccessTimeout = 5 * time.Minute
+	// subjectAccessWorkers is how many archives are generated at the same time
+	subjectAccessWorkers = 2
+)
+
+// subjectDataDescriptions explain each archive section in the manifest
+var subjectDataDescriptions = map[string]string{
+	"users":                 "Account details",
+	"parents":               "Admission: room and lifecycle status",
+	"staff":                 "Ward assignment of clinical staff",
+	"parent_status_history": "Admissions, discharges and other status changes",
+	"parent_room_history":   "Room transfers",
+	"visitors":              "Family visitors invited by the parent",
+	"invitations":           "Invitation emails sent to the user to set up their account",
+	"admin_approvals":       "Admin approvals to create or remove the user as an admin, or requested or decided by the user",
+	"outbox_events":         "Events about the user sent to other clinic services",
+	"audit_log":             "Privileged actions taken by or on the user",
+	"authz_decisions":       "Authorization decisions on the user's requests",
+	"sessions":              "Current sign-in session and enrolled devices",
+}
+
+// subjectAccessNotes tell the subject about data that is deliberately not in the archive
+var subjectAccessNotes = []string{
+	"Sign-in goes through Google; no identity provider account links are stored, accounts are matched by email address.",
+	"Only current sessions are stored; ended and expired sessions leave no history beyond the audit log.",
+	"Data held by other clinic services, such as baby records, is not part of this archive.",
+}
+
+// SubjectAccessService answers subject access requests (GDPR article 15) with
+// a JSON archive of all data held about a user. Archives are generated in the
+// background by a small pool of workers; callers poll the request's status.
+type SubjectAccessService struct {
+	requests  ports.SubjectAccessRepository
+	userRepo  ports.UserRepository
+	auditRepo ports.AuditRepository
+	sessions  ports.SessionLister
+
+	workers chan struct{}
+	running sync.WaitGroup
+}
+
+func NewSubjectAccessService(
+	requests ports.SubjectAccessRepository,
+	userRepo ports.UserRepository,
+	auditRepo ports.AuditRepository,
+	sessions ports.SessionLister,
+) *SubjectAccessService {
+	return &SubjectAccessService{
+		requests:  requests,
+		userRepo:  userRepo,
+		auditRepo: auditRepo,
+		sessions:  sessions,
+		workers:   make(chan struct{}, subjectAccessWorkers),
+	}
+}
+
+// Request audits and queues the export of all data held about the user,
+// including deactivated users. Requests still open from before the generation
+// timeout (e.g. interrupted by a restart) no longer block a new one.
+func (s *SubjectAccessService) Request(ctx context.Context, actor domain.Actor, userID string) (*domain.SubjectAccessRequest, error) {
+	if _, err := s.userRepo.FindErasableUser(ctx, userID); err != nil {
+		if errors.Is(err, domain.ErrNothingToErase) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	request := domain.SubjectAccessRequest{
+		ID:          uuid.NewString(),
+		UserID:      userID,
+		RequestedBy: actor.ID,
+		Status:      domain.SubjectAccessPending,
+		CreatedAt:   now,
+	}
+
+	tenant, _ := domain.TenantFromContext(ctx)
+	if err := s.auditRepo.RecordAudit(ctx, domain.AuditEntry{
+		ID:        uuid.NewString(),
+		ActorID:   actor.ID,
+		Action:    domain.AuditSubjectAccessRequested,
+		SubjectID: userID,
+		Metadata:  map[string]string{"tenant_id": tenant, "request_id": request.ID},
+		CreatedAt: now,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := s.requests.CreateSubjectAccessRequest(ctx, request, now.Add(-2*subjectAccessTimeout)); err != nil {
+		return nil, err
+	}
+
+	// The archive outlives the HTTP request but keeps its tenant
+	s.running.Add(1)
+	go s.generate(context.WithoutCancel(ctx), request)
+	return &request, nil
+}
+
+// Get returns a request. A non-empty subjectID restricts the lookup to
+// requests about that user, for self-service callers.
+func (s *SubjectAccessService) Get(ctx context.Context, requestID, subjectID string) (*domain.SubjectAccessRequest, error) {
+	request, err := s.requests.GetSubjectAccessRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if subjectID != "" && request.UserID != subjectID {
+		return nil, domain.ErrSubjectAccessNotFound
+	}
+	return request, nil
+}
+
+// Archive returns the archive of a completed request that has not expired
+// yet. Every download is audited; nothing is returned if it cannot be.
+func (s *SubjectAccessService) Archive(ctx context.Context, actor domain.Actor, requestID, subjectID string) ([]byte, error) {
+	request, err := s.Get(ctx, requestID, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != domain.SubjectAccessCompleted {
+		return nil, domain.ErrSubjectAccessNotReady
+	}
+	if request.Expired(time.Now()) {
+		return nil, domain.ErrSubjectAccessExpired
+	}
+
+	archive, err := s.requests.GetSubjectAccessArchive(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	tenant, _ := domain.TenantFromContext(ctx)
+	if err := s.auditRepo.RecordAudit(ctx, domain.AuditEntry{
+		ID:        uuid.NewString(),
+		ActorID:   actor.ID,
+		Action:    domain.AuditSubjectAccessDownloaded,
+		SubjectID: request.UserID,
+		Metadata:  map[string]string{"tenant_id": tenant, "request_id": request.ID},
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+// Wait blocks until every queued archive is generated or ctx is done, for graceful shutdown
+func (s *SubjectAccessService) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// generate builds the archive once a worker is free and records the outcome
+func (s *SubjectAccessService) generate(ctx context.Context, request domain.SubjectAccessRequest) {
+	defer s.running.Done()
+
+	ctx, cancel := context.WithTimeout(ctx, subjectAccessTimeout)
+	defer cancel()
+	// The outcome is recorded even when generation ran out of time
+	store := context.WithoutCancel(ctx)
+
+	archive, err := s.build(ctx, &request)
+	now := time.Now().UTC()
+	request.CompletedAt = &now
+	if err != nil {
+		log.Printf("Subject access request %s failed: %v", request.ID, err)
+		request.Status = domain.SubjectAccessFailed
+		request.Error = "the archive could not be generated; please submit a new request"
+		archive = nil
+	} else {
+		expiresAt := now.Add(SubjectAccessArchiveTTL)
+		request.Status = domain.SubjectAccessCompleted
+		request.ExpiresAt = &expiresAt
+	}
+
+	if err := s.requests.UpdateSubjectAccessRequest(store, request, archive); err != nil {
+		log.Printf("Recording subject access request %s failed: %v", request.ID, err)
+	}
+}
+
+func (s *SubjectAccessService) build(ctx context.Context, request *domain.SubjectAccessRequest) ([]byte, error) {
+	select {
+	case s.workers <- struct{}{}:
+		defer func() { <-s.workers }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	request.Status = domain.SubjectAccessRunning
+	if err := s.requests.UpdateSubjectAccessRequest(ctx, *request, nil); err != nil {
+		return nil, err
+	}
+
+	sections, err := s.requests.CollectSubjectData(ctx, request.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.sessions.ListSessions(ctx, request.UserID)
+	if err != nil {
+		return nil, err
+	}
+	sessionRecords := make([]json.RawMessage, 0, len(sessions))
+	for _, session := range sessions {
+		record, err := json.Marshal(session)
+		if err != nil {
+			return nil, err
+		}
+		sessionRecords = append(sessionRecords, record)
+	}
+	sections = append(sections, domain.SubjectDataSection{Name: "sessions", Records: sessionRecords})
+
+	tenant, _ := domain.TenantFromContext(ctx)
+	archive := domain.SubjectAccessArchive{
+		Manifest: domain.SubjectAccessManifest{
+			Format:      domain.SubjectAccessArchiveFormat,
+			RequestID:   request.ID,
+			TenantID:    tenant,
+			UserID:      request.UserID,
+			GeneratedAt: time.Now().UTC(),
+			Notes:       subjectAccessNotes,
+		},
+		Data: make(map[string][]json.RawMessage, len(sections)),
+	}
+	for _, section := range sections {
+		archive.Manifest.Sections = append(archive.Manifest.Sections, domain.SubjectAccessManifestEntry{
+			Name:        section.Name,
+			Description: subjectDataDescriptions[section.Name],
+			Records:     len(section.Records),
+		})
+		archive.Data[section.Name] = section.Records
+	}
+	return json.Marshal(archive)
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	redis "github.com/redis/go-redis/v9"
+)
+
+// TokenIssuer signs system JWTs and manages their lifecycle in Redis. Logins
+// and revocations of user sessions are queued as outbox events.
+type TokenIssuer struct {
+	privateKey     *rsa.PrivateKey
+	redisClient    *redis.Client
+	permissionRepo ports.PermissionRepository
+	events         ports.EventRepository
+}
+
+// IssuedToken is a signed system JWT together with the metadata needed to revoke it.
+type IssuedToken struct {
+	Token     string
+	JTI       string
+	ExpiresAt time.Time
+}
+
+func NewTokenIssuer(
+	privateKey *rsa.PrivateKey,
+	redisClient *redis.Client,
+	permissionRepo ports.PermissionRepository,
+	events ports.EventRepository,
+) *TokenIssuer {
+	return &TokenIssuer{
+		privateKey:     privateKey,
+		redisClient:    redisClient,
+		permissionRepo: permissionRepo,
+		events:         events,
+	}
+}
+
+// Issue signs the given claims, adding jti, iat and exp for the requested lifetime.
+// When the claims carry a role, the role's permission set and version are embedded as well.
+func (t *TokenIssuer) Issue(ctx context.Context, claims jwt.MapClaims, ttl time.Duration) (*IssuedToken, error) {
+	// Every token is bound to the clinic it was issued for; AuthMiddleware enforces it
+	tenant, ok := domain.TenantFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	claims["tenant"] = tenant
+
+	if role, ok := claims["role"].(string); ok && t.permissionRepo != nil {
+		definition, err := t.permissionRepo.GetRole(ctx, domain.Role(role))
+		if err != nil {
+			return nil, err
+		}
+		claims["perms"] = definition.Permissions
+		claims["role_version"] = definition.Version
+	}
+
+	jti := uuid.New().String()
+	now := time.Now()
+	expTime := now.Add(ttl)
+
+	claims["jti"] = jti
+	claims["iat"] = now.Unix()
+	claims["exp"] = expTime.Unix()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	signedToken, err := token.SignedString(t.privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IssuedToken{Token: signedToken, JTI: jti, ExpiresAt: expTime}, nil
+}
+
+// userClaims identifies the user a token is issued to, with their preferred
+// locale once they chose one, so errors can be answered in their language
+func userClaims(user domain.User) jwt.MapClaims {
+	claims := jwt.MapClaims{
+		"sub":  user.ID,
+		"role": string(user.Role),
+	}
+	if user.Locale != "" {
+		claims["locale"] = user.Locale
+	}
+	return claims
+}
+
+// IssueSession issues the user's session token and queues a user.logged_in
+// event for it. method is one of the ports.Login* constants.
+func (t *TokenIssuer) IssueSession(ctx context.Context, user domain.User, method string) (*IssuedToken, error) {
+	issued, err := t.Issue(ctx, userClaims(user), TokenDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	// The token is only handed out once its login is recorded
+	tenant, _ := domain.TenantFromContext(ctx)
+	if err := t.appendEvent(ctx, user.ID, ports.EventUserLoggedIn, ports.UserLoggedInEvent{
+		TenantID:   tenant,
+		UserID:     user.ID,
+		Role:       string(user.Role),
+		Method:     method,
+		SessionID:  issued.JTI,
+		LoggedInAt: time.Now().UTC(),
+	}); err != nil {
+		return nil, err
+	}
+	return issued, nil
+}
+
+// EndSession revokes a session token its user logged out of, with a
+// session.revoked event. Expired tokens need neither.
+func (t *TokenIssuer) EndSession(ctx context.Context, userID, jti string, expTime int64) error {
+	if time.Until(time.Unix(expTime, 0)) <= 0 {
+		return nil
+	}
+	if err := t.recordRevocation(ctx, userID, jti, ports.SessionLogout); err != nil {
+		return err
+	}
+	return t.Revoke(ctx, jti, expTime)
+}
+
+// recordRevocation queues a session.revoked event. It is written before the
+// token is revoked, so a failed revocation is retried rather than left unreported.
+func (t *TokenIssuer) recordRevocation(ctx context.Context, userID, jti, reason string) error {
+	tenant, _ := domain.TenantFromContext(ctx)
+	return t.appendEvent(ctx, userID, ports.EventSessionRevoked, ports.SessionRevokedEvent{
+		TenantID:  tenant,
+		UserID:    userID,
+		SessionID: jti,
+		Reason:    reason,
+		RevokedAt: time.Now().UTC(),
+	})
+}
+
+// appendEvent queues an event about the user, unless the issuer has no event repository
+func (t *TokenIssuer) appendEvent(ctx context.Context, userID, eventType string, event any) error {
+	if t.events == nil {
+		return nil
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return t.events.AppendEvent(ctx, "user", userID, ports.OutboxEvent{Type: eventType, Payload: payload})
+}
+
+// TrackSession stores the token as the user's active session so it can be revoked later
+func (t *TokenIssuer) TrackSession(ctx context.Context, userID string, issued *IssuedToken) error {
+	session := RedisSession{JTI: issued.JTI, Exp: issued.ExpiresAt.Unix()}
+	data, _ := json.Marshal(session)
+
+	return t.redisClient.Set(ctx, "active_session:"+userID, data, time.Until(issued.ExpiresAt)).Err()
+}
+
+// RevokeSession blacklists the user's active session token, if any, and forgets it
+func (t *TokenIssuer) RevokeSession(ctx context.Context, userID string) error {
+	metadata, err := t.redisClient.Get(ctx, "active_session:"+userID).Result()
+	if err == redis.Nil {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var session RedisSession
+	if err := json.Unmarshal([]byte(metadata), &session); err != nil {
+		return err
+	}
+
+	if err := t.recordRevocation(ctx, userID, session.JTI, ports.SessionRevoked); err != nil {
+		return err
+	}
+	if err := t.Revoke(ctx, session.JTI, session.Exp); err != nil {
+		return err
+	}
+
+	return t.redisClient.Del(ctx, "active_session:"+userID).Err()
+}
+
+// TrackImpersonation remembers an impersonation token issued for the user until it expires
+func (t *TokenIssuer) TrackImpersonation(ctx context.Context, userID, jti string, expiresAt time.Time) error {
+	key := "impersonations:" + userID
+	pipe := t.redisClient.TxPipeline()
+	pipe.HSet(ctx, key, jti, expiresAt.Unix())
+	// Impersonation tokens share one lifetime, so the newest one expires last
+	pipe.Expire(ctx, key, time.Until(expiresAt))
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// RevokeImpersonations blacklists every impersonation token issued for the user and forgets them
+func (t *TokenIssuer) RevokeImpersonations(ctx context.Context, userID string) error {
+	key := "impersonations:" + userID
+	tokens, err := t.redisClient.HGetAll(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+
+	for jti, exp := range tokens {
+		expTime, err := strconv.ParseInt(exp, 10, 64)
+		if err != nil {
+			return err
+		}
+		if err := t.Revoke(ctx, jti, expTime); err != nil {
+			return err
+		}
+	}
+
+	return t.redisClient.Del(ctx, key).Err()
+}
+
+// Revoke blacklists a specific JTI until its natural expiration
+func (t *TokenIssuer) Revoke(ctx context.Context, jti string, expTime int64) error {
+	expirationTime := time.Unix(expTime, 0)
+	ttl := time.Until(expirationTime)
+	if ttl <= 0 {
+		return nil
+	}
+	return t.redisClient.Set(ctx, "blacklist:"+jti, "revoked", ttl).Err()
+}
+
+// ListSessions describes the user's active session and enrolled devices. Only
+// a token's ID and expiry are stored, never the token itself.
+func (t *TokenIssuer) ListSessions(ctx context.Context, userID string) ([]domain.SessionRecord, error) {
+	var sessions []domain.SessionRecord
+
+	metadata, err := t.redisClient.Get(ctx, "active_session:"+userID).Result()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+	if err == nil {
+		var session RedisSession
+		if err := json.Unmarshal([]byte(metadata), &session); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, domain.SessionRecord{
+			Kind:      "token",
+			ID:        session.JTI,
+			ExpiresAt: time.Unix(session.Exp, 0).UTC(),
+		})
+	}
+
+	keys, err := t.redisClient.SMembers(ctx, "devices:"+userID).Result()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		data, err := t.redisClient.Get(ctx, "device_credential:"+key).Result()
+		if err == redis.Nil {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		var device DeviceSession
+		if err := json.Unmarshal([]byte(data), &device); err != nil {
+			return nil, err
+		}
+		createdAt := time.Unix(device.CreatedAt, 0).UTC()
+		sessions = append(sessions, domain.SessionRecord{
+			Kind:       "device",
+			ID:         device.DeviceID,
+			DeviceName: device.DeviceName,
+			RoomNumber: device.RoomNumber,
+			CreatedAt:  &createdAt,
+			ExpiresAt:  time.Unix(device.Exp, 0).UTC(),
+		})
+	}
+	return sessions, nil
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+const maxTransferReasonLength = 500
+
+var (
+	ErrParentNotFound        = fmt.Errorf("parent %w", domain.ErrNotFound)
+	ErrParentNotActive       = errors.New("only admitted parents can be transferred")
+	ErrSameRoom              = errors.New("parent is already in that room")
+	ErrInvalidTransferReason = domain.InvalidField("reason", "reason must be at most 500 characters")
+)
+
+// TransferService moves parents between rooms and tells the baby service about it.
+type TransferService struct {
+	userRepo   ports.UserRepository
+	admissions ports.AdmissionRepository
+}
+
+func NewTransferService(userRepo ports.UserRepository, admissions ports.AdmissionRepository) *TransferService {
+	return &TransferService{userRepo: userRepo, admissions: admissions}
+}
+
+// Transfer moves the parent, and the co-parents sharing their room, to roomNumber.
+// The room changes, their history entries and one parent.room_changed event are
+// stored atomically.
+func (s *TransferService) Transfer(ctx context.Context, actor domain.Actor, parentID, roomNumber, reason string) (*domain.RoomTransfer, error) {
+	roomNumber = strings.TrimSpace(roomNumber)
+	if roomNumber == "" || len(roomNumber) > maxRoomNumberLength {
+		return nil, ErrInvalidRoomNumber
+	}
+	reason = strings.TrimSpace(reason)
+	if len(reason) > maxTransferReasonLength {
+		return nil, ErrInvalidTransferReason
+	}
+
+	parent, err := s.userRepo.FindParentByID(ctx, parentID)
+	if err != nil {
+		return nil, notFoundOr(err, ErrParentNotFound)
+	}
+	if !parent.Status.IsAdmitted() {
+		return nil, notAdmitted(parent.Status, ErrParentNotActive)
+	}
+	if parent.RoomNumber == roomNumber {
+		return nil, ErrSameRoom
+	}
+
+	coParentIDs, err := s.coParentsInRoom(ctx, *parent)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer := domain.RoomTransfer{
+		ID:            uuid.NewString(),
+		ParentID:      parent.ID,
+		CoParentIDs:   coParentIDs,
+		FromRoom:      parent.RoomNumber,
+		ToRoom:        roomNumber,
+		Reason:        reason,
+		TransferredBy: actor.ID,
+		TransferredAt: time.Now().UTC(),
+	}
+
+	tenant, _ := domain.TenantFromContext(ctx)
+	outboxPayload, err := json.Marshal(ports.ParentRoomChangedEvent{
+		TenantID:    tenant,
+		UserID:      parent.ID,
+		AdmissionID: parent.AdmissionID,
+		LastName:    parent.LastName,
+		FromRoom:    transfer.FromRoom,
+		ToRoom:      transfer.ToRoom,
+		Reason:      transfer.Reason,
+		ChangedAt:   transfer.TransferredAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.TransferParent(ctx, transfer, outboxPayload); err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// coParentsInRoom returns the other parents of the admission who are still in the parent's room
+func (s *TransferService) coParentsInRoom(ctx context.Context, parent domain.Parent) ([]string, error) {
+	if parent.AdmissionID == "" {
+		return nil, nil
+	}
+	admission, err := s.admissions.GetAdmission(ctx, parent.AdmissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, coParent := range admission.Parents {
+		if coParent.ID != parent.ID && !coParent.Status.HasLeft() && coParent.RoomNumber == parent.RoomNumber {
+			ids = append(ids, coParent.ID)
+		}
+	}
+	return ids, nil
+}
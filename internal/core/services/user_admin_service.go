@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+// Column sizes of the users and parents tables
+const (
+	maxEmailLength      = 255
+	maxNameLength       = 100
+	maxRoomNumberLength = 20
+)
+
+var (
+	ErrEmptyUserUpdate    = domain.InvalidField("", "update contains no fields")
+	ErrInvalidEmail       = domain.InvalidField("email", "email is not a valid address")
+	ErrInvalidName        = domain.InvalidField("name", "first_name and last_name must be 1 to 100 characters")
+	ErrInvalidRoomNumber  = domain.InvalidField("room_number", "room_number must be 1 to 20 characters")
+	ErrRoomNotApplicable  = domain.InvalidField("room_number", "room_number only applies to parents")
+	ErrSelfDeactivation   = errors.New("admins cannot deactivate their own account")
+	ErrMissingUserVersion = errors.New("the current user version is required")
+)
+
+// UserAdminService is the write side of user administration: corrections to
+// registered users and deactivation.
+type UserAdminService struct {
+	userRepo       ports.UserRepository
+	visitorRepo    ports.VisitorRepository
+	sessions       ports.SessionRevoker
+	adminApprovals *AdminApprovalService
+	transfers      *TransferService
+}
+
+func NewUserAdminService(
+	userRepo ports.UserRepository,
+	visitorRepo ports.VisitorRepository,
+	sessions ports.SessionRevoker,
+	adminApprovals *AdminApprovalService,
+	transfers *TransferService,
+) *UserAdminService {
+	return &UserAdminService{
+		userRepo:       userRepo,
+		visitorRepo:    visitorRepo,
+		sessions:       sessions,
+		adminApprovals: adminApprovals,
+		transfers:      transfers,
+	}
+}
+
+// Update applies a partial update if the user is still at expectedVersion and
+// returns the updated user. A user.updated event is queued with the change. A
+// new room moves the parent through the transfer service, so the room history
+// and parent.room_changed event are recorded as for any other transfer.
+func (s *UserAdminService) Update(ctx context.Context, actor domain.Actor, id string, expectedVersion int, update domain.UserUpdate) (*domain.UserRecord, error) {
+	if expectedVersion < 1 {
+		return nil, ErrMissingUserVersion
+	}
+
+	record, err := s.userRepo.GetUserRecord(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, ErrUserNotFound)
+	}
+	if record.Version != expectedVersion {
+		return nil, domain.ErrVersionConflict
+	}
+
+	changed, roomNumber, err := s.apply(ctx, record, update)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changed) > 0 {
+		if err := updateUser(ctx, s.userRepo, record, expectedVersion, changed); err != nil {
+			return nil, err
+		}
+	}
+
+	// The room moves last so the version check of the other fields still guards the
+	// whole request; the transfer bumps the version once more
+	if roomNumber != "" {
+		if _, err := s.transfers.Transfer(ctx, actor, id, roomNumber, ""); err != nil {
+			return nil, err
+		}
+		record.Parent.RoomNumber = roomNumber
+		record.Version++
+	}
+	return record, nil
+}
+
+// updateUser stores the changed record with its user.updated event and bumps its version
+func updateUser(ctx context.Context, userRepo ports.UserRepository, record *domain.UserRecord, expectedVersion int, changed []string) error {
+	tenant, _ := domain.TenantFromContext(ctx)
+	event := ports.UserUpdatedEvent{
+		TenantID:      tenant,
+		UserID:        record.ID,
+		Role:          string(record.Role),
+		ChangedFields: changed,
+		Email:         record.Email,
+		FirstName:     record.FirstName,
+		LastName:      record.LastName,
+		DisplayName:   record.DisplayName,
+		Locale:        record.Locale,
+		ContactEmail:  record.ContactEmail,
+		Version:       expectedVersion + 1,
+	}
+	if record.Parent != nil {
+		event.RoomNumber = record.Parent.RoomNumber
+	}
+
+	outboxPayload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if err := userRepo.UpdateUser(ctx, *record, expectedVersion, outboxPayload); err != nil {
+		return err
+	}
+
+	record.Version = expectedVersion + 1
+	return nil
+}
+
+// apply validates the update and writes it into record, returning the names of
+// the fields whose value changed and the room the parent has to be transferred
+// to, if it changed
+func (s *UserAdminService) apply(ctx context.Context, record *domain.UserRecord, update domain.UserUpdate) ([]string, string, error) {
+	if update.Email == nil && update.FirstName == nil && update.LastName == nil && update.RoomNumber == nil {
+		return nil, "", ErrEmptyUserUpdate
+	}
+
+	// A room change is a transfer, validated here so that nothing is stored when it would be refused
+	var roomNumber string
+	if update.RoomNumber != nil {
+		if record.Parent == nil {
+			return nil, "", ErrRoomNotApplicable
+		}
+		room := strings.TrimSpace(*update.RoomNumber)
+		if room == "" || len(room) > maxRoomNumberLength {
+			return nil, "", ErrInvalidRoomNumber
+		}
+		if room != record.Parent.RoomNumber {
+			if !record.Parent.Status.IsAdmitted() {
+				return nil, "", notAdmitted(record.Parent.Status, ErrParentNotActive)
+			}
+			roomNumber = room
+		}
+	}
+
+	changed := []string{}
+
+	if update.Email != nil {
+		email := strings.TrimSpace(*update.Email)
+		if !validEmail(email) {
+			return nil, "", ErrInvalidEmail
+		}
+		if !strings.EqualFold(email, record.Email) {
+			if existing, err := s.userRepo.FindByEmail(ctx, email); err == nil && existing.ID != record.ID {
+				return nil, "", domain.ErrEmailTaken
+			}
+		}
+		if email != record.Email {
+			record.Email = email
+			changed = append(changed, "email")
+		}
+	}
+
+	for _, field := range []struct {
+		name   string
+		value  *string
+		target *string
+	}{
+		{"first_name", update.FirstName, &record.FirstName},
+		{"last_name", update.LastName, &record.LastName},
+	} {
+		if field.value == nil {
+			continue
+		}
+		name := strings.TrimSpace(*field.value)
+		if name == "" || len(name) > maxNameLength {
+			return nil, "", ErrInvalidName
+		}
+		if name != *field.target {
+			*field.target = name
+			changed = append(changed, field.name)
+		}
+	}
+
+	return changed, roomNumber, nil
+}
+
+// validEmail accepts a bare address such as jane@example.com that fits the users table
+func validEmail(email string) bool {
+	addr, err := mail.ParseAddress(email)
+	return err == nil && addr.Address == email && len(email) <= maxEmailLength
+}
+
+// Deactivate soft-deletes a user after ending all of their sessions, including
+// enrolled devices and, for parents, their visitors' access. An admin is not
+// deactivated right away: the returned approval waits for a second admin.
+func (s *UserAdminService) Deactivate(ctx context.Context, actor domain.Actor, id string) (*domain.AdminApproval, error) {
+	if actor.ID == id {
+		return nil, ErrSelfDeactivation
+	}
+
+	record, err := s.userRepo.GetUserRecord(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, ErrUserNotFound)
+	}
+	if record.Role == domain.RoleAdmin {
+		return s.adminApprovals.RequestRemoval(ctx, actor, id)
+	}
+
+	// Sessions are revoked first so that a failure leaves the account active and the call can be retried
+	if err := revokeAccess(ctx, s.sessions, s.visitorRepo, id, record.Parent != nil); err != nil {
+		return nil, err
+	}
+
+	tenant, _ := domain.TenantFromContext(ctx)
+	outboxPayload, err := json.Marshal(ports.UserDeactivatedEvent{
+		TenantID:      tenant,
+		UserID:        record.ID,
+		Role:          string(record.Role),
+		DeactivatedBy: actor.ID,
+		DeactivatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, s.userRepo.DeactivateUser(ctx, id, outboxPayload)
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+const (
+	DefaultUserPageSize = 50
+	MaxUserPageSize     = 200
+)
+
+var (
+	ErrInvalidCursor    = domain.InvalidField("cursor", "invalid cursor")
+	ErrInvalidPageSize  = domain.InvalidField("limit", "limit must be between 1 and 200")
+	ErrInvalidDateRange = domain.InvalidField("created_from", "created_from must be before created_to")
+	ErrUserNotFound     = fmt.Errorf("user %w", domain.ErrNotFound)
+)
+
+// UserPage is one page of the user directory. NextCursor is empty on the last page.
+type UserPage struct {
+	Users      []domain.UserRecord `json:"users"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+// UserQuery is a directory listing request as received from the API.
+type UserQuery struct {
+	Filter domain.UserFilter
+	Cursor string
+}
+
+// UserDirectoryService is the read side of user administration.
+type UserDirectoryService struct {
+	userRepo ports.UserRepository
+}
+
+func NewUserDirectoryService(userRepo ports.UserRepository) *UserDirectoryService {
+	return &UserDirectoryService{userRepo: userRepo}
+}
+
+// List returns a page of users matching the filter, newest first
+func (s *UserDirectoryService) List(ctx context.Context, query UserQuery) (*UserPage, error) {
+	filter := query.Filter
+
+	if filter.Limit == 0 {
+		filter.Limit = DefaultUserPageSize
+	}
+	if filter.Limit < 1 || filter.Limit > MaxUserPageSize {
+		return nil, ErrInvalidPageSize
+	}
+	if !filter.CreatedFrom.IsZero() && !filter.CreatedTo.IsZero() && !filter.CreatedFrom.Before(filter.CreatedTo) {
+		return nil, ErrInvalidDateRange
+	}
+	if query.Cursor != "" {
+		after, err := decodeUserCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = after
+	}
+
+	pageSize := filter.Limit
+	filter.Limit++ // one extra row tells us whether there is a next page
+
+	users, err := s.userRepo.ListUsers(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &UserPage{Users: users}
+	if len(users) > pageSize {
+		page.Users = users[:pageSize]
+		last := page.Users[pageSize-1]
+		page.NextCursor = encodeUserCursor(domain.UserCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	return page, nil
+}
+
+func (s *UserDirectoryService) Get(ctx context.Context, id string) (*domain.UserRecord, error) {
+	record, err := s.userRepo.GetUserRecord(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, ErrUserNotFound)
+	}
+	return record, nil
+}
+
+// Cursors are opaque to clients: base64("<created_at RFC3339Nano>|<id>")
+func encodeUserCursor(c domain.UserCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID))
+}
+
+func decodeUserCursor(cursor string) (*domain.UserCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	createdAt, id, ok := strings.Cut(string(data), "|")
+	if !ok || id == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &domain.UserCursor{CreatedAt: t, ID: id}, nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidExportFormat = domain.InvalidField("format", "format must be csv or ndjson")
+	ErrInvalidExportColumn = domain.InvalidField("columns", "columns must be distinct export columns")
+)
+
+// UserExport is an export request: the directory filter, the output format and
+// the columns to include. Empty Format and Columns select CSV with every column.
+type UserExport struct {
+	Filter  domain.UserFilter
+	Format  domain.ExportFormat
+	Columns []string
+}
+
+// UserExportService streams user lists for reporting. Every export is audited
+// before the first row is read.
+type UserExportService struct {
+	userRepo  ports.UserRepository
+	auditRepo ports.AuditRepository
+}
+
+func NewUserExportService(userRepo ports.UserRepository, auditRepo ports.AuditRepository) *UserExportService {
+	return &UserExportService{userRepo: userRepo, auditRepo: auditRepo}
+}
+
+// Prepare validates the export and fills in the default format and columns
+func (s *UserExportService) Prepare(export UserExport) (UserExport, error) {
+	switch export.Format {
+	case "":
+		export.Format = domain.ExportCSV
+	case domain.ExportCSV, domain.ExportNDJSON:
+	default:
+		return export, ErrInvalidExportFormat
+	}
+
+	if len(export.Columns) == 0 {
+		export.Columns = domain.ExportColumns
+	}
+	for i, column := range export.Columns {
+		if !slices.Contains(domain.ExportColumns, column) || slices.Contains(export.Columns[:i], column) {
+			return export, ErrInvalidExportColumn
+		}
+	}
+
+	filter := export.Filter
+	if !filter.CreatedFrom.IsZero() && !filter.CreatedTo.IsZero() && !filter.CreatedFrom.Before(filter.CreatedTo) {
+		return export, ErrInvalidDateRange
+	}
+	// Exports are never paged
+	export.Filter.After = nil
+	export.Filter.Limit = 0
+	return export, nil
+}
+
+// Export records the export in the audit log and then calls fn for every
+// matching user, newest first. Nothing is streamed if the audit entry cannot be written.
+func (s *UserExportService) Export(ctx context.Context, actor domain.Actor, export UserExport, fn func(domain.UserRecord) error) error {
+	export, err := s.Prepare(export)
+	if err != nil {
+		return err
+	}
+
+	tenant, _ := domain.TenantFromContext(ctx)
+	metadata := map[string]string{
+		"tenant_id": tenant,
+		"format":    string(export.Format),
+		"columns":   strings.Join(export.Columns, ","),
+	}
+	filter := export.Filter
+	for key, value := range map[string]string{
+		"role":   string(filter.Role),
+		"status": string(filter.ParentStatus),
+		"room":   filter.RoomNumber,
+		"q":      filter.Search,
+	} {
+		if value != "" {
+			metadata[key] = value
+		}
+	}
+	if !filter.CreatedFrom.IsZero() {
+		metadata["created_from"] = filter.CreatedFrom.UTC().Format(time.RFC3339)
+	}
+	if !filter.CreatedTo.IsZero() {
+		metadata["created_to"] = filter.CreatedTo.UTC().Format(time.RFC3339)
+	}
+
+	if err := s.auditRepo.RecordAudit(ctx, domain.AuditEntry{
+		ID:        uuid.NewString(),
+		ActorID:   actor.ID,
+		Action:    domain.AuditUsersExported,
+		Metadata:  metadata,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	return s.userRepo.StreamUsers(ctx, export.Filter, fn)
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const MaxVisitorAccessDuration = 30 * 24 * time.Hour
+
+var (
+	ErrVisitorInviteNotAllowed = errors.New("only active parents can invite visitors")
+	ErrVisitorInvalidExpiry    = domain.InvalidField("expires_at", "visitor expiry must be in the future and within 30 days")
+	ErrVisitorEmailTaken       = errors.New("email belongs to a registered user")
+	ErrVisitorNotFound         = fmt.Errorf("visitor %w", domain.ErrNotFound)
+	ErrVisitorAccessDenied     = errors.New("visitor access is revoked or expired")
+)
+
+// VisitorInvitation is returned to the inviting parent. Code is only set
+// for code-based invitations and is never retrievable again.
+type VisitorInvitation struct {
+	Visitor domain.Visitor
+	Code    string
+}
+
+// VisitorService manages family visitors delegated by parents.
+type VisitorService struct {
+	userRepo    ports.UserRepository
+	visitorRepo ports.VisitorRepository
+	tokens      *TokenIssuer
+}
+
+func NewVisitorService(
+	userRepo ports.UserRepository,
+	visitorRepo ports.VisitorRepository,
+	tokens *TokenIssuer,
+) *VisitorService {
+	return &VisitorService{
+		userRepo:    userRepo,
+		visitorRepo: visitorRepo,
+		tokens:      tokens,
+	}
+}
+
+// Invite creates a visitor for the parent. Without an email a redeemable code is generated.
+func (s *VisitorService) Invite(ctx context.Context, parentID, email, displayName string, expiresAt time.Time) (*VisitorInvitation, error) {
+	if _, err := s.activeParent(ctx, parentID); err != nil {
+		if errors.Is(err, ErrVisitorInviteNotAllowed) {
+			return nil, err
+		}
+		return nil, ErrVisitorInviteNotAllowed
+	}
+
+	now := time.Now()
+	if !expiresAt.After(now) || expiresAt.Sub(now) > MaxVisitorAccessDuration {
+		return nil, ErrVisitorInvalidExpiry
+	}
+
+	var code, codeHash string
+	if email == "" {
+		var err error
+		code, err = randomSecret()
+		if err != nil {
+			return nil, err
+		}
+		codeHash = hashSecret(code)
+	} else if _, err := s.userRepo.FindByEmail(ctx, email); err == nil {
+		return nil, ErrVisitorEmailTaken
+	}
+
+	visitor := domain.Visitor{
+		ID:          uuid.NewString(),
+		ParentID:    parentID,
+		Email:       email,
+		DisplayName: displayName,
+		ExpiresAt:   expiresAt,
+		CreatedAt:   now,
+	}
+
+	if err := s.visitorRepo.CreateVisitor(ctx, visitor, codeHash); err != nil {
+		return nil, err
+	}
+
+	return &VisitorInvitation{Visitor: visitor, Code: code}, nil
+}
+
+// List returns all visitors invited by the parent, including revoked and expired ones
+func (s *VisitorService) List(ctx context.Context, parentID string) ([]domain.Visitor, error) {
+	return s.visitorRepo.ListVisitorsByParent(ctx, parentID)
+}
+
+// Revoke disables a single visitor of the parent and ends their session
+func (s *VisitorService) Revoke(ctx context.Context, parentID, visitorID string) error {
+	if err := s.visitorRepo.RevokeVisitor(ctx, parentID, visitorID); err != nil {
+		return notFoundOr(err, ErrVisitorNotFound)
+	}
+	return s.tokens.RevokeSession(ctx, visitorID)
+}
+
+// RedeemCode issues a visitor token for a code-based invitation
+func (s *VisitorService) RedeemCode(ctx context.Context, code string) (string, error) {
+	visitor, err := s.visitorRepo.FindVisitorByCodeHash(ctx, hashSecret(code))
+	if err != nil {
+		return "", ErrVisitorAccessDenied
+	}
+	return s.IssueToken(ctx, visitor)
+}
+
+// AuthenticateEmail issues a visitor token for a verified email, if an active invitation exists
+func (s *VisitorService) AuthenticateEmail(ctx context.Context, email string) (string, error) {
+	visitor, err := s.visitorRepo.FindVisitorByEmail(ctx, email)
+	if err != nil {
+		return "", ErrVisitorAccessDenied
+	}
+	return s.IssueToken(ctx, visitor)
+}
+
+// IssueToken signs a visitor JWT linked to the inviting parent and their current room.
+// The token never outlives the visitor's access window.
+func (s *VisitorService) IssueToken(ctx context.Context, visitor *domain.Visitor) (string, error) {
+	now := time.Now()
+	if !visitor.IsActive(now) {
+		return "", ErrVisitorAccessDenied
+	}
+
+	parent, err := s.activeParent(ctx, visitor.ParentID)
+	if err != nil {
+		return "", ErrVisitorAccessDenied
+	}
+
+	ttl := TokenDuration
+	if remaining := visitor.ExpiresAt.Sub(now); remaining < ttl {
+		ttl = remaining
+	}
+
+	issued, err := s.tokens.Issue(ctx, jwt.MapClaims{
+		"sub":         visitor.ID,
+		"role":        string(domain.RoleVisitor),
+		"parent_id":   parent.ID,
+		"room_number": parent.RoomNumber,
+	}, ttl)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.tokens.TrackSession(ctx, visitor.ID, issued); err != nil {
+		log.Printf("Warning: failed to store visitor session in redis: %v", err)
+	}
+
+	return issued.Token, nil
+}
+
+func (s *VisitorService) activeParent(ctx context.Context, parentID string) (*domain.Parent, error) {
+	parent, err := s.userRepo.FindParentByID(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	if !parent.Status.IsAdmitted() {
+		return nil, notAdmitted(parent.Status, ErrVisitorInviteNotAllowed)
+	}
+	return parent, nil
+}
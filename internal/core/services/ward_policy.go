@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+var ErrOutsideWard = errors.New("parent is not on the actor's ward")
+
+// WardPolicy scopes members of ward-scoped roles, such as nurses, to their
+// own ward. Admins and other actors whose role is not ward-scoped are not
+// ward-bound and are always allowed.
+type WardPolicy struct {
+	wardRepo ports.WardRepository
+	userRepo ports.UserRepository
+}
+
+var _ ports.WardPolicy = (*WardPolicy)(nil)
+
+func NewWardPolicy(wardRepo ports.WardRepository, userRepo ports.UserRepository) *WardPolicy {
+	return &WardPolicy{wardRepo: wardRepo, userRepo: userRepo}
+}
+
+// AuthorizeRegistration allows ward-bound staff to register parents only, and only into rooms on their ward
+func (p *WardPolicy) AuthorizeRegistration(ctx context.Context, actor domain.Actor, role domain.Role, roomNumber string) error {
+	staffWard, bound, err := p.actorWard(ctx, actor)
+	if err != nil || !bound {
+		return err
+	}
+	if role != domain.RoleParent {
+		return ErrOutsideWard
+	}
+	return p.authorizeRoom(ctx, staffWard, roomNumber)
+}
+
+// AuthorizeParent allows ward-bound staff to act on a parent only while the parent's room is on their ward
+func (p *WardPolicy) AuthorizeParent(ctx context.Context, actor domain.Actor, parentID string) error {
+	staffWard, bound, err := p.actorWard(ctx, actor)
+	if err != nil || !bound {
+		return err
+	}
+
+	parent, err := p.userRepo.FindParentByID(ctx, parentID)
+	if isNotFound(err) {
+		return ErrOutsideWard
+	}
+	if err != nil {
+		return fmt.Errorf("looking up parent %s: %w", parentID, err)
+	}
+	return p.authorizeRoom(ctx, staffWard, parent.RoomNumber)
+}
+
+// AuthorizeParentRooms is AuthorizeRegistration of parents for many rooms, looking
+// the actor's ward and the rooms' wards up once rather than per room
+func (p *WardPolicy) AuthorizeParentRooms(ctx context.Context, actor domain.Actor, roomNumbers []string) (map[string]error, error) {
+	staffWard, bound, err := p.actorWard(ctx, actor)
+	if err != nil && !errors.Is(err, ErrOutsideWard) {
+		return nil, err
+	}
+	denied := make(map[string]error)
+	if !bound {
+		return denied, nil
+	}
+
+	var roomWards map[string]string
+	if err == nil {
+		if roomWards, err = p.wardRepo.GetRoomWards(ctx, roomNumbers); err != nil {
+			return nil, fmt.Errorf("looking up room wards: %w", err)
+		}
+	}
+	for _, roomNumber := range roomNumbers {
+		if ward, ok := roomWards[roomNumber]; !ok || ward != staffWard {
+			denied[roomNumber] = ErrOutsideWard
+		}
+	}
+	return denied, nil
+}
+
+// actorWard returns the ward the actor is bound to, if their role is
+// ward-scoped. A member of a ward-scoped role without a ward is denied, and a
+// failed lookup is returned as an error so the policy fails closed.
+func (p *WardPolicy) actorWard(ctx context.Context, actor domain.Actor) (string, bool, error) {
+	scoped, err := p.wardRepo.IsWardScoped(ctx, actor.Role)
+	if isNotFound(err) || (err == nil && !scoped) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("looking up role %s: %w", actor.Role, err)
+	}
+
+	ward, err := p.wardRepo.GetStaffWard(ctx, actor.ID)
+	if isNotFound(err) {
+		return "", true, ErrOutsideWard
+	}
+	if err != nil {
+		return "", true, fmt.Errorf("looking up the ward of %s: %w", actor.ID, err)
+	}
+	return ward, true, nil
+}
+
+func (p *WardPolicy) authorizeRoom(ctx context.Context, staffWard, roomNumber string) error {
+	roomWard, err := p.wardRepo.GetRoomWard(ctx, roomNumber)
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("looking up the ward of room %s: %w", roomNumber, err)
+	}
+	if err != nil || roomWard != staffWard {
+		return ErrOutsideWard
+	}
+	return nil
+}
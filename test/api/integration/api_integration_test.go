@@ -36,16 +36,23 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	_ "github.com/lib/pq"
 
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/handler"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/mailer"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/middleware"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/repository"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/config"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
 	redis "github.com/redis/go-redis/v9"
 )
@@ -54,6 +61,28 @@ import (
 var testDB *sql.DB
 var testRedis *redis.Client
 
+// testTenant is the clinic all integration requests are scoped to.
+const testTenant = "test-clinic"
+
+// tenantRouter scopes every request to the test clinic, as TenantMiddleware does in production.
+func tenantRouter(mux http.Handler) http.Handler {
+	cfg := &config.Config{
+		Tenants:       []config.TenantConfig{{ID: testTenant}},
+		DefaultTenant: testTenant,
+	}
+	return middleware.TenantMiddleware(cfg)(mux)
+}
+
+// newRegistrationService invites parents through a mailer that only logs.
+func newRegistrationService(repo *repository.SQLRepository) *services.RegistrationService {
+	invitations := services.NewInvitationService(repo, repo, services.NewParentLifecycleService(repo, repo, nil), mailer.LogMailer{}, services.InvitationSettings{
+		SigningKey: []byte("integration-test-invitation-signing-key"),
+		AcceptURLs: map[string]string{testTenant: "https://clinic.example.com/invitations/accept"},
+		TTL:        time.Hour,
+	})
+	return services.NewRegistrationService(repo, repo, invitations)
+}
+
 // TestMain sets up and tears down the test environment.
 // This function runs before and after all tests in the package.
 func TestMain(m *testing.M) {
@@ -110,24 +139,75 @@ func setupTestSchema(db *sql.DB) error {
 	schema := `
 		CREATE TABLE IF NOT EXISTS users (
 			id VARCHAR(36) PRIMARY KEY,
-			email VARCHAR(255) UNIQUE NOT NULL,
+			tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+			email VARCHAR(255) NOT NULL,
 			role VARCHAR(20) NOT NULL,
 			first_name VARCHAR(100) NOT NULL,
 			last_name VARCHAR(100) NOT NULL,
-			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			display_name VARCHAR(100),
+			locale VARCHAR(10),
+			contact_email VARCHAR(255),
+			version INTEGER NOT NULL DEFAULT 1,
+			deleted_at TIMESTAMP,
+			UNIQUE (tenant_id, email)
+		);
+
+		CREATE TABLE IF NOT EXISTS admissions (
+			id VARCHAR(36) PRIMARY KEY,
+			tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
 		);
 
 		CREATE TABLE IF NOT EXISTS parents (
 			user_id VARCHAR(36) PRIMARY KEY REFERENCES users(id),
+			tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
 			room_number VARCHAR(20) NOT NULL,
-			status VARCHAR(20) NOT NULL DEFAULT 'Active'
+			status VARCHAR(20) NOT NULL DEFAULT 'Active',
+			admission_id VARCHAR(36) REFERENCES admissions(id)
 		);
 
+		CREATE TABLE IF NOT EXISTS invitations (
+			id VARCHAR(36) PRIMARY KEY,
+			tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+			user_id VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			email VARCHAR(255) NOT NULL,
+			status VARCHAR(20) NOT NULL,
+			sends INTEGER NOT NULL DEFAULT 1,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			last_sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			expires_at TIMESTAMPTZ NOT NULL,
+			accepted_at TIMESTAMPTZ,
+			cancelled_at TIMESTAMPTZ
+		);
+
+		CREATE TABLE IF NOT EXISTS admin_approvals (
+			id VARCHAR(36) PRIMARY KEY,
+			tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
+			action VARCHAR(20) NOT NULL,
+			status VARCHAR(20) NOT NULL,
+			email VARCHAR(255),
+			first_name VARCHAR(100),
+			last_name VARCHAR(100),
+			target_user_id VARCHAR(36) REFERENCES users(id) ON DELETE CASCADE,
+			requested_by VARCHAR(36) NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			expires_at TIMESTAMPTZ NOT NULL,
+			decided_by VARCHAR(36),
+			decided_at TIMESTAMPTZ
+		);
+
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_admin_approvals_pending_email
+			ON admin_approvals (tenant_id, LOWER(email)) WHERE status = 'Pending' AND action = 'CreateAdmin';
+
 		CREATE TABLE IF NOT EXISTS outbox_events (
 			id VARCHAR(36) PRIMARY KEY,
+			tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
 			aggregate_type VARCHAR(50) NOT NULL,
 			aggregate_id VARCHAR(36) NOT NULL,
 			event_type VARCHAR(50) NOT NULL,
+			schema_version INT NOT NULL DEFAULT 1,
+			correlation_id VARCHAR(64),
 			payload JSONB NOT NULL,
 			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			processed_at TIMESTAMP
@@ -139,7 +219,10 @@ func setupTestSchema(db *sql.DB) error {
 
 // cleanupTestData removes all test data.
 func cleanupTestData(db *sql.DB) {
+	_, _ = db.Exec("DELETE FROM invitations")
+	_, _ = db.Exec("DELETE FROM admin_approvals")
 	_, _ = db.Exec("DELETE FROM parents")
+	_, _ = db.Exec("DELETE FROM admissions")
 	_, _ = db.Exec("DELETE FROM outbox_events")
 	_, _ = db.Exec("DELETE FROM users")
 }
@@ -155,13 +238,13 @@ func TestIntegration_RegisterParent(t *testing.T) {
 
 	// Setup real repository and service
 	repo := repository.NewSQLRepository(testDB)
-	service := services.NewRegistrationService(repo)
-	h := handler.NewRegistrationHandler(service)
+	service := newRegistrationService(repo)
+	h := handler.NewRegistrationHandler(service, services.NewWardPolicy(repo, repo), services.NewAdminApprovalService(repo, repo, nil, time.Hour))
 
 	// Create test server
 	mux := http.NewServeMux()
 	mux.HandleFunc("/register", h.Register)
-	server := httptest.NewServer(mux)
+	server := httptest.NewServer(tenantRouter(mux))
 	defer server.Close()
 
 	// Test registration
@@ -213,12 +296,13 @@ func TestIntegration_RegisterAdmin(t *testing.T) {
 	cleanupTestData(testDB)
 
 	repo := repository.NewSQLRepository(testDB)
-	service := services.NewRegistrationService(repo)
-	h := handler.NewRegistrationHandler(service)
+	service := newRegistrationService(repo)
+	approvals := services.NewAdminApprovalService(repo, repo, nil, time.Hour)
+	h := handler.NewRegistrationHandler(service, services.NewWardPolicy(repo, repo), approvals)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/register", h.Register)
-	server := httptest.NewServer(mux)
+	server := httptest.NewServer(tenantRouter(mux))
 	defer server.Close()
 
 	body := map[string]string{
@@ -235,18 +319,34 @@ func TestIntegration_RegisterAdmin(t *testing.T) {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusCreated {
-		t.Errorf("expected status %d, got %d", http.StatusCreated, resp.StatusCode)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d", http.StatusAccepted, resp.StatusCode)
+	}
+	var registered handler.RegistrationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&registered); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	// The admin only exists once a second admin approves
+	var count int
+	_ = testDB.QueryRow("SELECT COUNT(*) FROM users WHERE email = $1", "admin-test@baby-kliniek.nl").Scan(&count)
+	if count != 0 {
+		t.Fatalf("expected no admin before approval, got %d", count)
+	}
+
+	ctx := domain.WithTenant(context.Background(), testTenant)
+	if _, err := approvals.Approve(ctx, domain.Actor{ID: "admin-2", Role: domain.RoleAdmin}, registered.ApprovalID); err != nil {
+		t.Fatalf("failed to approve: %v", err)
 	}
 
-	// Verify in database
 	var role string
 	err = testDB.QueryRow("SELECT role FROM users WHERE email = $1", "admin-test@baby-kliniek.nl").Scan(&role)
 	if err != nil {
 		t.Fatalf("failed to query database: %v", err)
 	}
-	// Note: There's a bug in RegisterAdmin - it sets PARENT instead of ADMIN
-	// This integration test will catch such bugs!
+	if role != string(domain.RoleAdmin) {
+		t.Errorf("expected role %s, got %s", domain.RoleAdmin, role)
+	}
 }
 
 // TestIntegration_DuplicateEmail tests duplicate email handling.
@@ -258,12 +358,12 @@ func TestIntegration_DuplicateEmail(t *testing.T) {
 	cleanupTestData(testDB)
 
 	repo := repository.NewSQLRepository(testDB)
-	service := services.NewRegistrationService(repo)
-	h := handler.NewRegistrationHandler(service)
+	service := newRegistrationService(repo)
+	h := handler.NewRegistrationHandler(service, services.NewWardPolicy(repo, repo), services.NewAdminApprovalService(repo, repo, nil, time.Hour))
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/register", h.Register)
-	server := httptest.NewServer(mux)
+	server := httptest.NewServer(tenantRouter(mux))
 	defer server.Close()
 
 	body := map[string]string{
@@ -287,8 +387,11 @@ func TestIntegration_DuplicateEmail(t *testing.T) {
 	resp, _ = http.Post(server.URL+"/register", "application/json", bytes.NewReader(jsonBody))
 	resp.Body.Close()
 
-	if resp.StatusCode != http.StatusInternalServerError {
-		t.Errorf("expected duplicate email to fail, got status %d", resp.StatusCode)
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected duplicate email to conflict, got status %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Content-Type"); got != problem.ContentType {
+		t.Errorf("expected a problem response, got %q", got)
 	}
 }
 
@@ -301,12 +404,12 @@ func TestIntegration_OutboxEvent(t *testing.T) {
 	cleanupTestData(testDB)
 
 	repo := repository.NewSQLRepository(testDB)
-	service := services.NewRegistrationService(repo)
-	h := handler.NewRegistrationHandler(service)
+	service := newRegistrationService(repo)
+	h := handler.NewRegistrationHandler(service, services.NewWardPolicy(repo, repo), services.NewAdminApprovalService(repo, repo, nil, time.Hour))
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/register", h.Register)
-	server := httptest.NewServer(mux)
+	server := httptest.NewServer(tenantRouter(mux))
 	defer server.Close()
 
 	body := map[string]string{
@@ -326,7 +429,7 @@ func TestIntegration_OutboxEvent(t *testing.T) {
 
 	// Verify outbox event was created
 	var count int
-	err = testDB.QueryRow("SELECT COUNT(*) FROM outbox_events WHERE event_type = 'babies'").Scan(&count)
+	err = testDB.QueryRow("SELECT COUNT(*) FROM outbox_events WHERE event_type = 'parent.registered'").Scan(&count)
 	if err != nil {
 		t.Fatalf("failed to query outbox: %v", err)
 	}
@@ -336,7 +439,7 @@ func TestIntegration_OutboxEvent(t *testing.T) {
 
 	// Verify event payload contains correct data
 	var payload []byte
-	err = testDB.QueryRow("SELECT payload FROM outbox_events WHERE event_type = 'babies'").Scan(&payload)
+	err = testDB.QueryRow("SELECT payload FROM outbox_events WHERE event_type = 'parent.registered'").Scan(&payload)
 	if err != nil {
 		t.Fatalf("failed to query payload: %v", err)
 	}
@@ -405,12 +508,12 @@ func TestIntegration_TransactionRollback(t *testing.T) {
 	// Create a scenario where the transaction should fail
 	// This tests the atomicity of the CreateParent operation
 	repo := repository.NewSQLRepository(testDB)
-	ctx := context.Background()
+	ctx := domain.WithTenant(context.Background(), testTenant)
 
 	// First, insert a user directly to cause a constraint violation
 	_, err := testDB.Exec(`
-		INSERT INTO users (id, email, role, first_name, last_name, created_at)
-		VALUES ('test-id', 'conflict@example.com', 'PARENT', 'Test', 'User', NOW())
+		INSERT INTO users (id, tenant_id, email, role, first_name, last_name, created_at)
+		VALUES ('test-id', 'test-clinic', 'conflict@example.com', 'PARENT', 'Test', 'User', NOW())
 	`)
 	if err != nil {
 		t.Fatalf("failed to insert test user: %v", err)
@@ -422,3 +525,119 @@ func TestIntegration_TransactionRollback(t *testing.T) {
 		t.Errorf("expected error for non-existent user, got: %v", user)
 	}
 }
+
+// TestIntegration_TenantIsolation verifies one clinic cannot read another clinic's parents.
+func TestIntegration_TenantIsolation(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Integration tests require database connection")
+	}
+
+	cleanupTestData(testDB)
+
+	repo := repository.NewSQLRepository(testDB)
+	clinicA := domain.WithTenant(context.Background(), "clinic-a")
+	clinicB := domain.WithTenant(context.Background(), "clinic-b")
+
+	service := newRegistrationService(repo)
+	if _, err := service.RegisterParent(clinicA, "shared@example.com", "Clinic", "A", "101", ""); err != nil {
+		t.Fatalf("registration in clinic a failed: %v", err)
+	}
+
+	user, err := repo.FindByEmail(clinicA, "shared@example.com")
+	if err != nil {
+		t.Fatalf("expected clinic a to find its parent: %v", err)
+	}
+
+	if _, err := repo.FindByEmail(clinicB, "shared@example.com"); err == nil {
+		t.Error("expected clinic b not to find clinic a's parent by email")
+	}
+	if _, err := repo.FindParentByID(clinicB, user.ID); err == nil {
+		t.Error("expected clinic b not to find clinic a's parent by ID")
+	}
+
+	// The same email may be registered independently in another clinic
+	if _, err := service.RegisterParent(clinicB, "shared@example.com", "Clinic", "B", "101", ""); err != nil {
+		t.Errorf("registration of the same email in clinic b failed: %v", err)
+	}
+
+	if _, err := repo.FindByEmail(context.Background(), "shared@example.com"); !errors.Is(err, domain.ErrNoTenant) {
+		t.Errorf("expected unscoped lookups to fail with ErrNoTenant, got %v", err)
+	}
+}
+
+// TestIntegration_Migrate tests that the startup migration upgrades the tables
+// created by an older schema in place and can run again on its own result.
+func TestIntegration_Migrate(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Integration tests require database connection")
+	}
+
+	repo := repository.NewSQLRepository(testDB)
+	for i := 0; i < 2; i++ {
+		if err := repo.Migrate(context.Background()); err != nil {
+			t.Fatalf("migration %d failed: %v", i+1, err)
+		}
+	}
+
+	columns := map[string]string{
+		"users":         "erased_at",
+		"parents":       "admission_id",
+		"outbox_events": "correlation_id",
+		"audit_log":     "tenant_id",
+		"roles":         "ward_scoped",
+	}
+	for table, column := range columns {
+		var exists bool
+		err := testDB.QueryRow(
+			"SELECT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = $1 AND column_name = $2)",
+			table, column,
+		).Scan(&exists)
+		if err != nil {
+			t.Fatalf("failed to look up %s.%s: %v", table, column, err)
+		}
+		if !exists {
+			t.Errorf("expected the migration to add %s.%s", table, column)
+		}
+	}
+}
+
+// TestIntegration_CaseInsensitiveEmails tests that an email is taken and found
+// regardless of case, for users as well as visitors.
+func TestIntegration_CaseInsensitiveEmails(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Integration tests require database connection")
+	}
+
+	cleanupTestData(testDB)
+
+	repo := repository.NewSQLRepository(testDB)
+	if err := repo.Migrate(context.Background()); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	ctx := domain.WithTenant(context.Background(), "clinic-a")
+
+	parentID, err := newRegistrationService(repo).RegisterParent(ctx, "case@example.com", "Case", "Test", "101", "")
+	if err != nil {
+		t.Fatalf("registration failed: %v", err)
+	}
+
+	_, err = testDB.Exec(
+		"INSERT INTO users (id, tenant_id, email, role, first_name, last_name) VALUES ($1, $2, $3, $4, $5, $6)",
+		"case-duplicate", "clinic-a", "CASE@example.com", domain.RoleParent, "Case", "Duplicate",
+	)
+	if err == nil {
+		t.Error("expected the same email in another case to be rejected")
+	}
+
+	now := time.Now()
+	if err := repo.CreateVisitor(ctx, domain.Visitor{
+		ID: "case-visitor", ParentID: parentID, Email: "grandma@example.com", DisplayName: "Grandma",
+		ExpiresAt: now.Add(time.Hour), CreatedAt: now,
+	}, ""); err != nil {
+		t.Fatalf("creating the visitor failed: %v", err)
+	}
+	visitor, err := repo.FindVisitorByEmail(ctx, "GrandMa@Example.com")
+	if err != nil || visitor.ID != "case-visitor" {
+		t.Errorf("expected the visitor to be found regardless of case, got %+v, %v", visitor, err)
+	}
+}
@@ -0,0 +1,327 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/handler"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/middleware"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/test/mocks"
+)
+
+var (
+	firstAdmin  = domain.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+	secondAdmin = domain.Actor{ID: "admin-2", Role: domain.RoleAdmin}
+)
+
+func newAdminApprovalService(repo *mocks.MockUserRepository) *services.AdminApprovalService {
+	return services.NewAdminApprovalService(repo, repo, mocks.NewMockSessionRevoker(), time.Hour)
+}
+
+func newUserAdminServiceFor(userRepo *mocks.MockUserRepository, visitorRepo *mocks.MockVisitorRepository, sessions *mocks.MockSessionRevoker) *services.UserAdminService {
+	return services.NewUserAdminService(userRepo, visitorRepo, sessions, services.NewAdminApprovalService(userRepo, userRepo, sessions, time.Hour),
+		services.NewTransferService(userRepo, userRepo))
+}
+
+// seedAdmins adds active admins admin-1 to admin-n.
+func seedAdmins(repo *mocks.MockUserRepository, n int) {
+	for i := 1; i <= n; i++ {
+		id := "admin-" + string(rune('0'+i))
+		repo.SeedUser(&domain.User{ID: id, Email: id + "@baby-kliniek.nl", Role: domain.RoleAdmin, FirstName: "Ada", LastName: "Admin"})
+	}
+}
+
+// TestAdminApprovalService_Creation verifies a new admin is only created,
+// with the ADMIN role, once a second admin approves.
+func TestAdminApprovalService_Creation(t *testing.T) {
+	repo := mocks.NewMockUserRepository()
+	seedAdmins(repo, 2)
+	service := newAdminApprovalService(repo)
+
+	approval, err := service.RequestCreation(tenantContext(), firstAdmin, " new@baby-kliniek.nl ", "Nia", "Admin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if approval.Status != domain.AdminApprovalPending || approval.Email != "new@baby-kliniek.nl" {
+		t.Errorf("unexpected approval: %+v", approval)
+	}
+	if _, err := repo.FindByEmail(context.Background(), "new@baby-kliniek.nl"); err == nil {
+		t.Fatal("expected no admin before approval")
+	}
+
+	if _, err := service.RequestCreation(tenantContext(), secondAdmin, "NEW@baby-kliniek.nl", "Nia", "Admin"); !errors.Is(err, domain.ErrAdminApprovalPending) {
+		t.Errorf("expected %v, got %v", domain.ErrAdminApprovalPending, err)
+	}
+	if _, err := service.Approve(tenantContext(), firstAdmin, approval.ID); !errors.Is(err, domain.ErrSelfApproval) {
+		t.Errorf("expected %v, got %v", domain.ErrSelfApproval, err)
+	}
+
+	approved, err := service.Approve(tenantContext(), secondAdmin, approval.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if approved.Status != domain.AdminApprovalApproved || approved.DecidedBy != "admin-2" || approved.DecidedAt == nil {
+		t.Errorf("unexpected approval: %+v", approved)
+	}
+	admin, err := repo.FindByEmail(context.Background(), "new@baby-kliniek.nl")
+	if err != nil || admin.Role != domain.RoleAdmin {
+		t.Fatalf("expected an admin to be created, got %+v, %v", admin, err)
+	}
+
+	var event ports.AdminRegisteredEvent
+	if len(repo.OutboxPayloads) != 1 || json.Unmarshal(repo.OutboxPayloads[0], &event) != nil {
+		t.Fatalf("expected an admin.registered event, got %d events", len(repo.OutboxPayloads))
+	}
+	if event.UserID != admin.ID || event.ApprovalID != approval.ID || event.RequestedBy != "admin-1" || event.ApprovedBy != "admin-2" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	if _, err := service.Reject(tenantContext(), secondAdmin, approval.ID); !errors.Is(err, domain.ErrAdminApprovalClosed) {
+		t.Errorf("expected %v, got %v", domain.ErrAdminApprovalClosed, err)
+	}
+	if _, err := service.RequestCreation(tenantContext(), firstAdmin, "new@baby-kliniek.nl", "Nia", "Admin"); !errors.Is(err, domain.ErrEmailTaken) {
+		t.Errorf("expected %v, got %v", domain.ErrEmailTaken, err)
+	}
+}
+
+// TestAdminApprovalService_Bootstrap verifies a clinic without admins gets its
+// first admin from configuration, and that a sole admin's request for a second
+// one waits for the operator.
+func TestAdminApprovalService_Bootstrap(t *testing.T) {
+	repo := mocks.NewMockUserRepository()
+	service := newAdminApprovalService(repo)
+
+	created, err := service.Bootstrap(tenantContext(), "first@baby-kliniek.nl", "Fay", "Admin")
+	if err != nil || !created {
+		t.Fatalf("expected the first admin to be created, got %v, %v", created, err)
+	}
+	first, err := repo.FindByEmail(context.Background(), "first@baby-kliniek.nl")
+	if err != nil || first.Role != domain.RoleAdmin {
+		t.Fatalf("expected an admin, got %+v, %v", first, err)
+	}
+	if created, err := service.Bootstrap(tenantContext(), "other@baby-kliniek.nl", "Oz", "Admin"); err != nil || created {
+		t.Errorf("expected no admin once the clinic has one, got %v, %v", created, err)
+	}
+
+	sole := domain.Actor{ID: first.ID, Role: domain.RoleAdmin}
+	approval, err := service.RequestCreation(tenantContext(), sole, "second@baby-kliniek.nl", "Sam", "Admin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if approval.Status != domain.AdminApprovalPending {
+		t.Errorf("expected the sole admin's request to wait for approval, got %s", approval.Status)
+	}
+	if _, err := service.Approve(tenantContext(), sole, approval.ID); !errors.Is(err, domain.ErrSelfApproval) {
+		t.Errorf("expected %v, got %v", domain.ErrSelfApproval, err)
+	}
+	if _, err := repo.FindByEmail(context.Background(), "second@baby-kliniek.nl"); err == nil {
+		t.Fatal("expected no admin before approval")
+	}
+
+	approved, err := service.ApproveAsOperator(tenantContext(), approval.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if approved.Status != domain.AdminApprovalApproved || approved.DecidedBy != "operator" {
+		t.Errorf("unexpected approval: %+v", approved)
+	}
+	if _, err := repo.FindByEmail(context.Background(), "second@baby-kliniek.nl"); err != nil {
+		t.Fatal("expected the second admin to be created")
+	}
+	if _, err := service.ApproveAsOperator(tenantContext(), approval.ID); !errors.Is(err, domain.ErrAdminApprovalClosed) {
+		t.Errorf("expected %v, got %v", domain.ErrAdminApprovalClosed, err)
+	}
+}
+
+// TestAdminApprovalService_Expired verifies an approval past its window can no
+// longer be approved and no longer blocks a new request.
+func TestAdminApprovalService_Expired(t *testing.T) {
+	repo := mocks.NewMockUserRepository()
+	seedAdmins(repo, 2)
+	service := newAdminApprovalService(repo)
+
+	approval, err := service.RequestCreation(tenantContext(), firstAdmin, "late@baby-kliniek.nl", "Lee", "Admin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo.ExpireAdminApproval(approval.ID)
+
+	expired, err := service.List(tenantContext(), "Expired")
+	if err != nil || len(expired) != 1 || expired[0].ID != approval.ID {
+		t.Errorf("expected the approval to be listed as expired, got %+v, %v", expired, err)
+	}
+	if _, err := service.Approve(tenantContext(), secondAdmin, approval.ID); !errors.Is(err, domain.ErrAdminApprovalExpired) {
+		t.Fatalf("expected %v, got %v", domain.ErrAdminApprovalExpired, err)
+	}
+	if len(repo.CreateAdminCalls) != 0 {
+		t.Error("expected no admin to be created")
+	}
+	if stored, _ := repo.GetAdminApproval(context.Background(), approval.ID); stored.Status != domain.AdminApprovalExpired {
+		t.Errorf("expected the approval to be recorded as expired, got %s", stored.Status)
+	}
+
+	if _, err := service.RequestCreation(tenantContext(), firstAdmin, "late@baby-kliniek.nl", "Lee", "Admin"); err != nil {
+		t.Errorf("expected a new request after expiry, got %v", err)
+	}
+	if _, err := service.List(tenantContext(), "Open"); !errors.Is(err, services.ErrInvalidAdminApprovalState) {
+		t.Errorf("expected %v, got %v", services.ErrInvalidAdminApprovalState, err)
+	}
+}
+
+// TestAdminApprovalService_Removal verifies deleting an admin waits for a
+// second admin, who then deactivates them and ends their sessions.
+func TestAdminApprovalService_Removal(t *testing.T) {
+	repo := mocks.NewMockUserRepository()
+	seedAdmins(repo, 3)
+	sessions := mocks.NewMockSessionRevoker()
+	admins := newUserAdminServiceFor(repo, mocks.NewMockVisitorRepository(), sessions)
+
+	approval, err := admins.Deactivate(tenantContext(), firstAdmin, "admin-3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if approval == nil || approval.Action != domain.AdminApprovalRemove || approval.TargetUserID != "admin-3" {
+		t.Fatalf("expected a removal approval, got %+v", approval)
+	}
+	if len(repo.DeactivateUserCalls) != 0 || len(sessions.RevokedSessions) != 0 {
+		t.Fatal("expected the admin to stay active until approved")
+	}
+
+	service := services.NewAdminApprovalService(repo, repo, sessions, time.Hour)
+	if _, err := service.Reject(tenantContext(), domain.Actor{ID: "admin-3", Role: domain.RoleAdmin}, approval.ID); !errors.Is(err, domain.ErrSelfApproval) {
+		t.Errorf("expected the target not to decide their own removal, got %v", err)
+	}
+	if _, err := service.Approve(tenantContext(), secondAdmin, approval.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := repo.FindByID(context.Background(), "admin-3"); err == nil {
+		t.Error("expected the admin to be deactivated")
+	}
+	if len(sessions.RevokedSessions) != 1 || sessions.RevokedSessions[0] != "admin-3" {
+		t.Errorf("expected the admin's sessions to be revoked, got %v", sessions.RevokedSessions)
+	}
+	var event ports.UserDeactivatedEvent
+	if len(repo.OutboxPayloads) != 1 || json.Unmarshal(repo.OutboxPayloads[0], &event) != nil || event.DeactivatedBy != "admin-2" {
+		t.Errorf("expected a user.deactivated event by the approver, got %+v", event)
+	}
+}
+
+// TestAdminApprovalService_LastAdmin verifies the clinic always keeps an active admin.
+func TestAdminApprovalService_LastAdmin(t *testing.T) {
+	repo := mocks.NewMockUserRepository()
+	seedAdmins(repo, 3)
+	service := newAdminApprovalService(repo)
+
+	approval, err := service.RequestRemoval(tenantContext(), firstAdmin, "admin-2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// admin-3 and admin-1 leave before the removal of admin-2 is approved
+	for _, id := range []string{"admin-3", "admin-1"} {
+		if err := repo.DeactivateUser(context.Background(), id, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, err := service.ApproveAsOperator(tenantContext(), approval.ID); !errors.Is(err, domain.ErrLastAdmin) {
+		t.Errorf("expected %v, got %v", domain.ErrLastAdmin, err)
+	}
+	if _, err := service.RequestRemoval(tenantContext(), domain.Actor{ID: "admin-3"}, "admin-2"); !errors.Is(err, domain.ErrLastAdmin) {
+		t.Errorf("expected %v, got %v", domain.ErrLastAdmin, err)
+	}
+
+	// The repositories refuse as well, whatever path reaches them
+	if err := repo.DeactivateUser(context.Background(), "admin-2", nil); !errors.Is(err, domain.ErrLastAdmin) {
+		t.Errorf("expected %v, got %v", domain.ErrLastAdmin, err)
+	}
+	if err := repo.EraseUser(context.Background(), domain.ErasureTombstone{UserID: "admin-2"}, nil); !errors.Is(err, domain.ErrLastAdmin) {
+		t.Errorf("expected %v, got %v", domain.ErrLastAdmin, err)
+	}
+	if _, err := service.RequestRemoval(tenantContext(), domain.Actor{ID: "admin-3"}, "admin-1"); !errors.Is(err, services.ErrUserNotFound) {
+		t.Errorf("expected %v, got %v", services.ErrUserNotFound, err)
+	}
+}
+
+// TestAdminApprovalService_RemovalWithTwoAdmins verifies that with two admins,
+// where neither can approve, a removal waits for the operator.
+func TestAdminApprovalService_RemovalWithTwoAdmins(t *testing.T) {
+	repo := mocks.NewMockUserRepository()
+	seedAdmins(repo, 2)
+	service := newAdminApprovalService(repo)
+
+	approval, err := service.RequestRemoval(tenantContext(), firstAdmin, "admin-2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, admin := range []domain.Actor{firstAdmin, secondAdmin} {
+		if _, err := service.Approve(tenantContext(), admin, approval.ID); !errors.Is(err, domain.ErrSelfApproval) {
+			t.Errorf("expected %s not to approve, got %v", admin.ID, err)
+		}
+	}
+
+	approved, err := service.ApproveAsOperator(tenantContext(), approval.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if approved.Status != domain.AdminApprovalApproved || approved.DecidedBy != "operator" {
+		t.Errorf("unexpected approval: %+v", approved)
+	}
+	if _, err := repo.FindByID(context.Background(), "admin-2"); err == nil {
+		t.Error("expected the admin to be deactivated")
+	}
+}
+
+// TestErasureService_ActiveAdmin verifies erasure cannot bypass an admin's removal approval.
+func TestErasureService_ActiveAdmin(t *testing.T) {
+	service, userRepo, _, _ := newErasureService()
+	seedAdmins(userRepo, 2)
+
+	if _, err := service.Erase(tenantContext(), firstAdmin, "admin-2"); !errors.Is(err, services.ErrActiveAdminErasure) {
+		t.Errorf("expected %v, got %v", services.ErrActiveAdminErasure, err)
+	}
+	if len(userRepo.EraseUserCalls) != 0 {
+		t.Error("expected the admin not to be erased")
+	}
+}
+
+// TestUserHandler_DeleteAdmin verifies DELETE /users/{userID} answers 202 for an admin.
+func TestUserHandler_DeleteAdmin(t *testing.T) {
+	tests := []struct {
+		name           string
+		admins         int
+		userID         string
+		expectedStatus int
+	}{
+		{name: "removal_requested", admins: 3, userID: "admin-2", expectedStatus: http.StatusAccepted},
+		{name: "removal_waits_for_operator", admins: 2, userID: "admin-2", expectedStatus: http.StatusAccepted},
+		{name: "unknown_user", admins: 2, userID: "admin-9", expectedStatus: http.StatusNotFound},
+		{name: "self", admins: 2, userID: "admin-1", expectedStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockUserRepository()
+			seedAdmins(repo, tt.admins)
+			h := handler.NewUserHandler(nil, newUserAdminServiceFor(repo, mocks.NewMockVisitorRepository(), mocks.NewMockSessionRevoker()), services.NewWardPolicy(repo, repo))
+			mux := http.NewServeMux()
+			mux.HandleFunc("DELETE /users/{userID}", h.DeleteUser)
+
+			req := httptest.NewRequest(http.MethodDelete, "/users/"+tt.userID, nil)
+			req = req.WithContext(context.WithValue(tenantContext(), middleware.UserIDKey, firstAdmin.ID))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/test/mocks"
+)
+
+// newAdmissionService seeds an admission in room 101 with one active parent.
+func newAdmissionService() (*services.AdmissionService, *mocks.MockUserRepository, *mocks.MockSessionRevoker) {
+	repo := newWardRepository()
+	repo.SeedParent(&domain.Parent{
+		User:        domain.User{ID: "parent-1", Email: "first@example.com", Role: domain.RoleParent, LastName: "Doe", CreatedAt: time.Now().Add(-time.Hour)},
+		RoomNumber:  "101",
+		Status:      domain.ParentActive,
+		AdmissionID: "admission-1",
+	})
+	sessions := mocks.NewMockSessionRevoker()
+	lifecycle := services.NewParentLifecycleService(repo, mocks.NewMockVisitorRepository(), sessions)
+	invitations := newInvitationService(repo, mocks.NewMockMailer(), time.Hour)
+	return services.NewAdmissionService(repo, repo, invitations, lifecycle), repo, sessions
+}
+
+// TestRegistrationService_OpensAdmission verifies a registered parent opens
+// an admission and its baby event carries the admission.
+func TestRegistrationService_OpensAdmission(t *testing.T) {
+	repo := mocks.NewMockUserRepository()
+	service := newRegistrationService(repo)
+
+	if _, err := service.RegisterParent(tenantContext(), "parent@example.com", "Pat", "Doe", "101", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parent := repo.CreateParentCalls[0]
+	if parent.AdmissionID == "" {
+		t.Fatal("expected the parent to open an admission")
+	}
+	var event ports.CreateBabyEvent
+	if err := json.Unmarshal(repo.OutboxPayloads[0], &event); err != nil {
+		t.Fatalf("invalid outbox payload: %v", err)
+	}
+	if event.AdmissionID != parent.AdmissionID {
+		t.Errorf("expected baby event for admission %q, got %+v", parent.AdmissionID, event)
+	}
+}
+
+// TestAdmissionService_AddCoParent verifies a co-parent joins the admission's
+// room without another baby being created.
+func TestAdmissionService_AddCoParent(t *testing.T) {
+	service, repo, _ := newAdmissionService()
+
+	coParent, msg, err := service.AddCoParent(tenantContext(), "admission-1", " second@example.com ", "Sam", "Doe", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(msg, "invitation sent") {
+		t.Errorf("unexpected message: %q", msg)
+	}
+	if coParent.RoomNumber != "101" || coParent.Status != domain.ParentPending || coParent.AdmissionID != "admission-1" {
+		t.Errorf("unexpected co-parent: %+v", coParent)
+	}
+	if len(repo.OutboxPayloads) != 0 {
+		t.Errorf("expected no baby event for a co-parent, got %d events", len(repo.OutboxPayloads))
+	}
+
+	admission, err := service.Get(tenantContext(), "admission-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(admission.Parents) != 2 || admission.Parents[0].ID != "parent-1" {
+		t.Errorf("unexpected admission: %+v", admission)
+	}
+
+	if _, _, err := service.AddCoParent(tenantContext(), "admission-1", "not-an-email", "", "Doe", ""); !errors.Is(err, services.ErrInvalidEmail) || !errors.Is(err, services.ErrInvalidName) {
+		t.Errorf("expected field errors, got %v", err)
+	}
+	if _, _, err := service.AddCoParent(tenantContext(), "missing", "third@example.com", "Alex", "Doe", ""); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected %v, got %v", domain.ErrNotFound, err)
+	}
+}
+
+// TestAdmissionService_Discharge verifies discharging the admission
+// discharges every admitted parent and archives pending co-parents.
+func TestAdmissionService_Discharge(t *testing.T) {
+	service, repo, sessions := newAdmissionService()
+	repo.SeedParent(&domain.Parent{
+		User:        domain.User{ID: "parent-2", Email: "second@example.com", Role: domain.RoleParent, CreatedAt: time.Now()},
+		RoomNumber:  "101",
+		Status:      domain.ParentReadmitted,
+		AdmissionID: "admission-1",
+	})
+	coParent, _, err := service.AddCoParent(tenantContext(), "admission-1", "third@example.com", "Alex", "Doe", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	nurse := domain.Actor{ID: "nurse-1", Role: domain.RoleNurse}
+
+	changes, err := service.Discharge(tenantContext(), nurse, "admission-1", "went home")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 status changes, got %+v", changes)
+	}
+
+	for id, want := range map[string]domain.ParentStatus{
+		"parent-1":  domain.ParentDischarged,
+		"parent-2":  domain.ParentDischarged,
+		coParent.ID: domain.ParentArchived,
+	} {
+		parent, _ := repo.FindParentByID(context.Background(), id)
+		if parent.Status != want {
+			t.Errorf("expected %s to be %s, got %s", id, want, parent.Status)
+		}
+	}
+	if strings.Join(sessions.RevokedSessions, ",") != "parent-1,parent-2" {
+		t.Errorf("expected both admitted parents' sessions revoked, got %v", sessions.RevokedSessions)
+	}
+
+	if _, err := service.Discharge(tenantContext(), nurse, "admission-1", ""); !errors.Is(err, domain.ErrAdmissionClosed) {
+		t.Errorf("expected %v, got %v", domain.ErrAdmissionClosed, err)
+	}
+	if _, _, err := service.AddCoParent(tenantContext(), "admission-1", "late@example.com", "Lee", "Doe", ""); !errors.Is(err, domain.ErrAdmissionClosed) {
+		t.Errorf("expected %v, got %v", domain.ErrAdmissionClosed, err)
+	}
+}
+
+// TestTransferService_MovesCoParents verifies co-parents in the same room move
+// along, with a single event for the admission's baby.
+func TestTransferService_MovesCoParents(t *testing.T) {
+	_, repo, _ := newAdmissionService()
+	repo.SeedParent(&domain.Parent{
+		User:        domain.User{ID: "parent-2", Email: "second@example.com", Role: domain.RoleParent, CreatedAt: time.Now()},
+		RoomNumber:  "101",
+		Status:      domain.ParentPending,
+		AdmissionID: "admission-1",
+	})
+	service := services.NewTransferService(repo, repo)
+
+	transfer, err := service.Transfer(tenantContext(), domain.Actor{ID: "admin-1", Role: domain.RoleAdmin}, "parent-1", "201", "NICU")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(transfer.CoParentIDs) != 1 || transfer.CoParentIDs[0] != "parent-2" {
+		t.Errorf("expected parent-2 to move along, got %+v", transfer)
+	}
+
+	coParent, _ := repo.FindParentByID(context.Background(), "parent-2")
+	if coParent.RoomNumber != "201" {
+		t.Errorf("expected co-parent in room 201, got %q", coParent.RoomNumber)
+	}
+	var event ports.ParentRoomChangedEvent
+	if len(repo.OutboxPayloads) != 1 || json.Unmarshal(repo.OutboxPayloads[0], &event) != nil || event.AdmissionID != "admission-1" {
+		t.Errorf("expected one room event for the admission, got %d events: %+v", len(repo.OutboxPayloads), event)
+	}
+}
@@ -81,8 +81,8 @@ func TestAuthHandler_LoginCallback_InvalidMethod(t *testing.T) {
 // TestRegistrationHandler_ResponseStructure tests response JSON structure.
 func TestRegistrationHandler_ResponseStructure(t *testing.T) {
 	mockRepo := mocks.NewMockUserRepository()
-	service := services.NewRegistrationService(mockRepo)
-	h := handler.NewRegistrationHandler(service)
+	service := newRegistrationService(mockRepo)
+	h := handler.NewRegistrationHandler(service, services.NewWardPolicy(mockRepo, mockRepo), newAdminApprovalService(mockRepo))
 
 	reqBody := `{"email":"test@example.com","role":"PARENT","first_name":"Test","last_name":"User","room_number":"101"}`
 	req := httptest.NewRequest(http.MethodPost, "/register", jsonReader(reqBody))
@@ -0,0 +1,220 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/handler"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/test/mocks"
+)
+
+// newTestAuthzService wires the authz service with the ward fixtures from ward_policy_test.go
+// and the default policies seeded in the database.
+func newTestAuthzService() (*services.AuthzService, *mocks.MockUserRepository, *mocks.MockPolicyRepository) {
+	mockRepo := newWardRepository()
+	mockRepo.SeedParent(&domain.Parent{
+		User:       domain.User{ID: "parent-discharged", Email: "d@example.com", Role: domain.RoleParent},
+		RoomNumber: "101",
+		Status:     domain.ParentDischarged,
+	})
+
+	policyRepo := mocks.NewMockPolicyRepository(
+		domain.Policy{
+			ID: "deny-discharged", Description: "discharged parents have no access",
+			Action: domain.AnyAction, Effect: domain.EffectDeny, SubjectRole: domain.RoleParent,
+			Conditions: []domain.PolicyCondition{{Subject: "status", Value: string(domain.ParentDischarged)}},
+		},
+		domain.Policy{
+			ID: "parent-room", Description: "parents see their own room",
+			Action: "room:view", Effect: domain.EffectAllow, SubjectRole: domain.RoleParent,
+			Conditions: []domain.PolicyCondition{{Subject: "room_number", Resource: "room_number"}},
+		},
+		domain.Policy{
+			ID: "nurse-ward", Description: "nurses see rooms on their ward",
+			Action: "room:view", Effect: domain.EffectAllow, SubjectRole: domain.RoleNurse,
+			Conditions: []domain.PolicyCondition{{Subject: "ward", Resource: "ward"}},
+		},
+	)
+
+	return services.NewAuthzService(mockRepo, mockRepo, policyRepo), mockRepo, policyRepo
+}
+
+// TestAuthzService_Check tests decisions against subject and resource attributes.
+func TestAuthzService_Check(t *testing.T) {
+	tests := []struct {
+		name             string
+		req              domain.AuthzRequest
+		expectedAllowed  bool
+		expectedPolicyID string
+	}{
+		{
+			name:             "parent_own_room",
+			req:              domain.AuthzRequest{Subject: "parent-maternity", Action: "room:view", Resource: map[string]string{"room_number": "101"}},
+			expectedAllowed:  true,
+			expectedPolicyID: "parent-room",
+		},
+		{
+			name:            "parent_other_room",
+			req:             domain.AuthzRequest{Subject: "parent-maternity", Action: "room:view", Resource: map[string]string{"room_number": "201"}},
+			expectedAllowed: false,
+		},
+		{
+			name:             "discharged_parent_denied_even_for_own_room",
+			req:              domain.AuthzRequest{Subject: "parent-discharged", Action: "room:view", Resource: map[string]string{"room_number": "101"}},
+			expectedAllowed:  false,
+			expectedPolicyID: "deny-discharged",
+		},
+		{
+			name:             "nurse_ward_resolved_from_parent_id",
+			req:              domain.AuthzRequest{Subject: "nurse-1", Action: "room:view", Resource: map[string]string{"parent_id": "parent-maternity"}},
+			expectedAllowed:  true,
+			expectedPolicyID: "nurse-ward",
+		},
+		{
+			name:            "nurse_other_ward",
+			req:             domain.AuthzRequest{Subject: "nurse-1", Action: "room:view", Resource: map[string]string{"room_number": "201"}},
+			expectedAllowed: false,
+		},
+		{
+			name:            "unknown_action",
+			req:             domain.AuthzRequest{Subject: "parent-maternity", Action: "baby:feed", Resource: map[string]string{"room_number": "101"}},
+			expectedAllowed: false,
+		},
+		{
+			name:            "unknown_subject",
+			req:             domain.AuthzRequest{Subject: "nobody", Action: "room:view", Resource: map[string]string{"room_number": "101"}},
+			expectedAllowed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _, _ := newTestAuthzService()
+
+			decision, err := service.Check(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if decision.Allowed != tt.expectedAllowed {
+				t.Errorf("expected allowed=%v, got %+v", tt.expectedAllowed, decision)
+			}
+			if decision.PolicyID != tt.expectedPolicyID {
+				t.Errorf("expected policy %q, got %q", tt.expectedPolicyID, decision.PolicyID)
+			}
+			if decision.Reason == "" {
+				t.Error("expected a reason")
+			}
+		})
+	}
+}
+
+// TestAuthzService_CheckBatch verifies order is preserved and every decision is logged.
+func TestAuthzService_CheckBatch(t *testing.T) {
+	service, _, policyRepo := newTestAuthzService()
+
+	decisions, err := service.CheckBatch(context.Background(), []domain.AuthzRequest{
+		{Subject: "parent-maternity", Action: "room:view", Resource: map[string]string{"room_number": "101"}},
+		{Subject: "parent-maternity", Action: "room:view", Resource: map[string]string{"room_number": "201"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(decisions) != 2 || !decisions[0].Allowed || decisions[1].Allowed {
+		t.Errorf("unexpected decisions: %+v", decisions)
+	}
+
+	logged := policyRepo.GetDecisions()
+	if len(logged) != 2 {
+		t.Fatalf("expected 2 logged decisions, got %d", len(logged))
+	}
+	if logged[0].Request.Resource["ward"] != "MATERNITY" {
+		t.Errorf("expected logged resource to include the resolved ward, got %v", logged[0].Request.Resource)
+	}
+}
+
+// TestAuthzService_CheckBatch_Errors tests rejected batches and fail-closed logging.
+func TestAuthzService_CheckBatch_Errors(t *testing.T) {
+	service, _, policyRepo := newTestAuthzService()
+
+	if _, err := service.CheckBatch(context.Background(), []domain.AuthzRequest{{Action: "room:view"}}); !errors.Is(err, services.ErrInvalidAuthzRequest) {
+		t.Errorf("expected ErrInvalidAuthzRequest, got %v", err)
+	}
+
+	tooMany := make([]domain.AuthzRequest, services.MaxAuthzBatchSize+1)
+	if _, err := service.CheckBatch(context.Background(), tooMany); !errors.Is(err, services.ErrAuthzBatchTooLarge) {
+		t.Errorf("expected ErrAuthzBatchTooLarge, got %v", err)
+	}
+
+	policyRepo.RecordDecisionsError = errors.New("database unavailable")
+	if _, err := service.Check(context.Background(), domain.AuthzRequest{Subject: "parent-maternity", Action: "room:view"}); err == nil {
+		t.Error("expected decisions that cannot be logged to fail")
+	}
+}
+
+// TestAuthzService_Check_AttributesUnavailable tests that a failed attribute
+// lookup fails the check with 503 instead of deciding without the attributes.
+func TestAuthzService_Check_AttributesUnavailable(t *testing.T) {
+	service, mockRepo, policyRepo := newTestAuthzService()
+	mockRepo.FindByIDError = errors.New("connection refused")
+
+	_, err := service.Check(context.Background(), domain.AuthzRequest{Subject: "parent-discharged", Action: "room:view"})
+	if !errors.Is(err, domain.ErrUnavailable) {
+		t.Fatalf("expected ErrUnavailable, got %v", err)
+	}
+	if logged := policyRepo.GetDecisions(); len(logged) != 0 {
+		t.Errorf("expected no decision to be logged, got %d", len(logged))
+	}
+
+	h := handler.NewAuthzHandler(service)
+	body := `{"subject":"parent-discharged","action":"room:view","resource":{"room_number":"101"}}`
+	rec := httptest.NewRecorder()
+	h.Check(rec, httptest.NewRequest(http.MethodPost, "/authz/check", strings.NewReader(body)))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+}
+
+// TestAuthzService_DeletePolicy tests that only a missing policy is reported as not found.
+func TestAuthzService_DeletePolicy(t *testing.T) {
+	service, _, policyRepo := newTestAuthzService()
+
+	if err := service.DeletePolicy(context.Background(), "missing"); !errors.Is(err, services.ErrPolicyNotFound) {
+		t.Errorf("expected ErrPolicyNotFound, got %v", err)
+	}
+
+	policyRepo.DeletePolicyError = errors.New("connection refused")
+	if err := service.DeletePolicy(context.Background(), "parent-room"); err == nil || errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected the repository error, got %v", err)
+	}
+}
+
+// TestAuthzService_CreatePolicy tests policy validation.
+func TestAuthzService_CreatePolicy(t *testing.T) {
+	service, _, _ := newTestAuthzService()
+
+	invalid := []domain.Policy{
+		{Action: "", Effect: domain.EffectAllow},
+		{Action: "room:view", Effect: "maybe"},
+		{Action: "room:view", Effect: domain.EffectAllow, Conditions: []domain.PolicyCondition{{Subject: "ward"}}},
+		{Action: "room:view", Effect: domain.EffectAllow, Conditions: []domain.PolicyCondition{{Subject: "ward", Resource: "ward", Value: "X"}}},
+	}
+	for _, p := range invalid {
+		if _, err := service.CreatePolicy(context.Background(), p); !errors.Is(err, services.ErrInvalidPolicy) {
+			t.Errorf("expected ErrInvalidPolicy for %+v, got %v", p, err)
+		}
+	}
+
+	policy, err := service.CreatePolicy(context.Background(), domain.Policy{Action: "baby:view", Effect: domain.EffectAllow})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy.ID == "" || policy.Conditions == nil {
+		t.Errorf("expected ID and empty conditions to be set, got %+v", policy)
+	}
+}
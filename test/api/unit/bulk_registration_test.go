@@ -0,0 +1,257 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/handler"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/middleware"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/test/mocks"
+)
+
+func newBulkRegistrationService() (*services.BulkRegistrationService, *mocks.MockUserRepository) {
+	service, userRepo, _ := newBulkRegistrationServiceWithMailer()
+	return service, userRepo
+}
+
+func newBulkRegistrationServiceWithMailer() (*services.BulkRegistrationService, *mocks.MockUserRepository, *mocks.MockMailer) {
+	userRepo := newWardRepository()
+	mailer := mocks.NewMockMailer()
+	invitations := newInvitationService(userRepo, mailer, time.Hour)
+	return services.NewBulkRegistrationService(userRepo, services.NewWardPolicy(userRepo, userRepo), invitations), userRepo, mailer
+}
+
+// TestBulkRegistrationService_RegisterParents verifies valid rows are created as
+// pending, invited parents, each with its own event, while invalid rows are reported.
+func TestBulkRegistrationService_RegisterParents(t *testing.T) {
+	service, userRepo, mailer := newBulkRegistrationServiceWithMailer()
+	admin := domain.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+
+	rows := []domain.BulkParentRow{
+		{Line: 2, Email: "anna@example.com", FirstName: "Anna", LastName: "Jansen", RoomNumber: "101"},
+		{Line: 3, Email: "not-an-email", FirstName: "Bob", LastName: "Bakker", RoomNumber: "102"},
+		{Line: 4, Email: "M@Example.com", FirstName: "Cor", LastName: "Visser", RoomNumber: "103"},
+		{Line: 5, Email: "Anna@example.com", FirstName: "Anna", LastName: "Jansen", RoomNumber: ""},
+		{Line: 6, Email: " dirk@example.com ", FirstName: "Dirk", LastName: "de Vries", RoomNumber: "201"},
+		{Line: 7, ParseError: "invalid JSON: unexpected EOF"},
+	}
+
+	report, err := service.RegisterParents(tenantContext(), admin, rows, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Total != 6 || report.Created != 2 || report.Invalid != 4 {
+		t.Errorf("unexpected totals: %+v", report)
+	}
+
+	expected := []struct {
+		status domain.BulkRowStatus
+		errors []string
+	}{
+		{status: domain.BulkRowCreated},
+		{status: domain.BulkRowInvalid, errors: []string{services.ErrInvalidEmail.Error()}},
+		{status: domain.BulkRowInvalid, errors: []string{domain.ErrEmailTaken.Error()}},
+		{status: domain.BulkRowInvalid, errors: []string{services.ErrDuplicateInRun.Error(), services.ErrMissingRoom.Error()}},
+		{status: domain.BulkRowCreated},
+		{status: domain.BulkRowInvalid, errors: []string{"invalid JSON: unexpected EOF"}},
+	}
+	for i, want := range expected {
+		row := report.Rows[i]
+		if row.Status != want.status || strings.Join(row.Errors, "|") != strings.Join(want.errors, "|") {
+			t.Errorf("line %d: expected %s %v, got %s %v", row.Line, want.status, want.errors, row.Status, row.Errors)
+		}
+	}
+
+	if len(userRepo.EmailsTakenCalls) != 1 || len(userRepo.FindByEmailCalls) != 0 {
+		t.Errorf("expected the emails to be checked in one query, got %d queries and %d lookups",
+			len(userRepo.EmailsTakenCalls), len(userRepo.FindByEmailCalls))
+	}
+	if len(userRepo.CreateParentsCalls) != 1 || len(userRepo.CreateParentsCalls[0]) != 2 {
+		t.Fatalf("expected one batch of 2 parents, got %v", userRepo.CreateParentsCalls)
+	}
+	if len(userRepo.OutboxPayloads) != 2 {
+		t.Fatalf("expected 2 outbox events, got %d", len(userRepo.OutboxPayloads))
+	}
+	var event ports.CreateBabyEvent
+	if err := json.Unmarshal(userRepo.OutboxPayloads[1], &event); err != nil {
+		t.Fatalf("invalid outbox payload: %v", err)
+	}
+	if event.TenantID != "test-clinic" || event.UserID != report.Rows[4].UserID || event.RoomNumber != "201" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	if _, err := userRepo.FindByEmail(context.Background(), "dirk@example.com"); err != nil {
+		t.Error("expected the trimmed email to be stored")
+	}
+
+	parent, _ := userRepo.FindParentByID(context.Background(), report.Rows[0].UserID)
+	if parent == nil || parent.Status != domain.ParentPending {
+		t.Errorf("expected a pending parent, got %+v", parent)
+	}
+	invitations, _ := userRepo.ListInvitations(context.Background(), domain.InvitationPending)
+	if len(invitations) != 2 || len(mailer.Sent) != 2 || !report.Rows[0].InvitationSent {
+		t.Errorf("expected 2 invitations mailed, got %d invitations and %d emails", len(invitations), len(mailer.Sent))
+	}
+}
+
+// TestBulkRegistrationService_DryRun verifies a dry run reports without storing anything.
+func TestBulkRegistrationService_DryRun(t *testing.T) {
+	service, userRepo := newBulkRegistrationService()
+
+	report, err := service.RegisterParents(tenantContext(), domain.Actor{ID: "admin-1", Role: domain.RoleAdmin},
+		[]domain.BulkParentRow{{Line: 2, Email: "anna@example.com", FirstName: "Anna", LastName: "Jansen", RoomNumber: "101"}}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !report.DryRun || report.Valid != 1 || report.Created != 0 || report.Rows[0].Status != domain.BulkRowValid {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if len(userRepo.CreateParentsCalls) != 0 {
+		t.Error("expected a dry run not to store parents")
+	}
+}
+
+// TestBulkRegistrationService_Ward verifies nurses can only bulk register into their own ward.
+func TestBulkRegistrationService_Ward(t *testing.T) {
+	service, userRepo := newBulkRegistrationService()
+	nurse := domain.Actor{ID: "nurse-1", Role: domain.RoleNurse}
+
+	report, err := service.RegisterParents(tenantContext(), nurse, []domain.BulkParentRow{
+		{Line: 2, Email: "anna@example.com", FirstName: "Anna", LastName: "Jansen", RoomNumber: "101"},
+		{Line: 3, Email: "bob@example.com", FirstName: "Bob", LastName: "Bakker", RoomNumber: "201"},
+		{Line: 4, Email: "cor@example.com", FirstName: "Cor", LastName: "Visser", RoomNumber: "999"},
+	}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Created != 1 || report.Rows[1].Errors[0] != services.ErrOutsideWard.Error() ||
+		report.Rows[2].Errors[0] != services.ErrOutsideWard.Error() {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if len(userRepo.GetRoomWardsCalls) != 1 {
+		t.Errorf("expected the rooms to be checked in one query, got %d", len(userRepo.GetRoomWardsCalls))
+	}
+}
+
+// TestBulkRegistrationService_Rejected tests uploads rejected as a whole.
+func TestBulkRegistrationService_Rejected(t *testing.T) {
+	service, _ := newBulkRegistrationService()
+	admin := domain.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+
+	if _, err := service.RegisterParents(tenantContext(), admin, nil, false); !errors.Is(err, services.ErrEmptyUpload) {
+		t.Errorf("expected %v, got %v", services.ErrEmptyUpload, err)
+	}
+	if _, err := service.RegisterParents(tenantContext(), admin, make([]domain.BulkParentRow, 501), false); !errors.Is(err, services.ErrTooManyRows) {
+		t.Errorf("expected %v, got %v", services.ErrTooManyRows, err)
+	}
+}
+
+// TestBulkRegistrationHandler_RegisterParents tests parsing of CSV and JSON lines uploads.
+func TestBulkRegistrationHandler_RegisterParents(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentType    string
+		query          string
+		body           string
+		expectedStatus int
+		expectedLines  []int
+	}{
+		{
+			name:        "csv",
+			contentType: "text/csv; charset=utf-8",
+			body: "\ufeffEmail,First_Name,Last_Name,Room_Number\n" +
+				"anna@example.com,Anna,Jansen,101\n" +
+				"\"bob@example.com\",Bob,\"Bakker, van\",102\n",
+			expectedStatus: http.StatusCreated,
+			expectedLines:  []int{2, 3},
+		},
+		{
+			name:           "csv_columns_reordered_dry_run",
+			contentType:    "text/csv",
+			query:          "?dry_run=true",
+			body:           "room_number,last_name,first_name,email\n101,Jansen,Anna,anna@example.com\n",
+			expectedStatus: http.StatusOK,
+			expectedLines:  []int{2},
+		},
+		{
+			name:           "csv_field_count",
+			contentType:    "text/csv",
+			body:           "email,first_name,last_name,room_number\nanna@example.com,Anna\n",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedLines:  []int{2},
+		},
+		{
+			name:           "csv_missing_column",
+			contentType:    "text/csv",
+			body:           "email,first_name,last_name\nanna@example.com,Anna,Jansen\n",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "json_lines",
+			contentType: "application/x-ndjson",
+			body: `{"email": "anna@example.com", "first_name": "Anna", "last_name": "Jansen", "room_number": "101"}` + "\n\n" +
+				`{"email": "bob@example.com", "first_name": "Bob"` + "\n",
+			expectedStatus: http.StatusCreated,
+			expectedLines:  []int{1, 3},
+		},
+		{
+			name:           "invalid_dry_run",
+			contentType:    "text/csv",
+			query:          "?dry_run=maybe",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unsupported_type",
+			contentType:    "application/json",
+			body:           `[]`,
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newBulkRegistrationService()
+			h := handler.NewBulkRegistrationHandler(service)
+
+			req := httptest.NewRequest(http.MethodPost, "/register/bulk"+tt.query, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			ctx := context.WithValue(tenantContext(), middleware.UserIDKey, "admin-1")
+			ctx = context.WithValue(ctx, middleware.RoleKey, string(domain.RoleAdmin))
+			req = req.WithContext(ctx)
+			rec := httptest.NewRecorder()
+			h.RegisterParents(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedLines == nil {
+				return
+			}
+
+			var report domain.BulkRegistrationReport
+			if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+				t.Fatalf("failed to decode report: %v", err)
+			}
+			var lines []int
+			for _, row := range report.Rows {
+				lines = append(lines, row.Line)
+			}
+			if len(lines) != len(tt.expectedLines) {
+				t.Fatalf("expected lines %v, got %v", tt.expectedLines, lines)
+			}
+			for i := range lines {
+				if lines[i] != tt.expectedLines[i] {
+					t.Errorf("expected lines %v, got %v", tt.expectedLines, lines)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/config"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/sony/gobreaker"
+)
+
+// TestCircuitBreaker_RejectionsKeepCircuitClosed checks that requests refused
+// by the data, such as duplicate emails or stale versions, do not open the
+// database breaker for every clinic.
+func TestCircuitBreaker_RejectionsKeepCircuitClosed(t *testing.T) {
+	rejections := []error{
+		domain.ErrEmailTaken,
+		domain.ErrVersionConflict,
+		domain.ErrLastAdmin,
+		domain.ErrAdminApprovalClosed,
+		domain.ErrAdminApprovalNotFound,
+		domain.ErrAdminApprovalPending,
+		domain.ErrNothingToErase,
+		domain.InvalidField("email", "email is invalid"),
+		fmt.Errorf("register: %w", domain.ErrEmailTaken),
+	}
+
+	cb := config.NewCircuitBreaker("PostgreSQL")
+	for i := 0; i < 10; i++ {
+		for _, rejection := range rejections {
+			_, err := cb.Execute(func() (interface{}, error) { return nil, rejection })
+			if !errors.Is(err, rejection) {
+				t.Fatalf("expected %v, got %v", rejection, err)
+			}
+		}
+	}
+
+	if cb.State() != gobreaker.StateClosed {
+		t.Errorf("expected circuit to stay closed, got %s", cb.State())
+	}
+}
+
+// TestCircuitBreaker_FailuresOpenCircuit checks that real failures still trip the breaker.
+func TestCircuitBreaker_FailuresOpenCircuit(t *testing.T) {
+	cb := config.NewCircuitBreaker("PostgreSQL")
+	for i := 0; i < 3; i++ {
+		_, _ = cb.Execute(func() (interface{}, error) { return nil, errors.New("connection refused") })
+	}
+
+	if cb.State() != gobreaker.StateOpen {
+		t.Errorf("expected circuit to open, got %s", cb.State())
+	}
+}
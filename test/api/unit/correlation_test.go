@@ -0,0 +1,48 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/middleware"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+)
+
+// TestCorrelationMiddleware verifies the caller's correlation ID is kept, a
+// missing or oversized one is replaced, and the ID reaches the context.
+func TestCorrelationMiddleware(t *testing.T) {
+	var seen string
+	h := middleware.CorrelationMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = domain.CorrelationIDFromContext(r.Context())
+	}))
+
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{"caller supplied", "checkout-42", true},
+		{"missing", "", false},
+		{"too long", strings.Repeat("x", 65), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(middleware.CorrelationHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			echoed := rec.Header().Get(middleware.CorrelationHeader)
+			if echoed == "" || echoed != seen {
+				t.Fatalf("expected the context's ID %q echoed, got %q", seen, echoed)
+			}
+			if (echoed == tt.header) != tt.keep {
+				t.Errorf("unexpected correlation ID %q for header %q", echoed, tt.header)
+			}
+		})
+	}
+}
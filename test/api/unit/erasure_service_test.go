@@ -0,0 +1,176 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/handler"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/middleware"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/test/mocks"
+)
+
+func newErasureService() (*services.ErasureService, *mocks.MockUserRepository, *mocks.MockVisitorRepository, *mocks.MockSessionRevoker) {
+	_, userRepo, visitorRepo, sessions := newUserAdminService()
+	return services.NewErasureService(userRepo, visitorRepo, sessions), userRepo, visitorRepo, sessions
+}
+
+// TestScrubPersonalData verifies personal fields are replaced at any depth and other fields kept.
+func TestScrubPersonalData(t *testing.T) {
+	scrubbed, err := domain.ScrubPersonalData([]byte(
+		`{"user_id": "parent-1", "last_name": "Smith", "room_number": "101", "changes": [{"email": "jane@example.com", "version": 2}]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var payload struct {
+		UserID     string `json:"user_id"`
+		LastName   string `json:"last_name"`
+		RoomNumber string `json:"room_number"`
+		Changes    []struct {
+			Email   string `json:"email"`
+			Version int    `json:"version"`
+		} `json:"changes"`
+	}
+	if err := json.Unmarshal(scrubbed, &payload); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if payload.LastName != domain.ErasedValue || payload.Changes[0].Email != domain.ErasedValue {
+		t.Errorf("expected personal data to be erased, got %s", scrubbed)
+	}
+	if payload.UserID != "parent-1" || payload.RoomNumber != "101" || payload.Changes[0].Version != 2 {
+		t.Errorf("expected other fields to be kept, got %s", scrubbed)
+	}
+}
+
+// TestErasureService_Erase verifies the family's access is revoked, the parent is
+// pseudonymized, earlier events are scrubbed and a user.erased event is queued.
+func TestErasureService_Erase(t *testing.T) {
+	service, userRepo, visitorRepo, sessions := newErasureService()
+	_ = visitorRepo.CreateVisitor(context.Background(), domain.Visitor{
+		ID: "visitor-1", ParentID: "parent-1", DisplayName: "Grandma",
+		ExpiresAt: time.Now().Add(time.Hour), CreatedAt: time.Now(),
+	}, "")
+
+	// An earlier correction left the parent's name in a stored event
+	if _, err := newUserAdminServiceFor(userRepo, visitorRepo, sessions).Update(tenantContext(), adminActor, "parent-1", 1,
+		domain.UserUpdate{LastName: stringPtr("Smith")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tombstone, err := service.Erase(tenantContext(), domain.Actor{ID: "admin-1", Role: domain.RoleAdmin}, "parent-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tombstone.UserID != "parent-1" || tombstone.Role != domain.RoleParent || tombstone.RequestedBy != "admin-1" {
+		t.Errorf("unexpected tombstone: %+v", tombstone)
+	}
+
+	if strings.Join(sessions.RevokedSessions, ",") != "parent-1,visitor-1" {
+		t.Errorf("unexpected revoked sessions: %v", sessions.RevokedSessions)
+	}
+	if _, err := userRepo.FindByEmail(context.Background(), "parent@example.com"); err == nil {
+		t.Error("expected the original email to be gone")
+	}
+
+	if len(userRepo.OutboxPayloads) != 2 {
+		t.Fatalf("expected 2 outbox events, got %d", len(userRepo.OutboxPayloads))
+	}
+	if strings.Contains(string(userRepo.OutboxPayloads[0]), "Smith") {
+		t.Errorf("expected the earlier event to be scrubbed, got %s", userRepo.OutboxPayloads[0])
+	}
+	var event ports.UserErasedEvent
+	if err := json.Unmarshal(userRepo.OutboxPayloads[1], &event); err != nil {
+		t.Fatalf("invalid outbox payload: %v", err)
+	}
+	if event.TenantID != "test-clinic" || event.UserID != "parent-1" || event.Role != "PARENT" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	// Erasing twice finds nothing left to erase
+	if _, err := service.Erase(tenantContext(), domain.Actor{ID: "admin-1"}, "parent-1"); !errors.Is(err, domain.ErrNothingToErase) {
+		t.Errorf("expected %v, got %v", domain.ErrNothingToErase, err)
+	}
+}
+
+// TestErasureService_Erase_Deactivated verifies families who already left can still be forgotten.
+func TestErasureService_Erase_Deactivated(t *testing.T) {
+	service, userRepo, visitorRepo, sessions := newErasureService()
+	admin := domain.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+
+	if _, err := newUserAdminServiceFor(userRepo, visitorRepo, sessions).Deactivate(tenantContext(), admin, "parent-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.Erase(tenantContext(), admin, "parent-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(userRepo.EraseUserCalls) != 1 {
+		t.Errorf("expected 1 EraseUser call, got %d", len(userRepo.EraseUserCalls))
+	}
+}
+
+// TestErasureService_Rejected tests erasures that must leave the user untouched.
+func TestErasureService_Rejected(t *testing.T) {
+	admin := domain.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+
+	t.Run("self", func(t *testing.T) {
+		service, userRepo, _, _ := newErasureService()
+		if _, err := service.Erase(tenantContext(), admin, "admin-1"); !errors.Is(err, services.ErrSelfErasure) {
+			t.Errorf("expected %v, got %v", services.ErrSelfErasure, err)
+		}
+		if len(userRepo.EraseUserCalls) != 0 {
+			t.Error("expected no erasure")
+		}
+	})
+
+	t.Run("revocation_fails", func(t *testing.T) {
+		service, userRepo, _, sessions := newErasureService()
+		sessions.RevokeSessionError = errors.New("redis unavailable")
+
+		if _, err := service.Erase(tenantContext(), admin, "parent-1"); err == nil {
+			t.Error("expected error but got none")
+		}
+		if len(userRepo.EraseUserCalls) != 0 {
+			t.Error("expected no erasure while sessions cannot be revoked")
+		}
+	})
+}
+
+// TestErasureHandler_EraseUser tests the status codes of POST /users/{userID}/erase.
+func TestErasureHandler_EraseUser(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		expectedStatus int
+	}{
+		{name: "success", userID: "parent-1", expectedStatus: http.StatusOK},
+		{name: "unknown_user", userID: "missing", expectedStatus: http.StatusNotFound},
+		{name: "self", userID: "admin-1", expectedStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _, _, _ := newErasureService()
+			h := handler.NewErasureHandler(service)
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /users/{userID}/erase", h.EraseUser)
+
+			req := httptest.NewRequest(http.MethodPost, "/users/"+tt.userID+"/erase", nil)
+			req = req.WithContext(context.WithValue(tenantContext(), middleware.UserIDKey, "admin-1"))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
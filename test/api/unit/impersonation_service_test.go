@@ -0,0 +1,180 @@
+package unit
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/test/mocks"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/sony/gobreaker"
+)
+
+// newTestTokenIssuer creates a TokenIssuer with a throwaway RSA key.
+// Redis is not needed for issuing tokens, only for session tracking and revocation.
+func newTestTokenIssuer(t *testing.T) (*services.TokenIssuer, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return services.NewTokenIssuer(key, nil, nil, nil), key
+}
+
+// tenantContext returns a context scoped to a test clinic; tokens can only be issued for a tenant.
+func tenantContext() context.Context {
+	return domain.WithTenant(context.Background(), "test-clinic")
+}
+
+// TestImpersonationService_Start verifies the issued token carries the act claim
+// and that the start of the session is audited.
+func TestImpersonationService_Start(t *testing.T) {
+	mockRepo := mocks.NewMockUserRepository()
+	mockRepo.SeedParent(&domain.Parent{
+		User:       domain.User{ID: "parent-1", Email: "parent@example.com", Role: domain.RoleParent},
+		RoomNumber: "101",
+		Status:     domain.ParentActive,
+	})
+	auditRepo := mocks.NewMockAuditRepository()
+	issuer, key := newTestTokenIssuer(t)
+	sessions := mocks.NewMockSessionRevoker()
+	service := services.NewImpersonationService(mockRepo, auditRepo, issuer, sessions)
+
+	issued, err := service.Start(tenantContext(), "admin-1", "parent-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	token, err := jwt.Parse(issued.Token, func(token *jwt.Token) (any, error) {
+		return &key.PublicKey, nil
+	})
+	if err != nil {
+		t.Fatalf("failed to parse issued token: %v", err)
+	}
+
+	claims := token.Claims.(jwt.MapClaims)
+	if claims["sub"] != "parent-1" {
+		t.Errorf("expected sub 'parent-1', got %v", claims["sub"])
+	}
+	act, ok := claims["act"].(map[string]interface{})
+	if !ok || act["sub"] != "admin-1" {
+		t.Errorf("expected act claim naming 'admin-1', got %v", claims["act"])
+	}
+
+	if jtis := sessions.Impersonations["parent-1"]; len(jtis) != 1 || jtis[0] != issued.JTI {
+		t.Errorf("expected the token to be tracked against the parent, got %v", sessions.Impersonations)
+	}
+
+	entries := auditRepo.GetEntries()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 audit entry, got %d", len(entries))
+	}
+	if entries[0].Action != domain.AuditImpersonationStarted {
+		t.Errorf("expected action %q, got %q", domain.AuditImpersonationStarted, entries[0].Action)
+	}
+	if entries[0].ActorID != "admin-1" || entries[0].SubjectID != "parent-1" {
+		t.Errorf("unexpected audit actor/subject: %+v", entries[0])
+	}
+}
+
+// TestImpersonationService_Start_Rejected tests targets that must not be impersonated.
+func TestImpersonationService_Start_Rejected(t *testing.T) {
+	tests := []struct {
+		name        string
+		targetID    string
+		expectedErr error
+	}{
+		{
+			name:        "admin_cannot_be_impersonated",
+			targetID:    "admin-2",
+			expectedErr: services.ErrImpersonationNotAllowed,
+		},
+		{
+			name:        "discharged_parent_cannot_be_impersonated",
+			targetID:    "parent-discharged",
+			expectedErr: services.ErrImpersonationNotAllowed,
+		},
+		{
+			name:        "self_impersonation",
+			targetID:    "admin-1",
+			expectedErr: services.ErrImpersonationNotAllowed,
+		},
+		{
+			name:        "unknown_user",
+			targetID:    "missing",
+			expectedErr: services.ErrImpersonationTargetNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUserRepository()
+			mockRepo.SeedUser(&domain.User{ID: "admin-2", Email: "admin2@baby-kliniek.nl", Role: domain.RoleAdmin})
+			mockRepo.SeedParent(&domain.Parent{
+				User:   domain.User{ID: "parent-discharged", Email: "gone@example.com", Role: domain.RoleParent},
+				Status: domain.ParentDischarged,
+			})
+			auditRepo := mocks.NewMockAuditRepository()
+			issuer, _ := newTestTokenIssuer(t)
+			service := services.NewImpersonationService(mockRepo, auditRepo, issuer, mocks.NewMockSessionRevoker())
+
+			_, err := service.Start(context.Background(), "admin-1", tt.targetID)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+			if len(auditRepo.GetEntries()) != 0 {
+				t.Errorf("expected no audit entries for rejected impersonation")
+			}
+		})
+	}
+}
+
+// TestImpersonationService_Start_LookupFails verifies a failed lookup of the
+// target is reported as such rather than as an unknown user.
+func TestImpersonationService_Start_LookupFails(t *testing.T) {
+	mockRepo := mocks.NewMockUserRepository()
+	mockRepo.FindByIDError = gobreaker.ErrOpenState
+	auditRepo := mocks.NewMockAuditRepository()
+	issuer, _ := newTestTokenIssuer(t)
+	service := services.NewImpersonationService(mockRepo, auditRepo, issuer, mocks.NewMockSessionRevoker())
+
+	_, err := service.Start(tenantContext(), "admin-1", "parent-1")
+	if errors.Is(err, services.ErrImpersonationTargetNotFound) || !errors.Is(err, gobreaker.ErrOpenState) {
+		t.Errorf("expected the repository error, got %v", err)
+	}
+}
+
+// TestAuthService_Logout_Impersonation verifies that logging out of an impersonation
+// token ends the impersonation and audits it against the admin.
+func TestAuthService_Logout_Impersonation(t *testing.T) {
+	auditRepo := mocks.NewMockAuditRepository()
+	issuer, _ := newTestTokenIssuer(t)
+	impersonations := services.NewImpersonationService(mocks.NewMockUserRepository(), auditRepo, issuer, mocks.NewMockSessionRevoker())
+	authService := services.NewAuthService(nil, mocks.NewMockUserRepository(), issuer, nil, nil, impersonations)
+
+	// An already expired token needs no blacklist entry, so Redis is not involved
+	issued, err := issuer.Issue(tenantContext(), jwt.MapClaims{
+		"sub": "parent-1",
+		"act": map[string]string{"sub": "admin-1"},
+	}, -time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := authService.Logout(tenantContext(), issued.Token); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries := auditRepo.GetEntries()
+	if len(entries) != 1 || entries[0].Action != domain.AuditImpersonationEnded {
+		t.Fatalf("expected an %q audit entry, got %+v", domain.AuditImpersonationEnded, entries)
+	}
+	if entries[0].ActorID != "admin-1" || entries[0].SubjectID != "parent-1" {
+		t.Errorf("unexpected audit actor/subject: %+v", entries[0])
+	}
+}
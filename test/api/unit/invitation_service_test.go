@@ -0,0 +1,221 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/test/mocks"
+)
+
+const testAcceptURL = "https://clinic.example.com/invitations/accept"
+
+func newInvitationService(repo *mocks.MockUserRepository, mailer *mocks.MockMailer, ttl time.Duration) *services.InvitationService {
+	lifecycle := services.NewParentLifecycleService(repo, mocks.NewMockVisitorRepository(), mocks.NewMockSessionRevoker())
+	return services.NewInvitationService(repo, repo, lifecycle, mailer, services.InvitationSettings{
+		SigningKey: []byte("test-invitation-signing-key-of-32-bytes"),
+		AcceptURLs: map[string]string{"test-clinic": testAcceptURL},
+		TTL:        ttl,
+	})
+}
+
+// newRegistrationService invites parents through a mock mailer.
+func newRegistrationService(repo *mocks.MockUserRepository) *services.RegistrationService {
+	return services.NewRegistrationService(repo, mocks.NewSeededPermissionRepository(), newInvitationService(repo, mocks.NewMockMailer(), time.Hour))
+}
+
+// invitedParent registers a parent and returns their invitation and the token of the mailed link.
+func invitedParent(t *testing.T, repo *mocks.MockUserRepository, service *services.InvitationService, mailer *mocks.MockMailer) (domain.Invitation, string) {
+	t.Helper()
+	parent := domain.Parent{
+		User:       domain.User{ID: "parent-1", Email: "parent@example.com", Role: domain.RoleParent, FirstName: "Pat", LastName: "Doe"},
+		RoomNumber: "101",
+		Status:     domain.ParentPending,
+	}
+	sent, err := service.InviteParent(tenantContext(), parent, nil)
+	if err != nil || !sent {
+		t.Fatalf("expected the invitation to be sent, got %v, %v", sent, err)
+	}
+
+	invitations, _ := repo.ListInvitations(context.Background(), domain.InvitationPending)
+	if len(invitations) != 1 {
+		t.Fatalf("expected 1 pending invitation, got %d", len(invitations))
+	}
+	return invitations[0], linkToken(t, mailer.Last())
+}
+
+// linkToken returns the token of the accept link in an invitation email
+func linkToken(t *testing.T, message ports.MailMessage) string {
+	t.Helper()
+	body := message.Body
+	start := strings.Index(body, testAcceptURL+"?token=")
+	if start < 0 {
+		t.Fatalf("expected an accept link in %q", body)
+	}
+	link := strings.Fields(body[start:])[0]
+	parsed, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("invalid link %q: %v", link, err)
+	}
+	return parsed.Query().Get("token")
+}
+
+// TestInvitationService_InviteAndAccept verifies an invited parent stays
+// pending until they sign in with their own email.
+func TestInvitationService_InviteAndAccept(t *testing.T) {
+	repo := mocks.NewMockUserRepository()
+	mailer := mocks.NewMockMailer()
+	service := newInvitationService(repo, mailer, time.Hour)
+	invitation, token := invitedParent(t, repo, service, mailer)
+
+	if mailer.Last().To != "parent@example.com" {
+		t.Errorf("expected the invitation to be mailed to the parent, got %q", mailer.Last().To)
+	}
+	if parent, _ := repo.FindParentByID(context.Background(), invitation.UserID); parent.Status != domain.ParentPending {
+		t.Fatalf("expected invited parent to be pending, got %s", parent.Status)
+	}
+
+	if _, err := service.Accept(tenantContext(), token, "someone-else@example.com"); !errors.Is(err, domain.ErrInvitationEmailMismatch) {
+		t.Fatalf("expected %v, got %v", domain.ErrInvitationEmailMismatch, err)
+	}
+	if _, err := service.Accept(domain.WithTenant(context.Background(), "other-clinic"), token, "parent@example.com"); !errors.Is(err, domain.ErrInvitationInvalid) {
+		t.Fatalf("expected a link of another clinic to be invalid, got %v", err)
+	}
+
+	accepted, err := service.Accept(tenantContext(), token, "Parent@Example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if accepted.Status != domain.InvitationAccepted || accepted.AcceptedAt == nil {
+		t.Errorf("unexpected invitation: %+v", accepted)
+	}
+	if parent, _ := repo.FindParentByID(context.Background(), invitation.UserID); parent.Status != domain.ParentActive {
+		t.Errorf("expected accepted parent to be active, got %s", parent.Status)
+	}
+
+	if _, err := service.Accept(tenantContext(), token, "parent@example.com"); !errors.Is(err, domain.ErrInvitationClosed) {
+		t.Errorf("expected a second acceptance to fail with %v, got %v", domain.ErrInvitationClosed, err)
+	}
+}
+
+// TestInvitationService_Expired verifies an expired link cannot be accepted.
+func TestInvitationService_Expired(t *testing.T) {
+	repo := mocks.NewMockUserRepository()
+	mailer := mocks.NewMockMailer()
+	service := newInvitationService(repo, mailer, -time.Minute)
+	_, token := invitedParent(t, repo, service, mailer)
+
+	if _, err := service.Accept(tenantContext(), token, "parent@example.com"); !errors.Is(err, domain.ErrInvitationExpired) {
+		t.Errorf("expected %v, got %v", domain.ErrInvitationExpired, err)
+	}
+}
+
+// TestInvitationService_ResendReplacesLink verifies only the latest link works.
+func TestInvitationService_ResendReplacesLink(t *testing.T) {
+	repo := mocks.NewMockUserRepository()
+	mailer := mocks.NewMockMailer()
+	service := newInvitationService(repo, mailer, time.Hour)
+	invitation, oldToken := invitedParent(t, repo, service, mailer)
+
+	resent, err := service.Resend(tenantContext(), invitation.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resent.Sends != 2 || len(mailer.Sent) != 2 {
+		t.Errorf("expected a second email, got %d sends and %d emails", resent.Sends, len(mailer.Sent))
+	}
+
+	if _, err := service.Verify(tenantContext(), oldToken); !errors.Is(err, domain.ErrInvitationInvalid) {
+		t.Errorf("expected the old link to be invalid, got %v", err)
+	}
+	if _, err := service.Verify(tenantContext(), linkToken(t, mailer.Last())); err != nil {
+		t.Errorf("expected the new link to be valid, got %v", err)
+	}
+	if _, err := service.Verify(tenantContext(), oldToken+"x"); !errors.Is(err, domain.ErrInvitationInvalid) {
+		t.Errorf("expected a tampered link to be invalid, got %v", err)
+	}
+}
+
+// TestInvitationService_EmailCorrected verifies that correcting a pending
+// parent's email moves their invitation to the new address.
+func TestInvitationService_EmailCorrected(t *testing.T) {
+	repo := mocks.NewMockUserRepository()
+	mailer := mocks.NewMockMailer()
+	service := newInvitationService(repo, mailer, time.Hour)
+	invitation, oldToken := invitedParent(t, repo, service, mailer)
+
+	admin := newUserAdminServiceFor(repo, mocks.NewMockVisitorRepository(), mocks.NewMockSessionRevoker())
+	if _, err := admin.Update(tenantContext(), domain.Actor{ID: "admin-1", Role: domain.RoleAdmin}, invitation.UserID, 1, domain.UserUpdate{Email: stringPtr("pat@example.com")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := service.Verify(tenantContext(), oldToken); !errors.Is(err, domain.ErrInvitationInvalid) {
+		t.Errorf("expected the link mailed to the old address to be invalid, got %v", err)
+	}
+	if _, err := service.Resend(tenantContext(), invitation.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mailer.Last().To != "pat@example.com" {
+		t.Errorf("expected the invitation to be resent to the corrected address, got %q", mailer.Last().To)
+	}
+	if _, err := service.Accept(tenantContext(), linkToken(t, mailer.Last()), "pat@example.com"); err != nil {
+		t.Errorf("expected the corrected email to be accepted, got %v", err)
+	}
+}
+
+// TestInvitationService_Cancel verifies a cancelled invitation archives the
+// pending parent and can no longer be accepted or resent.
+func TestInvitationService_Cancel(t *testing.T) {
+	repo := mocks.NewMockUserRepository()
+	mailer := mocks.NewMockMailer()
+	service := newInvitationService(repo, mailer, time.Hour)
+	invitation, token := invitedParent(t, repo, service, mailer)
+	admin := domain.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+
+	cancelled, err := service.Cancel(tenantContext(), admin, invitation.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cancelled.Status != domain.InvitationCancelled {
+		t.Errorf("expected cancelled invitation, got %s", cancelled.Status)
+	}
+	if parent, _ := repo.FindParentByID(context.Background(), invitation.UserID); parent.Status != domain.ParentArchived {
+		t.Errorf("expected the parent to be archived, got %s", parent.Status)
+	}
+
+	if _, err := service.Accept(tenantContext(), token, "parent@example.com"); !errors.Is(err, domain.ErrInvitationClosed) {
+		t.Errorf("expected %v, got %v", domain.ErrInvitationClosed, err)
+	}
+	if _, err := service.Resend(tenantContext(), invitation.ID); !errors.Is(err, domain.ErrInvitationClosed) {
+		t.Errorf("expected %v, got %v", domain.ErrInvitationClosed, err)
+	}
+	if _, err := service.List(tenantContext(), "Expired"); !errors.Is(err, services.ErrInvalidInvitationStatus) {
+		t.Errorf("expected %v, got %v", services.ErrInvalidInvitationStatus, err)
+	}
+}
+
+// TestRegistrationService_MailFailure verifies a parent is still registered
+// when their invitation email cannot be sent.
+func TestRegistrationService_MailFailure(t *testing.T) {
+	repo := mocks.NewMockUserRepository()
+	mailer := mocks.NewMockMailer()
+	mailer.SendError = errors.New("relay unavailable")
+	service := services.NewRegistrationService(repo, mocks.NewSeededPermissionRepository(), newInvitationService(repo, mailer, time.Hour))
+
+	msg, err := service.RegisterParent(tenantContext(), "parent@example.com", "Pat", "Doe", "101", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(msg, "resend") {
+		t.Errorf("expected the message to ask for a resend, got %q", msg)
+	}
+	if len(repo.CreateParentCalls) != 1 || repo.CreateParentCalls[0].Status != domain.ParentPending {
+		t.Errorf("expected one pending parent, got %+v", repo.CreateParentCalls)
+	}
+}
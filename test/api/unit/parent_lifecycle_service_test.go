@@ -0,0 +1,235 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/handler"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/middleware"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/test/mocks"
+)
+
+func newParentLifecycleService() (*services.ParentLifecycleService, *mocks.MockUserRepository, *mocks.MockVisitorRepository, *mocks.MockSessionRevoker) {
+	userRepo := newWardRepository()
+	visitorRepo := mocks.NewMockVisitorRepository()
+	sessions := mocks.NewMockSessionRevoker()
+	return services.NewParentLifecycleService(userRepo, visitorRepo, sessions), userRepo, visitorRepo, sessions
+}
+
+// TestParentLifecycleService_DischargeAndReadmit walks a parent through a full stay and back.
+func TestParentLifecycleService_DischargeAndReadmit(t *testing.T) {
+	service, userRepo, visitorRepo, sessions := newParentLifecycleService()
+	nurse := domain.Actor{ID: "nurse-1", Role: domain.RoleNurse}
+	_ = visitorRepo.CreateVisitor(context.Background(), domain.Visitor{
+		ID: "visitor-1", ParentID: "parent-maternity", DisplayName: "Grandpa",
+		ExpiresAt: time.Now().Add(time.Hour), CreatedAt: time.Now(),
+	}, "")
+
+	if _, err := service.Discharge(tenantContext(), nurse, "parent-maternity", "went home"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(sessions.RevokedSessions, ",") != "parent-maternity,visitor-1" {
+		t.Errorf("unexpected revoked sessions: %v", sessions.RevokedSessions)
+	}
+	if strings.Join(sessions.RevokedImpersonations, ",") != "parent-maternity" {
+		t.Errorf("expected impersonations of the parent to be revoked, got %v", sessions.RevokedImpersonations)
+	}
+
+	change, err := service.Readmit(tenantContext(), nurse, "parent-maternity", "baby readmitted")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if change.FromStatus != domain.ParentDischarged || change.ToStatus != domain.ParentReadmitted {
+		t.Errorf("unexpected change: %+v", change)
+	}
+
+	parent, _ := userRepo.FindParentByID(context.Background(), "parent-maternity")
+	if !parent.Status.IsAdmitted() {
+		t.Errorf("expected readmitted parent to count as admitted, got %s", parent.Status)
+	}
+
+	history, err := service.History(tenantContext(), "parent-maternity")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history) != 2 || history[0].Reason != "went home" {
+		t.Errorf("unexpected history: %+v", history)
+	}
+
+	// The discharge queues parent.status_changed and parent.discharged, the readmission only the former
+	if len(userRepo.OutboxPayloads) != 3 {
+		t.Fatalf("expected 3 outbox events, got %d", len(userRepo.OutboxPayloads))
+	}
+	var discharged ports.ParentDischargedEvent
+	if err := json.Unmarshal(userRepo.OutboxPayloads[1], &discharged); err != nil {
+		t.Fatalf("invalid outbox payload: %v", err)
+	}
+	if discharged.UserID != "parent-maternity" || discharged.RoomNumber == "" || discharged.Reason != "went home" ||
+		discharged.DischargedBy != "nurse-1" || discharged.DischargedAt.IsZero() {
+		t.Errorf("unexpected discharge event: %+v", discharged)
+	}
+
+	var event ports.ParentStatusChangedEvent
+	if err := json.Unmarshal(userRepo.OutboxPayloads[2], &event); err != nil {
+		t.Fatalf("invalid outbox payload: %v", err)
+	}
+	if event.TenantID != "test-clinic" || event.FromStatus != "Discharged" || event.ToStatus != "Readmitted" ||
+		event.ChangedBy != "nurse-1" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+}
+
+// TestParentLifecycleService_Rejected tests transitions that must not reach the repository.
+func TestParentLifecycleService_Rejected(t *testing.T) {
+	admin := domain.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+
+	tests := []struct {
+		name        string
+		transition  func(s *services.ParentLifecycleService) error
+		expectedErr error
+	}{
+		{
+			name: "activate_active_parent",
+			transition: func(s *services.ParentLifecycleService) error {
+				_, err := s.Activate(tenantContext(), admin, "parent-maternity", "")
+				return err
+			},
+			expectedErr: services.ErrInvalidTransition,
+		},
+		{
+			name: "readmit_active_parent",
+			transition: func(s *services.ParentLifecycleService) error {
+				_, err := s.Readmit(tenantContext(), admin, "parent-maternity", "")
+				return err
+			},
+			expectedErr: services.ErrInvalidTransition,
+		},
+		{
+			name: "archive_active_parent",
+			transition: func(s *services.ParentLifecycleService) error {
+				_, err := s.Archive(tenantContext(), admin, "parent-maternity", "")
+				return err
+			},
+			expectedErr: services.ErrInvalidTransition,
+		},
+		{
+			name: "unknown_parent",
+			transition: func(s *services.ParentLifecycleService) error {
+				_, err := s.Discharge(tenantContext(), admin, "missing", "")
+				return err
+			},
+			expectedErr: services.ErrParentNotFound,
+		},
+		{
+			name: "reason_too_long",
+			transition: func(s *services.ParentLifecycleService) error {
+				_, err := s.Discharge(tenantContext(), admin, "parent-maternity", strings.Repeat("x", 501))
+				return err
+			},
+			expectedErr: services.ErrInvalidStatusReason,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, userRepo, _, sessions := newParentLifecycleService()
+
+			if err := tt.transition(service); !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected %v, got %v", tt.expectedErr, err)
+			}
+			if len(userRepo.ChangeStatusCalls) != 0 {
+				t.Errorf("expected no ChangeParentStatus calls, got %d", len(userRepo.ChangeStatusCalls))
+			}
+			if len(sessions.RevokedSessions) != 0 {
+				t.Errorf("expected no revoked sessions, got %v", sessions.RevokedSessions)
+			}
+		})
+	}
+}
+
+// TestParentLifecycleService_Archive verifies a discharged parent can be archived but not readmitted afterwards.
+func TestParentLifecycleService_Archive(t *testing.T) {
+	service, _, _, _ := newParentLifecycleService()
+	admin := domain.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+
+	if _, err := service.Discharge(tenantContext(), admin, "parent-maternity", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.Archive(tenantContext(), admin, "parent-maternity", "stay closed"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := service.Readmit(tenantContext(), admin, "parent-maternity", ""); !errors.Is(err, services.ErrInvalidTransition) {
+		t.Errorf("expected %v, got %v", services.ErrInvalidTransition, err)
+	}
+}
+
+// TestParentHandler_ChangeStatus tests the status codes of the lifecycle endpoints.
+func TestParentHandler_ChangeStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		role           domain.Role
+		userID         string
+		body           string
+		expectedStatus int
+	}{
+		{name: "discharge", path: "/discharge", role: domain.RoleNurse, userID: "nurse-1",
+			body: `{"parent_id": "parent-maternity"}`, expectedStatus: http.StatusOK},
+		{name: "discharge_outside_ward", path: "/discharge", role: domain.RoleNurse, userID: "nurse-1",
+			body: `{"parent_id": "parent-neonatal"}`, expectedStatus: http.StatusForbidden},
+		{name: "readmit_active_parent", path: "/parents/parent-maternity/readmit", role: domain.RoleAdmin,
+			userID: "admin-1", expectedStatus: http.StatusConflict},
+		{name: "archive_unknown_parent", path: "/parents/missing/archive", role: domain.RoleAdmin,
+			userID: "admin-1", expectedStatus: http.StatusNotFound},
+		{name: "activate_invited_parent", path: "/parents/parent-invited/activate", role: domain.RoleAdmin,
+			userID: "admin-1", expectedStatus: http.StatusConflict},
+		{name: "activate_uninvited_parent", path: "/parents/parent-uninvited/activate", role: domain.RoleAdmin,
+			userID: "admin-1", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, userRepo, _, _ := newParentLifecycleService()
+			invited := domain.Parent{
+				User:       domain.User{ID: "parent-invited", Email: "invited@example.com", Role: domain.RoleParent},
+				RoomNumber: "101",
+				Status:     domain.ParentPending,
+			}
+			invitation := domain.Invitation{ID: "invitation-1", UserID: invited.ID, Email: invited.Email, Status: domain.InvitationPending}
+			_ = userRepo.CreateParentInvitation(tenantContext(), invited, invitation, nil)
+			userRepo.SeedParent(&domain.Parent{
+				User:       domain.User{ID: "parent-uninvited", Email: "uninvited@example.com", Role: domain.RoleParent},
+				RoomNumber: "101",
+				Status:     domain.ParentPending,
+			})
+
+			invitations := newInvitationService(userRepo, mocks.NewMockMailer(), time.Hour)
+			h := handler.NewParentHandler(nil, service, invitations, services.NewWardPolicy(userRepo, userRepo))
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /parents/{parentID}/activate", h.Activate)
+			mux.HandleFunc("POST /discharge", h.DischargeParent)
+			mux.HandleFunc("POST /parents/{parentID}/readmit", h.Readmit)
+			mux.HandleFunc("POST /parents/{parentID}/archive", h.Archive)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			ctx := context.WithValue(req.Context(), middleware.UserIDKey, tt.userID)
+			ctx = context.WithValue(ctx, middleware.RoleKey, string(tt.role))
+			req = req.WithContext(ctx)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/handler"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/test/mocks"
+)
+
+// TestValidationError_Is verifies a collected validation error still matches
+// each single-field error it contains, and the ErrValidation family.
+func TestValidationError_Is(t *testing.T) {
+	var v domain.Validation
+	v.Append(services.ErrInvalidEmail)
+	v.Append(services.ErrMissingRoom)
+	err := v.Err()
+
+	if !errors.Is(err, domain.ErrValidation) || !errors.Is(err, services.ErrInvalidEmail) || !errors.Is(err, services.ErrMissingRoom) {
+		t.Errorf("expected %v to match its fields", err)
+	}
+	if errors.Is(err, services.ErrMissingWard) {
+		t.Error("expected no match for a field that was not rejected")
+	}
+	if err.Error() != "email is not a valid address; room_number is required" {
+		t.Errorf("unexpected message: %q", err.Error())
+	}
+
+	var empty domain.Validation
+	if empty.Err() != nil {
+		t.Error("expected no error without rejected fields")
+	}
+}
+
+// TestNotAdmitted_Discharged verifies services report a discharged parent as domain.ErrDischarged.
+func TestNotAdmitted_Discharged(t *testing.T) {
+	mockRepo := newWardRepository()
+	_ = mockRepo.ChangeParentStatus(context.Background(), domain.ParentStatusChange{
+		ParentID: "parent-neonatal", FromStatus: domain.ParentActive, ToStatus: domain.ParentDischarged,
+	}, nil)
+	service := services.NewTransferService(mockRepo, mockRepo)
+
+	_, err := service.Transfer(tenantContext(), domain.Actor{ID: "admin-1", Role: domain.RoleAdmin}, "parent-neonatal", "101", "")
+	if !errors.Is(err, services.ErrParentNotActive) || !errors.Is(err, domain.ErrDischarged) {
+		t.Errorf("expected %v and %v, got %v", services.ErrParentNotActive, domain.ErrDischarged, err)
+	}
+}
+
+// TestRegistrationHandler_Problems tests that rejected registrations are
+// problem responses with the right status and field details.
+func TestRegistrationHandler_Problems(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*mocks.MockUserRepository)
+		expectedStatus int
+		expectedFields []string
+	}{
+		{
+			name:           "empty_email",
+			body:           `{"email":"","role":"ADMIN","first_name":"Ada","last_name":"Admin"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []string{"email"},
+		},
+		{
+			name:           "parent_without_room",
+			body:           `{"email":"not-an-email","role":"PARENT","first_name":"","last_name":"Doe"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []string{"email", "name", "room_number"},
+		},
+		{
+			name:           "staff_without_ward",
+			body:           `{"email":"nurse@example.com","role":"NURSE","first_name":"Nina","last_name":"Nurse"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []string{"ward"},
+		},
+		{
+			name:           "unknown_field",
+			body:           `{"email":"parent@example.com","role":"PARENT","first_name":"John","last_name":"Doe","room_number":"101","rooom":"102"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []string{"rooom"},
+		},
+		{
+			name:           "unsupported_role",
+			body:           `{"email":"parent@example.com","role":"VISITOR","first_name":"John","last_name":"Doe"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []string{"role"},
+		},
+		{
+			name: "email_taken",
+			body: `{"email":"parent@example.com","role":"PARENT","first_name":"John","last_name":"Doe","room_number":"101"}`,
+			setupMock: func(m *mocks.MockUserRepository) {
+				m.CreateParentError = domain.ErrEmailTaken
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "storage_failure",
+			body: `{"email":"parent@example.com","role":"PARENT","first_name":"John","last_name":"Doe","room_number":"101"}`,
+			setupMock: func(m *mocks.MockUserRepository) {
+				m.CreateParentError = context.DeadlineExceeded
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockUserRepository()
+			if tt.setupMock != nil {
+				tt.setupMock(mockRepo)
+			}
+			service := newRegistrationService(mockRepo)
+			h := handler.NewRegistrationHandler(service, services.NewWardPolicy(mockRepo, mockRepo), newAdminApprovalService(mockRepo))
+
+			req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewReader([]byte(tt.body)))
+			rec := httptest.NewRecorder()
+			h.Register(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if got := rec.Header().Get("Content-Type"); got != problem.ContentType {
+				t.Errorf("expected Content-Type %q, got %q", problem.ContentType, got)
+			}
+
+			var details problem.Details
+			if err := json.NewDecoder(rec.Body).Decode(&details); err != nil {
+				t.Fatalf("invalid problem: %v", err)
+			}
+			if details.Status != tt.expectedStatus || details.Title != http.StatusText(tt.expectedStatus) || details.Type != "about:blank" {
+				t.Errorf("unexpected problem: %+v", details)
+			}
+			if tt.expectedStatus == http.StatusInternalServerError && details.Detail != "Registration failed" {
+				t.Errorf("expected internal errors to stay hidden, got %q", details.Detail)
+			}
+
+			var fields []string
+			for _, f := range details.Errors {
+				fields = append(fields, f.Field)
+			}
+			if len(fields) != len(tt.expectedFields) {
+				t.Fatalf("expected fields %v, got %v", tt.expectedFields, fields)
+			}
+			for i := range fields {
+				if fields[i] != tt.expectedFields[i] {
+					t.Errorf("expected fields %v, got %v", tt.expectedFields, fields)
+				}
+			}
+			if len(mockRepo.CreateParentCalls)+len(mockRepo.CreateAdminCalls)+len(mockRepo.CreateStaffCalls) > 0 && len(tt.expectedFields) > 0 {
+				t.Error("expected an invalid registration not to reach the repository")
+			}
+		})
+	}
+}
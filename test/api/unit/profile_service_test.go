@@ -0,0 +1,164 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/handler"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/middleware"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/problem"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/test/mocks"
+)
+
+// newProfileRepository seeds parent-1 in room 101
+func newProfileRepository() *mocks.MockUserRepository {
+	repo := mocks.NewMockUserRepository()
+	repo.SeedParent(&domain.Parent{
+		User:       domain.User{ID: "parent-1", Email: "parent@example.com", Role: domain.RoleParent, FirstName: "Pat", LastName: "Doe", CreatedAt: time.Now()},
+		RoomNumber: "101",
+		Status:     domain.ParentActive,
+	})
+	return repo
+}
+
+// TestProfileService_Update verifies a user changes their own display name,
+// locale and contact email, with a user.updated event carrying the change.
+func TestProfileService_Update(t *testing.T) {
+	repo := newProfileRepository()
+	service := services.NewProfileService(repo)
+
+	record, err := service.Update(tenantContext(), "parent-1", 1, domain.ProfileUpdate{
+		DisplayName:  stringPtr(" Pat "),
+		Locale:       stringPtr("nl-BE"),
+		ContactEmail: stringPtr("pat@example.org"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.DisplayName != "Pat" || record.Locale != domain.LocaleDutch || record.ContactEmail != "pat@example.org" || record.Version != 2 {
+		t.Errorf("unexpected profile: %+v", record)
+	}
+	if record.Email != "parent@example.com" || record.Parent.RoomNumber != "101" {
+		t.Errorf("expected the clinic's fields unchanged, got %+v", record)
+	}
+
+	var event ports.UserUpdatedEvent
+	if err := json.Unmarshal(repo.OutboxPayloads[0], &event); err != nil {
+		t.Fatalf("invalid outbox payload: %v", err)
+	}
+	if strings.Join(event.ChangedFields, ",") != "display_name,locale,contact_email" || event.Locale != domain.LocaleDutch {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	// An empty value clears the field
+	record, err = service.Update(tenantContext(), "parent-1", 2, domain.ProfileUpdate{Locale: stringPtr("")})
+	if err != nil || record.Locale != "" {
+		t.Errorf("expected the locale cleared, got %+v, %v", record, err)
+	}
+
+	_, err = service.Update(tenantContext(), "parent-1", 3, domain.ProfileUpdate{
+		DisplayName:  stringPtr(strings.Repeat("a", 101)),
+		Locale:       stringPtr("fr"),
+		ContactEmail: stringPtr("not-an-email"),
+	})
+	for _, want := range []error{services.ErrInvalidDisplayName, services.ErrUnsupportedLocale, services.ErrInvalidContactEmail} {
+		if !errors.Is(err, want) {
+			t.Errorf("expected %v, got %v", want, err)
+		}
+	}
+	if _, err := service.Update(tenantContext(), "parent-1", 1, domain.ProfileUpdate{DisplayName: stringPtr("Pat")}); !errors.Is(err, domain.ErrVersionConflict) {
+		t.Errorf("expected %v, got %v", domain.ErrVersionConflict, err)
+	}
+}
+
+// TestProfileHandler_LockedFields verifies fields maintained by the clinic are
+// rejected, in the caller's language.
+func TestProfileHandler_LockedFields(t *testing.T) {
+	h := handler.NewProfileHandler(services.NewProfileService(newProfileRepository()))
+
+	req := httptest.NewRequest(http.MethodPatch, "/me/profile", strings.NewReader(`{"room_number":"201"}`))
+	req.Header.Set("If-Match", `"1"`)
+	req.Header.Set("Accept-Language", "nl-NL,nl;q=0.9,en;q=0.8")
+	req = req.WithContext(context.WithValue(tenantContext(), middleware.UserIDKey, "parent-1"))
+	rec := httptest.NewRecorder()
+	middleware.LocaleMiddleware(http.HandlerFunc(h.UpdateProfile)).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusBadRequest, rec.Code, rec.Body.String())
+	}
+	var p problem.Details
+	if err := json.NewDecoder(rec.Body).Decode(&p); err != nil {
+		t.Fatalf("invalid problem: %v", err)
+	}
+	if len(p.Errors) != 1 || p.Errors[0].Field != "room_number" || !strings.HasPrefix(p.Errors[0].Message, "dit veld wordt door de kliniek beheerd") {
+		t.Errorf("expected a Dutch locked field error, got %+v", p)
+	}
+	if rec.Header().Get("Content-Language") != domain.LocaleDutch {
+		t.Errorf("expected Content-Language nl, got %q", rec.Header().Get("Content-Language"))
+	}
+}
+
+// TestProblem_Localize verifies problems are translated to the writer's
+// locale, that the token's locale wins, and that shared errors stay English.
+func TestProblem_Localize(t *testing.T) {
+	if got := middleware.MatchLocale("fr-FR, en;q=0.5, nl;q=0.8"); got != domain.LocaleDutch {
+		t.Errorf("expected nl, got %q", got)
+	}
+	if got := middleware.MatchLocale("fr, nl;q=0"); got != domain.DefaultLocale {
+		t.Errorf("expected the default locale, got %q", got)
+	}
+
+	rec := httptest.NewRecorder()
+	w := problem.WithLocale(rec, domain.LocaleEnglish)
+	problem.SetLocale(w, "nl-NL")
+	problem.Validation(w, services.ErrInvalidEmail)
+
+	var p problem.Details
+	if err := json.NewDecoder(rec.Body).Decode(&p); err != nil {
+		t.Fatalf("invalid problem: %v", err)
+	}
+	if p.Title != "Ongeldig verzoek" || p.Detail != "e-mailadres is geen geldig adres" || p.Errors[0].Message != p.Detail {
+		t.Errorf("unexpected problem: %+v", p)
+	}
+	if services.ErrInvalidEmail.Fields[0].Message != "email is not a valid address" {
+		t.Errorf("expected the shared error untouched, got %+v", services.ErrInvalidEmail)
+	}
+
+	// Without a translation the English message is kept
+	rec = httptest.NewRecorder()
+	problem.Error(problem.WithLocale(rec, domain.LocaleDutch), "some new failure", http.StatusConflict)
+	if !strings.Contains(rec.Body.String(), "some new failure") {
+		t.Errorf("expected the English detail, got %s", rec.Body.String())
+	}
+}
+
+// TestInvitationService_LocalizedMail verifies the invitation is written in
+// the locale the parent was registered with.
+func TestInvitationService_LocalizedMail(t *testing.T) {
+	repo := mocks.NewMockUserRepository()
+	mailer := mocks.NewMockMailer()
+	service := services.NewRegistrationService(repo, mocks.NewSeededPermissionRepository(), newInvitationService(repo, mailer, time.Hour))
+
+	if _, err := service.RegisterParent(tenantContext(), "ouder@example.com", "Sanne", "de Vries", "101", "nl"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mailer.Sent) != 1 || !strings.HasPrefix(mailer.Sent[0].Body, "Hallo Sanne") {
+		t.Errorf("expected a Dutch invitation, got %+v", mailer.Sent)
+	}
+	if repo.CreateParentCalls[0].Locale != domain.LocaleDutch {
+		t.Errorf("expected the parent's locale stored, got %q", repo.CreateParentCalls[0].Locale)
+	}
+
+	if _, err := service.RegisterParent(tenantContext(), "other@example.com", "Alex", "Doe", "101", "xx"); !errors.Is(err, services.ErrUnsupportedLocale) {
+		t.Errorf("expected %v, got %v", services.ErrUnsupportedLocale, err)
+	}
+}
@@ -31,8 +31,8 @@ import (
 func TestRegistrationHandler_Register_Success(t *testing.T) {
 	// ARRANGE
 	mockRepo := mocks.NewMockUserRepository()
-	service := services.NewRegistrationService(mockRepo)
-	h := handler.NewRegistrationHandler(service)
+	service := newRegistrationService(mockRepo)
+	h := handler.NewRegistrationHandler(service, services.NewWardPolicy(mockRepo, mockRepo), newAdminApprovalService(mockRepo))
 
 	// Create request body
 	body := map[string]string{
@@ -45,7 +45,7 @@ func TestRegistrationHandler_Register_Success(t *testing.T) {
 	jsonBody, _ := json.Marshal(body)
 
 	// Create HTTP request
-	req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewReader(jsonBody))
+	req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewReader(jsonBody)).WithContext(tenantContext())
 	req.Header.Set("Content-Type", "application/json")
 
 	// Create response recorder
@@ -64,7 +64,7 @@ func TestRegistrationHandler_Register_Success(t *testing.T) {
 		t.Fatalf("failed to decode response: %v", err)
 	}
 
-	if response["message"] != "Parent registered successfully" {
+	if response["message"] != "Parent registered successfully; invitation sent" {
 		t.Errorf("unexpected message: %s", response["message"])
 	}
 
@@ -77,8 +77,9 @@ func TestRegistrationHandler_Register_Success(t *testing.T) {
 // TestRegistrationHandler_Register_AdminRole tests admin registration.
 func TestRegistrationHandler_Register_AdminRole(t *testing.T) {
 	mockRepo := mocks.NewMockUserRepository()
-	service := services.NewRegistrationService(mockRepo)
-	h := handler.NewRegistrationHandler(service)
+	seedAdmins(mockRepo, 2)
+	service := newRegistrationService(mockRepo)
+	h := handler.NewRegistrationHandler(service, services.NewWardPolicy(mockRepo, mockRepo), newAdminApprovalService(mockRepo))
 
 	body := map[string]string{
 		"email":      "admin@baby-kliniek.nl",
@@ -94,20 +95,25 @@ func TestRegistrationHandler_Register_AdminRole(t *testing.T) {
 
 	h.Register(rec, req)
 
-	if rec.Code != http.StatusCreated {
-		t.Errorf("expected status %d, got %d", http.StatusCreated, rec.Code)
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("expected status %d, got %d", http.StatusAccepted, rec.Code)
 	}
 
-	if len(mockRepo.CreateAdminCalls) != 1 {
-		t.Errorf("expected 1 CreateAdmin call, got %d", len(mockRepo.CreateAdminCalls))
+	// The admin only exists once another admin approves
+	if len(mockRepo.CreateAdminCalls) != 0 {
+		t.Errorf("expected no admin to be created yet, got %d", len(mockRepo.CreateAdminCalls))
+	}
+	var resp handler.RegistrationResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || resp.ApprovalID == "" {
+		t.Errorf("expected an approval id, got %+v, %v", resp, err)
 	}
 }
 
 // TestRegistrationHandler_Register_InvalidMethod tests method not allowed.
 func TestRegistrationHandler_Register_InvalidMethod(t *testing.T) {
 	mockRepo := mocks.NewMockUserRepository()
-	service := services.NewRegistrationService(mockRepo)
-	h := handler.NewRegistrationHandler(service)
+	service := newRegistrationService(mockRepo)
+	h := handler.NewRegistrationHandler(service, services.NewWardPolicy(mockRepo, mockRepo), newAdminApprovalService(mockRepo))
 
 	// Test with GET instead of POST
 	req := httptest.NewRequest(http.MethodGet, "/register", nil)
@@ -123,8 +129,8 @@ func TestRegistrationHandler_Register_InvalidMethod(t *testing.T) {
 // TestRegistrationHandler_Register_InvalidJSON tests malformed JSON handling.
 func TestRegistrationHandler_Register_InvalidJSON(t *testing.T) {
 	mockRepo := mocks.NewMockUserRepository()
-	service := services.NewRegistrationService(mockRepo)
-	h := handler.NewRegistrationHandler(service)
+	service := newRegistrationService(mockRepo)
+	h := handler.NewRegistrationHandler(service, services.NewWardPolicy(mockRepo, mockRepo), newAdminApprovalService(mockRepo))
 
 	// Invalid JSON
 	req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewReader([]byte("not json")))
@@ -141,8 +147,8 @@ func TestRegistrationHandler_Register_InvalidJSON(t *testing.T) {
 // TestRegistrationHandler_Register_UnsupportedRole tests invalid role handling.
 func TestRegistrationHandler_Register_UnsupportedRole(t *testing.T) {
 	mockRepo := mocks.NewMockUserRepository()
-	service := services.NewRegistrationService(mockRepo)
-	h := handler.NewRegistrationHandler(service)
+	service := newRegistrationService(mockRepo)
+	h := handler.NewRegistrationHandler(service, services.NewWardPolicy(mockRepo, mockRepo), newAdminApprovalService(mockRepo))
 
 	body := map[string]string{
 		"email":      "user@example.com",
@@ -167,8 +173,8 @@ func TestRegistrationHandler_Register_UnsupportedRole(t *testing.T) {
 func TestRegistrationHandler_Register_DatabaseError(t *testing.T) {
 	mockRepo := mocks.NewMockUserRepository()
 	mockRepo.CreateParentError = context.DeadlineExceeded
-	service := services.NewRegistrationService(mockRepo)
-	h := handler.NewRegistrationHandler(service)
+	service := newRegistrationService(mockRepo)
+	h := handler.NewRegistrationHandler(service, services.NewWardPolicy(mockRepo, mockRepo), newAdminApprovalService(mockRepo))
 
 	body := map[string]string{
 		"email":       "parent@example.com",
@@ -193,8 +199,8 @@ func TestRegistrationHandler_Register_DatabaseError(t *testing.T) {
 // TestRegistrationHandler_ContentTypeValidation ensures Content-Type is set correctly.
 func TestRegistrationHandler_ContentTypeValidation(t *testing.T) {
 	mockRepo := mocks.NewMockUserRepository()
-	service := services.NewRegistrationService(mockRepo)
-	h := handler.NewRegistrationHandler(service)
+	service := newRegistrationService(mockRepo)
+	h := handler.NewRegistrationHandler(service, services.NewWardPolicy(mockRepo, mockRepo), newAdminApprovalService(mockRepo))
 
 	body := map[string]string{
 		"email":       "parent@example.com",
@@ -38,7 +38,6 @@ import (
 	"time"
 
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
-	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/test/mocks"
 )
 
@@ -67,7 +66,7 @@ func TestRegistrationService_RegisterParent(t *testing.T) {
 			lastName:    "Doe",
 			roomNumber:  "101",
 			setupMock:   func(m *mocks.MockUserRepository) {},
-			expectedMsg: "Parent registered successfully",
+			expectedMsg: "Parent registered successfully; invitation sent",
 			expectError: false,
 		},
 		{
@@ -93,11 +92,11 @@ func TestRegistrationService_RegisterParent(t *testing.T) {
 
 			// The service receives the mock through dependency injection
 			// The service doesn't know (or care) if it's talking to a real database
-			service := services.NewRegistrationService(mockRepo)
+			service := newRegistrationService(mockRepo)
 
 			// ACT: Execute the method under test
-			ctx := context.Background()
-			msg, err := service.RegisterParent(ctx, tt.email, tt.firstName, tt.lastName, tt.roomNumber)
+			ctx := tenantContext()
+			msg, err := service.RegisterParent(ctx, tt.email, tt.firstName, tt.lastName, tt.roomNumber, "")
 
 			// ASSERT: Verify results
 			if tt.expectError {
@@ -122,72 +121,13 @@ func TestRegistrationService_RegisterParent(t *testing.T) {
 	}
 }
 
-// TestRegistrationService_RegisterAdmin tests admin registration.
-func TestRegistrationService_RegisterAdmin(t *testing.T) {
-	tests := []struct {
-		name        string
-		email       string
-		firstName   string
-		lastName    string
-		setupMock   func(*mocks.MockUserRepository)
-		expectedMsg string
-		expectError bool
-	}{
-		{
-			name:        "successful_admin_registration",
-			email:       "admin@baby-kliniek.nl",
-			firstName:   "Admin",
-			lastName:    "User",
-			setupMock:   func(m *mocks.MockUserRepository) {},
-			expectedMsg: "Admin registered successfully",
-			expectError: false,
-		},
-		{
-			name:      "registration_fails_on_duplicate_email",
-			email:     "existing@baby-kliniek.nl",
-			firstName: "Admin",
-			lastName:  "User",
-			setupMock: func(m *mocks.MockUserRepository) {
-				m.CreateAdminError = context.DeadlineExceeded
-			},
-			expectedMsg: "Registration failed",
-			expectError: true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			mockRepo := mocks.NewMockUserRepository()
-			tt.setupMock(mockRepo)
-			service := services.NewRegistrationService(mockRepo)
-
-			ctx := context.Background()
-			msg, err := service.RegisterAdmin(ctx, tt.email, tt.firstName, tt.lastName)
-
-			if tt.expectError {
-				if err == nil {
-					t.Errorf("expected error but got none")
-				}
-			} else {
-				if err != nil {
-					t.Errorf("unexpected error: %v", err)
-				}
-			}
-
-			if msg != tt.expectedMsg {
-				t.Errorf("expected message %q, got %q", tt.expectedMsg, msg)
-			}
-		})
-	}
-}
-
 // TestRegistrationService_ParentData verifies that parent data is correctly populated.
 func TestRegistrationService_ParentData(t *testing.T) {
 	mockRepo := mocks.NewMockUserRepository()
-	service := services.NewRegistrationService(mockRepo)
+	service := newRegistrationService(mockRepo)
 
 	ctx := context.Background()
-	_, err := service.RegisterParent(ctx, "test@example.com", "Jane", "Smith", "202")
+	_, err := service.RegisterParent(ctx, "test@example.com", "Jane", "Smith", "202", "")
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -216,8 +156,9 @@ func TestRegistrationService_ParentData(t *testing.T) {
 	if createdParent.Role != domain.RoleParent {
 		t.Errorf("expected role 'PARENT', got %q", createdParent.Role)
 	}
-	if createdParent.Status != domain.ParentActive {
-		t.Errorf("expected status 'Active', got %q", createdParent.Status)
+	// Invited parents become active once they accept
+	if createdParent.Status != domain.ParentPending {
+		t.Errorf("expected status 'Pending', got %q", createdParent.Status)
 	}
 	if createdParent.ID == "" {
 		t.Error("expected non-empty ID")
@@ -231,12 +172,12 @@ func TestRegistrationService_ParentData(t *testing.T) {
 func TestRegistrationService_ContextCancellation(t *testing.T) {
 	mockRepo := mocks.NewMockUserRepository()
 	mockRepo.CreateParentError = context.Canceled
-	service := services.NewRegistrationService(mockRepo)
+	service := newRegistrationService(mockRepo)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel() // Cancel immediately
 
-	_, err := service.RegisterParent(ctx, "test@example.com", "John", "Doe", "101")
+	_, err := service.RegisterParent(ctx, "test@example.com", "John", "Doe", "101", "")
 
 	if err == nil {
 		t.Error("expected error due to cancelled context")
@@ -246,7 +187,7 @@ func TestRegistrationService_ContextCancellation(t *testing.T) {
 // TestRegistrationService_ConcurrentRegistrations verifies thread safety.
 func TestRegistrationService_ConcurrentRegistrations(t *testing.T) {
 	mockRepo := mocks.NewMockUserRepository()
-	service := services.NewRegistrationService(mockRepo)
+	service := newRegistrationService(mockRepo)
 
 	ctx := context.Background()
 	const numGoroutines = 10
@@ -255,7 +196,7 @@ func TestRegistrationService_ConcurrentRegistrations(t *testing.T) {
 
 	for i := 0; i < numGoroutines; i++ {
 		go func(n int) {
-			_, err := service.RegisterParent(ctx, "test@example.com", "Test", "User", "101")
+			_, err := service.RegisterParent(ctx, "test@example.com", "Test", "User", "101", "")
 			if err != nil {
 				t.Errorf("goroutine %d: unexpected error: %v", n, err)
 			}
@@ -0,0 +1,149 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/handler"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/test/mocks"
+)
+
+var retentionPolicy = domain.RetentionPolicy{
+	ParentAnonymizeAfter: 30 * 24 * time.Hour,
+	OutboxRetention:      30 * 24 * time.Hour,
+	BatchSize:            2,
+}
+
+// newRetentionService seeds parents discharged 100 and 5 days ago, an active
+// parent, three old and one recent processed outbox events, and an expired and
+// a downloadable subject access archive
+func newRetentionService(t *testing.T, policy domain.RetentionPolicy) (*services.RetentionService, *mocks.MockUserRepository, *mocks.MockRetentionMetrics) {
+	t.Helper()
+	_, userRepo, visitorRepo, sessions := newUserAdminService()
+	now := time.Now()
+
+	for id, dischargedDaysAgo := range map[string]int{"parent-old": 100, "parent-recent": 5} {
+		userRepo.SeedParent(&domain.Parent{
+			User:   domain.User{ID: id, Email: id + "@example.com", Role: domain.RoleParent, FirstName: "Jane", LastName: "Doe"},
+			Status: domain.ParentActive,
+		})
+		if err := userRepo.ChangeParentStatus(tenantContext(), domain.ParentStatusChange{
+			ParentID: id, FromStatus: domain.ParentActive, ToStatus: domain.ParentDischarged,
+			ChangedAt: now.AddDate(0, 0, -dischargedDaysAgo),
+		}, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	old := now.AddDate(0, 0, -60)
+	userRepo.ProcessedOutboxEvents = []time.Time{old, old, old, now}
+	userRepo.SubjectAccessArchives = []time.Time{now.Add(-time.Hour), now.Add(24 * time.Hour)}
+
+	erasure := services.NewErasureService(userRepo, visitorRepo, sessions)
+	metrics := &mocks.MockRetentionMetrics{}
+	return services.NewRetentionService(policy, userRepo, erasure, metrics, []string{"test-clinic"}), userRepo, metrics
+}
+
+// TestRetentionService_Report verifies the dry run counts what is due without changing anything.
+func TestRetentionService_Report(t *testing.T) {
+	service, userRepo, _ := newRetentionService(t, retentionPolicy)
+
+	report, err := service.Report(tenantContext())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !report.DryRun || report.TenantID != "test-clinic" || len(report.Rules) != 3 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if report.Rules[0].Due != 1 || report.Rules[1].Due != 3 || report.Rules[2].Due != 1 {
+		t.Errorf("unexpected due counts: %+v", report.Rules)
+	}
+	if len(userRepo.EraseUserCalls) != 0 || len(userRepo.ProcessedOutboxEvents) != 4 || len(userRepo.SubjectAccessArchives) != 2 {
+		t.Error("expected a dry run to change nothing")
+	}
+}
+
+// TestRetentionService_Run verifies old parents are erased and old events deleted in batches.
+func TestRetentionService_Run(t *testing.T) {
+	service, userRepo, metrics := newRetentionService(t, retentionPolicy)
+
+	reports, err := service.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reports) != 1 || reports[0].DryRun {
+		t.Fatalf("unexpected reports: %+v", reports)
+	}
+	parents, outbox, archives := reports[0].Rules[0], reports[0].Rules[1], reports[0].Rules[2]
+	if parents.Applied != 1 || parents.Failed != 0 || outbox.Applied != 3 || archives.Applied != 1 {
+		t.Errorf("unexpected rules: %+v", reports[0].Rules)
+	}
+
+	if len(userRepo.EraseUserCalls) != 1 || userRepo.EraseUserCalls[0].UserID != "parent-old" ||
+		userRepo.EraseUserCalls[0].RequestedBy != domain.RetentionActor {
+		t.Errorf("unexpected erasures: %+v", userRepo.EraseUserCalls)
+	}
+	if len(userRepo.ProcessedOutboxEvents) != 1 {
+		t.Errorf("expected the recent event to be kept, got %d events", len(userRepo.ProcessedOutboxEvents))
+	}
+	if len(userRepo.SubjectAccessArchives) != 1 {
+		t.Errorf("expected the downloadable archive to be kept, got %d archives", len(userRepo.SubjectAccessArchives))
+	}
+	if len(metrics.Reports) != 1 {
+		t.Errorf("expected the run to be observed, got %d reports", len(metrics.Reports))
+	}
+
+	// A second run finds nothing left to do
+	reports, _ = service.Run(context.Background())
+	if reports[0].Rules[0].Applied != 0 || reports[0].Rules[1].Applied != 0 || reports[0].Rules[2].Applied != 0 {
+		t.Errorf("expected nothing to do, got %+v", reports[0].Rules)
+	}
+}
+
+// TestRetentionService_Run_Locked verifies only one replica applies the rules at a time.
+func TestRetentionService_Run_Locked(t *testing.T) {
+	service, userRepo, metrics := newRetentionService(t, retentionPolicy)
+	userRepo.RetentionLocked = true
+
+	if _, err := service.Run(context.Background()); !errors.Is(err, services.ErrRetentionInProgress) {
+		t.Errorf("expected %v, got %v", services.ErrRetentionInProgress, err)
+	}
+	if metrics.Skipped != 1 || len(userRepo.EraseUserCalls) != 0 || len(userRepo.ProcessedOutboxEvents) != 4 {
+		t.Error("expected the run to be skipped")
+	}
+}
+
+// TestRetentionService_Run_Disabled verifies a zero period disables its rule.
+func TestRetentionService_Run_Disabled(t *testing.T) {
+	service, userRepo, _ := newRetentionService(t, domain.RetentionPolicy{OutboxRetention: retentionPolicy.OutboxRetention, BatchSize: 10})
+
+	reports, err := service.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reports[0].Rules[0].Enabled || reports[0].Rules[0].Cutoff != nil {
+		t.Errorf("expected the parent rule to be disabled, got %+v", reports[0].Rules[0])
+	}
+	if len(userRepo.EraseUserCalls) != 0 {
+		t.Error("expected no erasure")
+	}
+}
+
+// TestRetentionHandler_Report tests GET /retention/report.
+func TestRetentionHandler_Report(t *testing.T) {
+	service, _, _ := newRetentionService(t, retentionPolicy)
+	h := handler.NewRetentionHandler(service)
+
+	req := httptest.NewRequest(http.MethodGet, "/retention/report", nil).WithContext(tenantContext())
+	rec := httptest.NewRecorder()
+	h.Report(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+}
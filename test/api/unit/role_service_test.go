@@ -0,0 +1,191 @@
+package unit
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/middleware"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/test/mocks"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// TestRoleService_CreateRole tests role creation and its validation rules.
+func TestRoleService_CreateRole(t *testing.T) {
+	tests := []struct {
+		name        string
+		roleName    domain.Role
+		permissions []domain.Permission
+		expectedErr error
+	}{
+		{
+			name:        "valid_role",
+			roleName:    "NURSE",
+			permissions: []domain.Permission{domain.PermParentsDischarge},
+		},
+		{
+			name:        "lower_case_name",
+			roleName:    "nurse",
+			expectedErr: services.ErrInvalidRoleName,
+		},
+		{
+			name:        "unknown_permission",
+			roleName:    "NURSE",
+			permissions: []domain.Permission{"babies:feed"},
+			expectedErr: services.ErrUnknownPermission,
+		},
+		{
+			name:        "admin_only_permission",
+			roleName:    "WARD_MANAGER",
+			permissions: []domain.Permission{domain.PermRolesManage},
+			expectedErr: services.ErrAdminOnlyPermission,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockPermissionRepository(domain.PermParentsDischarge, domain.PermSessionLogout, domain.PermRolesManage)
+			service := services.NewRoleService(repo)
+
+			role, err := service.CreateRole(context.Background(), tt.roleName, "", tt.permissions, false)
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if role.Version != 1 {
+				t.Errorf("expected version 1, got %d", role.Version)
+			}
+		})
+	}
+}
+
+// TestRoleService_SetRolePermissions verifies mapping updates bump the role version.
+func TestRoleService_SetRolePermissions(t *testing.T) {
+	repo := mocks.NewMockPermissionRepository(domain.PermParentsDischarge, domain.PermSessionLogout)
+	repo.SeedRole(domain.RoleDefinition{Name: "NURSE", Version: 1})
+	service := services.NewRoleService(repo)
+
+	role, err := service.SetRolePermissions(context.Background(), "NURSE",
+		[]domain.Permission{domain.PermParentsDischarge, domain.PermSessionLogout})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if role.Version != 2 {
+		t.Errorf("expected version 2, got %d", role.Version)
+	}
+
+	_, err = service.SetRolePermissions(context.Background(), "MIDWIFE", nil)
+	if !errors.Is(err, services.ErrRoleNotFound) {
+		t.Errorf("expected %v, got %v", services.ErrRoleNotFound, err)
+	}
+}
+
+// TestRoleService_SetRolePermissions_AdminSafeguards verifies a single admin can
+// neither strip the ADMIN role nor hand admin-only permissions to another role.
+func TestRoleService_SetRolePermissions_AdminSafeguards(t *testing.T) {
+	repo := mocks.NewMockPermissionRepository(domain.PermAdminsApprove, domain.PermRolesManage, domain.PermSessionLogout)
+	repo.SeedRole(domain.RoleDefinition{Name: domain.RoleAdmin, Permissions: []domain.Permission{domain.PermAdminsApprove}, Version: 1})
+	repo.SeedRole(domain.RoleDefinition{Name: "NURSE", Version: 1})
+	service := services.NewRoleService(repo)
+
+	_, err := service.SetRolePermissions(context.Background(), domain.RoleAdmin, nil)
+	if !errors.Is(err, services.ErrAdminRoleProtected) {
+		t.Errorf("expected %v, got %v", services.ErrAdminRoleProtected, err)
+	}
+
+	for _, permission := range []domain.Permission{domain.PermAdminsApprove, domain.PermRolesManage} {
+		_, err = service.SetRolePermissions(context.Background(), "NURSE", []domain.Permission{domain.PermSessionLogout, permission})
+		if !errors.Is(err, services.ErrAdminOnlyPermission) {
+			t.Errorf("expected %v for %s, got %v", services.ErrAdminOnlyPermission, permission, err)
+		}
+	}
+
+	admin, _ := repo.GetRole(context.Background(), domain.RoleAdmin)
+	nurse, _ := repo.GetRole(context.Background(), "NURSE")
+	if len(admin.Permissions) != 1 || len(nurse.Permissions) != 0 {
+		t.Errorf("expected the roles to be unchanged, got %+v and %+v", admin, nurse)
+	}
+}
+
+// TestTokenIssuer_EmbedsPermissions verifies issued tokens carry the role's permission set.
+func TestTokenIssuer_EmbedsPermissions(t *testing.T) {
+	repo := mocks.NewMockPermissionRepository()
+	repo.SeedRole(domain.RoleDefinition{
+		Name:        domain.RoleParent,
+		Permissions: []domain.Permission{domain.PermVisitorsManage, domain.PermSessionLogout},
+		Version:     3,
+	})
+	_, key := newTestTokenIssuer(t)
+	issuer := services.NewTokenIssuer(key, nil, repo, nil)
+
+	issued, err := issuer.Issue(tenantContext(), jwt.MapClaims{
+		"sub":  "parent-1",
+		"role": string(domain.RoleParent),
+	}, services.TokenDuration)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	token, err := jwt.Parse(issued.Token, func(token *jwt.Token) (any, error) {
+		return &key.PublicKey, nil
+	})
+	if err != nil {
+		t.Fatalf("failed to parse issued token: %v", err)
+	}
+
+	claims := token.Claims.(jwt.MapClaims)
+	perms, ok := claims["perms"].([]interface{})
+	if !ok || len(perms) != 2 || perms[0] != string(domain.PermVisitorsManage) {
+		t.Errorf("unexpected perms claim: %v", claims["perms"])
+	}
+	if claims["role_version"] != float64(3) {
+		t.Errorf("expected role_version 3, got %v", claims["role_version"])
+	}
+}
+
+// TestAuthMiddleware_RejectsOutdatedRoleVersion verifies that a permission
+// change reaches tokens issued before it. The rejections happen before the
+// Redis blacklist lookup, so no Redis is needed.
+func TestAuthMiddleware_RejectsOutdatedRoleVersion(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	repo := mocks.NewMockPermissionRepository(domain.PermParentsDischarge, domain.PermSessionLogout)
+	repo.SeedRole(domain.RoleDefinition{Name: domain.RoleNurse, Permissions: []domain.Permission{domain.PermParentsDischarge}, Version: 1})
+	issuer := services.NewTokenIssuer(key, nil, repo, nil)
+
+	issued, err := issuer.Issue(tenantContext(), jwt.MapClaims{"sub": "nurse-1", "role": string(domain.RoleNurse)}, services.TokenDuration)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := services.NewRoleService(repo).SetRolePermissions(tenantContext(), domain.RoleNurse, []domain.Permission{domain.PermSessionLogout}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	auth := middleware.NewAuthMiddleware(&key.PublicKey, nil, repo)
+	handler := auth.RequirePermission(domain.PermParentsDischarge, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler must not be reached")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/parents/discharge", nil)
+	req.Header.Set("Authorization", "Bearer "+issued.Token)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d for a token of an outdated role version, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
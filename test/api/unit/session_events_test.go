@@ -0,0 +1,64 @@
+package unit
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/test/mocks"
+)
+
+// TestTokenIssuer_LoginEvent verifies every issued session is queued as a
+// user.logged_in event, and that no token is handed out when it cannot be.
+func TestTokenIssuer_LoginEvent(t *testing.T) {
+	_, key := newTestTokenIssuer(t)
+	repo := mocks.NewMockUserRepository()
+	issuer := services.NewTokenIssuer(key, nil, nil, repo)
+	user := domain.User{ID: "parent-1", Email: "parent@example.com", Role: domain.RoleParent}
+
+	issued, err := issuer.IssueSession(tenantContext(), user, ports.LoginInvitation)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.AppendedEvents) != 1 || repo.AppendedEvents[0].Type != ports.EventUserLoggedIn {
+		t.Fatalf("expected a user.logged_in event, got %+v", repo.AppendedEvents)
+	}
+	var event ports.UserLoggedInEvent
+	if err := json.Unmarshal(repo.AppendedEvents[0].Payload, &event); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if event.TenantID != "test-clinic" || event.UserID != "parent-1" || event.Role != "PARENT" ||
+		event.Method != ports.LoginInvitation || event.SessionID != issued.JTI {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	repo.AppendEventError = errors.New("database unavailable")
+	if issued, err := issuer.IssueSession(tenantContext(), user, ports.LoginGoogle); err == nil || issued != nil {
+		t.Errorf("expected no token without a recorded login, got %+v, %v", issued, err)
+	}
+}
+
+// TestTokenIssuer_LogoutEvent verifies a logout is recorded before the token
+// is revoked, and that expired tokens need no event.
+func TestTokenIssuer_LogoutEvent(t *testing.T) {
+	_, key := newTestTokenIssuer(t)
+	repo := mocks.NewMockUserRepository()
+	issuer := services.NewTokenIssuer(key, nil, nil, repo)
+
+	if err := issuer.EndSession(tenantContext(), "parent-1", "jti-1", time.Now().Add(-time.Minute).Unix()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.AppendedEvents) != 0 {
+		t.Errorf("expected no event for an expired token, got %+v", repo.AppendedEvents)
+	}
+
+	// Without Redis the revocation itself would fail; the event must be written first
+	repo.AppendEventError = errors.New("database unavailable")
+	if err := issuer.EndSession(tenantContext(), "parent-1", "jti-1", time.Now().Add(time.Minute).Unix()); !errors.Is(err, repo.AppendEventError) {
+		t.Errorf("expected the event error, got %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/handler"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/middleware"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/repository"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/test/mocks"
+)
+
+func newSubjectAccessService() (*services.SubjectAccessService, *mocks.MockSubjectAccessRepository, *mocks.MockAuditRepository, *mocks.MockSessionRevoker) {
+	_, userRepo, _, sessions := newUserAdminService()
+	requests := mocks.NewMockSubjectAccessRepository()
+	requests.Sections = []domain.SubjectDataSection{
+		{Name: "users", Records: []json.RawMessage{json.RawMessage(`{"id":"parent-1","email":"parent@example.com"}`)}},
+		{Name: "audit_log", Records: []json.RawMessage{}},
+	}
+	auditRepo := mocks.NewMockAuditRepository()
+	return services.NewSubjectAccessService(requests, userRepo, auditRepo, sessions), requests, auditRepo, sessions
+}
+
+// TestSubjectAccessService_Request verifies the archive is generated in the
+// background with a manifest, and that the request and download are audited.
+func TestSubjectAccessService_Request(t *testing.T) {
+	service, _, auditRepo, sessions := newSubjectAccessService()
+	sessions.Sessions = map[string][]domain.SessionRecord{
+		"parent-1": {{Kind: "device", ID: "device-1", DeviceName: "Kitchen tablet", ExpiresAt: time.Now().Add(time.Hour)}},
+	}
+	admin := domain.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+
+	request, err := service.Request(tenantContext(), admin, "parent-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if request.Status != domain.SubjectAccessPending || request.RequestedBy != "admin-1" {
+		t.Errorf("unexpected request: %+v", request)
+	}
+	if err := service.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	request, err = service.Get(tenantContext(), request.ID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if request.Status != domain.SubjectAccessCompleted || request.ExpiresAt == nil {
+		t.Fatalf("expected a completed request with an expiry, got %+v", request)
+	}
+
+	data, err := service.Archive(tenantContext(), admin, request.ID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var archive domain.SubjectAccessArchive
+	if err := json.Unmarshal(data, &archive); err != nil {
+		t.Fatalf("invalid archive: %v", err)
+	}
+	manifest := archive.Manifest
+	if manifest.RequestID != request.ID || manifest.UserID != "parent-1" || manifest.TenantID != "test-clinic" || len(manifest.Notes) == 0 {
+		t.Errorf("unexpected manifest: %+v", manifest)
+	}
+	if len(manifest.Sections) != 3 || manifest.Sections[2].Name != "sessions" || manifest.Sections[2].Records != 1 {
+		t.Errorf("unexpected sections: %+v", manifest.Sections)
+	}
+	if len(archive.Data["users"]) != 1 || len(archive.Data["sessions"]) != 1 {
+		t.Errorf("unexpected data: %v", archive.Data)
+	}
+
+	entries := auditRepo.GetEntries()
+	if len(entries) != 2 || entries[0].Action != domain.AuditSubjectAccessRequested ||
+		entries[1].Action != domain.AuditSubjectAccessDownloaded || entries[1].SubjectID != "parent-1" {
+		t.Errorf("unexpected audit trail: %+v", entries)
+	}
+}
+
+// TestSubjectAccessService_SectionDescriptions verifies every section the
+// repository exports is explained in the manifest.
+func TestSubjectAccessService_SectionDescriptions(t *testing.T) {
+	service, requests, _, _ := newSubjectAccessService()
+	requests.Sections = nil
+	for _, name := range repository.SubjectDataSections() {
+		requests.Sections = append(requests.Sections, domain.SubjectDataSection{Name: name, Records: []json.RawMessage{}})
+	}
+	admin := domain.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+
+	request, err := service.Request(tenantContext(), admin, "parent-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := service.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := service.Archive(tenantContext(), admin, request.ID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var archive domain.SubjectAccessArchive
+	if err := json.Unmarshal(data, &archive); err != nil {
+		t.Fatalf("invalid archive: %v", err)
+	}
+
+	// The sessions section comes from the session store rather than the repository
+	if len(archive.Manifest.Sections) != len(requests.Sections)+1 {
+		t.Fatalf("expected %d sections, got %+v", len(requests.Sections)+1, archive.Manifest.Sections)
+	}
+	for _, section := range archive.Manifest.Sections {
+		if section.Description == "" {
+			t.Errorf("section %q has no description", section.Name)
+		}
+	}
+}
+
+// TestSubjectAccessService_InProgress verifies only one request per user runs
+// at a time, unless the open one was abandoned.
+func TestSubjectAccessService_InProgress(t *testing.T) {
+	admin := domain.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+
+	t.Run("open", func(t *testing.T) {
+		service, requests, _, _ := newSubjectAccessService()
+		requests.SeedSubjectAccessRequest(domain.SubjectAccessRequest{
+			ID: "sar-1", UserID: "parent-1", Status: domain.SubjectAccessRunning, CreatedAt: time.Now(),
+		}, nil)
+
+		if _, err := service.Request(tenantContext(), admin, "parent-1"); !errors.Is(err, domain.ErrSubjectAccessInProgress) {
+			t.Errorf("expected %v, got %v", domain.ErrSubjectAccessInProgress, err)
+		}
+	})
+
+	t.Run("abandoned", func(t *testing.T) {
+		service, requests, _, _ := newSubjectAccessService()
+		requests.SeedSubjectAccessRequest(domain.SubjectAccessRequest{
+			ID: "sar-1", UserID: "parent-1", Status: domain.SubjectAccessRunning, CreatedAt: time.Now().Add(-time.Hour),
+		}, nil)
+
+		if _, err := service.Request(tenantContext(), admin, "parent-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_ = service.Wait(context.Background())
+
+		abandoned, _ := service.Get(tenantContext(), "sar-1", "")
+		if abandoned.Status != domain.SubjectAccessFailed {
+			t.Errorf("expected the abandoned request to be failed, got %s", abandoned.Status)
+		}
+	})
+}
+
+// TestSubjectAccessService_Failed verifies a failed generation is recorded and has no archive.
+func TestSubjectAccessService_Failed(t *testing.T) {
+	service, requests, _, _ := newSubjectAccessService()
+	requests.CollectError = errors.New("database unavailable")
+	admin := domain.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+
+	request, err := service.Request(tenantContext(), admin, "parent-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = service.Wait(context.Background())
+
+	request, _ = service.Get(tenantContext(), request.ID, "")
+	if request.Status != domain.SubjectAccessFailed || request.Error == "" {
+		t.Errorf("expected a failed request with a reason, got %+v", request)
+	}
+	if _, err := service.Archive(tenantContext(), admin, request.ID, ""); !errors.Is(err, domain.ErrSubjectAccessNotReady) {
+		t.Errorf("expected %v, got %v", domain.ErrSubjectAccessNotReady, err)
+	}
+}
+
+// TestSubjectAccessHandler tests the status codes of the subject access endpoints.
+func TestSubjectAccessHandler(t *testing.T) {
+	expired := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		userID         string
+		expectedStatus int
+	}{
+		{name: "request", method: http.MethodPost, path: "/users/parent-1/subject-access-requests", userID: "admin-1", expectedStatus: http.StatusAccepted},
+		{name: "request_unknown_user", method: http.MethodPost, path: "/users/missing/subject-access-requests", userID: "admin-1", expectedStatus: http.StatusNotFound},
+		{name: "request_own", method: http.MethodPost, path: "/me/subject-access-requests", userID: "parent-1", expectedStatus: http.StatusAccepted},
+		{name: "status", method: http.MethodGet, path: "/subject-access-requests/sar-ready", userID: "admin-1", expectedStatus: http.StatusOK},
+		{name: "own_status", method: http.MethodGet, path: "/me/subject-access-requests/sar-ready", userID: "parent-1", expectedStatus: http.StatusOK},
+		{name: "other_users_status", method: http.MethodGet, path: "/me/subject-access-requests/sar-ready", userID: "admin-1", expectedStatus: http.StatusNotFound},
+		{name: "download", method: http.MethodGet, path: "/subject-access-requests/sar-ready/archive", userID: "admin-1", expectedStatus: http.StatusOK},
+		{name: "download_expired", method: http.MethodGet, path: "/subject-access-requests/sar-expired/archive", userID: "admin-1", expectedStatus: http.StatusGone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, requests, _, _ := newSubjectAccessService()
+			inAWeek := time.Now().Add(services.SubjectAccessArchiveTTL)
+			requests.SeedSubjectAccessRequest(domain.SubjectAccessRequest{
+				ID: "sar-ready", UserID: "parent-1", Status: domain.SubjectAccessCompleted, ExpiresAt: &inAWeek,
+			}, []byte(`{"manifest":{},"data":{}}`))
+			requests.SeedSubjectAccessRequest(domain.SubjectAccessRequest{
+				ID: "sar-expired", UserID: "parent-1", Status: domain.SubjectAccessCompleted, ExpiresAt: &expired,
+			}, []byte(`{"manifest":{},"data":{}}`))
+
+			h := handler.NewSubjectAccessHandler(service)
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /users/{userID}/subject-access-requests", h.RequestForUser)
+			mux.HandleFunc("POST /me/subject-access-requests", h.RequestOwn)
+			mux.HandleFunc("GET /subject-access-requests/{requestID}", h.Status)
+			mux.HandleFunc("GET /me/subject-access-requests/{requestID}", h.OwnStatus)
+			mux.HandleFunc("GET /subject-access-requests/{requestID}/archive", h.Download)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req = req.WithContext(context.WithValue(tenantContext(), middleware.UserIDKey, tt.userID))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			_ = service.Wait(context.Background())
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if rec.Code == http.StatusAccepted && rec.Header().Get("Location") == "" {
+				t.Error("expected a Location header")
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/middleware"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/config"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+func newTenantConfig() *config.Config {
+	return &config.Config{
+		Tenants: []config.TenantConfig{
+			{ID: "clinic-a", Hosts: []string{"a.baby-kliniek.nl"}, CORSAllowedOrigins: []string{"https://app.a.baby-kliniek.nl"}},
+			{ID: "clinic-b", Hosts: []string{"b.baby-kliniek.nl"}, CORSAllowedOrigins: []string{"https://app.b.baby-kliniek.nl"}},
+		},
+	}
+}
+
+// TestTenantMiddleware tests how the clinic is resolved for a request.
+func TestTenantMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		host           string
+		header         string
+		defaultTenant  string
+		expectedTenant string
+		expectedStatus int
+	}{
+		{name: "by_host", host: "b.baby-kliniek.nl:8080", expectedTenant: "clinic-b", expectedStatus: http.StatusOK},
+		{name: "header_wins_over_host", host: "b.baby-kliniek.nl", header: "clinic-a", expectedTenant: "clinic-a", expectedStatus: http.StatusOK},
+		{name: "unknown_header", host: "b.baby-kliniek.nl", header: "clinic-x", expectedStatus: http.StatusBadRequest},
+		{name: "default_tenant", host: "localhost", defaultTenant: "clinic-a", expectedTenant: "clinic-a", expectedStatus: http.StatusOK},
+		{name: "unresolved_stays_unscoped", host: "localhost", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTenantConfig()
+			cfg.DefaultTenant = tt.defaultTenant
+
+			var gotTenant string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotTenant, _ = domain.TenantFromContext(r.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/login", nil)
+			req.Host = tt.host
+			if tt.header != "" {
+				req.Header.Set(middleware.TenantHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			middleware.TenantMiddleware(cfg)(next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if gotTenant != tt.expectedTenant {
+				t.Errorf("expected tenant %q, got %q", tt.expectedTenant, gotTenant)
+			}
+		})
+	}
+}
+
+// TestCORSMiddleware_PerTenantOrigins verifies each clinic only allows its own frontend.
+func TestCORSMiddleware_PerTenantOrigins(t *testing.T) {
+	cfg := newTenantConfig()
+	router := middleware.TenantMiddleware(cfg)(middleware.CORSMiddleware(cfg)(http.NotFoundHandler()))
+
+	req := httptest.NewRequest(http.MethodOptions, "/login", nil)
+	req.Host = "a.baby-kliniek.nl"
+	req.Header.Set("Origin", "https://app.b.baby-kliniek.nl")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("expected clinic b's origin to be refused on clinic a, got %q", got)
+	}
+
+	req.Header.Set("Origin", "https://app.a.baby-kliniek.nl")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.a.baby-kliniek.nl" {
+		t.Errorf("expected clinic a's origin to be allowed, got %q", got)
+	}
+}
+
+// TestCORSMiddleware_ConditionalUpdates verifies a browser can send PATCH with
+// If-Match and read the ETag it needs for it.
+func TestCORSMiddleware_ConditionalUpdates(t *testing.T) {
+	cfg := newTenantConfig()
+	router := middleware.TenantMiddleware(cfg)(middleware.CORSMiddleware(cfg)(http.NotFoundHandler()))
+
+	req := httptest.NewRequest(http.MethodOptions, "/users/parent-1", nil)
+	req.Host = "a.baby-kliniek.nl"
+	req.Header.Set("Origin", "https://app.a.baby-kliniek.nl")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPatch)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Methods"); !strings.Contains(got, http.MethodPatch) {
+		t.Errorf("expected PATCH to be allowed, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Headers"); !strings.Contains(got, "If-Match") {
+		t.Errorf("expected If-Match to be allowed, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Expose-Headers"); got != "ETag" {
+		t.Errorf("expected ETag to be exposed, got %q", got)
+	}
+}
+
+// TestAuthMiddleware_EnforcesTenantClaim verifies tokens cannot cross clinics.
+// Both rejections happen before the Redis blacklist lookup, so no Redis is needed.
+func TestAuthMiddleware_EnforcesTenantClaim(t *testing.T) {
+	issuer, key := newTestTokenIssuer(t)
+	auth := middleware.NewAuthMiddleware(&key.PublicKey, nil, nil)
+	handler := auth.RequirePermission(domain.PermSessionLogout, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler must not be reached")
+	})
+
+	issued, err := issuer.Issue(domain.WithTenant(context.Background(), "clinic-a"), jwt.MapClaims{"sub": "parent-1"}, services.TokenDuration)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+	req.Header.Set("Authorization", "Bearer "+issued.Token)
+	req = req.WithContext(domain.WithTenant(req.Context(), "clinic-b"))
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected status %d for a token from another clinic, got %d", http.StatusForbidden, rec.Code)
+	}
+
+	untenanted := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "parent-1"})
+	signed, err := untenanted.SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/logout", nil)
+	req.Header.Set("Authorization", "Bearer "+signed)
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d for a token without tenant, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+// TestTokenIssuer_RequiresTenant verifies tokens are never issued outside a clinic.
+func TestTokenIssuer_RequiresTenant(t *testing.T) {
+	issuer, _ := newTestTokenIssuer(t)
+
+	_, err := issuer.Issue(context.Background(), jwt.MapClaims{"sub": "parent-1"}, services.TokenDuration)
+	if !errors.Is(err, domain.ErrNoTenant) {
+		t.Errorf("expected ErrNoTenant, got %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/handler"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/middleware"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+)
+
+// TestTransferService_Transfer verifies the move is stored together with its event.
+func TestTransferService_Transfer(t *testing.T) {
+	mockRepo := newWardRepository()
+	service := services.NewTransferService(mockRepo, mockRepo)
+	admin := domain.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+
+	transfer, err := service.Transfer(tenantContext(), admin, "parent-maternity", "201", "baby moved to NICU")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if transfer.FromRoom != "101" || transfer.ToRoom != "201" || transfer.TransferredBy != "admin-1" {
+		t.Errorf("unexpected transfer: %+v", transfer)
+	}
+
+	parent, _ := mockRepo.FindParentByID(context.Background(), "parent-maternity")
+	if parent.RoomNumber != "201" {
+		t.Errorf("expected parent in room 201, got %q", parent.RoomNumber)
+	}
+
+	if len(mockRepo.OutboxPayloads) != 1 {
+		t.Fatalf("expected 1 outbox event, got %d", len(mockRepo.OutboxPayloads))
+	}
+	var event ports.ParentRoomChangedEvent
+	if err := json.Unmarshal(mockRepo.OutboxPayloads[0], &event); err != nil {
+		t.Fatalf("invalid outbox payload: %v", err)
+	}
+	if event.TenantID != "test-clinic" || event.FromRoom != "101" || event.ToRoom != "201" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+}
+
+// TestTransferService_Rejected tests transfers that must not reach the repository.
+func TestTransferService_Rejected(t *testing.T) {
+	tests := []struct {
+		name        string
+		parentID    string
+		roomNumber  string
+		expectedErr error
+	}{
+		{name: "unknown_parent", parentID: "missing", roomNumber: "201", expectedErr: services.ErrParentNotFound},
+		{name: "same_room", parentID: "parent-maternity", roomNumber: "101", expectedErr: services.ErrSameRoom},
+		{name: "blank_room", parentID: "parent-maternity", roomNumber: " ", expectedErr: services.ErrInvalidRoomNumber},
+		{name: "discharged_parent", parentID: "parent-neonatal", roomNumber: "101", expectedErr: services.ErrParentNotActive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := newWardRepository()
+			_ = mockRepo.ChangeParentStatus(context.Background(), domain.ParentStatusChange{
+				ParentID: "parent-neonatal", FromStatus: domain.ParentActive, ToStatus: domain.ParentDischarged,
+			}, nil)
+			service := services.NewTransferService(mockRepo, mockRepo)
+
+			_, err := service.Transfer(tenantContext(), domain.Actor{ID: "admin-1"}, tt.parentID, tt.roomNumber, "")
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected %v, got %v", tt.expectedErr, err)
+			}
+			if len(mockRepo.TransferParentCalls) != 0 {
+				t.Errorf("expected no TransferParent calls, got %d", len(mockRepo.TransferParentCalls))
+			}
+		})
+	}
+}
+
+// TestParentHandler_Transfer_OutsideWard verifies nurses cannot move parents off their ward.
+func TestParentHandler_Transfer_OutsideWard(t *testing.T) {
+	mockRepo := newWardRepository()
+	h := handler.NewParentHandler(services.NewTransferService(mockRepo, mockRepo), nil, nil, services.NewWardPolicy(mockRepo, mockRepo))
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /parents/{parentID}/transfer", h.Transfer)
+
+	req := httptest.NewRequest(http.MethodPost, "/parents/parent-maternity/transfer",
+		strings.NewReader(`{"room_number": "201"}`))
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, "nurse-1")
+	ctx = context.WithValue(ctx, middleware.RoleKey, string(domain.RoleNurse))
+	req = req.WithContext(ctx)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected status %d, got %d", http.StatusForbidden, rec.Code)
+	}
+	if len(mockRepo.TransferParentCalls) != 0 {
+		t.Error("expected no transfer outside the ward")
+	}
+}
@@ -0,0 +1,234 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/handler"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/middleware"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/test/mocks"
+)
+
+func newUserAdminService() (*services.UserAdminService, *mocks.MockUserRepository, *mocks.MockVisitorRepository, *mocks.MockSessionRevoker) {
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.SeedUser(&domain.User{ID: "admin-1", Email: "admin@baby-kliniek.nl", Role: domain.RoleAdmin,
+		FirstName: "Ada", LastName: "Admin"})
+	userRepo.SeedParent(&domain.Parent{
+		User: domain.User{ID: "parent-1", Email: "parent@example.com", Role: domain.RoleParent,
+			FirstName: "Jane", LastName: "Smiht"},
+		RoomNumber: "101",
+		Status:     domain.ParentActive,
+	})
+	visitorRepo := mocks.NewMockVisitorRepository()
+	sessions := mocks.NewMockSessionRevoker()
+	return newUserAdminServiceFor(userRepo, visitorRepo, sessions), userRepo, visitorRepo, sessions
+}
+
+var adminActor = domain.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+// TestUserAdminService_Update verifies a correction bumps the version and queues an event.
+func TestUserAdminService_Update(t *testing.T) {
+	service, userRepo, _, _ := newUserAdminService()
+
+	record, err := service.Update(context.Background(), adminActor, "parent-1", 1, domain.UserUpdate{
+		LastName: stringPtr("Smith"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.Version != 2 || record.LastName != "Smith" || record.Parent.RoomNumber != "101" {
+		t.Errorf("unexpected record: %+v", record)
+	}
+
+	if len(userRepo.OutboxPayloads) != 1 {
+		t.Fatalf("expected 1 outbox event, got %d", len(userRepo.OutboxPayloads))
+	}
+	var event ports.UserUpdatedEvent
+	if err := json.Unmarshal(userRepo.OutboxPayloads[0], &event); err != nil {
+		t.Fatalf("invalid outbox payload: %v", err)
+	}
+	if strings.Join(event.ChangedFields, ",") != "last_name" || event.Version != 2 {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	// The version read before the update is now stale
+	_, err = service.Update(context.Background(), adminActor, "parent-1", 1, domain.UserUpdate{FirstName: stringPtr("Joan")})
+	if !errors.Is(err, domain.ErrVersionConflict) {
+		t.Errorf("expected %v, got %v", domain.ErrVersionConflict, err)
+	}
+}
+
+// TestUserAdminService_Update_Validation tests field validation.
+func TestUserAdminService_Update_Validation(t *testing.T) {
+	tests := []struct {
+		name        string
+		userID      string
+		update      domain.UserUpdate
+		expectedErr error
+	}{
+		{name: "empty_update", userID: "parent-1", expectedErr: services.ErrEmptyUserUpdate},
+		{name: "invalid_email", userID: "parent-1", update: domain.UserUpdate{Email: stringPtr("not-an-email")}, expectedErr: services.ErrInvalidEmail},
+		{name: "email_taken", userID: "parent-1", update: domain.UserUpdate{Email: stringPtr("admin@baby-kliniek.nl")}, expectedErr: domain.ErrEmailTaken},
+		{name: "blank_name", userID: "parent-1", update: domain.UserUpdate{FirstName: stringPtr("  ")}, expectedErr: services.ErrInvalidName},
+		{name: "blank_room", userID: "parent-1", update: domain.UserUpdate{LastName: stringPtr("Smith"), RoomNumber: stringPtr(" ")}, expectedErr: services.ErrInvalidRoomNumber},
+		{name: "room_of_admin", userID: "admin-1", update: domain.UserUpdate{RoomNumber: stringPtr("102")}, expectedErr: services.ErrRoomNotApplicable},
+		{name: "unknown_user", userID: "missing", update: domain.UserUpdate{FirstName: stringPtr("Joan")}, expectedErr: services.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, userRepo, _, _ := newUserAdminService()
+
+			_, err := service.Update(context.Background(), adminActor, tt.userID, 1, tt.update)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected %v, got %v", tt.expectedErr, err)
+			}
+			if len(userRepo.UpdateUserCalls) != 0 || len(userRepo.TransferParentCalls) != 0 {
+				t.Errorf("expected no writes, got %d updates and %d transfers", len(userRepo.UpdateUserCalls), len(userRepo.TransferParentCalls))
+			}
+		})
+	}
+}
+
+// TestUserAdminService_Update_Room verifies a new room is recorded as a transfer
+// next to the correction of the other fields.
+func TestUserAdminService_Update_Room(t *testing.T) {
+	service, userRepo, _, _ := newUserAdminService()
+
+	record, err := service.Update(tenantContext(), adminActor, "parent-1", 1, domain.UserUpdate{
+		LastName:   stringPtr("Smith"),
+		RoomNumber: stringPtr("102"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.Version != 3 || record.LastName != "Smith" || record.Parent.RoomNumber != "102" {
+		t.Errorf("unexpected record: %+v", record)
+	}
+	if len(userRepo.TransferParentCalls) != 1 {
+		t.Fatalf("expected 1 transfer, got %d", len(userRepo.TransferParentCalls))
+	}
+	if transfer := userRepo.TransferParentCalls[0]; transfer.FromRoom != "101" || transfer.ToRoom != "102" || transfer.TransferredBy != "admin-1" {
+		t.Errorf("unexpected transfer: %+v", transfer)
+	}
+	if len(userRepo.OutboxPayloads) != 2 {
+		t.Errorf("expected user.updated and parent.room_changed events, got %d", len(userRepo.OutboxPayloads))
+	}
+
+	// The ETag returned covers the transfer, so it can be used for the next edit
+	if _, err := service.Update(tenantContext(), adminActor, "parent-1", 3, domain.UserUpdate{RoomNumber: stringPtr("102")}); err != nil {
+		t.Errorf("expected an unchanged room to be accepted, got %v", err)
+	}
+	if len(userRepo.TransferParentCalls) != 1 {
+		t.Errorf("expected no transfer for an unchanged room, got %d", len(userRepo.TransferParentCalls))
+	}
+}
+
+// TestUserAdminService_Deactivate verifies sessions of the parent and their visitors are revoked.
+func TestUserAdminService_Deactivate(t *testing.T) {
+	service, userRepo, visitorRepo, sessions := newUserAdminService()
+	_ = visitorRepo.CreateVisitor(context.Background(), domain.Visitor{
+		ID: "visitor-1", ParentID: "parent-1", DisplayName: "Grandma",
+		ExpiresAt: time.Now().Add(time.Hour), CreatedAt: time.Now(),
+	}, "")
+
+	_, err := service.Deactivate(context.Background(), domain.Actor{ID: "admin-1", Role: domain.RoleAdmin}, "parent-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Join(sessions.RevokedSessions, ",") != "parent-1,visitor-1" {
+		t.Errorf("unexpected revoked sessions: %v", sessions.RevokedSessions)
+	}
+	if len(sessions.RevokedDevices) != 1 {
+		t.Errorf("expected device credentials to be revoked, got %v", sessions.RevokedDevices)
+	}
+	if len(userRepo.DeactivateUserCalls) != 1 || len(userRepo.OutboxPayloads) != 1 {
+		t.Errorf("expected the user to be deactivated with an event")
+	}
+	if _, err := userRepo.FindByID(context.Background(), "parent-1"); err == nil {
+		t.Error("expected deactivated user to be gone from lookups")
+	}
+}
+
+// TestUserAdminService_Deactivate_Rejected tests the cases that leave the user untouched.
+func TestUserAdminService_Deactivate_Rejected(t *testing.T) {
+	admin := domain.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+
+	t.Run("self", func(t *testing.T) {
+		service, _, _, _ := newUserAdminService()
+		if _, err := service.Deactivate(context.Background(), admin, "admin-1"); !errors.Is(err, services.ErrSelfDeactivation) {
+			t.Errorf("expected %v, got %v", services.ErrSelfDeactivation, err)
+		}
+	})
+
+	t.Run("revocation_fails", func(t *testing.T) {
+		service, userRepo, _, sessions := newUserAdminService()
+		sessions.RevokeSessionError = errors.New("redis unavailable")
+
+		if _, err := service.Deactivate(context.Background(), admin, "parent-1"); err == nil {
+			t.Error("expected error but got none")
+		}
+		if len(userRepo.DeactivateUserCalls) != 0 {
+			t.Error("expected the user to stay active when sessions cannot be revoked")
+		}
+	})
+}
+
+// TestUserHandler_UpdateUser tests the If-Match handling of PATCH /users/{userID}.
+func TestUserHandler_UpdateUser(t *testing.T) {
+	tests := []struct {
+		name           string
+		ifMatch        string
+		body           string
+		permissions    []string
+		expectedStatus int
+		expectedETag   string
+	}{
+		{name: "success", ifMatch: `"1"`, body: `{"last_name": "Smith"}`, expectedStatus: http.StatusOK, expectedETag: `"2"`},
+		{name: "missing_if_match", body: `{"last_name": "Smith"}`, expectedStatus: http.StatusPreconditionRequired},
+		{name: "stale_version", ifMatch: `"4"`, body: `{"last_name": "Smith"}`, expectedStatus: http.StatusPreconditionFailed},
+		{name: "unknown_field", ifMatch: `"1"`, body: `{"role": "ADMIN"}`, expectedStatus: http.StatusBadRequest},
+		{name: "room_change", ifMatch: `"1"`, body: `{"room_number": "102"}`, permissions: []string{string(domain.PermParentsTransfer)},
+			expectedStatus: http.StatusOK, expectedETag: `"2"`},
+		{name: "room_change_without_transfer_permission", ifMatch: `"1"`, body: `{"room_number": "102"}`, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, userRepo, _, _ := newUserAdminService()
+			h := handler.NewUserHandler(nil, service, services.NewWardPolicy(userRepo, userRepo))
+			mux := http.NewServeMux()
+			mux.HandleFunc("PATCH /users/{userID}", h.UpdateUser)
+
+			req := httptest.NewRequest(http.MethodPatch, "/users/parent-1", strings.NewReader(tt.body))
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			ctx := context.WithValue(req.Context(), middleware.UserIDKey, "admin-1")
+			ctx = context.WithValue(ctx, middleware.PermissionsKey, tt.permissions)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req.WithContext(ctx))
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedETag != "" && rec.Header().Get("ETag") != tt.expectedETag {
+				t.Errorf("expected ETag %s, got %q", tt.expectedETag, rec.Header().Get("ETag"))
+			}
+		})
+	}
+}
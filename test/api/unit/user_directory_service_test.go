@@ -0,0 +1,193 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/handler"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/test/mocks"
+	"github.com/sony/gobreaker"
+)
+
+// newDirectoryRepository seeds an admin and four parents created one day apart.
+func newDirectoryRepository() *mocks.MockUserRepository {
+	mockRepo := mocks.NewMockUserRepository()
+	base := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	mockRepo.SeedUser(&domain.User{ID: "admin-1", Email: "admin@baby-kliniek.nl", Role: domain.RoleAdmin,
+		FirstName: "Ada", LastName: "Admin", CreatedAt: base})
+	for i := 1; i <= 4; i++ {
+		status := domain.ParentActive
+		if i == 4 {
+			status = domain.ParentDischarged
+		}
+		mockRepo.SeedParent(&domain.Parent{
+			User: domain.User{ID: fmt.Sprintf("parent-%d", i), Email: fmt.Sprintf("parent%d@example.com", i),
+				Role: domain.RoleParent, FirstName: "Parent", LastName: fmt.Sprintf("Number%d", i),
+				CreatedAt: base.AddDate(0, 0, i)},
+			RoomNumber: fmt.Sprintf("10%d", i),
+			Status:     status,
+		})
+	}
+	return mockRepo
+}
+
+// TestUserDirectoryService_Pagination walks every page and checks ordering and completeness.
+func TestUserDirectoryService_Pagination(t *testing.T) {
+	service := services.NewUserDirectoryService(newDirectoryRepository())
+
+	var ids []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("pagination did not terminate")
+		}
+		page, err := service.List(context.Background(), services.UserQuery{
+			Filter: domain.UserFilter{Limit: 2},
+			Cursor: cursor,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, u := range page.Users {
+			ids = append(ids, u.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	expected := []string{"parent-4", "parent-3", "parent-2", "parent-1", "admin-1"}
+	if fmt.Sprint(ids) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, ids)
+	}
+}
+
+// TestUserDirectoryService_Filters tests filtering and search.
+func TestUserDirectoryService_Filters(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   domain.UserFilter
+		expected []string
+	}{
+		{name: "role", filter: domain.UserFilter{Role: domain.RoleAdmin}, expected: []string{"admin-1"}},
+		{name: "parent_status", filter: domain.UserFilter{ParentStatus: domain.ParentDischarged}, expected: []string{"parent-4"}},
+		{name: "room", filter: domain.UserFilter{RoomNumber: "102"}, expected: []string{"parent-2"}},
+		{name: "search_name", filter: domain.UserFilter{Search: "number3"}, expected: []string{"parent-3"}},
+		{name: "search_email", filter: domain.UserFilter{Search: "BABY-KLINIEK"}, expected: []string{"admin-1"}},
+		{
+			name: "created_range",
+			filter: domain.UserFilter{
+				CreatedFrom: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+				CreatedTo:   time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC),
+			},
+			expected: []string{"parent-2", "parent-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := services.NewUserDirectoryService(newDirectoryRepository())
+
+			page, err := service.List(context.Background(), services.UserQuery{Filter: tt.filter})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var ids []string
+			for _, u := range page.Users {
+				ids = append(ids, u.ID)
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, ids)
+			}
+		})
+	}
+}
+
+// TestUserDirectoryService_InvalidQueries tests validation of client input.
+func TestUserDirectoryService_InvalidQueries(t *testing.T) {
+	service := services.NewUserDirectoryService(newDirectoryRepository())
+	day := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		query       services.UserQuery
+		expectedErr error
+	}{
+		{name: "garbage_cursor", query: services.UserQuery{Cursor: "not-a-cursor!"}, expectedErr: services.ErrInvalidCursor},
+		{name: "limit_too_large", query: services.UserQuery{Filter: domain.UserFilter{Limit: services.MaxUserPageSize + 1}}, expectedErr: services.ErrInvalidPageSize},
+		{name: "inverted_range", query: services.UserQuery{Filter: domain.UserFilter{CreatedFrom: day, CreatedTo: day}}, expectedErr: services.ErrInvalidDateRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.List(context.Background(), tt.query); !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+// TestUserHandler_GetUser tests the user detail endpoint.
+func TestUserHandler_GetUser(t *testing.T) {
+	h := handler.NewUserHandler(services.NewUserDirectoryService(newDirectoryRepository()), nil, nil)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{userID}", h.GetUser)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/parent-2", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var record domain.UserRecord
+	if err := json.NewDecoder(rec.Body).Decode(&record); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if record.Parent == nil || record.Parent.RoomNumber != "102" {
+		t.Errorf("expected parent record for room 102, got %+v", record.Parent)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+// TestUserHandler_GetUser_Unavailable tests that a failed lookup is not reported as a missing user.
+func TestUserHandler_GetUser_Unavailable(t *testing.T) {
+	repo := newDirectoryRepository()
+	repo.GetUserRecordError = gobreaker.ErrOpenState
+	h := handler.NewUserHandler(services.NewUserDirectoryService(repo), nil, nil)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{userID}", h.GetUser)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/parent-2", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+}
+
+// TestUserHandler_ListUsers_BadDate tests that malformed dates are rejected.
+func TestUserHandler_ListUsers_BadDate(t *testing.T) {
+	h := handler.NewUserHandler(services.NewUserDirectoryService(newDirectoryRepository()), nil, nil)
+
+	rec := httptest.NewRecorder()
+	h.ListUsers(rec, httptest.NewRequest(http.MethodGet, "/users?created_from=yesterday", nil))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
@@ -0,0 +1,173 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/handler"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/middleware"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/test/mocks"
+)
+
+// TestUserExportService_Prepare tests the defaults and validation of export requests.
+func TestUserExportService_Prepare(t *testing.T) {
+	service := services.NewUserExportService(mocks.NewMockUserRepository(), mocks.NewMockAuditRepository())
+
+	export, err := service.Prepare(services.UserExport{Filter: domain.UserFilter{Limit: 2}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if export.Format != domain.ExportCSV || len(export.Columns) != len(domain.ExportColumns) || export.Filter.Limit != 0 {
+		t.Errorf("unexpected defaults: %+v", export)
+	}
+
+	tests := []struct {
+		name        string
+		export      services.UserExport
+		expectedErr error
+	}{
+		{name: "unknown_format", export: services.UserExport{Format: "xlsx"}, expectedErr: services.ErrInvalidExportFormat},
+		{name: "unknown_column", export: services.UserExport{Columns: []string{"email", "password"}}, expectedErr: services.ErrInvalidExportColumn},
+		{name: "duplicate_column", export: services.UserExport{Columns: []string{"email", "email"}}, expectedErr: services.ErrInvalidExportColumn},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.Prepare(tt.export); !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+// TestUserExportService_Export verifies the export is audited with its filters before any row is streamed.
+func TestUserExportService_Export(t *testing.T) {
+	auditRepo := mocks.NewMockAuditRepository()
+	service := services.NewUserExportService(newDirectoryRepository(), auditRepo)
+	admin := domain.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+
+	var ids []string
+	err := service.Export(tenantContext(), admin, services.UserExport{
+		Filter: domain.UserFilter{ParentStatus: domain.ParentDischarged},
+	}, func(record domain.UserRecord) error {
+		ids = append(ids, record.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(ids, ",") != "parent-4" {
+		t.Errorf("unexpected rows: %v", ids)
+	}
+
+	entries := auditRepo.GetEntries()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 audit entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.Action != domain.AuditUsersExported || entry.ActorID != "admin-1" ||
+		entry.Metadata["status"] != "Discharged" || entry.Metadata["tenant_id"] != "test-clinic" {
+		t.Errorf("unexpected audit entry: %+v", entry)
+	}
+
+	t.Run("audit_unavailable", func(t *testing.T) {
+		auditRepo.RecordAuditError = errors.New("database unavailable")
+		called := false
+		err := service.Export(tenantContext(), admin, services.UserExport{}, func(domain.UserRecord) error {
+			called = true
+			return nil
+		})
+		if err == nil || called {
+			t.Error("expected an export that cannot be audited to stream nothing")
+		}
+	})
+}
+
+// TestExportHandler_ExportUsers tests the rendered CSV and NDJSON output.
+func TestExportHandler_ExportUsers(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedType   string
+		expectedBody   string
+	}{
+		{
+			name:           "csv",
+			query:          "?columns=email,room_number,status&status=Discharged",
+			expectedStatus: http.StatusOK,
+			expectedType:   "text/csv; charset=utf-8",
+			expectedBody:   "email,room_number,status\nparent4@example.com,104,Discharged\n",
+		},
+		{
+			name:           "ndjson",
+			query:          "?format=ndjson&columns=id,role&role=ADMIN",
+			expectedStatus: http.StatusOK,
+			expectedType:   "application/x-ndjson",
+			expectedBody:   `{"id":"admin-1","role":"ADMIN"}` + "\n",
+		},
+		{
+			name:           "empty_csv_has_header",
+			query:          "?columns=email&room=999",
+			expectedStatus: http.StatusOK,
+			expectedType:   "text/csv; charset=utf-8",
+			expectedBody:   "email\n",
+		},
+		{
+			name:           "unknown_column",
+			query:          "?columns=email,password",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid_date",
+			query:          "?created_from=yesterday",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := services.NewUserExportService(newDirectoryRepository(), mocks.NewMockAuditRepository())
+			h := handler.NewExportHandler(service)
+
+			req := httptest.NewRequest(http.MethodGet, "/exports/users"+tt.query, nil)
+			req = req.WithContext(context.WithValue(tenantContext(), middleware.UserIDKey, "admin-1"))
+			rec := httptest.NewRecorder()
+			h.ExportUsers(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.expectedType {
+				t.Errorf("expected Content-Type %q, got %q", tt.expectedType, got)
+			}
+			if rec.Body.String() != tt.expectedBody {
+				t.Errorf("expected body %q, got %q", tt.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+// TestExportHandler_FormulaValues verifies spreadsheet formulas in names are neutralized.
+func TestExportHandler_FormulaValues(t *testing.T) {
+	userRepo := mocks.NewMockUserRepository()
+	userRepo.SeedUser(&domain.User{ID: "admin-1", Email: "admin@baby-kliniek.nl", Role: domain.RoleAdmin,
+		FirstName: "=HYPERLINK(\"http://evil\")", LastName: "Admin"})
+	h := handler.NewExportHandler(services.NewUserExportService(userRepo, mocks.NewMockAuditRepository()))
+
+	req := httptest.NewRequest(http.MethodGet, "/exports/users?columns=first_name", nil)
+	rec := httptest.NewRecorder()
+	h.ExportUsers(rec, req)
+
+	if !strings.Contains(rec.Body.String(), `"'=HYPERLINK(""http://evil"")"`) {
+		t.Errorf("expected the formula to be escaped, got %q", rec.Body.String())
+	}
+}
@@ -0,0 +1,133 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/test/mocks"
+)
+
+func seedVisitorParents(repo *mocks.MockUserRepository) {
+	repo.SeedParent(&domain.Parent{
+		User:       domain.User{ID: "parent-1", Email: "parent@example.com", Role: domain.RoleParent},
+		RoomNumber: "101",
+		Status:     domain.ParentActive,
+	})
+	repo.SeedParent(&domain.Parent{
+		User:       domain.User{ID: "parent-gone", Email: "gone@example.com", Role: domain.RoleParent},
+		RoomNumber: "102",
+		Status:     domain.ParentDischarged,
+	})
+}
+
+// TestVisitorService_Invite tests visitor invitations by email and by code.
+func TestVisitorService_Invite(t *testing.T) {
+	tests := []struct {
+		name        string
+		parentID    string
+		email       string
+		expiresIn   time.Duration
+		expectCode  bool
+		expectedErr error
+	}{
+		{
+			name:       "email_invitation",
+			parentID:   "parent-1",
+			email:      "grandma@example.com",
+			expiresIn:  48 * time.Hour,
+			expectCode: false,
+		},
+		{
+			name:       "code_invitation",
+			parentID:   "parent-1",
+			expiresIn:  48 * time.Hour,
+			expectCode: true,
+		},
+		{
+			name:        "discharged_parent_cannot_invite",
+			parentID:    "parent-gone",
+			expiresIn:   48 * time.Hour,
+			expectedErr: services.ErrVisitorInviteNotAllowed,
+		},
+		{
+			name:        "expiry_in_the_past",
+			parentID:    "parent-1",
+			expiresIn:   -time.Hour,
+			expectedErr: services.ErrVisitorInvalidExpiry,
+		},
+		{
+			name:        "expiry_too_far_ahead",
+			parentID:    "parent-1",
+			expiresIn:   60 * 24 * time.Hour,
+			expectedErr: services.ErrVisitorInvalidExpiry,
+		},
+		{
+			name:        "email_of_registered_user",
+			parentID:    "parent-1",
+			email:       "parent@example.com",
+			expiresIn:   48 * time.Hour,
+			expectedErr: services.ErrVisitorEmailTaken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := mocks.NewMockUserRepository()
+			seedVisitorParents(userRepo)
+			visitorRepo := mocks.NewMockVisitorRepository()
+			issuer, _ := newTestTokenIssuer(t)
+			service := services.NewVisitorService(userRepo, visitorRepo, issuer)
+
+			invitation, err := service.Invite(context.Background(), tt.parentID, tt.email, "Grandma", time.Now().Add(tt.expiresIn))
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (invitation.Code != "") != tt.expectCode {
+				t.Errorf("expected code present=%v, got %q", tt.expectCode, invitation.Code)
+			}
+			if invitation.Visitor.ParentID != tt.parentID {
+				t.Errorf("expected parent %q, got %q", tt.parentID, invitation.Visitor.ParentID)
+			}
+
+			visitors, _ := service.List(context.Background(), tt.parentID)
+			if len(visitors) != 1 {
+				t.Errorf("expected 1 visitor, got %d", len(visitors))
+			}
+		})
+	}
+}
+
+// TestVisitorService_RedeemCode_Revoked verifies revoked visitors cannot log in.
+func TestVisitorService_RedeemCode_Revoked(t *testing.T) {
+	userRepo := mocks.NewMockUserRepository()
+	seedVisitorParents(userRepo)
+	visitorRepo := mocks.NewMockVisitorRepository()
+	issuer, _ := newTestTokenIssuer(t)
+	service := services.NewVisitorService(userRepo, visitorRepo, issuer)
+
+	ctx := context.Background()
+	invitation, err := service.Invite(ctx, "parent-1", "", "Brother", time.Now().Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := visitorRepo.RevokeVisitorsByParent(ctx, "parent-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = service.RedeemCode(ctx, invitation.Code)
+	if !errors.Is(err, services.ErrVisitorAccessDenied) {
+		t.Errorf("expected %v, got %v", services.ErrVisitorAccessDenied, err)
+	}
+}
@@ -0,0 +1,224 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/handler"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/middleware"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/services"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/test/mocks"
+	"github.com/sony/gobreaker"
+)
+
+// newWardRepository seeds a maternity and a neonatal ward with one nurse on maternity.
+func newWardRepository() *mocks.MockUserRepository {
+	mockRepo := mocks.NewMockUserRepository()
+	mockRepo.SeedRoom("101", "MATERNITY")
+	mockRepo.SeedRoom("201", "NEONATAL")
+	mockRepo.SeedStaff(&domain.Staff{
+		User: domain.User{ID: "nurse-1", Email: "nurse@baby-kliniek.nl", Role: domain.RoleNurse},
+		Ward: "MATERNITY",
+	})
+	mockRepo.SeedParent(&domain.Parent{
+		User:       domain.User{ID: "parent-maternity", Email: "m@example.com", Role: domain.RoleParent},
+		RoomNumber: "101",
+		Status:     domain.ParentActive,
+	})
+	mockRepo.SeedParent(&domain.Parent{
+		User:       domain.User{ID: "parent-neonatal", Email: "n@example.com", Role: domain.RoleParent},
+		RoomNumber: "201",
+		Status:     domain.ParentActive,
+	})
+	return mockRepo
+}
+
+// TestWardPolicy_AuthorizeRegistration tests which registrations a nurse may perform.
+func TestWardPolicy_AuthorizeRegistration(t *testing.T) {
+	nurse := domain.Actor{ID: "nurse-1", Role: domain.RoleNurse}
+	admin := domain.Actor{ID: "admin-1", Role: domain.RoleAdmin}
+
+	tests := []struct {
+		name        string
+		actor       domain.Actor
+		role        domain.Role
+		roomNumber  string
+		expectedErr error
+	}{
+		{name: "nurse_registers_parent_on_own_ward", actor: nurse, role: domain.RoleParent, roomNumber: "101"},
+		{name: "nurse_registers_parent_on_other_ward", actor: nurse, role: domain.RoleParent, roomNumber: "201", expectedErr: services.ErrOutsideWard},
+		{name: "nurse_registers_parent_in_unknown_room", actor: nurse, role: domain.RoleParent, roomNumber: "999", expectedErr: services.ErrOutsideWard},
+		{name: "nurse_cannot_register_admin", actor: nurse, role: domain.RoleAdmin, expectedErr: services.ErrOutsideWard},
+		{name: "admin_is_not_ward_bound", actor: admin, role: domain.RoleParent, roomNumber: "201"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := newWardRepository()
+			policy := services.NewWardPolicy(mockRepo, mockRepo)
+
+			err := policy.AuthorizeRegistration(context.Background(), tt.actor, tt.role, tt.roomNumber)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+// TestWardPolicy_AuthorizeParent tests that nurses can only act on parents on their ward.
+func TestWardPolicy_AuthorizeParent(t *testing.T) {
+	mockRepo := newWardRepository()
+	policy := services.NewWardPolicy(mockRepo, mockRepo)
+	nurse := domain.Actor{ID: "nurse-1", Role: domain.RoleNurse}
+
+	if err := policy.AuthorizeParent(context.Background(), nurse, "parent-maternity"); err != nil {
+		t.Errorf("expected parent on own ward to be allowed, got %v", err)
+	}
+	if err := policy.AuthorizeParent(context.Background(), nurse, "parent-neonatal"); !errors.Is(err, services.ErrOutsideWard) {
+		t.Errorf("expected ErrOutsideWard, got %v", err)
+	}
+
+	unassigned := domain.Actor{ID: "nurse-2", Role: domain.RoleNurse}
+	if err := policy.AuthorizeParent(context.Background(), unassigned, "parent-maternity"); !errors.Is(err, services.ErrOutsideWard) {
+		t.Errorf("expected nurse without ward to be denied, got %v", err)
+	}
+}
+
+// TestRegistrationHandler_Register_NurseOutsideWard tests that the handler rejects out-of-ward registrations.
+func TestRegistrationHandler_Register_NurseOutsideWard(t *testing.T) {
+	mockRepo := newWardRepository()
+	service := newRegistrationService(mockRepo)
+	h := handler.NewRegistrationHandler(service, services.NewWardPolicy(mockRepo, mockRepo), newAdminApprovalService(mockRepo))
+
+	reqBody := `{"email":"p@example.com","role":"PARENT","first_name":"P","last_name":"Q","room_number":"201"}`
+	req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewBufferString(reqBody))
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, "nurse-1")
+	ctx = context.WithValue(ctx, middleware.RoleKey, string(domain.RoleNurse))
+	rec := httptest.NewRecorder()
+
+	h.Register(rec, req.WithContext(ctx))
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected status %d, got %d", http.StatusForbidden, rec.Code)
+	}
+	if len(mockRepo.CreateParentCalls) != 0 {
+		t.Errorf("expected no parent to be created")
+	}
+}
+
+// TestRegistrationHandler_Register_WardCheckUnavailable tests that a ward
+// check the database could not answer is reported as an outage, not a denial.
+func TestRegistrationHandler_Register_WardCheckUnavailable(t *testing.T) {
+	mockRepo := newWardRepository()
+	mockRepo.GetRoomWardError = gobreaker.ErrOpenState
+	service := newRegistrationService(mockRepo)
+	policy := services.NewWardPolicy(mockRepo, mockRepo)
+	h := handler.NewRegistrationHandler(service, policy, newAdminApprovalService(mockRepo))
+
+	nurse := domain.Actor{ID: "nurse-1", Role: domain.RoleNurse}
+	if err := policy.AuthorizeRegistration(context.Background(), nurse, domain.RoleParent, "101"); errors.Is(err, services.ErrOutsideWard) || !errors.Is(err, gobreaker.ErrOpenState) {
+		t.Errorf("expected the repository error to be propagated, got %v", err)
+	}
+
+	reqBody := `{"email":"p@example.com","role":"PARENT","first_name":"P","last_name":"Q","room_number":"101"}`
+	req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewBufferString(reqBody))
+	ctx := context.WithValue(req.Context(), middleware.UserIDKey, "nurse-1")
+	ctx = context.WithValue(ctx, middleware.RoleKey, string(domain.RoleNurse))
+	rec := httptest.NewRecorder()
+
+	h.Register(rec, req.WithContext(ctx))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+	if len(mockRepo.CreateParentCalls) != 0 {
+		t.Errorf("expected no parent to be created")
+	}
+}
+
+// TestRegistrationService_RegisterStaff tests clinical staff registration.
+func TestRegistrationService_RegisterStaff(t *testing.T) {
+	mockRepo := mocks.NewMockUserRepository()
+	service := newRegistrationService(mockRepo)
+
+	if _, err := service.RegisterStaff(context.Background(), "n@baby-kliniek.nl", "N", "Urse", domain.RoleNurse, "MATERNITY"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mockRepo.CreateStaffCalls) != 1 || mockRepo.CreateStaffCalls[0].Ward != "MATERNITY" {
+		t.Errorf("expected staff to be created on MATERNITY, got %+v", mockRepo.CreateStaffCalls)
+	}
+
+	if _, err := service.RegisterStaff(context.Background(), "x@baby-kliniek.nl", "X", "Y", domain.RolePediatrician, ""); !errors.Is(err, services.ErrMissingWard) {
+		t.Errorf("expected ErrMissingWard, got %v", err)
+	}
+	if _, err := service.RegisterStaff(context.Background(), "x@baby-kliniek.nl", "X", "Y", domain.RoleAdmin, "MATERNITY"); !errors.Is(err, services.ErrInvalidStaffRole) {
+		t.Errorf("expected ErrInvalidStaffRole, got %v", err)
+	}
+}
+
+// TestRegistrationService_RegisterStaff_ClinicRoles tests that roles created by
+// a clinic can be assigned, with a ward only when the role is ward-scoped.
+func TestRegistrationService_RegisterStaff_ClinicRoles(t *testing.T) {
+	mockRepo := mocks.NewMockUserRepository()
+	permissions := mocks.NewSeededPermissionRepository()
+	permissions.SeedRole(domain.RoleDefinition{Name: "RECEPTIONIST"})
+	permissions.SeedRole(domain.RoleDefinition{Name: "MIDWIFE", WardScoped: true})
+	service := services.NewRegistrationService(mockRepo, permissions, newInvitationService(mockRepo, mocks.NewMockMailer(), time.Hour))
+
+	tests := []struct {
+		name        string
+		role        domain.Role
+		ward        string
+		expectedErr error
+	}{
+		{name: "role_without_ward", role: "RECEPTIONIST"},
+		{name: "ward_scoped_role", role: "MIDWIFE", ward: "MATERNITY"},
+		{name: "ward_on_role_without_ward", role: "RECEPTIONIST", ward: "MATERNITY", expectedErr: services.ErrWardNotApplicable},
+		{name: "ward_scoped_role_without_ward", role: "MIDWIFE", expectedErr: services.ErrMissingWard},
+		{name: "unknown_role", role: "JANITOR", expectedErr: services.ErrUnsupportedRole},
+		{name: "visitor_role", role: domain.RoleVisitor, expectedErr: services.ErrInvalidStaffRole},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			email := fmt.Sprintf("staff-%d@baby-kliniek.nl", i)
+			_, err := service.RegisterStaff(context.Background(), email, "S", "Taff", tt.role, tt.ward)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+
+	if len(mockRepo.CreateStaffCalls) != 2 || mockRepo.CreateStaffCalls[0].Ward != "" || mockRepo.CreateStaffCalls[1].Ward != "MATERNITY" {
+		t.Errorf("expected a receptionist without ward and a midwife on MATERNITY, got %+v", mockRepo.CreateStaffCalls)
+	}
+}
+
+// TestWardPolicy_ClinicRoles tests that ward scoping follows the role data
+// rather than a fixed list of clinical roles.
+func TestWardPolicy_ClinicRoles(t *testing.T) {
+	mockRepo := newWardRepository()
+	mockRepo.SeedWardScopedRole("MIDWIFE")
+	mockRepo.SeedStaff(&domain.Staff{
+		User: domain.User{ID: "midwife-1", Email: "midwife@baby-kliniek.nl", Role: "MIDWIFE"},
+		Ward: "MATERNITY",
+	})
+	policy := services.NewWardPolicy(mockRepo, mockRepo)
+
+	midwife := domain.Actor{ID: "midwife-1", Role: "MIDWIFE"}
+	if err := policy.AuthorizeParent(context.Background(), midwife, "parent-neonatal"); !errors.Is(err, services.ErrOutsideWard) {
+		t.Errorf("expected midwife to be bound to MATERNITY, got %v", err)
+	}
+
+	receptionist := domain.Actor{ID: "receptionist-1", Role: "RECEPTIONIST"}
+	if err := policy.AuthorizeParent(context.Background(), receptionist, "parent-neonatal"); err != nil {
+		t.Errorf("expected a role that is not ward-scoped to be allowed, got %v", err)
+	}
+}
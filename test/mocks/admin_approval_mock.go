@@ -0,0 +1,177 @@
+package mocks
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+// Ensure MockUserRepository implements ports.AdminApprovalRepository at compile time.
+var _ ports.AdminApprovalRepository = (*MockUserRepository)(nil)
+
+// CreateAdminApproval expires overdue approvals and stores the new one,
+// unless one is still pending for the same email or admin.
+// This implements ports.AdminApprovalRepository.CreateAdminApproval
+func (m *MockUserRepository) CreateAdminApproval(ctx context.Context, approval domain.AdminApproval) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.adminApprovals {
+		if existing.Expired(approval.CreatedAt) {
+			existing.Status = domain.AdminApprovalExpired
+		}
+		if existing.Status != domain.AdminApprovalPending || existing.Action != approval.Action {
+			continue
+		}
+		if (approval.Action == domain.AdminApprovalCreate && strings.EqualFold(existing.Email, approval.Email)) ||
+			(approval.Action == domain.AdminApprovalRemove && existing.TargetUserID == approval.TargetUserID) {
+			return domain.ErrAdminApprovalPending
+		}
+	}
+	m.adminApprovals[approval.ID] = &approval
+	return nil
+}
+
+// GetAdminApproval returns a copy of the stored approval.
+// This implements ports.AdminApprovalRepository.GetAdminApproval
+func (m *MockUserRepository) GetAdminApproval(ctx context.Context, id string) (*domain.AdminApproval, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	approval, ok := m.adminApprovals[id]
+	if !ok {
+		return nil, domain.ErrAdminApprovalNotFound
+	}
+	found := *approval
+	return &found, nil
+}
+
+// ListAdminApprovals returns the stored approvals with the status, or all of them, newest first.
+// This implements ports.AdminApprovalRepository.ListAdminApprovals
+func (m *MockUserRepository) ListAdminApprovals(ctx context.Context, status domain.AdminApprovalStatus) ([]domain.AdminApproval, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	approvals := []domain.AdminApproval{}
+	for _, approval := range m.adminApprovals {
+		if status == "" || approval.Status == status {
+			approvals = append(approvals, *approval)
+		}
+	}
+	sort.Slice(approvals, func(i, j int) bool {
+		return approvals[i].CreatedAt.After(approvals[j].CreatedAt)
+	})
+	return approvals, nil
+}
+
+// DecideAdminApproval records the decision of a pending approval.
+// This implements ports.AdminApprovalRepository.DecideAdminApproval
+func (m *MockUserRepository) DecideAdminApproval(ctx context.Context, approval domain.AdminApproval) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.decideAdminApproval(approval)
+}
+
+// ApproveAdminCreation records the approval and creates the admin, tracked in CreateAdminCalls.
+// This implements ports.AdminApprovalRepository.ApproveAdminCreation
+func (m *MockUserRepository) ApproveAdminCreation(ctx context.Context, approval domain.AdminApproval, admin domain.User, outboxPayload []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.CreateAdminCalls = append(m.CreateAdminCalls, admin)
+	if m.CreateAdminError != nil {
+		return m.CreateAdminError
+	}
+	for _, user := range m.users {
+		if strings.EqualFold(user.Email, admin.Email) {
+			return domain.ErrEmailTaken
+		}
+	}
+	if err := m.decideAdminApproval(approval); err != nil {
+		return err
+	}
+	m.users[admin.Email] = &admin
+	m.OutboxPayloads = append(m.OutboxPayloads, outboxPayload)
+	return nil
+}
+
+// ApproveAdminRemoval records the approval and deactivates the admin, tracked in DeactivateUserCalls.
+// This implements ports.AdminApprovalRepository.ApproveAdminRemoval
+func (m *MockUserRepository) ApproveAdminRemoval(ctx context.Context, approval domain.AdminApproval, outboxPayload []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.DeactivateUserCalls = append(m.DeactivateUserCalls, approval.TargetUserID)
+	if m.lastAdmin(approval.TargetUserID) {
+		return domain.ErrLastAdmin
+	}
+	for email, user := range m.users {
+		if user.ID != approval.TargetUserID {
+			continue
+		}
+		if err := m.decideAdminApproval(approval); err != nil {
+			return err
+		}
+		delete(m.users, email)
+		m.deactivated[user.ID] = user
+		m.OutboxPayloads = append(m.OutboxPayloads, outboxPayload)
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+// CountActiveAdmins counts the admins that have not been deactivated.
+// This implements ports.AdminApprovalRepository.CountActiveAdmins
+func (m *MockUserRepository) CountActiveAdmins(ctx context.Context) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.activeAdmins(), nil
+}
+
+// ExpireAdminApproval moves an approval's deadline into the past for test setup.
+func (m *MockUserRepository) ExpireAdminApproval(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if approval, ok := m.adminApprovals[id]; ok {
+		approval.ExpiresAt = approval.CreatedAt
+	}
+}
+
+func (m *MockUserRepository) decideAdminApproval(approval domain.AdminApproval) error {
+	stored, ok := m.adminApprovals[approval.ID]
+	if !ok {
+		return domain.ErrAdminApprovalNotFound
+	}
+	if stored.Status != domain.AdminApprovalPending {
+		return domain.ErrAdminApprovalClosed
+	}
+	m.adminApprovals[approval.ID] = &approval
+	return nil
+}
+
+// lastAdmin reports whether id is the only active admin; the caller holds the lock
+func (m *MockUserRepository) lastAdmin(id string) bool {
+	for _, user := range m.users {
+		if user.ID == id && user.Role == domain.RoleAdmin {
+			return m.activeAdmins() == 1
+		}
+	}
+	return false
+}
+
+func (m *MockUserRepository) activeAdmins() int {
+	admins := 0
+	for _, user := range m.users {
+		if user.Role == domain.RoleAdmin {
+			admins++
+		}
+	}
+	return admins
+}
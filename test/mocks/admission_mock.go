@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+// Ensure MockUserRepository implements ports.AdmissionRepository at compile time.
+var _ ports.AdmissionRepository = (*MockUserRepository)(nil)
+
+// GetAdmission assembles an admission from the stored parents linked to it, oldest first.
+// This implements ports.AdmissionRepository.GetAdmission
+func (m *MockUserRepository) GetAdmission(ctx context.Context, id string) (*domain.Admission, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	admission := domain.Admission{ID: id, Parents: []domain.Parent{}}
+	for _, parent := range m.parents {
+		if parent.AdmissionID == id {
+			admission.Parents = append(admission.Parents, *parent)
+		}
+	}
+	if len(admission.Parents) == 0 {
+		return nil, domain.ErrAdmissionNotFound
+	}
+
+	sort.Slice(admission.Parents, func(i, j int) bool {
+		return admission.Parents[i].CreatedAt.Before(admission.Parents[j].CreatedAt)
+	})
+	admission.CreatedAt = admission.Parents[0].CreatedAt
+	admission.RoomNumber = admission.CurrentRoom()
+	return &admission, nil
+}
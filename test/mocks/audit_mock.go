@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+// MockAuditRepository implements ports.AuditRepository for testing.
+// It keeps recorded entries in memory so tests can assert on the audit trail.
+type MockAuditRepository struct {
+	mu sync.RWMutex
+
+	Entries []domain.AuditEntry
+
+	// Error injection for testing error scenarios
+	RecordAuditError error
+}
+
+// Ensure MockAuditRepository implements ports.AuditRepository at compile time.
+var _ ports.AuditRepository = (*MockAuditRepository)(nil)
+
+// NewMockAuditRepository creates a new mock audit repository.
+func NewMockAuditRepository() *MockAuditRepository {
+	return &MockAuditRepository{}
+}
+
+// RecordAudit stores the entry in memory.
+// This implements ports.AuditRepository.RecordAudit
+func (m *MockAuditRepository) RecordAudit(ctx context.Context, entry domain.AuditEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.RecordAuditError != nil {
+		return m.RecordAuditError
+	}
+
+	m.Entries = append(m.Entries, entry)
+	return nil
+}
+
+// GetEntries returns a copy of all recorded entries.
+func (m *MockAuditRepository) GetEntries() []domain.AuditEntry {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entries := make([]domain.AuditEntry, len(m.Entries))
+	copy(entries, m.Entries)
+	return entries
+}
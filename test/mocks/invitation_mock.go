@@ -0,0 +1,159 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+// Ensure MockUserRepository implements ports.InvitationRepository at compile time.
+var _ ports.InvitationRepository = (*MockUserRepository)(nil)
+
+// CreateParentInvitation stores a pending parent with their invitation.
+// It is tracked in CreateParentCalls and fails with CreateParentError, like CreateParent;
+// the registration event, if any, is kept in OutboxPayloads.
+// This implements ports.InvitationRepository.CreateParentInvitation
+func (m *MockUserRepository) CreateParentInvitation(ctx context.Context, parent domain.Parent, invitation domain.Invitation, outboxPayload []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.CreateParentCalls = append(m.CreateParentCalls, parent)
+
+	if m.CreateParentError != nil {
+		return m.CreateParentError
+	}
+
+	m.parents[parent.ID] = &parent
+	m.users[parent.Email] = &parent.User
+	m.invitations[invitation.ID] = &invitation
+	if len(outboxPayload) > 0 {
+		m.OutboxPayloads = append(m.OutboxPayloads, outboxPayload)
+	}
+	return nil
+}
+
+// CreateParentInvitations stores a batch of pending parents with their invitations;
+// CreateParentError fails the whole batch.
+// This implements ports.InvitationRepository.CreateParentInvitations
+func (m *MockUserRepository) CreateParentInvitations(ctx context.Context, parents []domain.Parent, invitations []domain.Invitation, outboxPayloads [][]byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.CreateParentsCalls = append(m.CreateParentsCalls, parents)
+
+	if m.CreateParentError != nil {
+		return m.CreateParentError
+	}
+
+	for i := range parents {
+		parent, invitation := parents[i], invitations[i]
+		m.parents[parent.ID] = &parent
+		m.users[parent.Email] = &parent.User
+		m.invitations[invitation.ID] = &invitation
+	}
+	m.OutboxPayloads = append(m.OutboxPayloads, outboxPayloads...)
+	return nil
+}
+
+// GetInvitation returns a copy of the stored invitation.
+// This implements ports.InvitationRepository.GetInvitation
+func (m *MockUserRepository) GetInvitation(ctx context.Context, id string) (*domain.Invitation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	invitation, ok := m.invitations[id]
+	if !ok {
+		return nil, domain.ErrInvitationNotFound
+	}
+	found := *invitation
+	return &found, nil
+}
+
+// FindPendingInvitation returns a copy of the user's pending invitation.
+// This implements ports.InvitationRepository.FindPendingInvitation
+func (m *MockUserRepository) FindPendingInvitation(ctx context.Context, userID string) (*domain.Invitation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, invitation := range m.invitations {
+		if invitation.UserID == userID && invitation.Status == domain.InvitationPending {
+			found := *invitation
+			return &found, nil
+		}
+	}
+	return nil, domain.ErrInvitationNotFound
+}
+
+// ListInvitations returns the stored invitations with the status, or all of them, newest first.
+// This implements ports.InvitationRepository.ListInvitations
+func (m *MockUserRepository) ListInvitations(ctx context.Context, status domain.InvitationStatus) ([]domain.Invitation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	invitations := []domain.Invitation{}
+	for _, invitation := range m.invitations {
+		if status == "" || invitation.Status == status {
+			invitations = append(invitations, *invitation)
+		}
+	}
+	sort.Slice(invitations, func(i, j int) bool {
+		return invitations[i].CreatedAt.After(invitations[j].CreatedAt)
+	})
+	return invitations, nil
+}
+
+// UpdateInvitation replaces a stored invitation.
+// This implements ports.InvitationRepository.UpdateInvitation
+func (m *MockUserRepository) UpdateInvitation(ctx context.Context, invitation domain.Invitation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.invitations[invitation.ID]; !ok {
+		return domain.ErrInvitationNotFound
+	}
+	m.invitations[invitation.ID] = &invitation
+	return nil
+}
+
+// MockMailer implements ports.Mailer by recording the messages it was asked to send.
+type MockMailer struct {
+	mu sync.Mutex
+
+	Sent []ports.MailMessage
+
+	// Error injection for testing error scenarios
+	SendError error
+}
+
+// Ensure MockMailer implements ports.Mailer at compile time.
+var _ ports.Mailer = (*MockMailer)(nil)
+
+func NewMockMailer() *MockMailer {
+	return &MockMailer{}
+}
+
+// Send records the message unless SendError is set.
+func (m *MockMailer) Send(ctx context.Context, message ports.MailMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.SendError != nil {
+		return m.SendError
+	}
+	m.Sent = append(m.Sent, message)
+	return nil
+}
+
+// Last returns the most recently sent message.
+func (m *MockMailer) Last() ports.MailMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.Sent) == 0 {
+		return ports.MailMessage{}
+	}
+	return m.Sent[len(m.Sent)-1]
+}
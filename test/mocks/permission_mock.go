@@ -0,0 +1,126 @@
+package mocks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+// MockPermissionRepository implements ports.PermissionRepository for testing.
+type MockPermissionRepository struct {
+	mu sync.RWMutex
+
+	roles       map[domain.Role]*domain.RoleDefinition
+	permissions map[domain.Permission]domain.PermissionDefinition
+}
+
+// Ensure MockPermissionRepository implements ports.PermissionRepository at compile time.
+var _ ports.PermissionRepository = (*MockPermissionRepository)(nil)
+
+// NewMockPermissionRepository creates a mock repository seeded with the given permissions.
+func NewMockPermissionRepository(permissions ...domain.Permission) *MockPermissionRepository {
+	m := &MockPermissionRepository{
+		roles:       make(map[domain.Role]*domain.RoleDefinition),
+		permissions: make(map[domain.Permission]domain.PermissionDefinition),
+	}
+	for _, p := range permissions {
+		m.permissions[p] = domain.PermissionDefinition{Name: p}
+	}
+	return m
+}
+
+// NewSeededPermissionRepository creates a mock repository holding the roles
+// every clinic is seeded with, nurses and pediatricians being ward-scoped.
+func NewSeededPermissionRepository() *MockPermissionRepository {
+	m := NewMockPermissionRepository()
+	for _, role := range []domain.RoleDefinition{
+		{Name: domain.RoleAdmin},
+		{Name: domain.RoleParent},
+		{Name: domain.RoleVisitor},
+		{Name: domain.RoleNurse, WardScoped: true},
+		{Name: domain.RolePediatrician, WardScoped: true},
+	} {
+		role.Permissions = []domain.Permission{}
+		role.Version = 1
+		m.SeedRole(role)
+	}
+	return m
+}
+
+// SeedRole adds a role definition for test setup.
+func (m *MockPermissionRepository) SeedRole(role domain.RoleDefinition) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.roles[role.Name] = &role
+}
+
+func (m *MockPermissionRepository) ListRoles(ctx context.Context) ([]domain.RoleDefinition, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	roles := []domain.RoleDefinition{}
+	for _, role := range m.roles {
+		roles = append(roles, *role)
+	}
+	return roles, nil
+}
+
+func (m *MockPermissionRepository) GetRole(ctx context.Context, name domain.Role) (*domain.RoleDefinition, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	role, ok := m.roles[name]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *role
+	return &copied, nil
+}
+
+func (m *MockPermissionRepository) CreateRole(ctx context.Context, role domain.RoleDefinition) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.roles[role.Name]; ok {
+		return errors.New("role already exists")
+	}
+	m.roles[role.Name] = &role
+	return nil
+}
+
+func (m *MockPermissionRepository) SetRolePermissions(ctx context.Context, name domain.Role, permissions []domain.Permission) (*domain.RoleDefinition, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	role, ok := m.roles[name]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	role.Permissions = permissions
+	role.Version++
+	copied := *role
+	return &copied, nil
+}
+
+func (m *MockPermissionRepository) ListPermissions(ctx context.Context) ([]domain.PermissionDefinition, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	permissions := []domain.PermissionDefinition{}
+	for _, p := range m.permissions {
+		permissions = append(permissions, p)
+	}
+	return permissions, nil
+}
+
+func (m *MockPermissionRepository) CreatePermission(ctx context.Context, permission domain.PermissionDefinition) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.permissions[permission.Name] = permission
+	return nil
+}
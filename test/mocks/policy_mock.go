@@ -0,0 +1,92 @@
+package mocks
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+// MockPolicyRepository implements ports.PolicyRepository for testing.
+// Recorded decisions are kept in memory so tests can assert on the decision log.
+type MockPolicyRepository struct {
+	mu sync.RWMutex
+
+	policies  []domain.Policy
+	Decisions []domain.DecisionLogEntry
+
+	// Error injection for testing error scenarios
+	RecordDecisionsError error
+	DeletePolicyError    error
+}
+
+// Ensure MockPolicyRepository implements ports.PolicyRepository at compile time.
+var _ ports.PolicyRepository = (*MockPolicyRepository)(nil)
+
+// NewMockPolicyRepository creates a mock repository seeded with the given policies.
+func NewMockPolicyRepository(policies ...domain.Policy) *MockPolicyRepository {
+	return &MockPolicyRepository{policies: policies}
+}
+
+func (m *MockPolicyRepository) ListPolicies(ctx context.Context) ([]domain.Policy, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]domain.Policy{}, m.policies...), nil
+}
+
+func (m *MockPolicyRepository) ListPoliciesForAction(ctx context.Context, action string) ([]domain.Policy, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	policies := []domain.Policy{}
+	for _, p := range m.policies {
+		if p.Action == action || p.Action == domain.AnyAction {
+			policies = append(policies, p)
+		}
+	}
+	return policies, nil
+}
+
+func (m *MockPolicyRepository) CreatePolicy(ctx context.Context, policy domain.Policy) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.policies = append(m.policies, policy)
+	return nil
+}
+
+func (m *MockPolicyRepository) DeletePolicy(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.DeletePolicyError != nil {
+		return m.DeletePolicyError
+	}
+
+	for i, p := range m.policies {
+		if p.ID == id {
+			m.policies = append(m.policies[:i], m.policies[i+1:]...)
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (m *MockPolicyRepository) RecordDecisions(ctx context.Context, entries []domain.DecisionLogEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.RecordDecisionsError != nil {
+		return m.RecordDecisionsError
+	}
+	m.Decisions = append(m.Decisions, entries...)
+	return nil
+}
+
+// GetDecisions returns a copy of the recorded decision log.
+func (m *MockPolicyRepository) GetDecisions() []domain.DecisionLogEntry {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]domain.DecisionLogEntry{}, m.Decisions...)
+}
@@ -7,18 +7,18 @@ import (
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
 )
 
-// MockBabyEventPublisher implements ports.BabyEventPublisher for testing.
+// MockEventPublisher implements ports.EventPublisher for testing.
 // This mock allows us to test the outbox relay without a real RabbitMQ connection.
 //
 // In the hexagonal architecture:
-// - ports.BabyEventPublisher is the port (interface)
+// - ports.EventPublisher is the port (interface)
 // - RabbitMQBroker is the real adapter (production)
-// - MockBabyEventPublisher is the test adapter (testing)
-type MockBabyEventPublisher struct {
+// - MockEventPublisher is the test adapter (testing)
+type MockEventPublisher struct {
 	mu sync.RWMutex
 
-	// Track published events for verification
-	PublishedEvents []ports.CreateBabyEvent
+	// Track published envelopes for verification
+	PublishedEvents []ports.EventEnvelope
 
 	// Error injection for testing error scenarios
 	PublishError error
@@ -27,19 +27,19 @@ type MockBabyEventPublisher struct {
 	PublishCallCount int
 }
 
-// Ensure MockBabyEventPublisher implements ports.BabyEventPublisher at compile time.
-var _ ports.BabyEventPublisher = (*MockBabyEventPublisher)(nil)
+// Ensure MockEventPublisher implements ports.EventPublisher at compile time.
+var _ ports.EventPublisher = (*MockEventPublisher)(nil)
 
-// NewMockBabyEventPublisher creates a new mock publisher.
-func NewMockBabyEventPublisher() *MockBabyEventPublisher {
-	return &MockBabyEventPublisher{
-		PublishedEvents: make([]ports.CreateBabyEvent, 0),
+// NewMockEventPublisher creates a new mock publisher.
+func NewMockEventPublisher() *MockEventPublisher {
+	return &MockEventPublisher{
+		PublishedEvents: make([]ports.EventEnvelope, 0),
 	}
 }
 
-// PublishBabyCreated captures published events for verification.
-// This implements ports.BabyEventPublisher.PublishBabyCreated
-func (m *MockBabyEventPublisher) PublishBabyCreated(ctx context.Context, evt ports.CreateBabyEvent) error {
+// Publish captures published envelopes for verification.
+// This implements ports.EventPublisher.Publish
+func (m *MockEventPublisher) Publish(ctx context.Context, envelope ports.EventEnvelope) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -49,34 +49,45 @@ func (m *MockBabyEventPublisher) PublishBabyCreated(ctx context.Context, evt por
 		return m.PublishError
 	}
 
-	m.PublishedEvents = append(m.PublishedEvents, evt)
+	m.PublishedEvents = append(m.PublishedEvents, envelope)
 	return nil
 }
 
-// GetPublishedEvents returns all events that were published.
-func (m *MockBabyEventPublisher) GetPublishedEvents() []ports.CreateBabyEvent {
+// GetPublishedEvents returns all envelopes that were published.
+func (m *MockEventPublisher) GetPublishedEvents() []ports.EventEnvelope {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
 	// Return a copy to prevent race conditions
-	events := make([]ports.CreateBabyEvent, len(m.PublishedEvents))
+	events := make([]ports.EventEnvelope, len(m.PublishedEvents))
 	copy(events, m.PublishedEvents)
 	return events
 }
 
-// GetPublishCount returns the number of times PublishBabyCreated was called.
-func (m *MockBabyEventPublisher) GetPublishCount() int {
+// GetPublishedOfType returns the published envelopes of one event type.
+func (m *MockEventPublisher) GetPublishedOfType(eventType string) []ports.EventEnvelope {
+	var events []ports.EventEnvelope
+	for _, envelope := range m.GetPublishedEvents() {
+		if envelope.Type == eventType {
+			events = append(events, envelope)
+		}
+	}
+	return events
+}
+
+// GetPublishCount returns the number of times Publish was called.
+func (m *MockEventPublisher) GetPublishCount() int {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	return m.PublishCallCount
 }
 
 // Reset clears all tracking data.
-func (m *MockBabyEventPublisher) Reset() {
+func (m *MockEventPublisher) Reset() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.PublishedEvents = make([]ports.CreateBabyEvent, 0)
+	m.PublishedEvents = make([]ports.EventEnvelope, 0)
 	m.PublishError = nil
 	m.PublishCallCount = 0
 }
@@ -5,8 +5,12 @@ package mocks
 
 import (
 	"context"
-	"errors"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
@@ -26,31 +30,83 @@ type MockUserRepository struct {
 	// In-memory storage for testing
 	users   map[string]*domain.User
 	parents map[string]*domain.Parent
+	staff   map[string]string // user ID -> ward
+	rooms   map[string]string // room number -> ward
+	// wardScoped holds the ward-scoped roles; nurses and pediatricians by default
+	wardScoped map[domain.Role]bool
+	versions   map[string]int // user ID -> version; users start at version 1
+
+	statusHistory map[string][]domain.ParentStatusChange
+	deactivated   map[string]*domain.User // user ID -> deactivated user, still erasable
+	erased        map[string]bool
+	invitations   map[string]*domain.Invitation
+
+	adminApprovals map[string]*domain.AdminApproval
+
+	// ProcessedOutboxEvents holds the processed_at times of events the retention job may delete
+	ProcessedOutboxEvents []time.Time
+	// SubjectAccessArchives holds the expires_at times of stored subject access archives
+	SubjectAccessArchives []time.Time
+	// RetentionLocked simulates another replica holding the retention lock
+	RetentionLocked bool
 
 	// Call tracking for verification
 	FindByEmailCalls     []string
+	EmailsTakenCalls     [][]string
+	GetRoomWardsCalls    [][]string
+	FindByIDCalls        []string
 	CreateParentCalls    []domain.Parent
+	CreateParentsCalls   [][]domain.Parent
 	CreateAdminCalls     []domain.User
-	UpdateParentCalls    []string
+	CreateStaffCalls     []domain.Staff
+	ChangeStatusCalls    []domain.ParentStatusChange
 	GetParentStatusCalls []string
+	UpdateUserCalls      []domain.UserRecord
+	DeactivateUserCalls  []string
+	TransferParentCalls  []domain.RoomTransfer
+	EraseUserCalls       []domain.ErasureTombstone
+	// OutboxPayloads holds the events queued by CreateParentInvitations, UpdateUser, DeactivateUser, TransferParent and ChangeParentStatus
+	OutboxPayloads [][]byte
+	// AppendedEvents holds the events queued on their own by AppendEvent, such as logins
+	AppendedEvents []ports.OutboxEvent
 
 	// Error injection for testing error scenarios
 	FindByEmailError     error
+	FindByIDError        error
+	GetUserRecordError   error
 	CreateParentError    error
 	CreateAdminError     error
-	UpdateParentError    error
+	CreateStaffError     error
+	ChangeStatusError    error
 	GetParentStatusError error
+	GetRoomWardError     error
+	UpdateUserError      error
+	AppendEventError     error
 }
 
 // Ensure MockUserRepository implements ports.UserRepository at compile time.
 // This is a common Go pattern to catch interface mismatches early.
 var _ ports.UserRepository = (*MockUserRepository)(nil)
+var _ ports.WardRepository = (*MockUserRepository)(nil)
+var _ ports.RetentionRepository = (*MockUserRepository)(nil)
+var _ ports.EventRepository = (*MockUserRepository)(nil)
 
 // NewMockUserRepository creates a new mock repository with empty storage.
 func NewMockUserRepository() *MockUserRepository {
 	return &MockUserRepository{
-		users:   make(map[string]*domain.User),
-		parents: make(map[string]*domain.Parent),
+		users:      make(map[string]*domain.User),
+		parents:    make(map[string]*domain.Parent),
+		staff:      make(map[string]string),
+		rooms:      make(map[string]string),
+		wardScoped: map[domain.Role]bool{domain.RoleNurse: true, domain.RolePediatrician: true},
+		versions:   make(map[string]int),
+
+		statusHistory: make(map[string][]domain.ParentStatusChange),
+		deactivated:   make(map[string]*domain.User),
+		erased:        make(map[string]bool),
+		invitations:   make(map[string]*domain.Invitation),
+
+		adminApprovals: make(map[string]*domain.AdminApproval),
 	}
 }
 
@@ -69,7 +125,22 @@ func (m *MockUserRepository) SeedParent(parent *domain.Parent) {
 	m.users[parent.Email] = &parent.User
 }
 
-// FindByEmail looks up a user by email address.
+// SeedStaff adds a clinical staff member and their ward assignment for test setup.
+func (m *MockUserRepository) SeedStaff(staff *domain.Staff) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.users[staff.Email] = &staff.User
+	m.staff[staff.ID] = staff.Ward
+}
+
+// SeedRoom places a room on a ward for test setup.
+func (m *MockUserRepository) SeedRoom(roomNumber, ward string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rooms[roomNumber] = ward
+}
+
+// FindByEmail looks up a user by email address, ignoring case like the SQL query.
 // This implements ports.UserRepository.FindByEmail
 func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
 	m.mu.Lock()
@@ -84,11 +155,77 @@ func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*do
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	user, ok := m.users[email]
+	if user, ok := m.users[email]; ok {
+		return user, nil
+	}
+	for stored, user := range m.users {
+		if strings.EqualFold(stored, email) {
+			return user, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// EmailsTaken reports which of the emails belong to a stored user.
+// This implements ports.UserRepository.EmailsTaken
+func (m *MockUserRepository) EmailsTaken(ctx context.Context, emails []string) (map[string]bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.EmailsTakenCalls = append(m.EmailsTakenCalls, emails)
+
+	if m.FindByEmailError != nil {
+		return nil, m.FindByEmailError
+	}
+
+	taken := make(map[string]bool)
+	for _, email := range emails {
+		for stored := range m.users {
+			if strings.EqualFold(stored, email) {
+				taken[strings.ToLower(email)] = true
+			}
+		}
+	}
+	return taken, nil
+}
+
+// FindByID looks up a user by ID.
+// This implements ports.UserRepository.FindByID
+func (m *MockUserRepository) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	m.mu.Lock()
+	m.FindByIDCalls = append(m.FindByIDCalls, id)
+	m.mu.Unlock()
+
+	if m.FindByIDError != nil {
+		return nil, m.FindByIDError
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, user := range m.users {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// FindParentByID looks up a parent by user ID.
+// This implements ports.UserRepository.FindParentByID
+func (m *MockUserRepository) FindParentByID(ctx context.Context, id string) (*domain.Parent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.FindByIDError != nil {
+		return nil, m.FindByIDError
+	}
+
+	parent, ok := m.parents[id]
 	if !ok {
-		return nil, errors.New("user not found")
+		return nil, sql.ErrNoRows
 	}
-	return user, nil
+	return parent, nil
 }
 
 // CreateParent creates a new parent record.
@@ -113,40 +250,391 @@ func (m *MockUserRepository) CreateParent(ctx context.Context, parent domain.Par
 	return &parent, nil
 }
 
-// CreateAdmin creates a new admin record.
-// This implements ports.UserRepository.CreateAdmin
-func (m *MockUserRepository) CreateAdmin(ctx context.Context, user domain.User) (*domain.User, error) {
+// CreateStaff creates a new clinical staff record.
+// This implements ports.UserRepository.CreateStaff
+func (m *MockUserRepository) CreateStaff(ctx context.Context, staff domain.Staff) (*domain.Staff, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.CreateAdminCalls = append(m.CreateAdminCalls, user)
+	m.CreateStaffCalls = append(m.CreateStaffCalls, staff)
 
-	if m.CreateAdminError != nil {
-		return nil, m.CreateAdminError
+	if m.CreateStaffError != nil {
+		return nil, m.CreateStaffError
 	}
 
-	m.users[user.Email] = &user
-	return &user, nil
+	m.users[staff.Email] = &staff.User
+	if staff.Ward != "" {
+		m.staff[staff.ID] = staff.Ward
+	}
+	return &staff, nil
 }
 
-// UpdateParentStatus updates a parent's status to discharged.
-// This implements ports.UserRepository.UpdateParentStatus
-func (m *MockUserRepository) UpdateParentStatus(ctx context.Context, parentID string) error {
+// ListUsers filters, orders and pages the stored users like the SQL directory query.
+// This implements ports.UserRepository.ListUsers
+func (m *MockUserRepository) ListUsers(ctx context.Context, filter domain.UserFilter) ([]domain.UserRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	records := []domain.UserRecord{}
+	for _, user := range m.users {
+		record := m.userRecord(user)
+
+		if filter.Role != "" && record.Role != filter.Role {
+			continue
+		}
+		if filter.ParentStatus != "" && (record.Parent == nil || record.Parent.Status != filter.ParentStatus) {
+			continue
+		}
+		if filter.RoomNumber != "" && (record.Parent == nil || record.Parent.RoomNumber != filter.RoomNumber) {
+			continue
+		}
+		if !filter.CreatedFrom.IsZero() && record.CreatedAt.Before(filter.CreatedFrom) {
+			continue
+		}
+		if !filter.CreatedTo.IsZero() && !record.CreatedAt.Before(filter.CreatedTo) {
+			continue
+		}
+		if filter.Search != "" {
+			search := strings.ToLower(filter.Search)
+			haystack := strings.ToLower(record.Email + " " + record.FirstName + " " + record.LastName)
+			if !strings.Contains(haystack, search) {
+				continue
+			}
+		}
+		if after := filter.After; after != nil {
+			if record.CreatedAt.After(after.CreatedAt) ||
+				(record.CreatedAt.Equal(after.CreatedAt) && record.ID >= after.ID) {
+				continue
+			}
+		}
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].CreatedAt.Equal(records[j].CreatedAt) {
+			return records[i].CreatedAt.After(records[j].CreatedAt)
+		}
+		return records[i].ID > records[j].ID
+	})
+
+	if filter.Limit > 0 && len(records) > filter.Limit {
+		records = records[:filter.Limit]
+	}
+	return records, nil
+}
+
+// StreamUsers calls fn for each user ListUsers would return.
+// This implements ports.UserRepository.StreamUsers
+func (m *MockUserRepository) StreamUsers(ctx context.Context, filter domain.UserFilter, fn func(domain.UserRecord) error) error {
+	records, err := m.ListUsers(ctx, filter)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetUserRecord returns a user with their parent record.
+// This implements ports.UserRepository.GetUserRecord
+func (m *MockUserRepository) GetUserRecord(ctx context.Context, id string) (*domain.UserRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.GetUserRecordError != nil {
+		return nil, m.GetUserRecordError
+	}
+
+	for _, user := range m.users {
+		if user.ID == id {
+			record := m.userRecord(user)
+			return &record, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// userRecord joins a user with their parent record; callers must hold the lock.
+func (m *MockUserRepository) userRecord(user *domain.User) domain.UserRecord {
+	record := domain.UserRecord{User: *user, Version: m.version(user.ID)}
+	if parent, ok := m.parents[user.ID]; ok {
+		record.Parent = &domain.ParentRecord{RoomNumber: parent.RoomNumber, Status: parent.Status}
+	}
+	return record
+}
+
+// version returns the current version of a user; callers must hold the lock.
+func (m *MockUserRepository) version(id string) int {
+	if v, ok := m.versions[id]; ok {
+		return v
+	}
+	return 1
+}
+
+// UpdateUser stores the changed user if the version still matches.
+// This implements ports.UserRepository.UpdateUser
+func (m *MockUserRepository) UpdateUser(ctx context.Context, record domain.UserRecord, expectedVersion int, outboxPayload []byte) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.UpdateParentCalls = append(m.UpdateParentCalls, parentID)
+	m.UpdateUserCalls = append(m.UpdateUserCalls, record)
 
-	if m.UpdateParentError != nil {
-		return m.UpdateParentError
+	if m.UpdateUserError != nil {
+		return m.UpdateUserError
 	}
 
-	if parent, ok := m.parents[parentID]; ok {
-		parent.Status = domain.ParentDischarged
+	var current *domain.User
+	for _, user := range m.users {
+		if user.ID == record.ID {
+			current = user
+		}
+	}
+	if current == nil {
+		return sql.ErrNoRows
+	}
+	if m.version(record.ID) != expectedVersion {
+		return domain.ErrVersionConflict
+	}
+
+	delete(m.users, current.Email)
+	updated := record.User
+	if parent, ok := m.parents[record.ID]; ok {
+		parent.User = updated
+		m.users[updated.Email] = &parent.User
+	} else {
+		m.users[updated.Email] = &updated
+	}
+	for _, invitation := range m.invitations {
+		if invitation.UserID == record.ID && invitation.Status == domain.InvitationPending && invitation.Email != updated.Email {
+			invitation.Email = updated.Email
+			invitation.Sends++
+		}
+	}
+	m.versions[record.ID] = expectedVersion + 1
+	m.OutboxPayloads = append(m.OutboxPayloads, outboxPayload)
+	return nil
+}
+
+// DeactivateUser removes the user from lookups, like the SQL soft delete.
+// This implements ports.UserRepository.DeactivateUser
+func (m *MockUserRepository) DeactivateUser(ctx context.Context, id string, outboxPayload []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.DeactivateUserCalls = append(m.DeactivateUserCalls, id)
+
+	if m.lastAdmin(id) {
+		return domain.ErrLastAdmin
+	}
+	for email, user := range m.users {
+		if user.ID == id {
+			delete(m.users, email)
+			delete(m.parents, id)
+			m.deactivated[id] = user
+			m.OutboxPayloads = append(m.OutboxPayloads, outboxPayload)
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+// FindErasableUser finds an active or deactivated user that has not been erased.
+// This implements ports.UserRepository.FindErasableUser
+func (m *MockUserRepository) FindErasableUser(ctx context.Context, id string) (*domain.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.erased[id] {
+		return nil, domain.ErrNothingToErase
+	}
+	if user, ok := m.deactivated[id]; ok {
+		return user, nil
+	}
+	for _, user := range m.users {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return nil, domain.ErrNothingToErase
+}
+
+// EraseUser pseudonymizes the user, archives a parent and scrubs the user's queued events.
+// This implements ports.UserRepository.EraseUser
+func (m *MockUserRepository) EraseUser(ctx context.Context, tombstone domain.ErasureTombstone, outboxPayload []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.EraseUserCalls = append(m.EraseUserCalls, tombstone)
+
+	if m.erased[tombstone.UserID] {
+		return domain.ErrNothingToErase
+	}
+	if m.lastAdmin(tombstone.UserID) {
+		return domain.ErrLastAdmin
+	}
+	for email, user := range m.users {
+		if user.ID == tombstone.UserID {
+			delete(m.users, email)
+			m.deactivated[user.ID] = user
+		}
+	}
+	user, ok := m.deactivated[tombstone.UserID]
+	if !ok {
+		return domain.ErrNothingToErase
 	}
+
+	user.Email = domain.ErasedEmail(user.ID)
+	user.FirstName = domain.ErasedValue
+	user.LastName = domain.ErasedValue
+	user.DisplayName = ""
+	user.ContactEmail = ""
+	if parent, ok := m.parents[user.ID]; ok {
+		parent.Status = domain.ParentArchived
+		delete(m.parents, user.ID)
+	}
+	m.erased[user.ID] = true
+
+	for i, payload := range m.OutboxPayloads {
+		var event struct {
+			UserID string `json:"user_id"`
+		}
+		if json.Unmarshal(payload, &event) != nil || event.UserID != user.ID {
+			continue
+		}
+		if scrubbed, err := domain.ScrubPersonalData(payload); err == nil {
+			m.OutboxPayloads[i] = scrubbed
+		}
+	}
+	m.OutboxPayloads = append(m.OutboxPayloads, outboxPayload)
 	return nil
 }
 
+// TransferParent moves an active parent and their co-parents if they are all still in the room the transfer starts from.
+// This implements ports.UserRepository.TransferParent
+func (m *MockUserRepository) TransferParent(ctx context.Context, transfer domain.RoomTransfer, outboxPayload []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.TransferParentCalls = append(m.TransferParentCalls, transfer)
+
+	parent, ok := m.parents[transfer.ParentID]
+	if !ok || parent.RoomNumber != transfer.FromRoom || !parent.Status.IsAdmitted() {
+		return domain.ErrVersionConflict
+	}
+	for _, coParentID := range transfer.CoParentIDs {
+		coParent, ok := m.parents[coParentID]
+		if !ok || coParent.RoomNumber != transfer.FromRoom || coParent.Status.HasLeft() {
+			return domain.ErrVersionConflict
+		}
+	}
+
+	for _, parentID := range append([]string{transfer.ParentID}, transfer.CoParentIDs...) {
+		m.parents[parentID].RoomNumber = transfer.ToRoom
+		m.versions[parentID] = m.version(parentID) + 1
+	}
+	m.OutboxPayloads = append(m.OutboxPayloads, outboxPayload)
+	return nil
+}
+
+// GetStaffWard returns the ward a staff member is assigned to.
+// This implements ports.WardRepository.GetStaffWard
+func (m *MockUserRepository) GetStaffWard(ctx context.Context, userID string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ward, ok := m.staff[userID]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+	return ward, nil
+}
+
+// SeedWardScopedRole makes members of the role ward-bound, like a clinic's own ward-scoped role.
+func (m *MockUserRepository) SeedWardScopedRole(role domain.Role) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.wardScoped[role] = true
+}
+
+// IsWardScoped reports whether members of the role are bound to a ward.
+// This implements ports.WardRepository.IsWardScoped
+func (m *MockUserRepository) IsWardScoped(ctx context.Context, role domain.Role) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.wardScoped[role], nil
+}
+
+// GetRoomWard returns the ward a room belongs to.
+// This implements ports.WardRepository.GetRoomWard
+func (m *MockUserRepository) GetRoomWard(ctx context.Context, roomNumber string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.GetRoomWardError != nil {
+		return "", m.GetRoomWardError
+	}
+	ward, ok := m.rooms[roomNumber]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+	return ward, nil
+}
+
+// GetRoomWards returns the ward of each known room.
+// This implements ports.WardRepository.GetRoomWards
+func (m *MockUserRepository) GetRoomWards(ctx context.Context, roomNumbers []string) (map[string]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.GetRoomWardsCalls = append(m.GetRoomWardsCalls, roomNumbers)
+
+	wards := make(map[string]string)
+	for _, roomNumber := range roomNumbers {
+		if ward, ok := m.rooms[roomNumber]; ok {
+			wards[roomNumber] = ward
+		}
+	}
+	return wards, nil
+}
+
+// ChangeParentStatus applies a lifecycle transition if the parent is still in its from status.
+// This implements ports.UserRepository.ChangeParentStatus
+func (m *MockUserRepository) ChangeParentStatus(ctx context.Context, change domain.ParentStatusChange, events []ports.OutboxEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.ChangeStatusCalls = append(m.ChangeStatusCalls, change)
+
+	if m.ChangeStatusError != nil {
+		return m.ChangeStatusError
+	}
+
+	parent, ok := m.parents[change.ParentID]
+	if !ok || parent.Status != change.FromStatus {
+		return domain.ErrVersionConflict
+	}
+
+	parent.Status = change.ToStatus
+	m.statusHistory[change.ParentID] = append(m.statusHistory[change.ParentID], change)
+	m.versions[change.ParentID] = m.version(change.ParentID) + 1
+	for _, event := range events {
+		m.OutboxPayloads = append(m.OutboxPayloads, event.Payload)
+	}
+	return nil
+}
+
+// ListParentStatusHistory returns the recorded transitions of a parent.
+// This implements ports.UserRepository.ListParentStatusHistory
+func (m *MockUserRepository) ListParentStatusHistory(ctx context.Context, parentID string) ([]domain.ParentStatusChange, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return append([]domain.ParentStatusChange{}, m.statusHistory[parentID]...), nil
+}
+
 // GetParentStatus retrieves a parent's current status.
 // This implements ports.UserRepository.GetParentStatus
 func (m *MockUserRepository) GetParentStatus(ctx context.Context, parentID string) (string, error) {
@@ -164,7 +652,7 @@ func (m *MockUserRepository) GetParentStatus(ctx context.Context, parentID strin
 	if parent, ok := m.parents[parentID]; ok {
 		return string(parent.Status), nil
 	}
-	return "", errors.New("parent not found")
+	return "", sql.ErrNoRows
 }
 
 // Reset clears all stored data and call tracking.
@@ -175,14 +663,54 @@ func (m *MockUserRepository) Reset() {
 
 	m.users = make(map[string]*domain.User)
 	m.parents = make(map[string]*domain.Parent)
+	m.staff = make(map[string]string)
+	m.rooms = make(map[string]string)
+	m.versions = make(map[string]int)
+	m.statusHistory = make(map[string][]domain.ParentStatusChange)
+	m.deactivated = make(map[string]*domain.User)
+	m.erased = make(map[string]bool)
+	m.invitations = make(map[string]*domain.Invitation)
+	m.adminApprovals = make(map[string]*domain.AdminApproval)
+	m.ProcessedOutboxEvents = nil
+	m.SubjectAccessArchives = nil
+	m.RetentionLocked = false
 	m.FindByEmailCalls = nil
+	m.EmailsTakenCalls = nil
+	m.GetRoomWardsCalls = nil
+	m.FindByIDCalls = nil
 	m.CreateParentCalls = nil
+	m.CreateParentsCalls = nil
 	m.CreateAdminCalls = nil
-	m.UpdateParentCalls = nil
+	m.CreateStaffCalls = nil
+	m.ChangeStatusCalls = nil
 	m.GetParentStatusCalls = nil
+	m.UpdateUserCalls = nil
+	m.DeactivateUserCalls = nil
+	m.TransferParentCalls = nil
+	m.EraseUserCalls = nil
+	m.OutboxPayloads = nil
+	m.AppendedEvents = nil
 	m.FindByEmailError = nil
+	m.FindByIDError = nil
 	m.CreateParentError = nil
 	m.CreateAdminError = nil
-	m.UpdateParentError = nil
+	m.CreateStaffError = nil
+	m.ChangeStatusError = nil
 	m.GetParentStatusError = nil
+	m.GetRoomWardError = nil
+	m.UpdateUserError = nil
+	m.AppendEventError = nil
+}
+
+// AppendEvent queues an event on its own, tracked in AppendedEvents.
+// This implements ports.EventRepository.AppendEvent
+func (m *MockUserRepository) AppendEvent(ctx context.Context, aggregateType, aggregateID string, event ports.OutboxEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.AppendEventError != nil {
+		return m.AppendEventError
+	}
+	m.AppendedEvents = append(m.AppendedEvents, event)
+	return nil
 }
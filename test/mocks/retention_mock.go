@@ -0,0 +1,167 @@
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+// AcquireRetentionLock fails to acquire the lock while RetentionLocked is set.
+// This implements ports.RetentionRepository.AcquireRetentionLock
+func (m *MockUserRepository) AcquireRetentionLock(ctx context.Context) (func(), bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.RetentionLocked {
+		return nil, false, nil
+	}
+	m.RetentionLocked = true
+	release := func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.RetentionLocked = false
+	}
+	return release, true, nil
+}
+
+// ListParentsDischargedBefore returns discharged or archived parents whose
+// last recorded discharge is before cutoff, ordered by ID.
+// This implements ports.RetentionRepository.ListParentsDischargedBefore
+func (m *MockUserRepository) ListParentsDischargedBefore(ctx context.Context, cutoff time.Time, limit int) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ids := m.parentsDischargedBefore(cutoff)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids, nil
+}
+
+// CountParentsDischargedBefore counts the parents ListParentsDischargedBefore selects.
+// This implements ports.RetentionRepository.CountParentsDischargedBefore
+func (m *MockUserRepository) CountParentsDischargedBefore(ctx context.Context, cutoff time.Time) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.parentsDischargedBefore(cutoff)), nil
+}
+
+func (m *MockUserRepository) parentsDischargedBefore(cutoff time.Time) []string {
+	var ids []string
+	for id, parent := range m.parents {
+		if m.erased[id] || (parent.Status != domain.ParentDischarged && parent.Status != domain.ParentArchived) {
+			continue
+		}
+		var dischargedAt time.Time
+		for _, change := range m.statusHistory[id] {
+			if change.ToStatus == domain.ParentDischarged && change.ChangedAt.After(dischargedAt) {
+				dischargedAt = change.ChangedAt
+			}
+		}
+		if !dischargedAt.IsZero() && dischargedAt.Before(cutoff) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// DeleteProcessedOutboxEvents removes up to limit processed events older than cutoff.
+// This implements ports.RetentionRepository.DeleteProcessedOutboxEvents
+func (m *MockUserRepository) DeleteProcessedOutboxEvents(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	kept := m.ProcessedOutboxEvents[:0]
+	deleted := 0
+	for _, processedAt := range m.ProcessedOutboxEvents {
+		if deleted < limit && processedAt.Before(cutoff) {
+			deleted++
+			continue
+		}
+		kept = append(kept, processedAt)
+	}
+	m.ProcessedOutboxEvents = kept
+	return deleted, nil
+}
+
+// CountProcessedOutboxEvents counts processed events older than cutoff.
+// This implements ports.RetentionRepository.CountProcessedOutboxEvents
+func (m *MockUserRepository) CountProcessedOutboxEvents(ctx context.Context, cutoff time.Time) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	count := 0
+	for _, processedAt := range m.ProcessedOutboxEvents {
+		if processedAt.Before(cutoff) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// PurgeExpiredSubjectAccessArchives drops up to limit archives expired at cutoff.
+// This implements ports.RetentionRepository.PurgeExpiredSubjectAccessArchives
+func (m *MockUserRepository) PurgeExpiredSubjectAccessArchives(ctx context.Context, cutoff time.Time, limit int) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	kept := m.SubjectAccessArchives[:0]
+	purged := 0
+	for _, expiresAt := range m.SubjectAccessArchives {
+		if purged < limit && !expiresAt.After(cutoff) {
+			purged++
+			continue
+		}
+		kept = append(kept, expiresAt)
+	}
+	m.SubjectAccessArchives = kept
+	return purged, nil
+}
+
+// CountExpiredSubjectAccessArchives counts archives expired at cutoff.
+// This implements ports.RetentionRepository.CountExpiredSubjectAccessArchives
+func (m *MockUserRepository) CountExpiredSubjectAccessArchives(ctx context.Context, cutoff time.Time) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	count := 0
+	for _, expiresAt := range m.SubjectAccessArchives {
+		if !expiresAt.After(cutoff) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// MockRetentionMetrics implements ports.RetentionMetrics and keeps what it observed.
+type MockRetentionMetrics struct {
+	mu sync.Mutex
+
+	Reports []domain.RetentionReport
+	Skipped int
+}
+
+// Ensure MockRetentionMetrics implements ports.RetentionMetrics at compile time.
+var _ ports.RetentionMetrics = (*MockRetentionMetrics)(nil)
+
+// ObserveRetentionRun records the report of one clinic.
+func (m *MockRetentionMetrics) ObserveRetentionRun(report domain.RetentionReport) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Reports = append(m.Reports, report)
+}
+
+// ObserveRetentionSkipped counts skipped runs.
+func (m *MockRetentionMetrics) ObserveRetentionSkipped() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Skipped++
+}
@@ -0,0 +1,98 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+// MockSessionRevoker implements ports.SessionRevoker, ports.SessionLister and
+// ports.ImpersonationTracker for testing without Redis.
+type MockSessionRevoker struct {
+	mu sync.Mutex
+
+	// Call tracking for verification
+	RevokedSessions       []string
+	RevokedDevices        []string
+	RevokedImpersonations []string
+
+	// Impersonation token IDs recorded by TrackImpersonation, keyed by user ID
+	Impersonations map[string][]string
+
+	// Sessions returned by ListSessions, keyed by user ID
+	Sessions map[string][]domain.SessionRecord
+
+	// Error injection for testing error scenarios
+	RevokeSessionError      error
+	ListSessionsError       error
+	TrackImpersonationError error
+}
+
+// Ensure MockSessionRevoker implements ports.SessionRevoker at compile time.
+var _ ports.SessionRevoker = (*MockSessionRevoker)(nil)
+var _ ports.SessionLister = (*MockSessionRevoker)(nil)
+var _ ports.ImpersonationTracker = (*MockSessionRevoker)(nil)
+
+func NewMockSessionRevoker() *MockSessionRevoker {
+	return &MockSessionRevoker{}
+}
+
+// RevokeSession records the user whose session was revoked.
+func (m *MockSessionRevoker) RevokeSession(ctx context.Context, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.RevokeSessionError != nil {
+		return m.RevokeSessionError
+	}
+	m.RevokedSessions = append(m.RevokedSessions, userID)
+	return nil
+}
+
+// RevokeDeviceCredentials records the user whose devices were revoked.
+func (m *MockSessionRevoker) RevokeDeviceCredentials(ctx context.Context, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.RevokedDevices = append(m.RevokedDevices, userID)
+	return nil
+}
+
+// RevokeImpersonations records the user whose impersonations were revoked and forgets their tracked tokens.
+func (m *MockSessionRevoker) RevokeImpersonations(ctx context.Context, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.RevokedImpersonations = append(m.RevokedImpersonations, userID)
+	delete(m.Impersonations, userID)
+	return nil
+}
+
+// TrackImpersonation records an impersonation token issued for the user.
+func (m *MockSessionRevoker) TrackImpersonation(ctx context.Context, userID, jti string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.TrackImpersonationError != nil {
+		return m.TrackImpersonationError
+	}
+	if m.Impersonations == nil {
+		m.Impersonations = make(map[string][]string)
+	}
+	m.Impersonations[userID] = append(m.Impersonations[userID], jti)
+	return nil
+}
+
+// ListSessions returns the sessions configured for the user.
+func (m *MockSessionRevoker) ListSessions(ctx context.Context, userID string) ([]domain.SessionRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.ListSessionsError != nil {
+		return nil, m.ListSessionsError
+	}
+	return m.Sessions[userID], nil
+}
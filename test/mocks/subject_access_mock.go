@@ -0,0 +1,127 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+// MockSubjectAccessRepository implements ports.SubjectAccessRepository for testing.
+// CollectSubjectData returns Sections for every user.
+type MockSubjectAccessRepository struct {
+	mu sync.RWMutex
+
+	requests map[string]domain.SubjectAccessRequest
+	archives map[string][]byte
+
+	Sections []domain.SubjectDataSection
+
+	// Error injection for testing error scenarios
+	CollectError error
+}
+
+// Ensure MockSubjectAccessRepository implements ports.SubjectAccessRepository at compile time.
+var _ ports.SubjectAccessRepository = (*MockSubjectAccessRepository)(nil)
+
+// NewMockSubjectAccessRepository creates a new mock subject access repository with empty storage.
+func NewMockSubjectAccessRepository() *MockSubjectAccessRepository {
+	return &MockSubjectAccessRepository{
+		requests: make(map[string]domain.SubjectAccessRequest),
+		archives: make(map[string][]byte),
+	}
+}
+
+// CreateSubjectAccessRequest stores the request, failing stale open requests
+// and rejecting it while another request for the user is open.
+func (m *MockSubjectAccessRepository) CreateSubjectAccessRequest(ctx context.Context, request domain.SubjectAccessRequest, staleBefore time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, existing := range m.requests {
+		if existing.UserID != request.UserID || !isOpen(existing) {
+			continue
+		}
+		if existing.CreatedAt.Before(staleBefore) {
+			existing.Status = domain.SubjectAccessFailed
+			m.requests[id] = existing
+			continue
+		}
+		return domain.ErrSubjectAccessInProgress
+	}
+
+	m.requests[request.ID] = request
+	return nil
+}
+
+// UpdateSubjectAccessRequest replaces the stored request and its archive
+// while the request is still open.
+func (m *MockSubjectAccessRepository) UpdateSubjectAccessRequest(ctx context.Context, request domain.SubjectAccessRequest, archive []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.requests[request.ID]
+	if !ok || (stored.Status != domain.SubjectAccessPending && stored.Status != domain.SubjectAccessRunning) {
+		return domain.ErrSubjectAccessNotFound
+	}
+	m.requests[request.ID] = request
+	if len(archive) > 0 {
+		m.archives[request.ID] = archive
+	}
+	return nil
+}
+
+// GetSubjectAccessRequest returns a stored request.
+func (m *MockSubjectAccessRepository) GetSubjectAccessRequest(ctx context.Context, id string) (*domain.SubjectAccessRequest, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	request, ok := m.requests[id]
+	if !ok {
+		return nil, domain.ErrSubjectAccessNotFound
+	}
+	return &request, nil
+}
+
+// GetSubjectAccessArchive returns the archive stored for a request.
+func (m *MockSubjectAccessRepository) GetSubjectAccessArchive(ctx context.Context, id string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.requests[id]; !ok {
+		return nil, domain.ErrSubjectAccessNotFound
+	}
+	archive, ok := m.archives[id]
+	if !ok {
+		return nil, domain.ErrSubjectAccessNotReady
+	}
+	return archive, nil
+}
+
+// CollectSubjectData returns the configured sections.
+func (m *MockSubjectAccessRepository) CollectSubjectData(ctx context.Context, userID string) ([]domain.SubjectDataSection, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.CollectError != nil {
+		return nil, m.CollectError
+	}
+	return append([]domain.SubjectDataSection(nil), m.Sections...), nil
+}
+
+// SeedSubjectAccessRequest adds a request directly, e.g. one left open by an earlier process.
+func (m *MockSubjectAccessRepository) SeedSubjectAccessRequest(request domain.SubjectAccessRequest, archive []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[request.ID] = request
+	if len(archive) > 0 {
+		m.archives[request.ID] = archive
+	}
+}
+
+func isOpen(request domain.SubjectAccessRequest) bool {
+	return request.Status == domain.SubjectAccessPending || request.Status == domain.SubjectAccessRunning
+}
@@ -0,0 +1,118 @@
+package mocks
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/domain"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+// MockVisitorRepository implements ports.VisitorRepository for testing.
+type MockVisitorRepository struct {
+	mu sync.RWMutex
+
+	visitors   map[string]*domain.Visitor
+	codeHashes map[string]string
+
+	// Error injection for testing error scenarios
+	CreateVisitorError error
+}
+
+// Ensure MockVisitorRepository implements ports.VisitorRepository at compile time.
+var _ ports.VisitorRepository = (*MockVisitorRepository)(nil)
+
+// NewMockVisitorRepository creates a new mock visitor repository with empty storage.
+func NewMockVisitorRepository() *MockVisitorRepository {
+	return &MockVisitorRepository{
+		visitors:   make(map[string]*domain.Visitor),
+		codeHashes: make(map[string]string),
+	}
+}
+
+// CreateVisitor stores a visitor and its optional code hash.
+func (m *MockVisitorRepository) CreateVisitor(ctx context.Context, visitor domain.Visitor, codeHash string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.CreateVisitorError != nil {
+		return m.CreateVisitorError
+	}
+
+	m.visitors[visitor.ID] = &visitor
+	if codeHash != "" {
+		m.codeHashes[codeHash] = visitor.ID
+	}
+	return nil
+}
+
+// FindVisitorByEmail returns an active visitor with the given email, ignoring case.
+func (m *MockVisitorRepository) FindVisitorByEmail(ctx context.Context, email string) (*domain.Visitor, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, visitor := range m.visitors {
+		if visitor.Email != "" && strings.EqualFold(visitor.Email, email) && visitor.IsActive(time.Now()) {
+			return visitor, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// FindVisitorByCodeHash returns the visitor owning the code hash.
+func (m *MockVisitorRepository) FindVisitorByCodeHash(ctx context.Context, codeHash string) (*domain.Visitor, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if id, ok := m.codeHashes[codeHash]; ok {
+		return m.visitors[id], nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+// ListVisitorsByParent returns all visitors of the parent.
+func (m *MockVisitorRepository) ListVisitorsByParent(ctx context.Context, parentID string) ([]domain.Visitor, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	visitors := []domain.Visitor{}
+	for _, visitor := range m.visitors {
+		if visitor.ParentID == parentID {
+			visitors = append(visitors, *visitor)
+		}
+	}
+	return visitors, nil
+}
+
+// RevokeVisitor marks a visitor of the parent as revoked.
+func (m *MockVisitorRepository) RevokeVisitor(ctx context.Context, parentID, visitorID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	visitor, ok := m.visitors[visitorID]
+	if !ok || visitor.ParentID != parentID || visitor.RevokedAt != nil {
+		return sql.ErrNoRows
+	}
+	now := time.Now()
+	visitor.RevokedAt = &now
+	return nil
+}
+
+// RevokeVisitorsByParent revokes every visitor of the parent.
+func (m *MockVisitorRepository) RevokeVisitorsByParent(ctx context.Context, parentID string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := []string{}
+	now := time.Now()
+	for _, visitor := range m.visitors {
+		if visitor.ParentID == parentID && visitor.RevokedAt == nil {
+			visitor.RevokedAt = &now
+			ids = append(ids, visitor.ID)
+		}
+	}
+	return ids, nil
+}
@@ -39,18 +39,23 @@ import (
 
 	"github.com/google/uuid"
 	_ "github.com/lib/pq"
+	amqp "github.com/rabbitmq/amqp091-go"
 
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/messaging"
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/outbox"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/config"
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
 )
 
 var (
-	testDB       *sql.DB
-	testDBURL    string
-	testRabbitMQ *messaging.RabbitMQBroker
+	testDB        *sql.DB
+	testDBURL     string
+	testRabbitURL string
+	testRabbitMQ  *messaging.RabbitMQBroker
 )
 
+var testCloudEvents = config.CloudEventsConfig{Mode: config.CloudEventsBinary, Source: "/identity-access-service"}
+
 // TestMain sets up the integration test environment.
 func TestMain(m *testing.M) {
 	// Check for integration test configuration
@@ -83,7 +88,12 @@ func TestMain(m *testing.M) {
 	}
 
 	// Connect to RabbitMQ
-	testRabbitMQ, err = messaging.NewRabbitMQBroker(rabbitURL, "test_babies")
+	testRabbitURL = rabbitURL
+	testRabbitMQ, err = messaging.NewRabbitMQBroker(rabbitURL, config.QueuesTopology(map[string]string{
+		ports.EventParentRegistered:  "test_babies",
+		ports.EventParentRoomChanged: "test_parent_room_changes",
+		ports.EventUserErased:        "test_user_erasures",
+	}, "test_identity_events"), testCloudEvents)
 	if err != nil {
 		fmt.Printf("Failed to connect to RabbitMQ: %v\n", err)
 		os.Exit(1)
@@ -110,9 +120,12 @@ func setupRelayTestSchema(db *sql.DB) error {
 	schema := `
 		CREATE TABLE IF NOT EXISTS outbox_events (
 			id VARCHAR(36) PRIMARY KEY,
+			tenant_id VARCHAR(50) NOT NULL DEFAULT 'default',
 			aggregate_type VARCHAR(50) NOT NULL,
 			aggregate_id VARCHAR(36) NOT NULL,
 			event_type VARCHAR(50) NOT NULL,
+			schema_version INT NOT NULL DEFAULT 1,
+			correlation_id VARCHAR(64),
 			payload JSONB NOT NULL,
 			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			processed_at TIMESTAMP
@@ -151,10 +164,6 @@ func TestIntegration_RelayProcessesEvent(t *testing.T) {
 
 	cleanupRelayTestData(testDB)
 
-	// Set environment for event type matching
-	os.Setenv("BABY_QUEUE_NAME", "test_babies")
-	defer os.Unsetenv("BABY_QUEUE_NAME")
-
 	// Create relay
 	relay := outbox.NewRelay(testDB, testDBURL, testRabbitMQ)
 
@@ -181,7 +190,7 @@ func TestIntegration_RelayProcessesEvent(t *testing.T) {
 	_, err := testDB.Exec(`
 		INSERT INTO outbox_events (id, aggregate_type, aggregate_id, event_type, payload, created_at)
 		VALUES ($1, $2, $3, $4, $5, $6)
-	`, eventID, "parent", "test-parent-123", "test_babies", payload, time.Now())
+	`, eventID, "parent", "test-parent-123", ports.EventParentRegistered, payload, time.Now())
 
 	if err != nil {
 		t.Fatalf("failed to insert outbox event: %v", err)
@@ -229,9 +238,6 @@ func TestIntegration_RelayProcessesUnprocessedOnStartup(t *testing.T) {
 
 	cleanupRelayTestData(testDB)
 
-	os.Setenv("BABY_QUEUE_NAME", "test_babies")
-	defer os.Unsetenv("BABY_QUEUE_NAME")
-
 	// Insert events BEFORE starting relay (simulate backlog)
 	for i := 1; i <= 3; i++ {
 		event := ports.CreateBabyEvent{
@@ -244,7 +250,7 @@ func TestIntegration_RelayProcessesUnprocessedOnStartup(t *testing.T) {
 		_, err := testDB.Exec(`
 			INSERT INTO outbox_events (id, aggregate_type, aggregate_id, event_type, payload, created_at)
 			VALUES ($1, $2, $3, $4, $5, $6)
-		`, uuid.New().String(), "parent", fmt.Sprintf("parent-%d", i), "test_babies", payload, time.Now())
+		`, uuid.New().String(), "parent", fmt.Sprintf("parent-%d", i), ports.EventParentRegistered, payload, time.Now())
 
 		if err != nil {
 			t.Fatalf("failed to insert backlog event %d: %v", i, err)
@@ -284,9 +290,6 @@ func TestIntegration_RelayHandlesInvalidPayload(t *testing.T) {
 
 	cleanupRelayTestData(testDB)
 
-	os.Setenv("BABY_QUEUE_NAME", "test_babies")
-	defer os.Unsetenv("BABY_QUEUE_NAME")
-
 	// Create invalid JSON payload
 	invalidPayload := []byte(`{}`)
 
@@ -295,7 +298,7 @@ func TestIntegration_RelayHandlesInvalidPayload(t *testing.T) {
 	_, err := testDB.Exec(`
 		INSERT INTO outbox_events (id, aggregate_type, aggregate_id, event_type, payload, created_at)
 		VALUES ($1, $2, $3, $4, $5, $6)
-	`, invalidEventID, "parent", "parent-123", "test_babies", invalidPayload, time.Now())
+	`, invalidEventID, "parent", "parent-123", ports.EventParentRegistered, invalidPayload, time.Now())
 
 	if err != nil {
 		t.Fatalf("failed to insert invalid event: %v", err)
@@ -324,6 +327,124 @@ func TestIntegration_RelayHandlesInvalidPayload(t *testing.T) {
 	}
 }
 
+// TestIntegration_RelayLeavesUnknownEventTypes tests that events of a type the
+// relay does not know stay in the outbox instead of being dropped.
+func TestIntegration_RelayLeavesUnknownEventTypes(t *testing.T) {
+	if testDB == nil || testRabbitMQ == nil {
+		t.Skip("Integration tests require database and RabbitMQ")
+	}
+
+	cleanupRelayTestData(testDB)
+
+	unknownEventID := uuid.New().String()
+	_, err := testDB.Exec(`
+		INSERT INTO outbox_events (id, aggregate_type, aggregate_id, event_type, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, unknownEventID, "user", "user-123", "user.renamed", []byte(`{}`), time.Now())
+
+	if err != nil {
+		t.Fatalf("failed to insert unknown event: %v", err)
+	}
+
+	relay := outbox.NewRelay(testDB, testDBURL, testRabbitMQ)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	go func() {
+		_ = relay.Start(ctx)
+	}()
+
+	time.Sleep(1 * time.Second)
+
+	var processedAt sql.NullTime
+	err = testDB.QueryRow("SELECT processed_at FROM outbox_events WHERE id = $1", unknownEventID).Scan(&processedAt)
+	if err != nil {
+		t.Fatalf("failed to query event: %v", err)
+	}
+
+	if processedAt.Valid {
+		t.Error("unknown event types should be left for a relay that knows them")
+	}
+}
+
+// TestIntegration_BrokerRoutesByEventType tests that in exchange mode every
+// queue bound to a matching routing key receives the event.
+func TestIntegration_BrokerRoutesByEventType(t *testing.T) {
+	if testRabbitMQ == nil {
+		t.Skip("Integration tests require RabbitMQ")
+	}
+
+	topology := config.TopologyConfig{
+		Mode:             config.TopologyExchange,
+		Exchange:         "test_identity",
+		RoutingKeyPrefix: config.DefaultRoutingKeyPrefix,
+		Exchanges: []config.ExchangeConfig{
+			{Name: "test_identity", Type: "topic"},
+			{Name: "test_identity_dlx", Type: "fanout"},
+		},
+		Queues: []config.QueueConfig{
+			{Name: "test_topic_babies", DeadLetterExchange: "test_identity_dlx", MessageTTL: "1h"},
+			{Name: "test_topic_audit"},
+		},
+		Bindings: []config.BindingConfig{
+			{Queue: "test_topic_babies", Exchange: "test_identity", RoutingKey: "identity.parent.registered"},
+			{Queue: "test_topic_audit", Exchange: "test_identity", RoutingKey: "identity.#"},
+		},
+	}
+	broker, err := messaging.NewRabbitMQBroker(testRabbitURL, topology, testCloudEvents)
+	if err != nil {
+		t.Fatalf("failed to declare topology: %v", err)
+	}
+	defer broker.Close()
+
+	conn, err := amqp.Dial(testRabbitURL)
+	if err != nil {
+		t.Fatalf("failed to connect to RabbitMQ: %v", err)
+	}
+	defer conn.Close()
+	ch, err := conn.Channel()
+	if err != nil {
+		t.Fatalf("failed to open channel: %v", err)
+	}
+	defer ch.Close()
+	for _, queue := range []string{"test_topic_babies", "test_topic_audit"} {
+		if _, err := ch.QueuePurge(queue, false); err != nil {
+			t.Fatalf("failed to purge %s: %v", queue, err)
+		}
+	}
+
+	envelope := ports.EventEnvelope{
+		ID:            uuid.New().String(),
+		Type:          ports.EventParentRegistered,
+		SchemaVersion: 1,
+		OccurredAt:    time.Now(),
+		TenantID:      "default",
+		AggregateType: "parent",
+		AggregateID:   "test-parent-123",
+		Payload:       json.RawMessage(`{"user_id":"test-parent-123"}`),
+	}
+	if err := broker.Publish(context.Background(), envelope); err != nil {
+		t.Fatalf("failed to publish: %v", err)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	for _, queue := range []string{"test_topic_babies", "test_topic_audit"} {
+		msg, ok, err := ch.Get(queue, true)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", queue, err)
+		}
+		if !ok {
+			t.Errorf("expected %s to receive the event", queue)
+			continue
+		}
+		if msg.RoutingKey != "identity.parent.registered" || msg.MessageId != envelope.ID {
+			t.Errorf("%s: unexpected message %q with routing key %q", queue, msg.MessageId, msg.RoutingKey)
+		}
+	}
+}
+
 // TestIntegration_RelayRespectsCircuitBreaker tests circuit breaker behavior.
 func TestIntegration_RelayRespectsCircuitBreaker(t *testing.T) {
 	// This test would require simulating database failures
@@ -0,0 +1,73 @@
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/adapters/messaging"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/config"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+// TestEncodeCloudEvent_Binary tests that binary mode keeps the payload as the
+// body and carries the attributes in cloudEvents: application properties.
+func TestEncodeCloudEvent_Binary(t *testing.T) {
+	envelope := newEnvelope(t, ports.EventParentRegistered, ports.CreateBabyEvent{UserID: "user-123", LastName: "TestFamily", RoomNumber: "101"})
+
+	msg, err := messaging.EncodeCloudEvent(envelope, config.CloudEventsConfig{Mode: config.CloudEventsBinary, Source: "/identity-access-service"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if msg.ContentType != "application/json" || string(msg.Body) != string(envelope.Payload) {
+		t.Errorf("expected the JSON payload as the body, got %q: %s", msg.ContentType, msg.Body)
+	}
+	expected := map[string]any{
+		"cloudEvents:specversion":   "1.0",
+		"cloudEvents:id":            "event-1",
+		"cloudEvents:type":          ports.EventParentRegistered,
+		"cloudEvents:source":        "/identity-access-service",
+		"cloudEvents:subject":       "user-123",
+		"cloudEvents:tenantid":      "default",
+		"cloudEvents:correlationid": "correlation-1",
+		"cloudEvents:schemaversion": int32(1),
+	}
+	for name, value := range expected {
+		if msg.Headers[name] != value {
+			t.Errorf("expected %s = %v, got %v", name, value, msg.Headers[name])
+		}
+	}
+	if msg.Headers["cloudEvents:time"] == "" || msg.MessageId != "event-1" {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+}
+
+// TestEncodeCloudEvent_Structured tests that structured mode sends the whole
+// event as an application/cloudevents+json body.
+func TestEncodeCloudEvent_Structured(t *testing.T) {
+	envelope := newEnvelope(t, ports.EventUserErased, ports.UserErasedEvent{UserID: "user-123", Role: "PARENT"})
+
+	msg, err := messaging.EncodeCloudEvent(envelope, config.CloudEventsConfig{Mode: config.CloudEventsStructured, Source: "/identity-access-service"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if msg.ContentType != "application/cloudevents+json; charset=utf-8" || len(msg.Headers) != 0 {
+		t.Errorf("unexpected structured message: %q, %v", msg.ContentType, msg.Headers)
+	}
+	var event struct {
+		SpecVersion     string                `json:"specversion"`
+		ID              string                `json:"id"`
+		Type            string                `json:"type"`
+		DataContentType string                `json:"datacontenttype"`
+		SchemaVersion   int                   `json:"schemaversion"`
+		Data            ports.UserErasedEvent `json:"data"`
+	}
+	if err := json.Unmarshal(msg.Body, &event); err != nil {
+		t.Fatalf("invalid body: %v", err)
+	}
+	if event.SpecVersion != "1.0" || event.ID != "event-1" || event.Type != ports.EventUserErased ||
+		event.DataContentType != "application/json" || event.SchemaVersion != 1 || event.Data.UserID != "user-123" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+}
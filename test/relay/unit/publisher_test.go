@@ -12,7 +12,7 @@
 //	- RabbitMQ (event publishing)
 //
 //	Unit tests mock these dependencies using:
-//	- MockBabyEventPublisher (replaces RabbitMQ)
+//	- MockEventPublisher (replaces RabbitMQ)
 //	- In-memory state verification
 //
 //	                    ┌─────────────────┐
@@ -30,25 +30,47 @@ package unit
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
 	"github.com/AchilleasB/baby-kliniek/identity-access-service/test/mocks"
 )
 
-// TestMockPublisher_PublishBabyCreated tests the mock publisher directly.
+// newEnvelope wraps a payload in an envelope as the relay does for an outbox row.
+func newEnvelope(t *testing.T, eventType string, payload any) ports.EventEnvelope {
+	t.Helper()
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+	return ports.EventEnvelope{
+		ID:            "event-1",
+		Type:          eventType,
+		SchemaVersion: ports.Events.SchemaVersion(eventType),
+		OccurredAt:    time.Now(),
+		TenantID:      "default",
+		AggregateType: "parent",
+		AggregateID:   "user-123",
+		CorrelationID: "correlation-1",
+		Payload:       body,
+	}
+}
+
+// TestMockPublisher_Publish tests the mock publisher directly.
 // This validates our mock implementation works correctly.
-func TestMockPublisher_PublishBabyCreated(t *testing.T) {
-	publisher := mocks.NewMockBabyEventPublisher()
+func TestMockPublisher_Publish(t *testing.T) {
+	publisher := mocks.NewMockEventPublisher()
 
-	event := ports.CreateBabyEvent{
+	envelope := newEnvelope(t, ports.EventParentRegistered, ports.CreateBabyEvent{
 		UserID:     "user-123",
 		LastName:   "TestFamily",
 		RoomNumber: "101",
-	}
+	})
 
 	ctx := context.Background()
-	err := publisher.PublishBabyCreated(ctx, event)
+	err := publisher.Publish(ctx, envelope)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -60,30 +82,30 @@ func TestMockPublisher_PublishBabyCreated(t *testing.T) {
 		t.Fatalf("expected 1 event, got %d", len(events))
 	}
 
-	if events[0].UserID != "user-123" {
-		t.Errorf("expected UserID 'user-123', got %q", events[0].UserID)
+	if events[0].ID != "event-1" || events[0].Type != ports.EventParentRegistered {
+		t.Errorf("unexpected envelope: %+v", events[0])
 	}
-	if events[0].LastName != "TestFamily" {
-		t.Errorf("expected LastName 'TestFamily', got %q", events[0].LastName)
+	if events[0].SchemaVersion != 1 {
+		t.Errorf("expected schema version 1, got %d", events[0].SchemaVersion)
 	}
-	if events[0].RoomNumber != "101" {
-		t.Errorf("expected RoomNumber '101', got %q", events[0].RoomNumber)
+	if events[0].CorrelationID != "correlation-1" {
+		t.Errorf("expected CorrelationID 'correlation-1', got %q", events[0].CorrelationID)
 	}
 }
 
 // TestMockPublisher_ErrorInjection tests error injection.
 func TestMockPublisher_ErrorInjection(t *testing.T) {
-	publisher := mocks.NewMockBabyEventPublisher()
+	publisher := mocks.NewMockEventPublisher()
 	publisher.PublishError = context.DeadlineExceeded
 
-	event := ports.CreateBabyEvent{
+	envelope := newEnvelope(t, ports.EventParentRegistered, ports.CreateBabyEvent{
 		UserID:     "user-123",
 		LastName:   "TestFamily",
 		RoomNumber: "101",
-	}
+	})
 
 	ctx := context.Background()
-	err := publisher.PublishBabyCreated(ctx, event)
+	err := publisher.Publish(ctx, envelope)
 
 	if err != context.DeadlineExceeded {
 		t.Errorf("expected DeadlineExceeded error, got: %v", err)
@@ -98,12 +120,12 @@ func TestMockPublisher_ErrorInjection(t *testing.T) {
 
 // TestMockPublisher_Reset tests the reset functionality.
 func TestMockPublisher_Reset(t *testing.T) {
-	publisher := mocks.NewMockBabyEventPublisher()
+	publisher := mocks.NewMockEventPublisher()
 
 	// Publish some events
 	ctx := context.Background()
-	_ = publisher.PublishBabyCreated(ctx, ports.CreateBabyEvent{UserID: "1"})
-	_ = publisher.PublishBabyCreated(ctx, ports.CreateBabyEvent{UserID: "2"})
+	_ = publisher.Publish(ctx, newEnvelope(t, ports.EventParentRegistered, ports.CreateBabyEvent{UserID: "1"}))
+	_ = publisher.Publish(ctx, newEnvelope(t, ports.EventParentRegistered, ports.CreateBabyEvent{UserID: "2"}))
 
 	if publisher.GetPublishCount() != 2 {
 		t.Fatalf("expected 2 calls before reset")
@@ -122,21 +144,21 @@ func TestMockPublisher_Reset(t *testing.T) {
 
 // TestMockPublisher_ConcurrentPublish tests thread safety.
 func TestMockPublisher_ConcurrentPublish(t *testing.T) {
-	publisher := mocks.NewMockBabyEventPublisher()
+	publisher := mocks.NewMockEventPublisher()
 
 	ctx := context.Background()
 	const numGoroutines = 100
 
+	envelope := newEnvelope(t, ports.EventParentRegistered, ports.CreateBabyEvent{
+		UserID:     "user",
+		LastName:   "Test",
+		RoomNumber: "101",
+	})
 	done := make(chan bool, numGoroutines)
 
 	for i := 0; i < numGoroutines; i++ {
 		go func(n int) {
-			event := ports.CreateBabyEvent{
-				UserID:     "user",
-				LastName:   "Test",
-				RoomNumber: "101",
-			}
-			_ = publisher.PublishBabyCreated(ctx, event)
+			_ = publisher.Publish(ctx, envelope)
 			done <- true
 		}(i)
 	}
@@ -165,8 +187,8 @@ func TestCreateBabyEvent_Structure(t *testing.T) {
 	}
 }
 
-// TestEventPayloadSerialization tests that events serialize correctly.
-// This is important for the relay which marshals/unmarshals events.
+// TestEventPayloadSerialization tests that payloads survive the envelope unchanged.
+// This is important for consumers, which receive the payload as the message body.
 func TestEventPayloadSerialization(t *testing.T) {
 	original := ports.CreateBabyEvent{
 		UserID:     "user-123",
@@ -175,9 +197,9 @@ func TestEventPayloadSerialization(t *testing.T) {
 	}
 
 	// Simulate what happens in the relay
-	publisher := mocks.NewMockBabyEventPublisher()
+	publisher := mocks.NewMockEventPublisher()
 	ctx := context.Background()
-	_ = publisher.PublishBabyCreated(ctx, original)
+	_ = publisher.Publish(ctx, newEnvelope(t, ports.EventParentRegistered, original))
 
 	// Retrieve and compare
 	events := publisher.GetPublishedEvents()
@@ -185,14 +207,71 @@ func TestEventPayloadSerialization(t *testing.T) {
 		t.Fatalf("expected 1 event")
 	}
 
-	received := events[0]
-	if received.UserID != original.UserID {
-		t.Errorf("UserID mismatch: %q != %q", received.UserID, original.UserID)
+	var received ports.CreateBabyEvent
+	if err := json.Unmarshal(events[0].Payload, &received); err != nil {
+		t.Fatalf("invalid payload: %v", err)
 	}
-	if received.LastName != original.LastName {
-		t.Errorf("LastName mismatch: %q != %q", received.LastName, original.LastName)
+	if received != original {
+		t.Errorf("payload mismatch: %+v != %+v", received, original)
 	}
-	if received.RoomNumber != original.RoomNumber {
-		t.Errorf("RoomNumber mismatch: %q != %q", received.RoomNumber, original.RoomNumber)
+}
+
+// TestMockPublisher_GetPublishedOfType tests that events of different types are told apart.
+func TestMockPublisher_GetPublishedOfType(t *testing.T) {
+	publisher := mocks.NewMockEventPublisher()
+	ctx := context.Background()
+
+	_ = publisher.Publish(ctx, newEnvelope(t, ports.EventParentRoomChanged, ports.ParentRoomChangedEvent{UserID: "user-123", FromRoom: "101", ToRoom: "NICU-1"}))
+	_ = publisher.Publish(ctx, newEnvelope(t, ports.EventUserErased, ports.UserErasedEvent{UserID: "user-123", Role: "PARENT"}))
+
+	if len(publisher.GetPublishedOfType(ports.EventParentRoomChanged)) != 1 || len(publisher.GetPublishedOfType(ports.EventUserErased)) != 1 {
+		t.Errorf("unexpected events: %+v", publisher.GetPublishedEvents())
+	}
+	if len(publisher.GetPublishedOfType(ports.EventParentRegistered)) != 0 {
+		t.Errorf("room changes and erasures must not be published as registrations")
+	}
+}
+
+// TestEventRegistry_Lookup tests schema versions, former names and unknown types.
+func TestEventRegistry_Lookup(t *testing.T) {
+	registry := ports.NewEventRegistry(
+		ports.EventType{Name: "parent.registered", SchemaVersion: 2},
+		ports.EventType{Name: "user.erased", SchemaVersion: 1},
+	).Alias("babies", "parent.registered")
+
+	eventType, ok := registry.Lookup("babies")
+	if !ok || eventType.Name != "parent.registered" || eventType.SchemaVersion != 2 {
+		t.Errorf("expected the former name resolved to parent.registered v2, got %+v, %v", eventType, ok)
+	}
+	if _, ok := registry.Lookup("user.renamed"); ok {
+		t.Error("expected an unknown type not to be found")
+	}
+	if registry.SchemaVersion("user.renamed") != 1 {
+		t.Errorf("expected unknown types at schema version 1")
+	}
+
+	names := registry.Names()
+	if len(names) != 3 || names[0] != "babies" || names[2] != "user.erased" {
+		t.Errorf("unexpected names: %v", names)
+	}
+}
+
+// TestEventRegistry_CoversEventTypes tests that every event the service emits can be delivered.
+func TestEventRegistry_CoversEventTypes(t *testing.T) {
+	for _, name := range []string{
+		ports.EventParentRegistered,
+		ports.EventParentRoomChanged,
+		ports.EventParentStatusChanged,
+		ports.EventUserUpdated,
+		ports.EventUserDeactivated,
+		ports.EventUserErased,
+		ports.EventParentDischarged,
+		ports.EventAdminRegistered,
+		ports.EventUserLoggedIn,
+		ports.EventSessionRevoked,
+	} {
+		if _, ok := ports.Events.Lookup(name); !ok {
+			t.Errorf("event type %s is not registered", name)
+		}
 	}
 }
@@ -0,0 +1,88 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/config"
+	"github.com/AchilleasB/baby-kliniek/identity-access-service/internal/core/ports"
+)
+
+// TestTopology_ExchangeDestination tests that exchange mode publishes every
+// event type to the topology's exchange with a routing key derived from the type.
+func TestTopology_ExchangeDestination(t *testing.T) {
+	topology := config.TopologyConfig{
+		Mode:             config.TopologyExchange,
+		Exchange:         "identity",
+		RoutingKeyPrefix: config.DefaultRoutingKeyPrefix,
+	}
+
+	tests := map[string]string{
+		ports.EventParentRegistered: "identity.parent.registered",
+		ports.EventUserErased:       "identity.user.erased",
+		ports.EventSessionRevoked:   "identity.session.revoked",
+	}
+	for eventType, expectedKey := range tests {
+		exchange, routingKey := topology.Destination(eventType)
+		if exchange != "identity" || routingKey != expectedKey {
+			t.Errorf("%s: expected identity/%s, got %q/%q", eventType, expectedKey, exchange, routingKey)
+		}
+	}
+}
+
+// TestTopology_QueuesDestination tests that the compatibility mode sends
+// routed types to their queue and everything else to the default queue,
+// through the default exchange.
+func TestTopology_QueuesDestination(t *testing.T) {
+	topology := config.QueuesTopology(map[string]string{
+		ports.EventParentRegistered: "babies",
+		ports.EventParentDischarged: "babies",
+	}, "identity-events")
+
+	exchange, routingKey := topology.Destination(ports.EventParentRegistered)
+	if exchange != "" || routingKey != "babies" {
+		t.Errorf("expected the babies queue, got %q/%q", exchange, routingKey)
+	}
+	exchange, routingKey = topology.Destination(ports.EventUserLoggedIn)
+	if exchange != "" || routingKey != "identity-events" {
+		t.Errorf("expected the default queue, got %q/%q", exchange, routingKey)
+	}
+
+	if len(topology.Queues) != 2 {
+		t.Errorf("expected each queue declared once, got %v", topology.Queues)
+	}
+}
+
+// TestTopology_QueueArguments tests the x- arguments of a queue with a
+// dead-letter exchange, a message TTL and a length limit.
+func TestTopology_QueueArguments(t *testing.T) {
+	queue := config.QueueConfig{
+		Name:                 "babies",
+		DeadLetterExchange:   "identity.dlx",
+		DeadLetterRoutingKey: "babies.dead",
+		MessageTTL:           "72h",
+		MaxLength:            10000,
+	}
+
+	args, err := queue.Arguments()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"x-dead-letter-exchange":    "identity.dlx",
+		"x-dead-letter-routing-key": "babies.dead",
+		"x-message-ttl":             int64(72 * 60 * 60 * 1000),
+		"x-max-length":              int64(10000),
+	}
+	for name, value := range expected {
+		if args[name] != value {
+			t.Errorf("expected %s = %v, got %v", name, value, args[name])
+		}
+	}
+
+	if args, err := (config.QueueConfig{Name: "plain"}).Arguments(); err != nil || args != nil {
+		t.Errorf("expected no arguments for a plain queue, got %v, %v", args, err)
+	}
+	if _, err := (config.QueueConfig{Name: "bad", MessageTTL: "soon"}).Arguments(); err == nil {
+		t.Error("expected an invalid message_ttl to be rejected")
+	}
+}